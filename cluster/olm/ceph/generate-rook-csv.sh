@@ -87,13 +87,18 @@ OLM_ROLE_YAML_FILE="$OLM_CATALOG_DIR/deploy/role.yaml"
 OLM_ROLE_BINDING_YAML_FILE="$OLM_CATALOG_DIR/deploy/role_binding.yaml"
 OLM_SERVICE_ACCOUNT_YAML_FILE="$OLM_CATALOG_DIR/deploy/service_account.yaml"
 CEPH_CRD_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephclusters.ceph.rook.io.crd.yaml"
+CEPH_OSD_REMOVALS_CRD_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephosdremovals.ceph.rook.io.crd.yaml"
 CEPH_BLOCK_POOLS_CRD_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephblockpools.ceph.rook.io.crd.yaml"
 CEPH_OBJECT_STORE_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephobjectstores.ceph.rook.io.crd.yaml"
 CEPH_OBJECT_STORE_USERS_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephobjectstoreusers.ceph.rook.io.crd.yaml"
 CEPH_OBJECT_REALM_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephobjectrealms.ceph.rook.io.crd.yaml"
 CEPH_OBJECT_ZONEGROUP_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephobjectzonegroups.ceph.rook.io.crd.yaml"
 CEPH_OBJECT_ZONE_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephobjectzones.ceph.rook.io.crd.yaml"
+CEPH_BUCKET_TOPIC_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephbuckettopics.ceph.rook.io.crd.yaml"
+CEPH_BUCKET_NOTIFICATION_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephbucketnotifications.ceph.rook.io.crd.yaml"
 CEPH_FILESYSTEMS_CRD_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephfilesystems.ceph.rook.io.crd.yaml"
+CEPH_FS_SUBVOLUMEGROUPS_CRD_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephfilesystemsubvolumegroups.ceph.rook.io.crd.yaml"
+CEPH_FS_MIRRORS_CRD_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephfilesystemmirrors.ceph.rook.io.crd.yaml"
 CEPH_NFS_CRD_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephnfses.ceph.rook.io.crd.yaml"
 CEPH_CLIENT_CRD_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephclients.ceph.rook.io.crd.yaml"
 CEPH_RBD_MIRROR_CRD_YAML_FILE="$OLM_CATALOG_DIR/deploy/crds/cephrbdmirrors.ceph.rook.io.crd.yaml"
@@ -210,11 +215,14 @@ function generate_service_account_yaml() {
 
 function generate_crds_yaml() {
     sed -n '/^# OLM: BEGIN CEPH CRD$/,/# OLM: END CEPH CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_CRD_YAML_FILE"
+    sed -n '/^# OLM: BEGIN CEPH OSD REMOVAL CRD$/,/# OLM: END CEPH OSD REMOVAL CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_OSD_REMOVALS_CRD_YAML_FILE"
     sed -n '/^# OLM: BEGIN CEPH OBJECT STORE CRD$/,/# OLM: END CEPH OBJECT STORE CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_OBJECT_STORE_YAML_FILE"
     sed -n '/^# OLM: BEGIN CEPH OBJECT STORE USERS CRD$/,/# OLM: END CEPH OBJECT STORE USERS CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_OBJECT_STORE_USERS_YAML_FILE"
     sed -n '/^# OLM: BEGIN CEPH OBJECT REALM CRD$/,/# OLM: END CEPH OBJECT REALM CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_OBJECT_REALM_YAML_FILE"
     sed -n '/^# OLM: BEGIN CEPH OBJECT ZONEGROUP CRD$/,/# OLM: END CEPH OBJECT ZONEGROUP CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_OBJECT_ZONEGROUP_YAML_FILE"
     sed -n '/^# OLM: BEGIN CEPH OBJECT ZONE CRD$/,/# OLM: END CEPH OBJECT ZONE CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_OBJECT_ZONE_YAML_FILE"
+    sed -n '/^# OLM: BEGIN CEPH BUCKET TOPIC CRD$/,/# OLM: END CEPH BUCKET TOPIC CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_BUCKET_TOPIC_YAML_FILE"
+    sed -n '/^# OLM: BEGIN CEPH BUCKET NOTIFICATION CRD$/,/# OLM: END CEPH BUCKET NOTIFICATION CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_BUCKET_NOTIFICATION_YAML_FILE"
     sed -n '/^# OLM: BEGIN CEPH BLOCK POOL CRD$/,/# OLM: END CEPH BLOCK POOL CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_BLOCK_POOLS_CRD_YAML_FILE"
     sed -n '/^# OLM: BEGIN CEPH NFS CRD$/,/# OLM: END CEPH NFS CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_NFS_CRD_YAML_FILE"
     sed -n '/^# OLM: BEGIN CEPH CLIENT CRD$/,/# OLM: END CEPH CLIENT CRD$/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_CLIENT_CRD_YAML_FILE"
@@ -222,6 +230,8 @@ function generate_crds_yaml() {
 
     if [ -n "$OLM_INCLUDE_CEPHFS_CSI" ]; then
         sed -n '/^# OLM: BEGIN CEPH FS CRD$/,/# OLM: END CEPH FS CRD/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_FILESYSTEMS_CRD_YAML_FILE"
+        sed -n '/^# OLM: BEGIN CEPH FS SUBVOLUMEGROUP CRD$/,/# OLM: END CEPH FS SUBVOLUMEGROUP CRD/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_FS_SUBVOLUMEGROUPS_CRD_YAML_FILE"
+        sed -n '/^# OLM: BEGIN CEPH FS MIRROR CRD$/,/# OLM: END CEPH FS MIRROR CRD/p' "$COMMON_YAML_FILE" | grep -v '^#' > "$CEPH_FS_MIRRORS_CRD_YAML_FILE"
     fi
 }
 
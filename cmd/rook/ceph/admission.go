@@ -41,6 +41,6 @@ func startAdmissionController(cmd *cobra.Command, args []string) {
 
 	context := rook.NewContext()
 
-	a := admission.New(context, "ceph", operator.ValidateCephResource)
+	a := admission.New(context, "ceph", operator.ValidateCephResource, operator.MutateCephResource)
 	a.StartServer()
 }
@@ -17,6 +17,10 @@ limitations under the License.
 package ceph
 
 import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
 	"github.com/rook/rook/cmd/rook/rook"
 	cleanup "github.com/rook/rook/pkg/daemon/ceph/cleanup"
 	"github.com/rook/rook/pkg/util/flags"
@@ -29,6 +33,7 @@ var (
 	monSecret       string
 	clusterFSID     string
 	clusterName     string
+	osdIDs          string
 )
 
 var cleanUpCmd = &cobra.Command{
@@ -42,6 +47,7 @@ func init() {
 	cleanUpCmd.Flags().StringVar(&monSecret, "mon-secret", "", "monitor secret from the keyring")
 	cleanUpCmd.Flags().StringVar(&clusterFSID, "cluster-fsid", "", "ceph cluster fsid")
 	cleanUpCmd.Flags().StringVar(&clusterName, "cluster-name", "", "ceph cluster name")
+	cleanUpCmd.Flags().StringVar(&osdIDs, "osd-ids", "", "comma separated list of the osds to wipe, all the osds of the cluster are wiped if empty")
 	flags.SetFlagsFromEnv(cleanUpCmd.Flags(), rook.RookEnvVarPrefix)
 	cleanUpCmd.RunE = startCleanUp
 }
@@ -57,14 +63,28 @@ func startCleanUp(cmd *cobra.Command, args []string) error {
 		cleanup.StartHostPathCleanup(namespaceDir, dataDirHostPath, monSecret)
 	}
 
+	ids := []int{}
+	if osdIDs != "" {
+		for _, osdID := range strings.Split(osdIDs, ",") {
+			id, err := strconv.Atoi(osdID)
+			if err != nil {
+				return errors.Wrapf(err, "invalid osd id %q", osdID)
+			}
+			ids = append(ids, id)
+		}
+	}
+
 	// Build Sanitizer
 	s := cleanup.NewDiskSanitizer(createContext(),
 		clusterName,
 		clusterFSID,
+		ids,
 	)
 
 	// Start OSD wipe process
-	cleanup.StartSanitizeDisks(s)
+	if err := cleanup.StartSanitizeDisks(s); err != nil {
+		return errors.Wrap(err, "failed to wipe the disks of the osds")
+	}
 
 	return nil
 }
@@ -49,6 +49,8 @@ func init() {
 
 	operatorCmd.Flags().BoolVar(&operator.EnableFlexDriver, "enable-flex-driver", true, "enable the rook flex driver")
 	operatorCmd.Flags().BoolVar(&operator.EnableDiscoveryDaemon, "enable-discovery-daemon", true, "enable the rook discovery daemon")
+	operatorCmd.Flags().BoolVar(&operator.EnableAdmissionController, "enable-admission-controller", false, "generate the certificates of the admission controller and register its validating webhook")
+	operatorCmd.Flags().StringVar(&operator.MetricsBindAddress, "metrics-bind-address", operator.MetricsBindAddress, "address to serve the operator prometheus metrics on, or \"0\" to disable the metrics endpoint")
 
 	// csi deployment templates
 	operatorCmd.Flags().StringVar(&csi.RBDPluginTemplatePath, "csi-rbd-plugin-template-path", csi.DefaultRBDPluginTemplatePath, "path to ceph-csi rbd plugin template")
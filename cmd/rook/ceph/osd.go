@@ -17,6 +17,8 @@ limitations under the License.
 package ceph
 
 import (
+	"encoding/json"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"strings"
@@ -25,7 +27,9 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/cmd/rook/rook"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	osddaemon "github.com/rook/rook/pkg/daemon/ceph/osd"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 	osdcfg "github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
@@ -51,6 +55,10 @@ var osdStartCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Starts the osd daemon", // OSDs that were provisioned by ceph-volume
 }
+var osdEncryptionKeyCmd = &cobra.Command{
+	Use:   "encryption-key",
+	Short: "Fetches the dm-crypt key of an osd on pvc from the key management system",
+}
 
 var (
 	osdDataDeviceFilter     string
@@ -67,6 +75,10 @@ var (
 	pvcBackedOSD            bool
 	blockPath               string
 	lvBackedPV              bool
+	encryptionPVCName       string
+	encryptionKeyFilePath   string
+	osdCrushHierarchy       string
+	osdDeviceClassRules     string
 )
 
 func addOSDFlags(command *cobra.Command) {
@@ -81,6 +93,8 @@ func addOSDFlags(command *cobra.Command) {
 	provisionCmd.Flags().BoolVar(&cfg.forceFormat, "force-format", false,
 		"true to force the format of any specified devices, even if they already have a filesystem.  BE CAREFUL!")
 	provisionCmd.Flags().BoolVar(&cfg.pvcBacked, "pvc-backed-osd", false, "true to specify a block mode pvc is backing the OSD")
+	provisionCmd.Flags().StringVar(&osdCrushHierarchy, "crush-hierarchy", "", "json list of the crush bucket types set by node labels")
+	provisionCmd.Flags().StringVar(&osdDeviceClassRules, "device-class-rules", "", "json list of the rules assigning the device classes")
 	// flags for generating the osd config
 	osdConfigCmd.Flags().IntVar(&osdID, "osd-id", -1, "osd id for which to generate config")
 	osdConfigCmd.Flags().BoolVar(&osdIsDevice, "is-device", false, "whether the osd is a device")
@@ -93,10 +107,15 @@ func addOSDFlags(command *cobra.Command) {
 	osdStartCmd.Flags().StringVar(&blockPath, "block-path", "", "Block path for the OSD created by ceph-volume")
 	osdStartCmd.Flags().BoolVar(&lvBackedPV, "lv-backed-pv", false, "Whether the PV located on LV")
 
+	// flags for fetching the dm-crypt key of an encrypted osd
+	osdEncryptionKeyCmd.Flags().StringVar(&encryptionPVCName, "pvc-name", "", "the pvc holding the encrypted osd block")
+	osdEncryptionKeyCmd.Flags().StringVar(&encryptionKeyFilePath, "key-file-path", "", "the file where the key is written")
+
 	// add the subcommands to the parent osd command
 	osdCmd.AddCommand(osdConfigCmd,
 		provisionCmd,
-		osdStartCmd)
+		osdStartCmd,
+		osdEncryptionKeyCmd)
 }
 
 func addOSDConfigFlags(command *cobra.Command) {
@@ -119,10 +138,37 @@ func init() {
 	flags.SetFlagsFromEnv(osdConfigCmd.Flags(), rook.RookEnvVarPrefix)
 	flags.SetFlagsFromEnv(provisionCmd.Flags(), rook.RookEnvVarPrefix)
 	flags.SetFlagsFromEnv(osdStartCmd.Flags(), rook.RookEnvVarPrefix)
+	flags.SetFlagsFromEnv(osdEncryptionKeyCmd.Flags(), rook.RookEnvVarPrefix)
 
 	osdConfigCmd.RunE = writeOSDConfig
 	provisionCmd.RunE = prepareOSD
 	osdStartCmd.RunE = startOSD
+	osdEncryptionKeyCmd.RunE = fetchOSDEncryptionKey
+}
+
+// Write the dm-crypt key of an osd on pvc stored in the KMS to a file
+func fetchOSDEncryptionKey(cmd *cobra.Command, args []string) error {
+	required := []string{"pvc-name", "key-file-path"}
+	if err := flags.VerifyRequiredFlags(osdEncryptionKeyCmd, required); err != nil {
+		return err
+	}
+
+	rook.SetLogLevel()
+	rook.LogStartupInfo(osdEncryptionKeyCmd.Flags())
+
+	context := createContext()
+	kmsConfig := kms.NewConfigFromEnv(context, clusterInfo.Name)
+	key, err := kmsConfig.GetSecret(kms.GenerateOSDEncryptionSecretName(encryptionPVCName))
+	if err != nil {
+		rook.TerminateFatal(errors.Wrapf(err, "failed to get encryption key for pvc %q", encryptionPVCName))
+	}
+
+	err = ioutil.WriteFile(encryptionKeyFilePath, []byte(key), 0400)
+	if err != nil {
+		rook.TerminateFatal(errors.Wrapf(err, "failed to write encryption key to %q", encryptionKeyFilePath))
+	}
+
+	return nil
 }
 
 // Start the osd daemon if provisioned by ceph-volume
@@ -200,9 +246,22 @@ func prepareOSD(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	var hierarchy []cephv1.CrushLevelSpec
+	if osdCrushHierarchy != "" {
+		if err := json.Unmarshal([]byte(osdCrushHierarchy), &hierarchy); err != nil {
+			rook.TerminateFatal(errors.Wrapf(err, "failed to parse the crush hierarchy (%q)", osdCrushHierarchy))
+		}
+	}
+	var deviceClassRules []cephv1.DeviceClassRule
+	if osdDeviceClassRules != "" {
+		if err := json.Unmarshal([]byte(osdDeviceClassRules), &deviceClassRules); err != nil {
+			rook.TerminateFatal(errors.Wrapf(err, "failed to parse the device class rules (%q)", osdDeviceClassRules))
+		}
+	}
+
 	context := createContext()
 	commonOSDInit(provisionCmd)
-	crushLocation, err := getLocation(context.Clientset)
+	crushLocation, err := getLocation(context.Clientset, hierarchy)
 	if err != nil {
 		rook.TerminateFatal(err)
 	}
@@ -212,7 +271,7 @@ func prepareOSD(cmd *cobra.Command, args []string) error {
 	ownerRef := opcontroller.ClusterOwnerRef(clusterInfo.Name, ownerRefID)
 	kv := k8sutil.NewConfigMapKVStore(clusterInfo.Name, context.Clientset, ownerRef)
 	agent := osddaemon.NewAgent(context, dataDevices, cfg.metadataDevice, forceFormat,
-		cfg.storeConfig, &clusterInfo, cfg.nodeName, kv, cfg.pvcBacked)
+		cfg.storeConfig, &clusterInfo, cfg.nodeName, kv, cfg.pvcBacked, deviceClassRules)
 
 	err = osddaemon.Provision(context, agent, crushLocation)
 	if err != nil {
@@ -238,19 +297,19 @@ func commonOSDInit(cmd *cobra.Command) {
 }
 
 // use zone/region/hostname labels in the crushmap
-func getLocation(clientset kubernetes.Interface) (string, error) {
+func getLocation(clientset kubernetes.Interface, hierarchy []cephv1.CrushLevelSpec) (string, error) {
 	// get the value the operator instructed to use as the host name in the CRUSH map
 	hostNameLabel := os.Getenv("ROOK_CRUSHMAP_HOSTNAME")
 
-	loc, err := oposd.GetLocationWithNode(clientset, os.Getenv(k8sutil.NodeNameEnvVar), hostNameLabel)
+	loc, err := oposd.GetLocationWithNode(clientset, os.Getenv(k8sutil.NodeNameEnvVar), hostNameLabel, hierarchy)
 	if err != nil {
 		return "", err
 	}
 	return loc, nil
 }
 
-func updateLocationWithNodeLabels(location *[]string, nodeLabels map[string]string) {
-	oposd.UpdateLocationWithNodeLabels(location, nodeLabels)
+func updateLocationWithNodeLabels(location *[]string, nodeLabels map[string]string, hierarchy []cephv1.CrushLevelSpec) {
+	oposd.UpdateLocationWithNodeLabels(location, nodeLabels, hierarchy)
 }
 
 // Parse the devices, which are comma separated. A colon indicates a non-default number of osds per device
@@ -19,6 +19,7 @@ package ceph
 import (
 	"testing"
 
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -91,7 +92,7 @@ func TestDetectCrushLocation(t *testing.T) {
 	nodeLabels := map[string]string{}
 
 	// no change to the location if there are no labels
-	updateLocationWithNodeLabels(&location, nodeLabels)
+	updateLocationWithNodeLabels(&location, nodeLabels, nil)
 	assert.Equal(t, 1, len(location))
 	assert.Equal(t, "host=foo", location[0])
 
@@ -101,7 +102,7 @@ func TestDetectCrushLocation(t *testing.T) {
 		"invalid.topology.rook.io/rack": "r1",
 		"topology.rook.io/zone":         "z1",
 	}
-	updateLocationWithNodeLabels(&location, nodeLabels)
+	updateLocationWithNodeLabels(&location, nodeLabels, nil)
 	assert.Equal(t, 1, len(location))
 	assert.Equal(t, "host=foo", location[0])
 
@@ -120,10 +121,16 @@ func TestDetectCrushLocation(t *testing.T) {
 		"row=row1",
 		"zone=zone1",
 	}
-	updateLocationWithNodeLabels(&location, nodeLabels)
+	updateLocationWithNodeLabels(&location, nodeLabels, nil)
 
 	assert.Equal(t, 5, len(location))
 	for i, locString := range location {
 		assert.Equal(t, locString, expected[i])
 	}
+
+	// the custom types of the crush hierarchy are set by their labels
+	nodeLabels["example.com/hall"] = "hall.1"
+	hierarchy := []cephv1.CrushLevelSpec{{Type: "hall", Label: "example.com/hall"}, {Type: "rack", Label: "example.com/rack"}}
+	updateLocationWithNodeLabels(&location, nodeLabels, hierarchy)
+	assert.Equal(t, []string{"host=foo", "rack=rack1", "region=region1", "row=row1", "zone=zone1", "hall=hall-1"}, location)
 }
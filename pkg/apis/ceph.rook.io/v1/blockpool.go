@@ -31,3 +31,13 @@ func (p *PoolSpec) IsCompressionEnabled() bool {
 func (p *ReplicatedSpec) IsTargetRatioEnabled() bool {
 	return p.TargetSizeRatio != 0
 }
+
+// IsEnabled returns whether a quota is set in the spec
+func (q *QuotaSpec) IsEnabled() bool {
+	return q.MaxBytes != nil || q.MaxObjects != nil
+}
+
+// SnapshotSchedulesEnabled returns whether snapshot schedules are set in the mirroring spec
+func (m *MirroringSpec) SnapshotSchedulesEnabled() bool {
+	return len(m.SnapshotSchedules) > 0
+}
@@ -16,6 +16,11 @@ limitations under the License.
 
 package v1
 
+const (
+	// ObjectStoreUserRotateKeysAnnotation rotates the keys of an object store user whenever its value changes
+	ObjectStoreUserRotateKeysAnnotation = "ceph.rook.io/rotate-keys"
+)
+
 func (s *ObjectStoreSpec) IsMultisite() bool {
 	return s.Zone.Name != ""
 }
@@ -58,16 +58,24 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CephClientList{},
 		&CephCluster{},
 		&CephClusterList{},
+		&CephOSDRemoval{},
+		&CephOSDRemovalList{},
 		&CephBlockPool{},
 		&CephBlockPoolList{},
 		&CephFilesystem{},
 		&CephFilesystemList{},
+		&CephFilesystemSubVolumeGroup{},
+		&CephFilesystemSubVolumeGroupList{},
 		&CephNFS{},
 		&CephNFSList{},
 		&CephObjectStore{},
 		&CephObjectStoreList{},
 		&CephObjectStoreUser{},
 		&CephObjectStoreUserList{},
+		&CephBucketTopic{},
+		&CephBucketTopicList{},
+		&CephBucketNotification{},
+		&CephBucketNotificationList{},
 		&CephObjectRealm{},
 		&CephObjectRealmList{},
 		&CephObjectZoneGroup{},
@@ -76,6 +84,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CephObjectZoneList{},
 		&CephRBDMirror{},
 		&CephRBDMirrorList{},
+		&CephFilesystemMirror{},
+		&CephFilesystemMirrorList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -21,6 +21,7 @@ import (
 
 	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -80,6 +81,16 @@ type ClusterSpec struct {
 	// ContinueUpgradeAfterChecksEvenIfNotHealthy defines if an upgrade should continue even if PGs are not clean
 	ContinueUpgradeAfterChecksEvenIfNotHealthy bool `json:"continueUpgradeAfterChecksEvenIfNotHealthy,omitempty"`
 
+	// UpgradeStrategy defines how the daemons are restarted when they are updated
+	UpgradeStrategy UpgradeStrategySpec `json:"upgradeStrategy,omitempty"`
+
+	// A spec for the CRUSH hierarchy and the device classes of the OSDs
+	Crush CrushSpec `json:"crush,omitempty"`
+
+	// CephConfig are the ceph config options set in the centralized mon config database, keyed by the entity they
+	// apply to (global, mon, osd, osd.3, client.rgw...) then by the name of the option
+	CephConfig map[string]map[string]string `json:"cephConfig,omitempty"`
+
 	// A spec for configuring disruption management.
 	DisruptionManagement DisruptionManagementSpec `json:"disruptionManagement,omitempty"`
 
@@ -108,6 +119,9 @@ type ClusterSpec struct {
 	// Indicates user intent when deleting a cluster; blocks orchestration and should not be set if cluster
 	// deletion is not imminent.
 	CleanupPolicy CleanupPolicySpec `json:"cleanupPolicy,omitempty"`
+
+	// Security represents security settings
+	Security SecuritySpec `json:"security,omitempty"`
 }
 
 // VersionSpec represents the settings for the Ceph version that Rook is orchestrating.
@@ -140,6 +154,40 @@ type MonitoringSpec struct {
 	// The namespace where the prometheus rules and alerts should be created.
 	// If empty, the same namespace as the cluster will be used.
 	RulesNamespace string `json:"rulesNamespace,omitempty"`
+
+	// Labels to add to the ServiceMonitors and the PrometheusRule created for the ceph cluster,
+	// so they are picked up by the selectors of the Prometheus instance.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Alerts overrides the default thresholds of the prometheus alerts
+	Alerts AlertsSpec `json:"alerts,omitempty"`
+
+	// ServiceMonitors enables the optional ServiceMonitors of the ceph daemons
+	ServiceMonitors ServiceMonitorsSpec `json:"serviceMonitors,omitempty"`
+}
+
+// AlertsSpec represents the thresholds of the prometheus alerts. Unset thresholds keep their default value.
+type AlertsSpec struct {
+	// OSDNearFullRatio is the used ratio of an OSD above which the CephOSDNearFull alert fires
+	OSDNearFullRatio float64 `json:"osdNearFullRatio,omitempty"`
+
+	// OSDFullRatio is the used ratio of an OSD above which the CephOSDCriticallyFull alert fires
+	OSDFullRatio float64 `json:"osdFullRatio,omitempty"`
+
+	// MonQuorumLossDuration is how long the mon quorum must be at risk before the CephMonQuorumAtRisk alert fires
+	MonQuorumLossDuration string `json:"monQuorumLossDuration,omitempty"`
+
+	// PGDegradedDuration is how long PGs must be degraded before the CephDataRecoveryTakingTooLong alert fires
+	PGDegradedDuration string `json:"pgDegradedDuration,omitempty"`
+}
+
+// ServiceMonitorsSpec represents the optional ServiceMonitors scraping the metrics of the ceph daemons
+type ServiceMonitorsSpec struct {
+	// RGW enables a ServiceMonitor for the metrics of the rados gateways
+	RGW bool `json:"rgw,omitempty"`
+
+	// RBDMirror enables a ServiceMonitor for the metrics of the rbd mirror daemons
+	RBDMirror bool `json:"rbdMirror,omitempty"`
 }
 
 type ClusterStatus struct {
@@ -149,6 +197,50 @@ type ClusterStatus struct {
 	Conditions  []Condition     `json:"conditions,omitempty"`
 	CephStatus  *CephStatus     `json:"ceph,omitempty"`
 	CephVersion *ClusterVersion `json:"version,omitempty"`
+	// OSDUpgrade is the progress of the last update of the OSDs by failure domain
+	OSDUpgrade *OSDUpgradeStatus `json:"osdUpgrade,omitempty"`
+	// CephConfig is the state of the options of the cephConfig settings
+	CephConfig *CephConfigStatus `json:"cephConfig,omitempty"`
+	// Crashes is the summary of the crashes of the ceph daemons
+	Crashes *CrashStatus `json:"crashes,omitempty"`
+}
+
+// CephConfigStatus is the state of the options of the cephConfig settings in the mon config database
+type CephConfigStatus struct {
+	// Applied are the options set by the operator, keyed by entity then by option. The options dropped from the
+	// cephConfig settings are removed from the mon config database.
+	Applied map[string]map[string]string `json:"applied,omitempty"`
+	// Rejected are the options that could not be set
+	Rejected []CephConfigRejectedOption `json:"rejected,omitempty"`
+	// LastUpdated is the time the applied or rejected options last changed
+	LastUpdated string `json:"lastUpdated,omitempty"`
+}
+
+// CephConfigRejectedOption is an option of the cephConfig settings that could not be set
+type CephConfigRejectedOption struct {
+	Who    string `json:"who"`
+	Option string `json:"option"`
+	Value  string `json:"value"`
+	// Reason is the reason the option was rejected
+	Reason string `json:"reason"`
+}
+
+// OSDUpgradeStatus is the progress of an update of the OSDs by failure domain
+type OSDUpgradeStatus struct {
+	// FailureDomain is the CRUSH level of the failure domains updated one at a time
+	FailureDomain string `json:"failureDomain,omitempty"`
+	// CurrentFailureDomain is the name of the failure domain whose OSDs are being restarted
+	CurrentFailureDomain string `json:"currentFailureDomain,omitempty"`
+	// UpdatedFailureDomains is the number of failure domains whose OSDs are all updated
+	UpdatedFailureDomains int `json:"updatedFailureDomains"`
+	// TotalFailureDomains is the number of failure domains with OSDs to update
+	TotalFailureDomains int `json:"totalFailureDomains"`
+	// UpdatedOSDs is the number of OSDs updated
+	UpdatedOSDs int `json:"updatedOSDs"`
+	// TotalOSDs is the number of OSDs to update
+	TotalOSDs int `json:"totalOSDs"`
+	// LastUpdated is the time of the last progress of the update
+	LastUpdated string `json:"lastUpdated,omitempty"`
 }
 
 type CephStatus struct {
@@ -157,6 +249,20 @@ type CephStatus struct {
 	LastChecked    string                       `json:"lastChecked,omitempty"`
 	LastChanged    string                       `json:"lastChanged,omitempty"`
 	PreviousHealth string                       `json:"previousHealth,omitempty"`
+	// History is the list of the most recent transitions of the health, the oldest first
+	History []CephHealthTransition `json:"history,omitempty"`
+}
+
+// CephHealthTransition is a change of the health or of the health checks of the ceph cluster
+type CephHealthTransition struct {
+	// Time is the time the change was detected
+	Time           string `json:"time"`
+	Health         string `json:"health"`
+	PreviousHealth string `json:"previousHealth,omitempty"`
+	// RaisedChecks are the codes of the health checks raised since the previous check
+	RaisedChecks []string `json:"raisedChecks,omitempty"`
+	// ClearedChecks are the codes of the health checks cleared since the previous check
+	ClearedChecks []string `json:"clearedChecks,omitempty"`
 }
 
 type ClusterVersion struct {
@@ -190,6 +296,8 @@ const (
 	ConditionFailure     ConditionType = "Failure"
 	ConditionUpgrading   ConditionType = "Upgrading"
 	ConditionDeleting    ConditionType = "Deleting"
+	// ConditionMonFailoverPending is set when mons should be failed over but the mon health check is in report only mode
+	ConditionMonFailoverPending ConditionType = "MonFailoverPending"
 	// DefaultFailureDomain for PoolSpec
 	DefaultFailureDomain = "host"
 )
@@ -205,10 +313,98 @@ const (
 	ClusterStateError      ClusterState = "Error"
 )
 
+// UpgradeStrategySpec represents how the daemons are restarted when they are updated
+type UpgradeStrategySpec struct {
+	// OSD is the strategy to update the OSDs
+	OSD OSDUpgradeStrategySpec `json:"osd,omitempty"`
+}
+
+// OSDUpgradeStrategySpec represents how the OSDs are restarted when they are updated
+type OSDUpgradeStrategySpec struct {
+	// FailureDomain is the CRUSH level (host, rack, zone...) of the failure domains updated one at a time. The OSDs of a
+	// failure domain are checked with "ceph osd ok-to-stop" and restarted together, then the PGs must be clean before
+	// the next failure domain is updated. If empty, the OSDs are updated one at a time.
+	FailureDomain string `json:"failureDomain,omitempty"`
+	// MaxInParallel is the maximum number of OSDs of a failure domain restarted together. If 0, all the OSDs of the
+	// failure domain are restarted together.
+	MaxInParallel int `json:"maxInParallel,omitempty"`
+}
+
+// CrushSpec represents the CRUSH hierarchy built from the node labels and the rules assigning the device classes
+type CrushSpec struct {
+	// Hierarchy is the list of CRUSH bucket types above the hosts, from the lowest to the highest, and the node
+	// labels setting the bucket of each type. The types missing from the CRUSH map are added to it.
+	Hierarchy []CrushLevelSpec `json:"hierarchy,omitempty"`
+	// DeviceClassRules assigns a device class to the devices without one, the class of the first matching rule is used
+	DeviceClassRules []DeviceClassRule `json:"deviceClassRules,omitempty"`
+}
+
+// CrushLevelSpec represents a CRUSH bucket type and the node label naming the bucket of a node
+type CrushLevelSpec struct {
+	// Type is the CRUSH bucket type (rack, row, datacenter, or a custom type)
+	Type string `json:"type"`
+	// Label is the node label whose value is the name of the bucket
+	Label string `json:"label"`
+}
+
+// DeviceClassRule represents the properties of the devices assigned to a device class. The unset properties match
+// all the devices.
+type DeviceClassRule struct {
+	// DeviceClass is the device class assigned to the matching devices
+	DeviceClass string `json:"deviceClass"`
+	// Rotational matches the rotational (true) or solid state (false) devices
+	Rotational *bool `json:"rotational,omitempty"`
+	// NVMe matches the NVMe (true) or the other (false) devices
+	NVMe *bool `json:"nvme,omitempty"`
+	// MinSize is the minimum size of the matching devices
+	MinSize *resource.Quantity `json:"minSize,omitempty"`
+	// MaxSize is the maximum size of the matching devices
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Model is a regular expression matching the model of the devices
+	Model string `json:"model,omitempty"`
+}
+
 type MonSpec struct {
 	Count                int                       `json:"count,omitempty"`
 	AllowMultiplePerNode bool                      `json:"allowMultiplePerNode,omitempty"`
 	VolumeClaimTemplate  *v1.PersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
+	// HealthCheck represents the settings of the mon health checks and failover
+	HealthCheck MonHealthCheckSpec `json:"healthCheck,omitempty"`
+	// StretchCluster spreads the mons across two data zones and an arbiter zone, and enables the stretch mode of ceph
+	StretchCluster *StretchClusterSpec `json:"stretchCluster,omitempty"`
+}
+
+// StretchClusterSpec represents the zones of a stretch cluster
+type StretchClusterSpec struct {
+	// FailureDomainLabel is the node label whose value is the zone of a node. Defaults to topology.kubernetes.io/zone.
+	// The CRUSH bucket type of the zones is the type of the crush hierarchy level with this label, or else the name of
+	// the label without its prefix, such as "zone". The type must exist in the CRUSH map.
+	FailureDomainLabel string `json:"failureDomainLabel,omitempty"`
+	// CrushRoot is the CRUSH root holding the zones, "default" if empty
+	CrushRoot string `json:"crushRoot,omitempty"`
+	// Zones are the two data zones and the arbiter zone of the cluster
+	Zones []StretchClusterZoneSpec `json:"zones,omitempty"`
+}
+
+// StretchClusterZoneSpec represents a zone of a stretch cluster
+type StretchClusterZoneSpec struct {
+	// Name is the value of the failure domain label of the nodes of the zone
+	Name string `json:"name,omitempty"`
+	// Arbiter is true for the zone running the tie-breaker mon. No OSD is expected in the arbiter zone.
+	Arbiter bool `json:"arbiter,omitempty"`
+}
+
+// MonHealthCheckSpec represents the settings of the mon health checks and failover
+type MonHealthCheckSpec struct {
+	// Interval is the interval to check if the mons are in quorum. Defaults to the interval set on the operator.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Timeout is the duration a mon can be out of quorum before it is failed over. Defaults to the timeout set on the operator.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// DisableFailover disables the failover of the mons out of quorum
+	DisableFailover bool `json:"disableFailover,omitempty"`
+	// ReportOnly reports the mons that would be failed over with events and a condition on the cluster CR,
+	// instead of failing them over
+	ReportOnly bool `json:"reportOnly,omitempty"`
 }
 
 // MgrSpec represents options to configure a ceph mgr
@@ -230,17 +426,139 @@ type ExternalSpec struct {
 // CrashCollectorSpec represents options to configure the crash controller
 type CrashCollectorSpec struct {
 	Disable bool `json:"disable"`
+	// DaysToArchive is the age in days of the crashes archived by the operator. The archived crashes are kept by the mgr
+	// crash module but do not raise the RECENT_CRASH health warning anymore. If 0, the crashes are not archived.
+	DaysToArchive int `json:"daysToArchive,omitempty"`
+	// Export sets where the metadata and the backtraces of the new crashes are exported
+	Export CrashExportSpec `json:"export,omitempty"`
+}
+
+// CrashExportSpec represents the destinations of the crash reports
+type CrashExportSpec struct {
+	// Events records a Kubernetes event on the CephCluster CR for each new crash
+	Events bool `json:"events,omitempty"`
+	// Bundle stores the reports of the most recent crashes in the "rook-ceph-crash-reports" ConfigMap or Secret.
+	// Valid values are "configMap" and "secret". If empty, the reports are not bundled.
+	Bundle string `json:"bundle,omitempty"`
+	// S3 uploads the report of each new crash to a bucket
+	S3 *CrashS3ExportSpec `json:"s3,omitempty"`
+}
+
+// CrashS3ExportSpec represents the bucket the crash reports are uploaded to
+type CrashS3ExportSpec struct {
+	// Endpoint is the URL of the S3 service, such as the service of a CephObjectStore of the cluster
+	Endpoint string `json:"endpoint"`
+	// Bucket is the name of the bucket
+	Bucket string `json:"bucket"`
+	// Region is the region of the bucket, "us-east-1" if empty
+	Region string `json:"region,omitempty"`
+	// CredentialsSecret is the name of the secret in the namespace of the cluster with the AWS_ACCESS_KEY_ID and
+	// AWS_SECRET_ACCESS_KEY keys, such as the secret of an ObjectBucketClaim
+	CredentialsSecret string `json:"credentialsSecret"`
+	// Prefix is prepended to the keys of the reports, which are named after the crash id
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// CrashStatus is the summary of the crashes of the ceph daemons reported to the mgr crash module
+type CrashStatus struct {
+	// NewCrashes is the number of crashes not archived yet
+	NewCrashes int `json:"newCrashes"`
+	// ArchivedCrashes is the number of archived crashes
+	ArchivedCrashes int `json:"archivedCrashes"`
+	// LastCrash is the time of the most recent crash
+	LastCrash string `json:"lastCrash,omitempty"`
+	// LastExported is the time of the most recent crash exported
+	LastExported string `json:"lastExported,omitempty"`
+	// LastChecked is the time the crashes were last checked
+	LastChecked string `json:"lastChecked,omitempty"`
 }
 
 // +genclient
 // +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+type CephOSDRemoval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              OSDRemovalSpec    `json:"spec"`
+	Status            *OSDRemovalStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CephOSDRemovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephOSDRemoval `json:"items"`
+}
+
+// OSDRemovalSpec represents the OSDs to remove from the cluster of the namespace
+type OSDRemovalSpec struct {
+	// OSDIDs are the IDs of the OSDs to remove
+	OSDIDs []int `json:"osdIDs,omitempty"`
+
+	// Devices are the devices of the OSDs to remove
+	Devices []OSDRemovalDevice `json:"devices,omitempty"`
+
+	// PreservePVC keeps the PVC of the OSDs running on PVCs. The PVCs are deleted by default so the storage class
+	// device sets create new PVCs to replace the OSDs.
+	PreservePVC bool `json:"preservePVC,omitempty"`
+
+	// WipeDisks wipes the disks of the OSDs not running on PVCs once they are removed, so the disks are provisioned
+	// again as new OSDs
+	WipeDisks bool `json:"wipeDisks,omitempty"`
+}
+
+// OSDRemovalDevice represents a device of an OSD to remove
+type OSDRemovalDevice struct {
+	// Node is the name of the node of the device, as reported by the OSD metadata
+	Node string `json:"node"`
+	// Device is the name of the device, such as "sdb" or "/dev/sdb"
+	Device string `json:"device"`
+}
+
+// OSDRemovalStatus represents the progress of the removal of the OSDs
+type OSDRemovalStatus struct {
+	Phase      string                `json:"phase,omitempty"`
+	Conditions []Condition           `json:"conditions,omitempty"`
+	OSDs       []OSDRemovalOSDStatus `json:"osds,omitempty"`
+}
+
+// OSDRemovalOSDStatus represents the progress of the removal of an OSD
+type OSDRemovalOSDStatus struct {
+	ID      int             `json:"id"`
+	State   OSDRemovalState `json:"state"`
+	Node    string          `json:"node,omitempty"`
+	Device  string          `json:"device,omitempty"`
+	PVC     string          `json:"pvc,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
+
+// OSDRemovalState is the step of the removal of an OSD
+type OSDRemovalState string
+
+const (
+	// OSDRemovalPending means the OSD is not out yet
+	OSDRemovalPending OSDRemovalState = "Pending"
+	// OSDRemovalDraining means the OSD is out and its data is migrating to the other OSDs
+	OSDRemovalDraining OSDRemovalState = "Draining"
+	// OSDRemovalPurged means the OSD was removed from the CRUSH map, the auth keys and the OSD map
+	OSDRemovalPurged OSDRemovalState = "Purged"
+	// OSDRemovalWiping means the disk of the OSD is being wiped
+	OSDRemovalWiping OSDRemovalState = "Wiping"
+	// OSDRemovalRemoved means the OSD is fully removed
+	OSDRemovalRemoved OSDRemovalState = "Removed"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 type CephBlockPool struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              PoolSpec `json:"spec"`
-	Status            *Status  `json:"status"`
+	Spec              PoolSpec             `json:"spec"`
+	Status            *CephBlockPoolStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -272,6 +590,152 @@ type PoolSpec struct {
 
 	// Parameters is a list of properties to enable on a given pool
 	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// The quota settings
+	Quotas QuotaSpec `json:"quotas,omitempty"`
+
+	// The mirroring settings, only applied to block pools
+	Mirroring MirroringSpec `json:"mirroring,omitempty"`
+}
+
+// MirroringSpec represents the setting for a mirrored pool
+type MirroringSpec struct {
+	// Enabled whether this pool is mirrored or not
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Mode is the mirroring mode: either "pool" to mirror all the images of the pool, or "image" to mirror the
+	// images with mirroring explicitly enabled
+	Mode string `json:"mode,omitempty"`
+
+	// SnapshotSchedules is the scheduling of the mirror snapshots of the images of the pool
+	SnapshotSchedules []SnapshotScheduleSpec `json:"snapshotSchedules,omitempty"`
+}
+
+// QuotaSpec represents the spec for quotas in a pool
+type QuotaSpec struct {
+	// MaxBytes represents the quota in bytes, the quota is removed when not set or 0
+	MaxBytes *uint64 `json:"maxBytes,omitempty"`
+
+	// MaxObjects represents the quota in objects, the quota is removed when not set or 0
+	MaxObjects *uint64 `json:"maxObjects,omitempty"`
+}
+
+// CephBlockPoolStatus represents the status of a CephBlockPool
+type CephBlockPoolStatus struct {
+	Phase      string      `json:"phase,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Pool is the state of the pool as applied by Ceph
+	Pool *PoolStatus `json:"pool,omitempty"`
+	// Quota is the usage of the pool against its quotas
+	Quota *PoolQuotaStatus `json:"quota,omitempty"`
+	// LastChecked is the last time the state of the pool was refreshed
+	LastChecked string `json:"lastChecked,omitempty"`
+	// MirroringInfo is the mirroring configuration of the pool
+	MirroringInfo *MirroringInfo `json:"mirroringInfo,omitempty"`
+	// MirroringStatus is the mirroring health of the pool as reported by the rbd-mirror daemons
+	MirroringStatus *MirroringStatusSummary `json:"mirroringStatus,omitempty"`
+	// CrushRuleChange is the progress of the data movement after the last change of the CRUSH rule of the pool
+	CrushRuleChange *PoolCrushRuleChangeStatus `json:"crushRuleChange,omitempty"`
+	// ErasureCodeDrift lists the erasure code settings of the spec that differ from the profile of the pool
+	ErasureCodeDrift []string `json:"erasureCodeDrift,omitempty"`
+	// ErasureCodeMigration is the progress of the migration of the data of the pool to a new erasure code profile
+	ErasureCodeMigration *PoolErasureCodeMigrationStatus `json:"erasureCodeMigration,omitempty"`
+}
+
+// PoolErasureCodeMigrationStatus is the progress of the migration of the data of an erasure coded pool to a new pool
+// created with the erasure code settings of the spec
+type PoolErasureCodeMigrationStatus struct {
+	// Phase is the step of the migration: MigratingImages, SwappingPools or Completed
+	Phase string `json:"phase"`
+	// TargetPool is the pool receiving the data, it is renamed after the pool once all the data is migrated
+	TargetPool string `json:"targetPool"`
+	// TargetProfile is the erasure code profile of the target pool
+	TargetProfile string `json:"targetProfile"`
+	// TargetSpec is the spec of the pool when the migration started, the target pool is created with its settings
+	TargetSpec *PoolSpec `json:"targetSpec,omitempty"`
+	// PreviousProfile is the erasure code profile of the pool before the migration
+	PreviousProfile string `json:"previousProfile"`
+	// MigratedImages is the number of rbd images whose data was migrated to the target pool
+	MigratedImages int `json:"migratedImages"`
+	// PendingImages are the rbd images that could not be migrated yet, such as the images in use
+	PendingImages []string `json:"pendingImages,omitempty"`
+	// ExecutingImages are the rbd images whose data is being copied to the target pool in the background
+	ExecutingImages []string `json:"executingImages,omitempty"`
+	// Message describes what the migration is waiting for
+	Message string `json:"message,omitempty"`
+	// StartTime is the time the migration started
+	StartTime string `json:"startTime,omitempty"`
+	// CompletionTime is the time the migration completed
+	CompletionTime string `json:"completionTime,omitempty"`
+}
+
+// PoolCrushRuleChangeStatus is the progress of the data movement after the CRUSH rule of a pool changed
+type PoolCrushRuleChangeStatus struct {
+	// PreviousRule is the name of the CRUSH rule used by the pool before the change
+	PreviousRule string `json:"previousRule"`
+	// Rule is the name of the CRUSH rule used by the pool
+	Rule string `json:"rule"`
+	// StartTime is the time the pool was switched to the rule
+	StartTime string `json:"startTime,omitempty"`
+	// MisplacedObjects is the number of object copies of the pool not yet moved to their new OSDs
+	MisplacedObjects uint64 `json:"misplacedObjects"`
+	// TotalObjects is the number of object copies of the pool
+	TotalObjects uint64 `json:"totalObjects"`
+	// Completed is true once all the object copies of the pool are moved
+	Completed bool `json:"completed"`
+	// CompletionTime is the time the data movement was found complete
+	CompletionTime string `json:"completionTime,omitempty"`
+}
+
+// MirroringStatusSummary represents the mirroring health of a pool
+type MirroringStatusSummary struct {
+	// Health is the overall mirroring health of the pool: OK, WARNING, ERROR or UNKNOWN
+	Health string `json:"health,omitempty"`
+	// DaemonHealth is the health of the rbd-mirror daemons
+	DaemonHealth string `json:"daemonHealth,omitempty"`
+	// ImageHealth is the health of the mirrored images
+	ImageHealth string `json:"imageHealth,omitempty"`
+	// States is the number of mirrored images in each state, e.g. replaying or stopped
+	States map[string]int `json:"states,omitempty"`
+}
+
+// PoolStatus represents the state of a pool as applied by Ceph
+type PoolStatus struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+	// Replicas is the number of replicas of a replicated pool, or the number of data and coding chunks of an erasure coded pool
+	Replicas uint `json:"replicas"`
+	// MinReplicas is the number of replicas required to serve I/O
+	MinReplicas uint `json:"minReplicas"`
+	// ErasureCodeProfile is the name of the erasure code profile of an erasure coded pool
+	ErasureCodeProfile string `json:"erasureCodeProfile,omitempty"`
+	// DataChunks is the number of data chunks of an erasure coded pool
+	DataChunks uint `json:"dataChunks,omitempty"`
+	// CodingChunks is the number of coding chunks of an erasure coded pool
+	CodingChunks uint `json:"codingChunks,omitempty"`
+	// PGs is the number of placement groups of the pool
+	PGs int `json:"pgs"`
+	// UsedBytes is the amount of data stored in the pool, without the replicas. Only reported from Nautilus.
+	UsedBytes uint64 `json:"usedBytes"`
+	// AvailableBytes is the amount of data that can still be stored in the pool
+	AvailableBytes uint64 `json:"availableBytes"`
+	// ErasureCodeDrift is the erasure code settings of the spec of a filesystem or object store pool that differ
+	// from the profile of the pool. They are not applied since the profile of a pool can't change.
+	ErasureCodeDrift []string `json:"erasureCodeDrift,omitempty"`
+}
+
+// PoolQuotaStatus represents the usage of a pool against its quotas
+type PoolQuotaStatus struct {
+	// MaxBytes is the quota in bytes currently set on the pool, 0 if not set
+	MaxBytes uint64 `json:"maxBytes"`
+	// MaxObjects is the quota in objects currently set on the pool, 0 if not set
+	MaxObjects uint64 `json:"maxObjects"`
+	// UsedBytes is the amount of data stored in the pool, without the replicas. Only reported from Nautilus.
+	UsedBytes uint64 `json:"usedBytes"`
+	// UsedObjects is the number of objects stored in the pool
+	UsedObjects uint64 `json:"usedObjects"`
+	// ProvisionedBytes is the size provisioned by the rbd images of the pool
+	ProvisionedBytes uint64 `json:"provisionedBytes,omitempty"`
 }
 
 type Status struct {
@@ -300,6 +764,11 @@ type ErasureCodedSpec struct {
 
 	// The algorithm for erasure coding
 	Algorithm string `json:"algorithm"`
+
+	// AllowMigration opts in the migration of the data of an existing block pool to a new pool when the erasure code
+	// settings of the spec differ from the profile of the pool. Only honored by CephBlockPools, the settings that differ
+	// from the profile of the filesystem and object store pools are only reported in their status.
+	AllowMigration bool `json:"allowMigration,omitempty"`
 }
 
 // +genclient
@@ -309,8 +778,8 @@ type ErasureCodedSpec struct {
 type CephFilesystem struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              FilesystemSpec `json:"spec"`
-	Status            *Status        `json:"status"`
+	Spec              FilesystemSpec        `json:"spec"`
+	Status            *CephFilesystemStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -334,6 +803,93 @@ type FilesystemSpec struct {
 
 	// The mds pod info
 	MetadataServer MetadataServerSpec `json:"metadataServer"`
+
+	// The mirroring settings
+	Mirroring *FSMirroringSpec `json:"mirroring,omitempty"`
+}
+
+// FSMirroringSpec represents the setting for a mirrored filesystem
+type FSMirroringSpec struct {
+	// Enabled whether this filesystem is mirrored or not
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Peers represents the peers of the filesystem
+	Peers *MirroringPeerSpec `json:"peers,omitempty"`
+
+	// Directories are the paths of the directories to mirror, relative to the root of the filesystem
+	Directories []string `json:"directories,omitempty"`
+
+	// SnapshotSchedules is the scheduling of the snapshots of the mirrored directories
+	SnapshotSchedules []SnapshotScheduleSpec `json:"snapshotSchedules,omitempty"`
+
+	// SnapshotRetention is the retention policy of the scheduled snapshots
+	SnapshotRetention []SnapshotScheduleRetentionSpec `json:"snapshotRetention,omitempty"`
+}
+
+// MirroringPeerSpec represents the specification of the mirror peers
+type MirroringPeerSpec struct {
+	// SecretNames are the names of the Kubernetes Secrets with the bootstrap peer tokens of the peers. The Secrets
+	// of the pool peers also hold the name of the local pool to add the peer to.
+	SecretNames []string `json:"secretNames,omitempty"`
+}
+
+// SnapshotScheduleSpec represents the snapshot scheduling settings of a mirrored directory or image
+type SnapshotScheduleSpec struct {
+	// Path is the path to snapshot, the root of the filesystem if empty. Only valid for filesystems.
+	Path string `json:"path,omitempty"`
+
+	// Interval represents the periodicity of the snapshot, e.g. 1h or 1d
+	Interval string `json:"interval,omitempty"`
+
+	// StartTime indicates when to start the snapshot, e.g. 2020-11-18T15:00:00
+	StartTime string `json:"startTime,omitempty"`
+}
+
+// SnapshotScheduleRetentionSpec represents the retention policy of the snapshots of a directory
+type SnapshotScheduleRetentionSpec struct {
+	// Path is the path of the snapshots, the root of the filesystem if empty
+	Path string `json:"path,omitempty"`
+
+	// Duration represents the retention duration of the snapshots, e.g. 7d or 24h
+	Duration string `json:"duration,omitempty"`
+}
+
+// MirroringInfo represents the mirroring status of a pool or a filesystem
+type MirroringInfo struct {
+	// BootstrapPeerSecretName is the name of the Secret with the bootstrap peer token of the local site, to add
+	// it as a peer of the remote sites
+	BootstrapPeerSecretName string `json:"bootstrapPeerSecretName,omitempty"`
+	// Mode is the mirroring mode of a pool
+	Mode string `json:"mode,omitempty"`
+	// SiteName is the name of the local site
+	SiteName string `json:"siteName,omitempty"`
+	// Peers are the remote sites of a pool
+	Peers []MirroringPeerInfo `json:"peers,omitempty"`
+}
+
+// MirroringPeerInfo represents a remote site of a mirrored pool
+type MirroringPeerInfo struct {
+	UUID       string `json:"uuid,omitempty"`
+	Direction  string `json:"direction,omitempty"`
+	SiteName   string `json:"siteName,omitempty"`
+	ClientName string `json:"clientName,omitempty"`
+}
+
+// CephFilesystemStatus represents the status of a CephFilesystem
+type CephFilesystemStatus struct {
+	Phase      string      `json:"phase,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Pools is the state of the filesystem pools as applied by Ceph, the metadata pool first
+	Pools []PoolStatus `json:"pools,omitempty"`
+	// LastChecked is the last time the state of the pools was refreshed
+	LastChecked string `json:"lastChecked,omitempty"`
+	// PoolQuotas is the usage of the filesystem pools against their quotas, indexed by pool name
+	PoolQuotas map[string]PoolQuotaStatus `json:"poolQuotas,omitempty"`
+	// Mirroring is the mirroring status of the filesystem
+	Mirroring *MirroringInfo `json:"mirroring,omitempty"`
+	// AppliedMirroring is the mirrored directories, snapshot schedules and retention last applied to Ceph, which are
+	// removed from Ceph once removed from the spec
+	AppliedMirroring *FSMirroringSpec `json:"appliedMirroring,omitempty"`
 }
 
 type MetadataServerSpec struct {
@@ -361,11 +917,74 @@ type MetadataServerSpec struct {
 // +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+type CephFilesystemSubVolumeGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              CephFilesystemSubVolumeGroupSpec    `json:"spec"`
+	Status            *CephFilesystemSubVolumeGroupStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CephFilesystemSubVolumeGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephFilesystemSubVolumeGroup `json:"items"`
+}
+
+// CephFilesystemSubVolumeGroupSpec represents the spec of a filesystem subvolume group
+type CephFilesystemSubVolumeGroupSpec struct {
+	// FilesystemName is the name of the filesystem of the group, the name of a CephFilesystem in the same namespace
+	FilesystemName string `json:"filesystemName"`
+
+	// Name of the group in Ceph, the name of the CR if not set
+	Name string `json:"name,omitempty"`
+
+	// DataPoolName is the data pool of the files of the group, the default data pool of the filesystem if not set
+	DataPoolName string `json:"dataPoolName,omitempty"`
+
+	// Quota is the maximum size of the group, e.g. 10Gi. No quota is set if empty.
+	Quota *resource.Quantity `json:"quota,omitempty"`
+
+	// Pinning pins the group to the MDS ranks of the filesystem
+	Pinning SubVolumeGroupPinning `json:"pinning,omitempty"`
+}
+
+// SubVolumeGroupPinning represents the pinning of a subvolume group to MDS ranks. Only one policy can be set.
+type SubVolumeGroupPinning struct {
+	// Export pins the group to the given MDS rank, -1 removes the pin
+	Export *int `json:"export,omitempty"`
+
+	// Distributed spreads the subvolumes of the group over the MDS ranks when set to 1
+	Distributed *int `json:"distributed,omitempty"`
+
+	// Random pins each subdirectory of the group to a random rank with the given probability, between 0.0 and 1.0
+	Random *float64 `json:"random,omitempty"`
+}
+
+// CephFilesystemSubVolumeGroupStatus represents the status of a filesystem subvolume group
+type CephFilesystemSubVolumeGroupStatus struct {
+	Phase      string      `json:"phase,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Path is the path of the group in the filesystem
+	Path string `json:"path,omitempty"`
+	// ClusterID is the cluster ID of the storage classes provisioning CSI volumes in the group
+	ClusterID string `json:"clusterID,omitempty"`
+	// Quota is the quota applied to the group
+	Quota string `json:"quota,omitempty"`
+	// Pinning is the pinning policy applied to the group and its setting, such as "export=1"
+	Pinning string `json:"pinning,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 type CephObjectStore struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              ObjectStoreSpec `json:"spec"`
-	Status            *Status         `json:"status"`
+	Spec              ObjectStoreSpec    `json:"spec"`
+	Status            *ObjectStoreStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -394,6 +1013,26 @@ type ObjectStoreSpec struct {
 	Zone ZoneSpec `json:"zone"`
 }
 
+// ObjectStoreStatus represents the status of a CephObjectStore
+type ObjectStoreStatus struct {
+	Phase      string      `json:"phase,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Endpoint is the in-cluster endpoint of the object store
+	Endpoint string `json:"endpoint,omitempty"`
+	// Realm is the name of the realm of the object store
+	Realm string `json:"realm,omitempty"`
+	// ZoneGroup is the name of the zone group of the object store
+	ZoneGroup string `json:"zoneGroup,omitempty"`
+	// Zone is the name of the zone of the object store
+	Zone string `json:"zone,omitempty"`
+	// Pools is the state of the object store pools as applied by Ceph
+	Pools []PoolStatus `json:"pools,omitempty"`
+	// LastChecked is the last time the state of the pools was refreshed
+	LastChecked string `json:"lastChecked,omitempty"`
+	// PoolQuotas is the usage of the object store pools against their quotas, indexed by pool name
+	PoolQuotas map[string]PoolQuotaStatus `json:"poolQuotas,omitempty"`
+}
+
 type GatewaySpec struct {
 	// The port the rgw service will be listening on (http)
 	Port int32 `json:"port"`
@@ -407,9 +1046,15 @@ type GatewaySpec struct {
 	// Whether the rgw pods should be started as a daemonset on all nodes
 	AllNodes bool `json:"allNodes"`
 
-	// The name of the secret that stores the ssl certificate for secure rgw connections
+	// The name of the secret that stores the ssl certificate for secure rgw connections. The secret either holds the
+	// certificate, its key and the intermediate certificates in a single PEM under the "cert" key, or is a
+	// "kubernetes.io/tls" secret with the "tls.crt" and "tls.key" keys and optionally the "ca.crt" of the issuer.
 	SSLCertificateRef string `json:"sslCertificateRef"`
 
+	// GenerateSSLCertificate generates a certificate for the DNS names of the rgw service, signed by a certificate
+	// authority of the operator, when no sslCertificateRef is set. The certificate is renewed before it expires.
+	GenerateSSLCertificate bool `json:"generateSSLCertificate,omitempty"`
+
 	// The affinity to place the rgw pods (default is to place on any available node)
 	Placement rookv1.Placement `json:"placement"`
 
@@ -435,8 +1080,8 @@ type ZoneSpec struct {
 type CephObjectStoreUser struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              ObjectStoreUserSpec `json:"spec"`
-	Status            *Status             `json:"status"`
+	Spec              ObjectStoreUserSpec    `json:"spec"`
+	Status            *ObjectStoreUserStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -453,6 +1098,205 @@ type ObjectStoreUserSpec struct {
 	Store string `json:"store,omitempty"`
 	//The display name for the ceph users
 	DisplayName string `json:"displayName,omitempty"`
+	// Quotas limit the buckets and the storage of the user
+	Quotas *ObjectUserQuotaSpec `json:"quotas,omitempty"`
+	// Capabilities grant the user access to the admin API of the object store
+	Capabilities *ObjectUserCapSpec `json:"capabilities,omitempty"`
+	// SubUsers are the Swift users of the user, each with its own key
+	SubUsers []ObjectUserSubUserSpec `json:"subUsers,omitempty"`
+	// KeyRotationGracePeriod is how long the previous keys of the user remain valid after a key rotation. Defaults to 24h.
+	KeyRotationGracePeriod *metav1.Duration `json:"keyRotationGracePeriod,omitempty"`
+}
+
+// ObjectUserQuotaSpec represents the quotas of an object store user. The quotas that are not set are unlimited.
+type ObjectUserQuotaSpec struct {
+	// MaxBuckets is the maximum number of buckets the user can own, 1000 by default
+	MaxBuckets *int `json:"maxBuckets,omitempty"`
+	// MaxSize is the maximum size of the objects of all the buckets of the user, e.g. 10Gi
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// MaxObjects is the maximum number of objects in all the buckets of the user
+	MaxObjects *int64 `json:"maxObjects,omitempty"`
+}
+
+// ObjectUserCapSpec represents the capabilities of an object store user on the admin API. Each capability is one of
+// "read", "write" or "read, write" ("*"). The user has no access to a resource whose capability is not set.
+type ObjectUserCapSpec struct {
+	// Users is the access to the users and their keys
+	Users string `json:"users,omitempty"`
+	// Buckets is the access to the buckets
+	Buckets string `json:"buckets,omitempty"`
+	// Usage is the access to the usage statistics
+	Usage string `json:"usage,omitempty"`
+	// Metadata is the access to the metadata of the users and the buckets
+	Metadata string `json:"metadata,omitempty"`
+}
+
+// ObjectUserSubUserSpec represents a Swift subuser of an object store user
+type ObjectUserSubUserSpec struct {
+	// Name of the subuser, the Swift user is "<user>:<name>"
+	Name string `json:"name"`
+	// Access of the subuser to the buckets of the user, one of "read", "write", "readwrite" or "full". Defaults to "full".
+	Access string `json:"access,omitempty"`
+}
+
+// ObjectStoreUserStatus represents the status of an object store user
+type ObjectStoreUserStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// KeyRotation is the value of the key rotation annotation that last rotated the keys of the user
+	KeyRotation string `json:"keyRotation,omitempty"`
+	// RetiredKeys are the previous keys of the user, removed from the object store once their grace period expires
+	RetiredKeys []RetiredObjectUserKey `json:"retiredKeys,omitempty"`
+	// MaxBuckets is the maximum number of buckets applied to the user from the spec, reset to the default of the
+	// object store once removed from the spec
+	MaxBuckets *int `json:"maxBuckets,omitempty"`
+}
+
+// RetiredObjectUserKey represents a previous key of an object store user
+type RetiredObjectUserKey struct {
+	// AccessKey is the access key of the retired key
+	AccessKey string `json:"accessKey"`
+	// ExpirationTime is the time after which the key is removed
+	ExpirationTime string `json:"expirationTime"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CephBucketTopic struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              BucketTopicSpec    `json:"spec"`
+	Status            *BucketTopicStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CephBucketTopicList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephBucketTopic `json:"items"`
+}
+
+// BucketTopicSpec represents the spec of a bucket notification topic
+type BucketTopicSpec struct {
+	// ObjectStoreName is the name of the CephObjectStore of the topic
+	ObjectStoreName string `json:"objectStoreName"`
+	// ObjectStoreNamespace is the namespace of the CephObjectStore, the namespace of the topic if not set
+	ObjectStoreNamespace string `json:"objectStoreNamespace,omitempty"`
+	// OpaqueData is added to each notification sent to the endpoint
+	OpaqueData string `json:"opaqueData,omitempty"`
+	// Persistent notifications are sent asynchronously and retried until the endpoint acknowledges them
+	Persistent bool `json:"persistent,omitempty"`
+	// Endpoint is the endpoint receiving the notifications
+	Endpoint TopicEndpointSpec `json:"endpoint"`
+}
+
+// TopicEndpointSpec represents the endpoint of a topic. Only one endpoint can be set.
+type TopicEndpointSpec struct {
+	HTTP  *HTTPEndpointSpec  `json:"http,omitempty"`
+	AMQP  *AMQPEndpointSpec  `json:"amqp,omitempty"`
+	Kafka *KafkaEndpointSpec `json:"kafka,omitempty"`
+	// CredentialsSecret is the name of a secret in the namespace of the topic with the "username" and "password"
+	// of the endpoint. They are added to the URI of the endpoint.
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// HTTPEndpointSpec represents an HTTP endpoint
+type HTTPEndpointSpec struct {
+	// URI of the endpoint, e.g. http://my-service.my-namespace:8080
+	URI string `json:"uri"`
+	// DisableVerifySSL skips the verification of the certificate of an https endpoint
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+}
+
+// AMQPEndpointSpec represents an AMQP 0.9.1 endpoint
+type AMQPEndpointSpec struct {
+	// URI of the endpoint, e.g. amqp://my-broker.my-namespace:5672/vhost
+	URI string `json:"uri"`
+	// Exchange the notifications are sent to
+	Exchange string `json:"exchange"`
+	// AckLevel is "none", "broker" or "routable", "broker" by default
+	AckLevel string `json:"ackLevel,omitempty"`
+	// DisableVerifySSL skips the verification of the certificate of an amqps endpoint
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+}
+
+// KafkaEndpointSpec represents a Kafka endpoint
+type KafkaEndpointSpec struct {
+	// URI of the endpoint, e.g. kafka://my-kafka.my-namespace:9092
+	URI string `json:"uri"`
+	// UseSSL connects to the brokers with TLS. Required by RGW to send the credentials of the endpoint.
+	UseSSL bool `json:"useSSL,omitempty"`
+	// AckLevel is "none" or "broker", "broker" by default
+	AckLevel string `json:"ackLevel,omitempty"`
+	// DisableVerifySSL skips the verification of the certificate of the brokers
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+}
+
+// BucketTopicStatus represents the status of a bucket notification topic
+type BucketTopicStatus struct {
+	Phase      string      `json:"phase,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	// ARN is the Amazon Resource Name of the topic, referenced by the bucket notifications
+	ARN string `json:"arn,omitempty"`
+	// ObservedGeneration is the generation of the spec last synced to the object store
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastSyncTime is the time the topic was last synced to the object store
+	LastSyncTime string `json:"lastSyncTime,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CephBucketNotification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              BucketNotificationSpec    `json:"spec"`
+	Status            *BucketNotificationStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CephBucketNotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephBucketNotification `json:"items"`
+}
+
+// BucketNotificationSpec represents the spec of a bucket notification
+type BucketNotificationSpec struct {
+	// Topic is the name of the CephBucketTopic receiving the notifications, in the namespace of the notification
+	Topic string `json:"topic"`
+	// ObjectBucketClaim is the name of the OBC of the bucket sending the notifications, in the namespace of the notification
+	ObjectBucketClaim string `json:"objectBucketClaim"`
+	// Events are the types of events notified, e.g. s3:ObjectCreated:*. All the events are notified if empty.
+	Events []string `json:"events,omitempty"`
+	// Filter restricts the notifications to some of the objects of the bucket
+	Filter NotificationFilterSpec `json:"filter,omitempty"`
+}
+
+// NotificationFilterSpec represents the filter of the objects notified
+type NotificationFilterSpec struct {
+	// Prefix of the keys of the objects
+	Prefix string `json:"prefix,omitempty"`
+	// Suffix of the keys of the objects
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// BucketNotificationStatus represents the status of a bucket notification
+type BucketNotificationStatus struct {
+	Phase      string      `json:"phase,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	// BucketName is the name of the bucket sending the notifications
+	BucketName string `json:"bucketName,omitempty"`
+	// TopicARN is the Amazon Resource Name of the topic receiving the notifications
+	TopicARN string `json:"topicARN,omitempty"`
+	// ObservedGeneration is the generation of the spec last synced to the object store
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastSyncTime is the time the notification was last synced to the object store
+	LastSyncTime string `json:"lastSyncTime,omitempty"`
 }
 
 // +genclient
@@ -476,6 +1320,14 @@ type CephObjectRealmList struct {
 
 // ObjectRealmSpec represent the spec of an ObjectRealm
 type ObjectRealmSpec struct {
+	// Pull is the endpoint the realm and its period are pulled from, e.g. the endpoint of a zone of another site
+	Pull PullSpec `json:"pull,omitempty"`
+}
+
+// PullSpec represents the endpoint of the zone a realm is pulled from
+type PullSpec struct {
+	// Endpoint is the URL of the rgw of the zone, e.g. http://rgw.site-b.example.com:80
+	Endpoint string `json:"endpoint,omitempty"`
 }
 
 // +genclient
@@ -510,8 +1362,8 @@ type ObjectZoneGroupSpec struct {
 type CephObjectZone struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              ObjectZoneSpec `json:"spec"`
-	Status            *Status        `json:"status"`
+	Spec              ObjectZoneSpec    `json:"spec"`
+	Status            *ObjectZoneStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -526,8 +1378,70 @@ type CephObjectZoneList struct {
 type ObjectZoneSpec struct {
 	//The display name for the ceph users
 	ZoneGroup string `json:"zoneGroup"`
+	// Master promotes the zone to the master zone of its zone group when true, e.g. during an outage of the site of
+	// the master zone. Setting it to false on the master zone lets another zone be promoted in its place once that
+	// zone has caught up with the sync. The master zone of the zone group is left as is when not set.
+	Master *bool `json:"master,omitempty"`
+}
+
+// ObjectZoneStatus represents the status of a CephObjectZone
+type ObjectZoneStatus struct {
+	Phase      string      `json:"phase,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Master is whether the zone is the master zone of its zone group
+	Master bool `json:"master,omitempty"`
+	// SyncStatus is the last replication status of the zone with the other zones of its zone group
+	SyncStatus *ZoneSyncStatus `json:"syncStatus,omitempty"`
+	// LastChecked is the time the sync status was last checked
+	LastChecked string `json:"lastChecked,omitempty"`
+	// PromotedGeneration is the generation of the spec the zone was last promoted to master for. The zone is not
+	// promoted again until its spec changes, so that two zones set as master don't take the role from each other.
+	PromotedGeneration int64 `json:"promotedGeneration,omitempty"`
+}
+
+// ZoneSyncStatus represents the replication status of a zone with the other zones of its zone group
+type ZoneSyncStatus struct {
+	// MetadataSync is the progress of the sync of the metadata from the master zone
+	MetadataSync SyncProgress `json:"metadataSync"`
+	// DataSync is the progress of the sync of the data from each of the other zones
+	DataSync []DataSyncProgress `json:"dataSync,omitempty"`
+	// BucketsChecked is the number of buckets whose sync status was checked, a sample of the buckets of the zone
+	BucketsChecked int `json:"bucketsChecked"`
+	// BucketsBehind is the number of the checked buckets whose sync is behind one of the other zones
+	BucketsBehind int `json:"bucketsBehind"`
+}
+
+// SyncProgress represents the progress of a sync
+type SyncProgress struct {
+	// State is Master, CaughtUp, Behind, Unreachable, Error or Unknown
+	State string `json:"state"`
+	// BehindShards is the number of shards with changes not synced yet
+	BehindShards int `json:"behindShards,omitempty"`
+	// LagSeconds is the age of the oldest change not synced yet
+	LagSeconds int64 `json:"lagSeconds,omitempty"`
+}
+
+// DataSyncProgress represents the progress of the sync of the data from another zone
+type DataSyncProgress struct {
+	SourceZone   string `json:"sourceZone"`
+	SyncProgress `json:",inline"`
 }
 
+const (
+	// SyncStateMaster is the state of the metadata sync of the master zone, which does not sync its metadata
+	SyncStateMaster = "Master"
+	// SyncStateCaughtUp is the state of a sync with no change left to sync
+	SyncStateCaughtUp = "CaughtUp"
+	// SyncStateBehind is the state of a sync with changes left to sync
+	SyncStateBehind = "Behind"
+	// SyncStateUnreachable is the state of a sync whose source zone could not be reached
+	SyncStateUnreachable = "Unreachable"
+	// SyncStateError is the state of a sync whose progress could not be retrieved
+	SyncStateError = "Error"
+	// SyncStateUnknown is the state of a sync whose progress could not be parsed, e.g. while preparing a full sync
+	SyncStateUnknown = "Unknown"
+)
+
 // +genclient
 // +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -628,6 +1542,20 @@ type ClientSpec struct {
 	Caps map[string]string `json:"caps"`
 }
 
+// SecuritySpec is security spec to include various security items such as kms
+type SecuritySpec struct {
+	// KeyManagementService is the main Key Management option
+	KeyManagementService KeyManagementServiceSpec `json:"kms,omitempty"`
+}
+
+// KeyManagementServiceSpec represent various details of the KMS server
+type KeyManagementServiceSpec struct {
+	// ConnectionDetails contains the KMS connection details (address, port etc)
+	ConnectionDetails map[string]string `json:"connectionDetails,omitempty"`
+	// TokenSecretName is the kubernetes secret containing the KMS token
+	TokenSecretName string `json:"tokenSecretName,omitempty"`
+}
+
 type CleanupPolicySpec struct {
 	Confirmation CleanupConfirmationProperty `json:"confirmation,omitempty"`
 }
@@ -668,4 +1596,41 @@ type RBDMirroringSpec struct {
 
 	// PriorityClassName sets priority classes on the rgw pods
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Peers represents the peers of the mirrored pools
+	Peers MirroringPeerSpec `json:"peers,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CephFilesystemMirror struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              FilesystemMirroringSpec `json:"spec"`
+	Status            *Status                 `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CephFilesystemMirrorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephFilesystemMirror `json:"items"`
+}
+
+// FilesystemMirroringSpec is the filesystem mirroring specification
+type FilesystemMirroringSpec struct {
+	// The affinity to place the cephfs-mirror pods (default is to place on any available node)
+	Placement rookv1.Placement `json:"placement,omitempty"`
+
+	// The annotations-related configuration to add/set on each Pod related object.
+	Annotations rookv1.Annotations `json:"annotations,omitempty"`
+
+	// The resource requirements for the cephfs-mirror pods
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PriorityClassName sets priority class on the cephfs-mirror pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
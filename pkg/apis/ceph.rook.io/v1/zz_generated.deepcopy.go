@@ -23,109 +23,70 @@ package v1
 import (
 	rookiov1 "github.com/rook/rook/pkg/apis/rook.io/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephBlockPool) DeepCopyInto(out *CephBlockPool) {
+func (in *AMQPEndpointSpec) DeepCopyInto(out *AMQPEndpointSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBlockPool.
-func (in *CephBlockPool) DeepCopy() *CephBlockPool {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMQPEndpointSpec.
+func (in *AMQPEndpointSpec) DeepCopy() *AMQPEndpointSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CephBlockPool)
+	out := new(AMQPEndpointSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephBlockPool) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephBlockPoolList) DeepCopyInto(out *CephBlockPoolList) {
+func (in *AlertsSpec) DeepCopyInto(out *AlertsSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]CephBlockPool, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBlockPoolList.
-func (in *CephBlockPoolList) DeepCopy() *CephBlockPoolList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsSpec.
+func (in *AlertsSpec) DeepCopy() *AlertsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CephBlockPoolList)
+	out := new(AlertsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephBlockPoolList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephClient) DeepCopyInto(out *CephClient) {
+func (in *BucketNotificationSpec) DeepCopyInto(out *BucketNotificationSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Filter = in.Filter
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephClient.
-func (in *CephClient) DeepCopy() *CephClient {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotificationSpec.
+func (in *BucketNotificationSpec) DeepCopy() *BucketNotificationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CephClient)
+	out := new(BucketNotificationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephClient) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephClientList) DeepCopyInto(out *CephClientList) {
+func (in *BucketNotificationStatus) DeepCopyInto(out *BucketNotificationStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]CephClient, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -133,60 +94,39 @@ func (in *CephClientList) DeepCopyInto(out *CephClientList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephClientList.
-func (in *CephClientList) DeepCopy() *CephClientList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotificationStatus.
+func (in *BucketNotificationStatus) DeepCopy() *BucketNotificationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CephClientList)
+	out := new(BucketNotificationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephClientList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephCluster) DeepCopyInto(out *CephCluster) {
+func (in *BucketTopicSpec) DeepCopyInto(out *BucketTopicSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.Endpoint.DeepCopyInto(&out.Endpoint)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCluster.
-func (in *CephCluster) DeepCopy() *CephCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketTopicSpec.
+func (in *BucketTopicSpec) DeepCopy() *BucketTopicSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CephCluster)
+	out := new(BucketTopicSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephCluster) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephClusterList) DeepCopyInto(out *CephClusterList) {
+func (in *BucketTopicStatus) DeepCopyInto(out *BucketTopicStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]CephCluster, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -194,50 +134,42 @@ func (in *CephClusterList) DeepCopyInto(out *CephClusterList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephClusterList.
-func (in *CephClusterList) DeepCopy() *CephClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketTopicStatus.
+func (in *BucketTopicStatus) DeepCopy() *BucketTopicStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CephClusterList)
+	out := new(BucketTopicStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephClusterList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephFilesystem) DeepCopyInto(out *CephFilesystem) {
+func (in *CephBlockPool) DeepCopyInto(out *CephBlockPool) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
+		*out = new(CephBlockPoolStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystem.
-func (in *CephFilesystem) DeepCopy() *CephFilesystem {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBlockPool.
+func (in *CephBlockPool) DeepCopy() *CephBlockPool {
 	if in == nil {
 		return nil
 	}
-	out := new(CephFilesystem)
+	out := new(CephBlockPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephFilesystem) DeepCopyObject() runtime.Object {
+func (in *CephBlockPool) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -245,13 +177,13 @@ func (in *CephFilesystem) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephFilesystemList) DeepCopyInto(out *CephFilesystemList) {
+func (in *CephBlockPoolList) DeepCopyInto(out *CephBlockPoolList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephFilesystem, len(*in))
+		*out = make([]CephBlockPool, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -259,18 +191,18 @@ func (in *CephFilesystemList) DeepCopyInto(out *CephFilesystemList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemList.
-func (in *CephFilesystemList) DeepCopy() *CephFilesystemList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBlockPoolList.
+func (in *CephBlockPoolList) DeepCopy() *CephBlockPoolList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephFilesystemList)
+	out := new(CephBlockPoolList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephFilesystemList) DeepCopyObject() runtime.Object {
+func (in *CephBlockPoolList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -278,47 +210,89 @@ func (in *CephFilesystemList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephHealthMessage) DeepCopyInto(out *CephHealthMessage) {
+func (in *CephBlockPoolStatus) DeepCopyInto(out *CephBlockPoolStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Pool != nil {
+		in, out := &in.Pool, &out.Pool
+		*out = new(PoolStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(PoolQuotaStatus)
+		**out = **in
+	}
+	if in.MirroringInfo != nil {
+		in, out := &in.MirroringInfo, &out.MirroringInfo
+		*out = new(MirroringInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MirroringStatus != nil {
+		in, out := &in.MirroringStatus, &out.MirroringStatus
+		*out = new(MirroringStatusSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CrushRuleChange != nil {
+		in, out := &in.CrushRuleChange, &out.CrushRuleChange
+		*out = new(PoolCrushRuleChangeStatus)
+		**out = **in
+	}
+	if in.ErasureCodeDrift != nil {
+		in, out := &in.ErasureCodeDrift, &out.ErasureCodeDrift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ErasureCodeMigration != nil {
+		in, out := &in.ErasureCodeMigration, &out.ErasureCodeMigration
+		*out = new(PoolErasureCodeMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephHealthMessage.
-func (in *CephHealthMessage) DeepCopy() *CephHealthMessage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBlockPoolStatus.
+func (in *CephBlockPoolStatus) DeepCopy() *CephBlockPoolStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CephHealthMessage)
+	out := new(CephBlockPoolStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephNFS) DeepCopyInto(out *CephNFS) {
+func (in *CephBucketNotification) DeepCopyInto(out *CephBucketNotification) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
+		*out = new(BucketNotificationStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephNFS.
-func (in *CephNFS) DeepCopy() *CephNFS {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBucketNotification.
+func (in *CephBucketNotification) DeepCopy() *CephBucketNotification {
 	if in == nil {
 		return nil
 	}
-	out := new(CephNFS)
+	out := new(CephBucketNotification)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephNFS) DeepCopyObject() runtime.Object {
+func (in *CephBucketNotification) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -326,13 +300,13 @@ func (in *CephNFS) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephNFSList) DeepCopyInto(out *CephNFSList) {
+func (in *CephBucketNotificationList) DeepCopyInto(out *CephBucketNotificationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephNFS, len(*in))
+		*out = make([]CephBucketNotification, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -340,18 +314,18 @@ func (in *CephNFSList) DeepCopyInto(out *CephNFSList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephNFSList.
-func (in *CephNFSList) DeepCopy() *CephNFSList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBucketNotificationList.
+func (in *CephBucketNotificationList) DeepCopy() *CephBucketNotificationList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephNFSList)
+	out := new(CephBucketNotificationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephNFSList) DeepCopyObject() runtime.Object {
+func (in *CephBucketNotificationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -359,31 +333,31 @@ func (in *CephNFSList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectRealm) DeepCopyInto(out *CephObjectRealm) {
+func (in *CephBucketTopic) DeepCopyInto(out *CephBucketTopic) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
+		*out = new(BucketTopicStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectRealm.
-func (in *CephObjectRealm) DeepCopy() *CephObjectRealm {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBucketTopic.
+func (in *CephBucketTopic) DeepCopy() *CephBucketTopic {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectRealm)
+	out := new(CephBucketTopic)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectRealm) DeepCopyObject() runtime.Object {
+func (in *CephBucketTopic) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -391,13 +365,13 @@ func (in *CephObjectRealm) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectRealmList) DeepCopyInto(out *CephObjectRealmList) {
+func (in *CephBucketTopicList) DeepCopyInto(out *CephBucketTopicList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephObjectRealm, len(*in))
+		*out = make([]CephBucketTopic, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -405,18 +379,18 @@ func (in *CephObjectRealmList) DeepCopyInto(out *CephObjectRealmList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectRealmList.
-func (in *CephObjectRealmList) DeepCopy() *CephObjectRealmList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBucketTopicList.
+func (in *CephBucketTopicList) DeepCopy() *CephBucketTopicList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectRealmList)
+	out := new(CephBucketTopicList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectRealmList) DeepCopyObject() runtime.Object {
+func (in *CephBucketTopicList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -424,31 +398,26 @@ func (in *CephObjectRealmList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectStore) DeepCopyInto(out *CephObjectStore) {
+func (in *CephClient) DeepCopyInto(out *CephClient) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStore.
-func (in *CephObjectStore) DeepCopy() *CephObjectStore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephClient.
+func (in *CephClient) DeepCopy() *CephClient {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectStore)
+	out := new(CephClient)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectStore) DeepCopyObject() runtime.Object {
+func (in *CephClient) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -456,13 +425,13 @@ func (in *CephObjectStore) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectStoreList) DeepCopyInto(out *CephObjectStoreList) {
+func (in *CephClientList) DeepCopyInto(out *CephClientList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephObjectStore, len(*in))
+		*out = make([]CephClient, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -470,18 +439,18 @@ func (in *CephObjectStoreList) DeepCopyInto(out *CephObjectStoreList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreList.
-func (in *CephObjectStoreList) DeepCopy() *CephObjectStoreList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephClientList.
+func (in *CephClientList) DeepCopy() *CephClientList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectStoreList)
+	out := new(CephClientList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectStoreList) DeepCopyObject() runtime.Object {
+func (in *CephClientList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -489,31 +458,27 @@ func (in *CephObjectStoreList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectStoreUser) DeepCopyInto(out *CephObjectStoreUser) {
+func (in *CephCluster) DeepCopyInto(out *CephCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
-	}
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreUser.
-func (in *CephObjectStoreUser) DeepCopy() *CephObjectStoreUser {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCluster.
+func (in *CephCluster) DeepCopy() *CephCluster {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectStoreUser)
+	out := new(CephCluster)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectStoreUser) DeepCopyObject() runtime.Object {
+func (in *CephCluster) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -521,13 +486,13 @@ func (in *CephObjectStoreUser) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectStoreUserList) DeepCopyInto(out *CephObjectStoreUserList) {
+func (in *CephClusterList) DeepCopyInto(out *CephClusterList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephObjectStoreUser, len(*in))
+		*out = make([]CephCluster, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -535,18 +500,18 @@ func (in *CephObjectStoreUserList) DeepCopyInto(out *CephObjectStoreUserList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreUserList.
-func (in *CephObjectStoreUserList) DeepCopy() *CephObjectStoreUserList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephClusterList.
+func (in *CephClusterList) DeepCopy() *CephClusterList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectStoreUserList)
+	out := new(CephClusterList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectStoreUserList) DeepCopyObject() runtime.Object {
+func (in *CephClusterList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -554,63 +519,85 @@ func (in *CephObjectStoreUserList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectZone) DeepCopyInto(out *CephObjectZone) {
+func (in *CephConfigRejectedOption) DeepCopyInto(out *CephConfigRejectedOption) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZone.
-func (in *CephObjectZone) DeepCopy() *CephObjectZone {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephConfigRejectedOption.
+func (in *CephConfigRejectedOption) DeepCopy() *CephConfigRejectedOption {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectZone)
+	out := new(CephConfigRejectedOption)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectZone) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephConfigStatus) DeepCopyInto(out *CephConfigStatus) {
+	*out = *in
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
 	}
-	return nil
+	if in.Rejected != nil {
+		in, out := &in.Rejected, &out.Rejected
+		*out = make([]CephConfigRejectedOption, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephConfigStatus.
+func (in *CephConfigStatus) DeepCopy() *CephConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephConfigStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectZoneGroup) DeepCopyInto(out *CephObjectZoneGroup) {
+func (in *CephFilesystem) DeepCopyInto(out *CephFilesystem) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
+		*out = new(CephFilesystemStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneGroup.
-func (in *CephObjectZoneGroup) DeepCopy() *CephObjectZoneGroup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystem.
+func (in *CephFilesystem) DeepCopy() *CephFilesystem {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectZoneGroup)
+	out := new(CephFilesystem)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectZoneGroup) DeepCopyObject() runtime.Object {
+func (in *CephFilesystem) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -618,13 +605,13 @@ func (in *CephObjectZoneGroup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectZoneGroupList) DeepCopyInto(out *CephObjectZoneGroupList) {
+func (in *CephFilesystemList) DeepCopyInto(out *CephFilesystemList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephObjectZoneGroup, len(*in))
+		*out = make([]CephFilesystem, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -632,18 +619,18 @@ func (in *CephObjectZoneGroupList) DeepCopyInto(out *CephObjectZoneGroupList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneGroupList.
-func (in *CephObjectZoneGroupList) DeepCopy() *CephObjectZoneGroupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemList.
+func (in *CephFilesystemList) DeepCopy() *CephFilesystemList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectZoneGroupList)
+	out := new(CephFilesystemList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectZoneGroupList) DeepCopyObject() runtime.Object {
+func (in *CephFilesystemList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -651,13 +638,45 @@ func (in *CephObjectZoneGroupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectZoneList) DeepCopyInto(out *CephObjectZoneList) {
+func (in *CephFilesystemMirror) DeepCopyInto(out *CephFilesystemMirror) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(Status)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemMirror.
+func (in *CephFilesystemMirror) DeepCopy() *CephFilesystemMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(CephFilesystemMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephFilesystemMirror) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephFilesystemMirrorList) DeepCopyInto(out *CephFilesystemMirrorList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephObjectZone, len(*in))
+		*out = make([]CephFilesystemMirror, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -665,18 +684,18 @@ func (in *CephObjectZoneList) DeepCopyInto(out *CephObjectZoneList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneList.
-func (in *CephObjectZoneList) DeepCopy() *CephObjectZoneList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemMirrorList.
+func (in *CephFilesystemMirrorList) DeepCopy() *CephFilesystemMirrorList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectZoneList)
+	out := new(CephFilesystemMirrorList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectZoneList) DeepCopyObject() runtime.Object {
+func (in *CephFilesystemMirrorList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -684,31 +703,78 @@ func (in *CephObjectZoneList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephRBDMirror) DeepCopyInto(out *CephRBDMirror) {
+func (in *CephFilesystemStatus) DeepCopyInto(out *CephFilesystemStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]PoolStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PoolQuotas != nil {
+		in, out := &in.PoolQuotas, &out.PoolQuotas
+		*out = make(map[string]PoolQuotaStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Mirroring != nil {
+		in, out := &in.Mirroring, &out.Mirroring
+		*out = new(MirroringInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AppliedMirroring != nil {
+		in, out := &in.AppliedMirroring, &out.AppliedMirroring
+		*out = new(FSMirroringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemStatus.
+func (in *CephFilesystemStatus) DeepCopy() *CephFilesystemStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephFilesystemStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephFilesystemSubVolumeGroup) DeepCopyInto(out *CephFilesystemSubVolumeGroup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
+		*out = new(CephFilesystemSubVolumeGroupStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephRBDMirror.
-func (in *CephRBDMirror) DeepCopy() *CephRBDMirror {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemSubVolumeGroup.
+func (in *CephFilesystemSubVolumeGroup) DeepCopy() *CephFilesystemSubVolumeGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(CephRBDMirror)
+	out := new(CephFilesystemSubVolumeGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephRBDMirror) DeepCopyObject() runtime.Object {
+func (in *CephFilesystemSubVolumeGroup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -716,13 +782,13 @@ func (in *CephRBDMirror) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephRBDMirrorList) DeepCopyInto(out *CephRBDMirrorList) {
+func (in *CephFilesystemSubVolumeGroupList) DeepCopyInto(out *CephFilesystemSubVolumeGroupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephRBDMirror, len(*in))
+		*out = make([]CephFilesystemSubVolumeGroup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -730,18 +796,18 @@ func (in *CephRBDMirrorList) DeepCopyInto(out *CephRBDMirrorList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephRBDMirrorList.
-func (in *CephRBDMirrorList) DeepCopy() *CephRBDMirrorList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemSubVolumeGroupList.
+func (in *CephFilesystemSubVolumeGroupList) DeepCopy() *CephFilesystemSubVolumeGroupList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephRBDMirrorList)
+	out := new(CephFilesystemSubVolumeGroupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephRBDMirrorList) DeepCopyObject() runtime.Object {
+func (in *CephFilesystemSubVolumeGroupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -749,388 +815,2137 @@ func (in *CephRBDMirrorList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephStatus) DeepCopyInto(out *CephStatus) {
+func (in *CephFilesystemSubVolumeGroupSpec) DeepCopyInto(out *CephFilesystemSubVolumeGroupSpec) {
 	*out = *in
-	if in.Details != nil {
-		in, out := &in.Details, &out.Details
-		*out = make(map[string]CephHealthMessage, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		x := (*in).DeepCopy()
+		*out = &x
 	}
+	in.Pinning.DeepCopyInto(&out.Pinning)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephStatus.
-func (in *CephStatus) DeepCopy() *CephStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemSubVolumeGroupSpec.
+func (in *CephFilesystemSubVolumeGroupSpec) DeepCopy() *CephFilesystemSubVolumeGroupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CephStatus)
+	out := new(CephFilesystemSubVolumeGroupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephVersionSpec) DeepCopyInto(out *CephVersionSpec) {
+func (in *CephFilesystemSubVolumeGroupStatus) DeepCopyInto(out *CephFilesystemSubVolumeGroupStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephVersionSpec.
-func (in *CephVersionSpec) DeepCopy() *CephVersionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemSubVolumeGroupStatus.
+func (in *CephFilesystemSubVolumeGroupStatus) DeepCopy() *CephFilesystemSubVolumeGroupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CephVersionSpec)
+	out := new(CephFilesystemSubVolumeGroupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CleanupPolicySpec) DeepCopyInto(out *CleanupPolicySpec) {
+func (in *CephHealthMessage) DeepCopyInto(out *CephHealthMessage) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupPolicySpec.
-func (in *CleanupPolicySpec) DeepCopy() *CleanupPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephHealthMessage.
+func (in *CephHealthMessage) DeepCopy() *CephHealthMessage {
 	if in == nil {
 		return nil
 	}
-	out := new(CleanupPolicySpec)
+	out := new(CephHealthMessage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClientSpec) DeepCopyInto(out *ClientSpec) {
+func (in *CephHealthTransition) DeepCopyInto(out *CephHealthTransition) {
 	*out = *in
-	if in.Caps != nil {
-		in, out := &in.Caps, &out.Caps
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.RaisedChecks != nil {
+		in, out := &in.RaisedChecks, &out.RaisedChecks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClearedChecks != nil {
+		in, out := &in.ClearedChecks, &out.ClearedChecks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSpec.
-func (in *ClientSpec) DeepCopy() *ClientSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephHealthTransition.
+func (in *CephHealthTransition) DeepCopy() *CephHealthTransition {
 	if in == nil {
 		return nil
 	}
-	out := new(ClientSpec)
+	out := new(CephHealthTransition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
+func (in *CephNFS) DeepCopyInto(out *CephNFS) {
 	*out = *in
-	out.CephVersion = in.CephVersion
-	in.Storage.DeepCopyInto(&out.Storage)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(rookiov1.AnnotationsSpec, len(*in))
-		for key, val := range *in {
-			var outVal map[string]string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make(rookiov1.Annotations, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val
-				}
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.Placement != nil {
-		in, out := &in.Placement, &out.Placement
-		*out = make(rookiov1.PlacementSpec, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	in.Network.DeepCopyInto(&out.Network)
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make(rookiov1.ResourceSpec, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.PriorityClassNames != nil {
-		in, out := &in.PriorityClassNames, &out.PriorityClassNames
-		*out = make(rookiov1.PriorityClassNamesSpec, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(Status)
+		**out = **in
 	}
-	out.DisruptionManagement = in.DisruptionManagement
-	in.Mon.DeepCopyInto(&out.Mon)
-	out.CrashCollector = in.CrashCollector
-	out.Dashboard = in.Dashboard
-	out.Monitoring = in.Monitoring
-	out.External = in.External
-	in.Mgr.DeepCopyInto(&out.Mgr)
-	out.CleanupPolicy = in.CleanupPolicy
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
-func (in *ClusterSpec) DeepCopy() *ClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephNFS.
+func (in *CephNFS) DeepCopy() *CephNFS {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSpec)
+	out := new(CephNFS)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephNFS) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
+func (in *CephNFSList) DeepCopyInto(out *CephNFSList) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephNFS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephNFSList.
+func (in *CephNFSList) DeepCopy() *CephNFSList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephNFSList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephNFSList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOSDRemoval) DeepCopyInto(out *CephOSDRemoval) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(OSDRemovalStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOSDRemoval.
+func (in *CephOSDRemoval) DeepCopy() *CephOSDRemoval {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOSDRemoval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephOSDRemoval) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOSDRemovalList) DeepCopyInto(out *CephOSDRemovalList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephOSDRemoval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOSDRemovalList.
+func (in *CephOSDRemovalList) DeepCopy() *CephOSDRemovalList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOSDRemovalList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephOSDRemovalList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectRealm) DeepCopyInto(out *CephObjectRealm) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(Status)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectRealm.
+func (in *CephObjectRealm) DeepCopy() *CephObjectRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectRealm) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectRealmList) DeepCopyInto(out *CephObjectRealmList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephObjectRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectRealmList.
+func (in *CephObjectRealmList) DeepCopy() *CephObjectRealmList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectRealmList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectRealmList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectStore) DeepCopyInto(out *CephObjectStore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ObjectStoreStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStore.
+func (in *CephObjectStore) DeepCopy() *CephObjectStore {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectStore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectStoreList) DeepCopyInto(out *CephObjectStoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephObjectStore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreList.
+func (in *CephObjectStoreList) DeepCopy() *CephObjectStoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectStoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectStoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectStoreUser) DeepCopyInto(out *CephObjectStoreUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ObjectStoreUserStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreUser.
+func (in *CephObjectStoreUser) DeepCopy() *CephObjectStoreUser {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectStoreUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectStoreUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectStoreUserList) DeepCopyInto(out *CephObjectStoreUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephObjectStoreUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreUserList.
+func (in *CephObjectStoreUserList) DeepCopy() *CephObjectStoreUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectStoreUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectStoreUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectZone) DeepCopyInto(out *CephObjectZone) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ObjectZoneStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZone.
+func (in *CephObjectZone) DeepCopy() *CephObjectZone {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectZone) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectZoneGroup) DeepCopyInto(out *CephObjectZoneGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(Status)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneGroup.
+func (in *CephObjectZoneGroup) DeepCopy() *CephObjectZoneGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectZoneGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectZoneGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectZoneGroupList) DeepCopyInto(out *CephObjectZoneGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephObjectZoneGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneGroupList.
+func (in *CephObjectZoneGroupList) DeepCopy() *CephObjectZoneGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectZoneGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectZoneGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectZoneList) DeepCopyInto(out *CephObjectZoneList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephObjectZone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneList.
+func (in *CephObjectZoneList) DeepCopy() *CephObjectZoneList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectZoneList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectZoneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephRBDMirror) DeepCopyInto(out *CephRBDMirror) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(Status)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephRBDMirror.
+func (in *CephRBDMirror) DeepCopy() *CephRBDMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(CephRBDMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephRBDMirror) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephRBDMirrorList) DeepCopyInto(out *CephRBDMirrorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephRBDMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephRBDMirrorList.
+func (in *CephRBDMirrorList) DeepCopy() *CephRBDMirrorList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephRBDMirrorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephRBDMirrorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephStatus) DeepCopyInto(out *CephStatus) {
+	*out = *in
+	if in.Details != nil {
+		in, out := &in.Details, &out.Details
+		*out = make(map[string]CephHealthMessage, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]CephHealthTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephStatus.
+func (in *CephStatus) DeepCopy() *CephStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephVersionSpec) DeepCopyInto(out *CephVersionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephVersionSpec.
+func (in *CephVersionSpec) DeepCopy() *CephVersionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephVersionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupPolicySpec) DeepCopyInto(out *CleanupPolicySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupPolicySpec.
+func (in *CleanupPolicySpec) DeepCopy() *CleanupPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientSpec) DeepCopyInto(out *ClientSpec) {
+	*out = *in
+	if in.Caps != nil {
+		in, out := &in.Caps, &out.Caps
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSpec.
+func (in *ClientSpec) DeepCopy() *ClientSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
+	*out = *in
+	out.CephVersion = in.CephVersion
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(rookiov1.AnnotationsSpec, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(rookiov1.Annotations, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = make(rookiov1.PlacementSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	in.Network.DeepCopyInto(&out.Network)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(rookiov1.ResourceSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.PriorityClassNames != nil {
+		in, out := &in.PriorityClassNames, &out.PriorityClassNames
+		*out = make(rookiov1.PriorityClassNamesSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.UpgradeStrategy = in.UpgradeStrategy
+	in.Crush.DeepCopyInto(&out.Crush)
+	if in.CephConfig != nil {
+		in, out := &in.CephConfig, &out.CephConfig
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	out.DisruptionManagement = in.DisruptionManagement
+	in.Mon.DeepCopyInto(&out.Mon)
+	in.CrashCollector.DeepCopyInto(&out.CrashCollector)
+	out.Dashboard = in.Dashboard
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	out.External = in.External
+	in.Mgr.DeepCopyInto(&out.Mgr)
+	out.CleanupPolicy = in.CleanupPolicy
+	in.Security.DeepCopyInto(&out.Security)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
+func (in *ClusterSpec) DeepCopy() *ClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CephStatus != nil {
+		in, out := &in.CephStatus, &out.CephStatus
+		*out = new(CephStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CephVersion != nil {
+		in, out := &in.CephVersion, &out.CephVersion
+		*out = new(ClusterVersion)
+		**out = **in
+	}
+	if in.OSDUpgrade != nil {
+		in, out := &in.OSDUpgrade, &out.OSDUpgrade
+		*out = new(OSDUpgradeStatus)
+		**out = **in
+	}
+	if in.CephConfig != nil {
+		in, out := &in.CephConfig, &out.CephConfig
+		*out = new(CephConfigStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Crashes != nil {
+		in, out := &in.Crashes, &out.Crashes
+		*out = new(CrashStatus)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
+func (in *ClusterStatus) DeepCopy() *ClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterVersion) DeepCopyInto(out *ClusterVersion) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVersion.
+func (in *ClusterVersion) DeepCopy() *ClusterVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrashCollectorSpec) DeepCopyInto(out *CrashCollectorSpec) {
+	*out = *in
+	in.Export.DeepCopyInto(&out.Export)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashCollectorSpec.
+func (in *CrashCollectorSpec) DeepCopy() *CrashCollectorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrashCollectorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrashExportSpec) DeepCopyInto(out *CrashExportSpec) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(CrashS3ExportSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashExportSpec.
+func (in *CrashExportSpec) DeepCopy() *CrashExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrashExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrashS3ExportSpec) DeepCopyInto(out *CrashS3ExportSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashS3ExportSpec.
+func (in *CrashS3ExportSpec) DeepCopy() *CrashS3ExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrashS3ExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrashStatus) DeepCopyInto(out *CrashStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashStatus.
+func (in *CrashStatus) DeepCopy() *CrashStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CrashStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushLevelSpec) DeepCopyInto(out *CrushLevelSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushLevelSpec.
+func (in *CrushLevelSpec) DeepCopy() *CrushLevelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushLevelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushSpec) DeepCopyInto(out *CrushSpec) {
+	*out = *in
+	if in.Hierarchy != nil {
+		in, out := &in.Hierarchy, &out.Hierarchy
+		*out = make([]CrushLevelSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeviceClassRules != nil {
+		in, out := &in.DeviceClassRules, &out.DeviceClassRules
+		*out = make([]DeviceClassRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushSpec.
+func (in *CrushSpec) DeepCopy() *CrushSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardSpec) DeepCopyInto(out *DashboardSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSpec.
+func (in *DashboardSpec) DeepCopy() *DashboardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSyncProgress) DeepCopyInto(out *DataSyncProgress) {
+	*out = *in
+	out.SyncProgress = in.SyncProgress
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSyncProgress.
+func (in *DataSyncProgress) DeepCopy() *DataSyncProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSyncProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClassRule) DeepCopyInto(out *DeviceClassRule) {
+	*out = *in
+	if in.Rotational != nil {
+		in, out := &in.Rotational, &out.Rotational
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NVMe != nil {
+		in, out := &in.NVMe, &out.NVMe
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassRule.
+func (in *DeviceClassRule) DeepCopy() *DeviceClassRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClassRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionManagementSpec) DeepCopyInto(out *DisruptionManagementSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionManagementSpec.
+func (in *DisruptionManagementSpec) DeepCopy() *DisruptionManagementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionManagementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErasureCodedSpec) DeepCopyInto(out *ErasureCodedSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErasureCodedSpec.
+func (in *ErasureCodedSpec) DeepCopy() *ErasureCodedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ErasureCodedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSpec) DeepCopyInto(out *ExternalSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSpec.
+func (in *ExternalSpec) DeepCopy() *ExternalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FSMirroringSpec) DeepCopyInto(out *FSMirroringSpec) {
+	*out = *in
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = new(MirroringPeerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Directories != nil {
+		in, out := &in.Directories, &out.Directories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SnapshotSchedules != nil {
+		in, out := &in.SnapshotSchedules, &out.SnapshotSchedules
+		*out = make([]SnapshotScheduleSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.SnapshotRetention != nil {
+		in, out := &in.SnapshotRetention, &out.SnapshotRetention
+		*out = make([]SnapshotScheduleRetentionSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FSMirroringSpec.
+func (in *FSMirroringSpec) DeepCopy() *FSMirroringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FSMirroringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemMirroringSpec) DeepCopyInto(out *FilesystemMirroringSpec) {
+	*out = *in
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(rookiov1.Annotations, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMirroringSpec.
+func (in *FilesystemMirroringSpec) DeepCopy() *FilesystemMirroringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemMirroringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemSpec) DeepCopyInto(out *FilesystemSpec) {
+	*out = *in
+	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
+	if in.DataPools != nil {
+		in, out := &in.DataPools, &out.DataPools
+		*out = make([]PoolSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.MetadataServer.DeepCopyInto(&out.MetadataServer)
+	if in.Mirroring != nil {
+		in, out := &in.Mirroring, &out.Mirroring
+		*out = new(FSMirroringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemSpec.
+func (in *FilesystemSpec) DeepCopy() *FilesystemSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GaneshaRADOSSpec) DeepCopyInto(out *GaneshaRADOSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GaneshaRADOSSpec.
+func (in *GaneshaRADOSSpec) DeepCopy() *GaneshaRADOSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GaneshaRADOSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GaneshaServerSpec) DeepCopyInto(out *GaneshaServerSpec) {
+	*out = *in
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(rookiov1.Annotations, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GaneshaServerSpec.
+func (in *GaneshaServerSpec) DeepCopy() *GaneshaServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GaneshaServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
+	*out = *in
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(rookiov1.Annotations, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
+func (in *GatewaySpec) DeepCopy() *GatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPEndpointSpec) DeepCopyInto(out *HTTPEndpointSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPEndpointSpec.
+func (in *HTTPEndpointSpec) DeepCopy() *HTTPEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaEndpointSpec) DeepCopyInto(out *KafkaEndpointSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaEndpointSpec.
+func (in *KafkaEndpointSpec) DeepCopy() *KafkaEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyManagementServiceSpec) DeepCopyInto(out *KeyManagementServiceSpec) {
+	*out = *in
+	if in.ConnectionDetails != nil {
+		in, out := &in.ConnectionDetails, &out.ConnectionDetails
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyManagementServiceSpec.
+func (in *KeyManagementServiceSpec) DeepCopy() *KeyManagementServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyManagementServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataServerSpec) DeepCopyInto(out *MetadataServerSpec) {
+	*out = *in
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(rookiov1.Annotations, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataServerSpec.
+func (in *MetadataServerSpec) DeepCopy() *MetadataServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MgrSpec) DeepCopyInto(out *MgrSpec) {
+	*out = *in
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = make([]Module, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MgrSpec.
+func (in *MgrSpec) DeepCopy() *MgrSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MgrSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroringInfo) DeepCopyInto(out *MirroringInfo) {
+	*out = *in
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = make([]MirroringPeerInfo, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringInfo.
+func (in *MirroringInfo) DeepCopy() *MirroringInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(MirroringInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroringPeerInfo) DeepCopyInto(out *MirroringPeerInfo) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringPeerInfo.
+func (in *MirroringPeerInfo) DeepCopy() *MirroringPeerInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(MirroringPeerInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroringPeerSpec) DeepCopyInto(out *MirroringPeerSpec) {
+	*out = *in
+	if in.SecretNames != nil {
+		in, out := &in.SecretNames, &out.SecretNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringPeerSpec.
+func (in *MirroringPeerSpec) DeepCopy() *MirroringPeerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MirroringPeerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroringSpec) DeepCopyInto(out *MirroringSpec) {
+	*out = *in
+	if in.SnapshotSchedules != nil {
+		in, out := &in.SnapshotSchedules, &out.SnapshotSchedules
+		*out = make([]SnapshotScheduleSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringSpec.
+func (in *MirroringSpec) DeepCopy() *MirroringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MirroringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroringStatusSummary) DeepCopyInto(out *MirroringStatusSummary) {
+	*out = *in
+	if in.States != nil {
+		in, out := &in.States, &out.States
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringStatusSummary.
+func (in *MirroringStatusSummary) DeepCopy() *MirroringStatusSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(MirroringStatusSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Module) DeepCopyInto(out *Module) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Module.
+func (in *Module) DeepCopy() *Module {
+	if in == nil {
+		return nil
+	}
+	out := new(Module)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonHealthCheckSpec) DeepCopyInto(out *MonHealthCheckSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonHealthCheckSpec.
+func (in *MonHealthCheckSpec) DeepCopy() *MonHealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonHealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonSpec) DeepCopyInto(out *MonSpec) {
+	*out = *in
+	if in.VolumeClaimTemplate != nil {
+		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
+		*out = new(corev1.PersistentVolumeClaim)
+		(*in).DeepCopyInto(*out)
+	}
+	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
+	if in.StretchCluster != nil {
+		in, out := &in.StretchCluster, &out.StretchCluster
+		*out = new(StretchClusterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonSpec.
+func (in *MonSpec) DeepCopy() *MonSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Alerts = in.Alerts
+	out.ServiceMonitors = in.ServiceMonitors
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NFSGaneshaSpec) DeepCopyInto(out *NFSGaneshaSpec) {
+	*out = *in
+	out.RADOS = in.RADOS
+	in.Server.DeepCopyInto(&out.Server)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NFSGaneshaSpec.
+func (in *NFSGaneshaSpec) DeepCopy() *NFSGaneshaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NFSGaneshaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+	*out = *in
+	in.NetworkSpec.DeepCopyInto(&out.NetworkSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
+func (in *NetworkSpec) DeepCopy() *NetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationFilterSpec) DeepCopyInto(out *NotificationFilterSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationFilterSpec.
+func (in *NotificationFilterSpec) DeepCopy() *NotificationFilterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationFilterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDRemovalDevice) DeepCopyInto(out *OSDRemovalDevice) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDRemovalDevice.
+func (in *OSDRemovalDevice) DeepCopy() *OSDRemovalDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDRemovalDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDRemovalOSDStatus) DeepCopyInto(out *OSDRemovalOSDStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDRemovalOSDStatus.
+func (in *OSDRemovalOSDStatus) DeepCopy() *OSDRemovalOSDStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDRemovalOSDStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDRemovalSpec) DeepCopyInto(out *OSDRemovalSpec) {
+	*out = *in
+	if in.OSDIDs != nil {
+		in, out := &in.OSDIDs, &out.OSDIDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]OSDRemovalDevice, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDRemovalSpec.
+func (in *OSDRemovalSpec) DeepCopy() *OSDRemovalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDRemovalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDRemovalStatus) DeepCopyInto(out *OSDRemovalStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
 		*out = make([]Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.CephStatus != nil {
-		in, out := &in.CephStatus, &out.CephStatus
-		*out = new(CephStatus)
+	if in.OSDs != nil {
+		in, out := &in.OSDs, &out.OSDs
+		*out = make([]OSDRemovalOSDStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDRemovalStatus.
+func (in *OSDRemovalStatus) DeepCopy() *OSDRemovalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDRemovalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDUpgradeStatus) DeepCopyInto(out *OSDUpgradeStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDUpgradeStatus.
+func (in *OSDUpgradeStatus) DeepCopy() *OSDUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDUpgradeStrategySpec) DeepCopyInto(out *OSDUpgradeStrategySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDUpgradeStrategySpec.
+func (in *OSDUpgradeStrategySpec) DeepCopy() *OSDUpgradeStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDUpgradeStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectRealmSpec) DeepCopyInto(out *ObjectRealmSpec) {
+	*out = *in
+	out.Pull = in.Pull
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRealmSpec.
+func (in *ObjectRealmSpec) DeepCopy() *ObjectRealmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectRealmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStoreSpec) DeepCopyInto(out *ObjectStoreSpec) {
+	*out = *in
+	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
+	in.DataPool.DeepCopyInto(&out.DataPool)
+	in.Gateway.DeepCopyInto(&out.Gateway)
+	out.Zone = in.Zone
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreSpec.
+func (in *ObjectStoreSpec) DeepCopy() *ObjectStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStoreStatus) DeepCopyInto(out *ObjectStoreStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]PoolStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PoolQuotas != nil {
+		in, out := &in.PoolQuotas, &out.PoolQuotas
+		*out = make(map[string]PoolQuotaStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreStatus.
+func (in *ObjectStoreStatus) DeepCopy() *ObjectStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStoreUserSpec) DeepCopyInto(out *ObjectStoreUserSpec) {
+	*out = *in
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = new(ObjectUserQuotaSpec)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.CephVersion != nil {
-		in, out := &in.CephVersion, &out.CephVersion
-		*out = new(ClusterVersion)
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(ObjectUserCapSpec)
+		**out = **in
+	}
+	if in.SubUsers != nil {
+		in, out := &in.SubUsers, &out.SubUsers
+		*out = make([]ObjectUserSubUserSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeyRotationGracePeriod != nil {
+		in, out := &in.KeyRotationGracePeriod, &out.KeyRotationGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreUserSpec.
+func (in *ObjectStoreUserSpec) DeepCopy() *ObjectStoreUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStoreUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStoreUserStatus) DeepCopyInto(out *ObjectStoreUserStatus) {
+	*out = *in
+	if in.RetiredKeys != nil {
+		in, out := &in.RetiredKeys, &out.RetiredKeys
+		*out = make([]RetiredObjectUserKey, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxBuckets != nil {
+		in, out := &in.MaxBuckets, &out.MaxBuckets
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreUserStatus.
+func (in *ObjectStoreUserStatus) DeepCopy() *ObjectStoreUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStoreUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectUserCapSpec) DeepCopyInto(out *ObjectUserCapSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUserCapSpec.
+func (in *ObjectUserCapSpec) DeepCopy() *ObjectUserCapSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectUserCapSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectUserQuotaSpec) DeepCopyInto(out *ObjectUserQuotaSpec) {
+	*out = *in
+	if in.MaxBuckets != nil {
+		in, out := &in.MaxBuckets, &out.MaxBuckets
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxObjects != nil {
+		in, out := &in.MaxObjects, &out.MaxObjects
+		*out = new(int64)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
-func (in *ClusterStatus) DeepCopy() *ClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUserQuotaSpec.
+func (in *ObjectUserQuotaSpec) DeepCopy() *ObjectUserQuotaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterStatus)
+	out := new(ObjectUserQuotaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterVersion) DeepCopyInto(out *ClusterVersion) {
+func (in *ObjectUserSubUserSpec) DeepCopyInto(out *ObjectUserSubUserSpec) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVersion.
-func (in *ClusterVersion) DeepCopy() *ClusterVersion {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUserSubUserSpec.
+func (in *ObjectUserSubUserSpec) DeepCopy() *ObjectUserSubUserSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterVersion)
+	out := new(ObjectUserSubUserSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Condition) DeepCopyInto(out *Condition) {
+func (in *ObjectZoneGroupSpec) DeepCopyInto(out *ObjectZoneGroupSpec) {
 	*out = *in
-	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectZoneGroupSpec.
+func (in *ObjectZoneGroupSpec) DeepCopy() *ObjectZoneGroupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(ObjectZoneGroupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CrashCollectorSpec) DeepCopyInto(out *CrashCollectorSpec) {
+func (in *ObjectZoneSpec) DeepCopyInto(out *ObjectZoneSpec) {
 	*out = *in
+	if in.Master != nil {
+		in, out := &in.Master, &out.Master
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashCollectorSpec.
-func (in *CrashCollectorSpec) DeepCopy() *CrashCollectorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectZoneSpec.
+func (in *ObjectZoneSpec) DeepCopy() *ObjectZoneSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CrashCollectorSpec)
+	out := new(ObjectZoneSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DashboardSpec) DeepCopyInto(out *DashboardSpec) {
+func (in *ObjectZoneStatus) DeepCopyInto(out *ObjectZoneStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SyncStatus != nil {
+		in, out := &in.SyncStatus, &out.SyncStatus
+		*out = new(ZoneSyncStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSpec.
-func (in *DashboardSpec) DeepCopy() *DashboardSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectZoneStatus.
+func (in *ObjectZoneStatus) DeepCopy() *ObjectZoneStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DashboardSpec)
+	out := new(ObjectZoneStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DisruptionManagementSpec) DeepCopyInto(out *DisruptionManagementSpec) {
+func (in *PoolCrushRuleChangeStatus) DeepCopyInto(out *PoolCrushRuleChangeStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionManagementSpec.
-func (in *DisruptionManagementSpec) DeepCopy() *DisruptionManagementSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolCrushRuleChangeStatus.
+func (in *PoolCrushRuleChangeStatus) DeepCopy() *PoolCrushRuleChangeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DisruptionManagementSpec)
+	out := new(PoolCrushRuleChangeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ErasureCodedSpec) DeepCopyInto(out *ErasureCodedSpec) {
+func (in *PoolErasureCodeMigrationStatus) DeepCopyInto(out *PoolErasureCodeMigrationStatus) {
 	*out = *in
+	if in.TargetSpec != nil {
+		in, out := &in.TargetSpec, &out.TargetSpec
+		*out = new(PoolSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingImages != nil {
+		in, out := &in.PendingImages, &out.PendingImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExecutingImages != nil {
+		in, out := &in.ExecutingImages, &out.ExecutingImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErasureCodedSpec.
-func (in *ErasureCodedSpec) DeepCopy() *ErasureCodedSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolErasureCodeMigrationStatus.
+func (in *PoolErasureCodeMigrationStatus) DeepCopy() *PoolErasureCodeMigrationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ErasureCodedSpec)
+	out := new(PoolErasureCodeMigrationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalSpec) DeepCopyInto(out *ExternalSpec) {
+func (in *PoolQuotaStatus) DeepCopyInto(out *PoolQuotaStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSpec.
-func (in *ExternalSpec) DeepCopy() *ExternalSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolQuotaStatus.
+func (in *PoolQuotaStatus) DeepCopy() *PoolQuotaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalSpec)
+	out := new(PoolQuotaStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FilesystemSpec) DeepCopyInto(out *FilesystemSpec) {
+func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
 	*out = *in
-	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
-	if in.DataPools != nil {
-		in, out := &in.DataPools, &out.DataPools
-		*out = make([]PoolSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	out.Replicated = in.Replicated
+	out.ErasureCoded = in.ErasureCoded
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
-	in.MetadataServer.DeepCopyInto(&out.MetadataServer)
+	in.Quotas.DeepCopyInto(&out.Quotas)
+	in.Mirroring.DeepCopyInto(&out.Mirroring)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemSpec.
-func (in *FilesystemSpec) DeepCopy() *FilesystemSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolSpec.
+func (in *PoolSpec) DeepCopy() *PoolSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FilesystemSpec)
+	out := new(PoolSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GaneshaRADOSSpec) DeepCopyInto(out *GaneshaRADOSSpec) {
+func (in *PoolStatus) DeepCopyInto(out *PoolStatus) {
 	*out = *in
+	if in.ErasureCodeDrift != nil {
+		in, out := &in.ErasureCodeDrift, &out.ErasureCodeDrift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GaneshaRADOSSpec.
-func (in *GaneshaRADOSSpec) DeepCopy() *GaneshaRADOSSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolStatus.
+func (in *PoolStatus) DeepCopy() *PoolStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GaneshaRADOSSpec)
+	out := new(PoolStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GaneshaServerSpec) DeepCopyInto(out *GaneshaServerSpec) {
+func (in *PullSpec) DeepCopyInto(out *PullSpec) {
 	*out = *in
-	in.Placement.DeepCopyInto(&out.Placement)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(rookiov1.Annotations, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.Resources.DeepCopyInto(&out.Resources)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GaneshaServerSpec.
-func (in *GaneshaServerSpec) DeepCopy() *GaneshaServerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullSpec.
+func (in *PullSpec) DeepCopy() *PullSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GaneshaServerSpec)
+	out := new(PullSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
+func (in *QuotaSpec) DeepCopyInto(out *QuotaSpec) {
 	*out = *in
-	in.Placement.DeepCopyInto(&out.Placement)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(rookiov1.Annotations, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.MaxBytes != nil {
+		in, out := &in.MaxBytes, &out.MaxBytes
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.MaxObjects != nil {
+		in, out := &in.MaxObjects, &out.MaxObjects
+		*out = new(uint64)
+		**out = **in
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
-func (in *GatewaySpec) DeepCopy() *GatewaySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaSpec.
+func (in *QuotaSpec) DeepCopy() *QuotaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GatewaySpec)
+	out := new(QuotaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetadataServerSpec) DeepCopyInto(out *MetadataServerSpec) {
+func (in *RBDMirroringSpec) DeepCopyInto(out *RBDMirroringSpec) {
 	*out = *in
 	in.Placement.DeepCopyInto(&out.Placement)
 	if in.Annotations != nil {
@@ -1141,306 +2956,299 @@ func (in *MetadataServerSpec) DeepCopyInto(out *MetadataServerSpec) {
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	in.Peers.DeepCopyInto(&out.Peers)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataServerSpec.
-func (in *MetadataServerSpec) DeepCopy() *MetadataServerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBDMirroringSpec.
+func (in *RBDMirroringSpec) DeepCopy() *RBDMirroringSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MetadataServerSpec)
+	out := new(RBDMirroringSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MgrSpec) DeepCopyInto(out *MgrSpec) {
+func (in *ReplicatedSpec) DeepCopyInto(out *ReplicatedSpec) {
 	*out = *in
-	if in.Modules != nil {
-		in, out := &in.Modules, &out.Modules
-		*out = make([]Module, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MgrSpec.
-func (in *MgrSpec) DeepCopy() *MgrSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicatedSpec.
+func (in *ReplicatedSpec) DeepCopy() *ReplicatedSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MgrSpec)
+	out := new(ReplicatedSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Module) DeepCopyInto(out *Module) {
+func (in *RetiredObjectUserKey) DeepCopyInto(out *RetiredObjectUserKey) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Module.
-func (in *Module) DeepCopy() *Module {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetiredObjectUserKey.
+func (in *RetiredObjectUserKey) DeepCopy() *RetiredObjectUserKey {
 	if in == nil {
 		return nil
 	}
-	out := new(Module)
+	out := new(RetiredObjectUserKey)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonSpec) DeepCopyInto(out *MonSpec) {
+func (in *SecuritySpec) DeepCopyInto(out *SecuritySpec) {
 	*out = *in
-	if in.VolumeClaimTemplate != nil {
-		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
-		*out = new(corev1.PersistentVolumeClaim)
-		(*in).DeepCopyInto(*out)
-	}
+	in.KeyManagementService.DeepCopyInto(&out.KeyManagementService)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonSpec.
-func (in *MonSpec) DeepCopy() *MonSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecuritySpec.
+func (in *SecuritySpec) DeepCopy() *SecuritySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MonSpec)
+	out := new(SecuritySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+func (in *ServiceMonitorsSpec) DeepCopyInto(out *ServiceMonitorsSpec) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
-func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMonitorsSpec.
+func (in *ServiceMonitorsSpec) DeepCopy() *ServiceMonitorsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MonitoringSpec)
+	out := new(ServiceMonitorsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NFSGaneshaSpec) DeepCopyInto(out *NFSGaneshaSpec) {
+func (in *SnapshotScheduleRetentionSpec) DeepCopyInto(out *SnapshotScheduleRetentionSpec) {
 	*out = *in
-	out.RADOS = in.RADOS
-	in.Server.DeepCopyInto(&out.Server)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NFSGaneshaSpec.
-func (in *NFSGaneshaSpec) DeepCopy() *NFSGaneshaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotScheduleRetentionSpec.
+func (in *SnapshotScheduleRetentionSpec) DeepCopy() *SnapshotScheduleRetentionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NFSGaneshaSpec)
+	out := new(SnapshotScheduleRetentionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+func (in *SnapshotScheduleSpec) DeepCopyInto(out *SnapshotScheduleSpec) {
 	*out = *in
-	in.NetworkSpec.DeepCopyInto(&out.NetworkSpec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
-func (in *NetworkSpec) DeepCopy() *NetworkSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotScheduleSpec.
+func (in *SnapshotScheduleSpec) DeepCopy() *SnapshotScheduleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkSpec)
+	out := new(SnapshotScheduleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectRealmSpec) DeepCopyInto(out *ObjectRealmSpec) {
+func (in *Status) DeepCopyInto(out *Status) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRealmSpec.
-func (in *ObjectRealmSpec) DeepCopy() *ObjectRealmSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
+func (in *Status) DeepCopy() *Status {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectRealmSpec)
+	out := new(Status)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectStoreSpec) DeepCopyInto(out *ObjectStoreSpec) {
+func (in *StretchClusterSpec) DeepCopyInto(out *StretchClusterSpec) {
 	*out = *in
-	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
-	in.DataPool.DeepCopyInto(&out.DataPool)
-	in.Gateway.DeepCopyInto(&out.Gateway)
-	out.Zone = in.Zone
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]StretchClusterZoneSpec, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreSpec.
-func (in *ObjectStoreSpec) DeepCopy() *ObjectStoreSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StretchClusterSpec.
+func (in *StretchClusterSpec) DeepCopy() *StretchClusterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectStoreSpec)
+	out := new(StretchClusterSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectStoreUserSpec) DeepCopyInto(out *ObjectStoreUserSpec) {
+func (in *StretchClusterZoneSpec) DeepCopyInto(out *StretchClusterZoneSpec) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreUserSpec.
-func (in *ObjectStoreUserSpec) DeepCopy() *ObjectStoreUserSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StretchClusterZoneSpec.
+func (in *StretchClusterZoneSpec) DeepCopy() *StretchClusterZoneSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectStoreUserSpec)
+	out := new(StretchClusterZoneSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectZoneGroupSpec) DeepCopyInto(out *ObjectZoneGroupSpec) {
+func (in *SubVolumeGroupPinning) DeepCopyInto(out *SubVolumeGroupPinning) {
 	*out = *in
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(int)
+		**out = **in
+	}
+	if in.Distributed != nil {
+		in, out := &in.Distributed, &out.Distributed
+		*out = new(int)
+		**out = **in
+	}
+	if in.Random != nil {
+		in, out := &in.Random, &out.Random
+		*out = new(float64)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectZoneGroupSpec.
-func (in *ObjectZoneGroupSpec) DeepCopy() *ObjectZoneGroupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubVolumeGroupPinning.
+func (in *SubVolumeGroupPinning) DeepCopy() *SubVolumeGroupPinning {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectZoneGroupSpec)
+	out := new(SubVolumeGroupPinning)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectZoneSpec) DeepCopyInto(out *ObjectZoneSpec) {
+func (in *SyncProgress) DeepCopyInto(out *SyncProgress) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectZoneSpec.
-func (in *ObjectZoneSpec) DeepCopy() *ObjectZoneSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncProgress.
+func (in *SyncProgress) DeepCopy() *SyncProgress {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectZoneSpec)
+	out := new(SyncProgress)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
+func (in *TopicEndpointSpec) DeepCopyInto(out *TopicEndpointSpec) {
 	*out = *in
-	out.Replicated = in.Replicated
-	out.ErasureCoded = in.ErasureCoded
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPEndpointSpec)
+		**out = **in
 	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolSpec.
-func (in *PoolSpec) DeepCopy() *PoolSpec {
-	if in == nil {
-		return nil
+	if in.AMQP != nil {
+		in, out := &in.AMQP, &out.AMQP
+		*out = new(AMQPEndpointSpec)
+		**out = **in
 	}
-	out := new(PoolSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RBDMirroringSpec) DeepCopyInto(out *RBDMirroringSpec) {
-	*out = *in
-	in.Placement.DeepCopyInto(&out.Placement)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(rookiov1.Annotations, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(KafkaEndpointSpec)
+		**out = **in
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBDMirroringSpec.
-func (in *RBDMirroringSpec) DeepCopy() *RBDMirroringSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicEndpointSpec.
+func (in *TopicEndpointSpec) DeepCopy() *TopicEndpointSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RBDMirroringSpec)
+	out := new(TopicEndpointSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicatedSpec) DeepCopyInto(out *ReplicatedSpec) {
+func (in *UpgradeStrategySpec) DeepCopyInto(out *UpgradeStrategySpec) {
 	*out = *in
+	out.OSD = in.OSD
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicatedSpec.
-func (in *ReplicatedSpec) DeepCopy() *ReplicatedSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStrategySpec.
+func (in *UpgradeStrategySpec) DeepCopy() *UpgradeStrategySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicatedSpec)
+	out := new(UpgradeStrategySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Status) DeepCopyInto(out *Status) {
+func (in *ZoneSpec) DeepCopyInto(out *ZoneSpec) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
-func (in *Status) DeepCopy() *Status {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneSpec.
+func (in *ZoneSpec) DeepCopy() *ZoneSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Status)
+	out := new(ZoneSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ZoneSpec) DeepCopyInto(out *ZoneSpec) {
+func (in *ZoneSyncStatus) DeepCopyInto(out *ZoneSyncStatus) {
 	*out = *in
+	out.MetadataSync = in.MetadataSync
+	if in.DataSync != nil {
+		in, out := &in.DataSync, &out.DataSync
+		*out = make([]DataSyncProgress, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneSpec.
-func (in *ZoneSpec) DeepCopy() *ZoneSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneSyncStatus.
+func (in *ZoneSyncStatus) DeepCopy() *ZoneSyncStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ZoneSpec)
+	out := new(ZoneSyncStatus)
 	in.DeepCopyInto(out)
 	return out
 }
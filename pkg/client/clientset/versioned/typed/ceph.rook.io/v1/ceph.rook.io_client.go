@@ -27,10 +27,15 @@ import (
 type CephV1Interface interface {
 	RESTClient() rest.Interface
 	CephBlockPoolsGetter
+	CephBucketNotificationsGetter
+	CephBucketTopicsGetter
 	CephClientsGetter
 	CephClustersGetter
 	CephFilesystemsGetter
+	CephFilesystemMirrorsGetter
+	CephFilesystemSubVolumeGroupsGetter
 	CephNFSesGetter
+	CephOSDRemovalsGetter
 	CephObjectRealmsGetter
 	CephObjectStoresGetter
 	CephObjectStoreUsersGetter
@@ -48,6 +53,14 @@ func (c *CephV1Client) CephBlockPools(namespace string) CephBlockPoolInterface {
 	return newCephBlockPools(c, namespace)
 }
 
+func (c *CephV1Client) CephBucketNotifications(namespace string) CephBucketNotificationInterface {
+	return newCephBucketNotifications(c, namespace)
+}
+
+func (c *CephV1Client) CephBucketTopics(namespace string) CephBucketTopicInterface {
+	return newCephBucketTopics(c, namespace)
+}
+
 func (c *CephV1Client) CephClients(namespace string) CephClientInterface {
 	return newCephClients(c, namespace)
 }
@@ -60,10 +73,22 @@ func (c *CephV1Client) CephFilesystems(namespace string) CephFilesystemInterface
 	return newCephFilesystems(c, namespace)
 }
 
+func (c *CephV1Client) CephFilesystemMirrors(namespace string) CephFilesystemMirrorInterface {
+	return newCephFilesystemMirrors(c, namespace)
+}
+
+func (c *CephV1Client) CephFilesystemSubVolumeGroups(namespace string) CephFilesystemSubVolumeGroupInterface {
+	return newCephFilesystemSubVolumeGroups(c, namespace)
+}
+
 func (c *CephV1Client) CephNFSes(namespace string) CephNFSInterface {
 	return newCephNFSes(c, namespace)
 }
 
+func (c *CephV1Client) CephOSDRemovals(namespace string) CephOSDRemovalInterface {
+	return newCephOSDRemovals(c, namespace)
+}
+
 func (c *CephV1Client) CephObjectRealms(namespace string) CephObjectRealmInterface {
 	return newCephObjectRealms(c, namespace)
 }
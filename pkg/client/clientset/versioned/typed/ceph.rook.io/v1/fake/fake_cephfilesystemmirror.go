@@ -0,0 +1,128 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	cephrookiov1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCephFilesystemMirrors implements CephFilesystemMirrorInterface
+type FakeCephFilesystemMirrors struct {
+	Fake *FakeCephV1
+	ns   string
+}
+
+var cephfilesystemmirrorsResource = schema.GroupVersionResource{Group: "ceph.rook.io", Version: "v1", Resource: "cephfilesystemmirrors"}
+
+var cephfilesystemmirrorsKind = schema.GroupVersionKind{Group: "ceph.rook.io", Version: "v1", Kind: "CephFilesystemMirror"}
+
+// Get takes name of the cephFilesystemMirror, and returns the corresponding cephFilesystemMirror object, and an error if there is any.
+func (c *FakeCephFilesystemMirrors) Get(name string, options v1.GetOptions) (result *cephrookiov1.CephFilesystemMirror, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cephfilesystemmirrorsResource, c.ns, name), &cephrookiov1.CephFilesystemMirror{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephFilesystemMirror), err
+}
+
+// List takes label and field selectors, and returns the list of CephFilesystemMirrors that match those selectors.
+func (c *FakeCephFilesystemMirrors) List(opts v1.ListOptions) (result *cephrookiov1.CephFilesystemMirrorList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cephfilesystemmirrorsResource, cephfilesystemmirrorsKind, c.ns, opts), &cephrookiov1.CephFilesystemMirrorList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &cephrookiov1.CephFilesystemMirrorList{ListMeta: obj.(*cephrookiov1.CephFilesystemMirrorList).ListMeta}
+	for _, item := range obj.(*cephrookiov1.CephFilesystemMirrorList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cephFilesystemMirrors.
+func (c *FakeCephFilesystemMirrors) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cephfilesystemmirrorsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cephFilesystemMirror and creates it.  Returns the server's representation of the cephFilesystemMirror, and an error, if there is any.
+func (c *FakeCephFilesystemMirrors) Create(cephFilesystemMirror *cephrookiov1.CephFilesystemMirror) (result *cephrookiov1.CephFilesystemMirror, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cephfilesystemmirrorsResource, c.ns, cephFilesystemMirror), &cephrookiov1.CephFilesystemMirror{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephFilesystemMirror), err
+}
+
+// Update takes the representation of a cephFilesystemMirror and updates it. Returns the server's representation of the cephFilesystemMirror, and an error, if there is any.
+func (c *FakeCephFilesystemMirrors) Update(cephFilesystemMirror *cephrookiov1.CephFilesystemMirror) (result *cephrookiov1.CephFilesystemMirror, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cephfilesystemmirrorsResource, c.ns, cephFilesystemMirror), &cephrookiov1.CephFilesystemMirror{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephFilesystemMirror), err
+}
+
+// Delete takes name of the cephFilesystemMirror and deletes it. Returns an error if one occurs.
+func (c *FakeCephFilesystemMirrors) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cephfilesystemmirrorsResource, c.ns, name), &cephrookiov1.CephFilesystemMirror{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCephFilesystemMirrors) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cephfilesystemmirrorsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &cephrookiov1.CephFilesystemMirrorList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cephFilesystemMirror.
+func (c *FakeCephFilesystemMirrors) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *cephrookiov1.CephFilesystemMirror, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cephfilesystemmirrorsResource, c.ns, name, pt, data, subresources...), &cephrookiov1.CephFilesystemMirror{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephFilesystemMirror), err
+}
@@ -0,0 +1,128 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	cephrookiov1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCephOSDRemovals implements CephOSDRemovalInterface
+type FakeCephOSDRemovals struct {
+	Fake *FakeCephV1
+	ns   string
+}
+
+var cephosdremovalsResource = schema.GroupVersionResource{Group: "ceph.rook.io", Version: "v1", Resource: "cephosdremovals"}
+
+var cephosdremovalsKind = schema.GroupVersionKind{Group: "ceph.rook.io", Version: "v1", Kind: "CephOSDRemoval"}
+
+// Get takes name of the cephOSDRemoval, and returns the corresponding cephOSDRemoval object, and an error if there is any.
+func (c *FakeCephOSDRemovals) Get(name string, options v1.GetOptions) (result *cephrookiov1.CephOSDRemoval, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cephosdremovalsResource, c.ns, name), &cephrookiov1.CephOSDRemoval{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephOSDRemoval), err
+}
+
+// List takes label and field selectors, and returns the list of CephOSDRemovals that match those selectors.
+func (c *FakeCephOSDRemovals) List(opts v1.ListOptions) (result *cephrookiov1.CephOSDRemovalList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cephosdremovalsResource, cephosdremovalsKind, c.ns, opts), &cephrookiov1.CephOSDRemovalList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &cephrookiov1.CephOSDRemovalList{ListMeta: obj.(*cephrookiov1.CephOSDRemovalList).ListMeta}
+	for _, item := range obj.(*cephrookiov1.CephOSDRemovalList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cephOSDRemovals.
+func (c *FakeCephOSDRemovals) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cephosdremovalsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cephOSDRemoval and creates it.  Returns the server's representation of the cephOSDRemoval, and an error, if there is any.
+func (c *FakeCephOSDRemovals) Create(cephOSDRemoval *cephrookiov1.CephOSDRemoval) (result *cephrookiov1.CephOSDRemoval, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cephosdremovalsResource, c.ns, cephOSDRemoval), &cephrookiov1.CephOSDRemoval{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephOSDRemoval), err
+}
+
+// Update takes the representation of a cephOSDRemoval and updates it. Returns the server's representation of the cephOSDRemoval, and an error, if there is any.
+func (c *FakeCephOSDRemovals) Update(cephOSDRemoval *cephrookiov1.CephOSDRemoval) (result *cephrookiov1.CephOSDRemoval, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cephosdremovalsResource, c.ns, cephOSDRemoval), &cephrookiov1.CephOSDRemoval{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephOSDRemoval), err
+}
+
+// Delete takes name of the cephOSDRemoval and deletes it. Returns an error if one occurs.
+func (c *FakeCephOSDRemovals) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cephosdremovalsResource, c.ns, name), &cephrookiov1.CephOSDRemoval{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCephOSDRemovals) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cephosdremovalsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &cephrookiov1.CephOSDRemovalList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cephOSDRemoval.
+func (c *FakeCephOSDRemovals) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *cephrookiov1.CephOSDRemoval, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cephosdremovalsResource, c.ns, name, pt, data, subresources...), &cephrookiov1.CephOSDRemoval{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephOSDRemoval), err
+}
@@ -20,14 +20,24 @@ package v1
 
 type CephBlockPoolExpansion interface{}
 
+type CephBucketNotificationExpansion interface{}
+
+type CephBucketTopicExpansion interface{}
+
 type CephClientExpansion interface{}
 
 type CephClusterExpansion interface{}
 
 type CephFilesystemExpansion interface{}
 
+type CephFilesystemMirrorExpansion interface{}
+
+type CephFilesystemSubVolumeGroupExpansion interface{}
+
 type CephNFSExpansion interface{}
 
+type CephOSDRemovalExpansion interface{}
+
 type CephObjectRealmExpansion interface{}
 
 type CephObjectStoreExpansion interface{}
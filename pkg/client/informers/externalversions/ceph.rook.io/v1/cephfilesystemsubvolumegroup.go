@@ -0,0 +1,89 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	time "time"
+
+	cephrookiov1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	versioned "github.com/rook/rook/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/rook/rook/pkg/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/rook/rook/pkg/client/listers/ceph.rook.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// CephFilesystemSubVolumeGroupInformer provides access to a shared informer and lister for
+// CephFilesystemSubVolumeGroups.
+type CephFilesystemSubVolumeGroupInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.CephFilesystemSubVolumeGroupLister
+}
+
+type cephFilesystemSubVolumeGroupInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewCephFilesystemSubVolumeGroupInformer constructs a new informer for CephFilesystemSubVolumeGroup type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewCephFilesystemSubVolumeGroupInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredCephFilesystemSubVolumeGroupInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredCephFilesystemSubVolumeGroupInformer constructs a new informer for CephFilesystemSubVolumeGroup type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredCephFilesystemSubVolumeGroupInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CephV1().CephFilesystemSubVolumeGroups(namespace).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CephV1().CephFilesystemSubVolumeGroups(namespace).Watch(options)
+			},
+		},
+		&cephrookiov1.CephFilesystemSubVolumeGroup{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *cephFilesystemSubVolumeGroupInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredCephFilesystemSubVolumeGroupInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *cephFilesystemSubVolumeGroupInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&cephrookiov1.CephFilesystemSubVolumeGroup{}, f.defaultInformer)
+}
+
+func (f *cephFilesystemSubVolumeGroupInformer) Lister() v1.CephFilesystemSubVolumeGroupLister {
+	return v1.NewCephFilesystemSubVolumeGroupLister(f.Informer().GetIndexer())
+}
@@ -26,14 +26,24 @@ import (
 type Interface interface {
 	// CephBlockPools returns a CephBlockPoolInformer.
 	CephBlockPools() CephBlockPoolInformer
+	// CephBucketNotifications returns a CephBucketNotificationInformer.
+	CephBucketNotifications() CephBucketNotificationInformer
+	// CephBucketTopics returns a CephBucketTopicInformer.
+	CephBucketTopics() CephBucketTopicInformer
 	// CephClients returns a CephClientInformer.
 	CephClients() CephClientInformer
 	// CephClusters returns a CephClusterInformer.
 	CephClusters() CephClusterInformer
 	// CephFilesystems returns a CephFilesystemInformer.
 	CephFilesystems() CephFilesystemInformer
+	// CephFilesystemMirrors returns a CephFilesystemMirrorInformer.
+	CephFilesystemMirrors() CephFilesystemMirrorInformer
+	// CephFilesystemSubVolumeGroups returns a CephFilesystemSubVolumeGroupInformer.
+	CephFilesystemSubVolumeGroups() CephFilesystemSubVolumeGroupInformer
 	// CephNFSes returns a CephNFSInformer.
 	CephNFSes() CephNFSInformer
+	// CephOSDRemovals returns a CephOSDRemovalInformer.
+	CephOSDRemovals() CephOSDRemovalInformer
 	// CephObjectRealms returns a CephObjectRealmInformer.
 	CephObjectRealms() CephObjectRealmInformer
 	// CephObjectStores returns a CephObjectStoreInformer.
@@ -64,6 +74,16 @@ func (v *version) CephBlockPools() CephBlockPoolInformer {
 	return &cephBlockPoolInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephBucketNotifications returns a CephBucketNotificationInformer.
+func (v *version) CephBucketNotifications() CephBucketNotificationInformer {
+	return &cephBucketNotificationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// CephBucketTopics returns a CephBucketTopicInformer.
+func (v *version) CephBucketTopics() CephBucketTopicInformer {
+	return &cephBucketTopicInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephClients returns a CephClientInformer.
 func (v *version) CephClients() CephClientInformer {
 	return &cephClientInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -79,11 +99,26 @@ func (v *version) CephFilesystems() CephFilesystemInformer {
 	return &cephFilesystemInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephFilesystemMirrors returns a CephFilesystemMirrorInformer.
+func (v *version) CephFilesystemMirrors() CephFilesystemMirrorInformer {
+	return &cephFilesystemMirrorInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// CephFilesystemSubVolumeGroups returns a CephFilesystemSubVolumeGroupInformer.
+func (v *version) CephFilesystemSubVolumeGroups() CephFilesystemSubVolumeGroupInformer {
+	return &cephFilesystemSubVolumeGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephNFSes returns a CephNFSInformer.
 func (v *version) CephNFSes() CephNFSInformer {
 	return &cephNFSInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephOSDRemovals returns a CephOSDRemovalInformer.
+func (v *version) CephOSDRemovals() CephOSDRemovalInformer {
+	return &cephOSDRemovalInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephObjectRealms returns a CephObjectRealmInformer.
 func (v *version) CephObjectRealms() CephObjectRealmInformer {
 	return &cephObjectRealmInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
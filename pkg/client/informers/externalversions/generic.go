@@ -66,14 +66,24 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		// Group=ceph.rook.io, Version=v1
 	case v1.SchemeGroupVersion.WithResource("cephblockpools"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephBlockPools().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cephbucketnotifications"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephBucketNotifications().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cephbuckettopics"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephBucketTopics().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephclients"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephClients().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephclusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephClusters().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephfilesystems"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephFilesystems().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cephfilesystemmirrors"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephFilesystemMirrors().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cephfilesystemsubvolumegroups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephFilesystemSubVolumeGroups().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephnfses"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephNFSes().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cephosdremovals"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephOSDRemovals().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephobjectrealms"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ceph().V1().CephObjectRealms().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cephobjectstores"):
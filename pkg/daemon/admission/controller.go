@@ -39,10 +39,14 @@ var (
 // admitFunc is a callback for admission controller logic. Given an AdmissionRequest, it returns an error that will be shown when the operation is rejected.
 type admitFunc func(*v1beta1.AdmissionRequest, *clusterd.Context) error
 
+// mutateFunc is a callback for mutating admission controller logic. Given an AdmissionRequest, it returns the JSON patch
+// to apply to the object, or nil if the object is not changed. The error is shown when the operation is rejected.
+type mutateFunc func(*v1beta1.AdmissionRequest, *clusterd.Context) ([]byte, error)
+
 // doServeAdmitFunc parses the HTTP request for an admission controller webhook, and -- in case of a well-formed
 // request -- delegates the admission control logic to the given admitFunc. The response body is then returned as raw
-// bytes.
-func doServeAdmitFunc(w http.ResponseWriter, r *http.Request, a *AdmissionController) ([]byte, error) {
+// bytes. If mutate is true, the request is delegated to the mutateFunc and the patch it returns is added to the response.
+func doServeAdmitFunc(w http.ResponseWriter, r *http.Request, a *AdmissionController, mutate bool) ([]byte, error) {
 	// Step 1: Request validation. Only handle POST requests with a body and json content type.
 	if r.Method != http.MethodPost {
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -73,7 +77,17 @@ func doServeAdmitFunc(w http.ResponseWriter, r *http.Request, a *AdmissionContro
 			UID: admissionReviewReq.Request.UID,
 		},
 	}
-	err = a.validator(admissionReviewReq.Request, a.context)
+	if mutate {
+		var patch []byte
+		patch, err = a.mutator(admissionReviewReq.Request, a.context)
+		if err == nil && len(patch) > 0 {
+			patchType := v1beta1.PatchTypeJSONPatch
+			admissionReviewResponse.Response.Patch = patch
+			admissionReviewResponse.Response.PatchType = &patchType
+		}
+	} else {
+		err = a.validator(admissionReviewReq.Request, a.context)
+	}
 	if err != nil {
 		// If the handler returned an error, incorporate the error message into the response and deny the object
 		// creation.
@@ -93,11 +107,11 @@ func doServeAdmitFunc(w http.ResponseWriter, r *http.Request, a *AdmissionContro
 }
 
 // serveAdmitFunc is a wrapper around doServeAdmitFunc that adds error handling and logging.
-func serveAdmitFunc(w http.ResponseWriter, r *http.Request, a *AdmissionController) {
+func serveAdmitFunc(w http.ResponseWriter, r *http.Request, a *AdmissionController, mutate bool) {
 	logger.Info("handling webhook request")
 
 	var writeErr error
-	if bytes, err := doServeAdmitFunc(w, r, a); err != nil {
+	if bytes, err := doServeAdmitFunc(w, r, a, mutate); err != nil {
 		logger.Errorf("failed to handle webhook request. %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		_, writeErr = w.Write([]byte(err.Error()))
@@ -114,6 +128,13 @@ func serveAdmitFunc(w http.ResponseWriter, r *http.Request, a *AdmissionControll
 // admitFuncHandler takes an admitFunc and wraps it into a http.Handler by means of calling serveAdmitFunc.
 func admitFuncHandler(a *AdmissionController) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		serveAdmitFunc(w, r, a)
+		serveAdmitFunc(w, r, a, false)
+	})
+}
+
+// mutateFuncHandler takes a mutateFunc and wraps it into a http.Handler by means of calling serveAdmitFunc.
+func mutateFuncHandler(a *AdmissionController) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveAdmitFunc(w, r, a, true)
 	})
 }
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// patchOperation is an operation of a JSON patch (RFC 6902)
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// CreateJSONPatch returns the JSON patch turning the raw object of an admission request into the mutated object.
+// The original object is the raw object decoded into its type, the fields of the raw object unknown to the type or
+// set to their empty value are only changed if the mutated object sets them. It returns nil if nothing changed.
+func CreateJSONPatch(raw []byte, original, mutated interface{}) ([]byte, error) {
+	var rawObj, base, target interface{}
+	if err := json.Unmarshal(raw, &rawObj); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal raw object")
+	}
+	if err := roundTrip(original, &base); err != nil {
+		return nil, errors.Wrap(err, "failed to convert original object")
+	}
+	if err := roundTrip(mutated, &target); err != nil {
+		return nil, errors.Wrap(err, "failed to convert mutated object")
+	}
+
+	baseMap, ok1 := base.(map[string]interface{})
+	targetMap, ok2 := target.(map[string]interface{})
+	rawMap, ok3 := rawObj.(map[string]interface{})
+	if !ok1 || !ok2 || !ok3 {
+		return nil, errors.New("the objects to patch must be JSON objects")
+	}
+
+	operations := []patchOperation{}
+	if err := diffObjects("", baseMap, targetMap, rawMap, &operations); err != nil {
+		return nil, err
+	}
+	if len(operations) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(operations)
+}
+
+// diffObjects adds the operations changing the base object into the target object. The raw object tells whether the
+// fields exist in the object to patch.
+func diffObjects(path string, base, target, raw map[string]interface{}, operations *[]patchOperation) error {
+	keys := make([]string, 0, len(target))
+	for key := range target {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		targetValue := target[key]
+		baseValue, inBase := base[key]
+		if inBase && reflect.DeepEqual(baseValue, targetValue) {
+			continue
+		}
+		fieldPath := path + "/" + escapePathKey(key)
+		rawValue, inRaw := raw[key]
+
+		// only the changed fields of the objects existing in the raw object are patched
+		targetChild, targetIsMap := targetValue.(map[string]interface{})
+		rawChild, rawIsMap := rawValue.(map[string]interface{})
+		if targetIsMap && rawIsMap {
+			baseChild, _ := baseValue.(map[string]interface{})
+			if err := diffObjects(fieldPath, baseChild, targetChild, rawChild, operations); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err := json.Marshal(targetValue)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal %q", fieldPath)
+		}
+		op := "add"
+		if inRaw {
+			op = "replace"
+		}
+		*operations = append(*operations, patchOperation{Op: op, Path: fieldPath, Value: value})
+	}
+
+	removed := []string{}
+	for key := range base {
+		if _, ok := target[key]; ok {
+			continue
+		}
+		if _, ok := raw[key]; ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		*operations = append(*operations, patchOperation{Op: "remove", Path: path + "/" + escapePathKey(key)})
+	}
+	return nil
+}
+
+// roundTrip converts an object to its generic JSON representation
+func roundTrip(obj interface{}, out *interface{}) error {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// escapePathKey escapes a key of a JSON pointer (RFC 6901)
+func escapePathKey(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
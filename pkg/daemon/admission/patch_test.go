@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSpec struct {
+	Count    int               `json:"count,omitempty"`
+	Port     int               `json:"port"`
+	Name     string            `json:"name,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Nested   testNested        `json:"nested"`
+	Previous []string          `json:"previous,omitempty"`
+}
+
+type testNested struct {
+	Domain string `json:"domain,omitempty"`
+}
+
+type testObject struct {
+	Spec testSpec `json:"spec"`
+}
+
+func TestCreateJSONPatch(t *testing.T) {
+	raw := []byte(`{"spec":{"count":0,"unknown":"foo","previous":["a"],"labels":{"a/b":"c"}}}`)
+	original := &testObject{}
+	assert.NoError(t, json.Unmarshal(raw, original))
+
+	// nothing changed
+	patch, err := CreateJSONPatch(raw, original, original)
+	assert.NoError(t, err)
+	assert.Nil(t, patch)
+
+	mutated := &testObject{}
+	assert.NoError(t, json.Unmarshal(raw, mutated))
+	mutated.Spec.Count = 3
+	mutated.Spec.Port = 80
+	mutated.Spec.Nested.Domain = "host"
+	mutated.Spec.Labels["a/b"] = "d"
+	mutated.Spec.Previous = nil
+	patch, err = CreateJSONPatch(raw, original, mutated)
+	assert.NoError(t, err)
+	// the fields set in the raw object are replaced, the missing fields are added and the unknown fields are kept
+	expected := `[` +
+		`{"op":"replace","path":"/spec/count","value":3},` +
+		`{"op":"replace","path":"/spec/labels/a~1b","value":"d"},` +
+		`{"op":"add","path":"/spec/nested","value":{"domain":"host"}},` +
+		`{"op":"add","path":"/spec/port","value":80},` +
+		`{"op":"remove","path":"/spec/previous"}]`
+	assert.Equal(t, expected, string(patch))
+}
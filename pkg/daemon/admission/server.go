@@ -35,19 +35,23 @@ const (
 var (
 	logger       = capnslog.NewPackageLogger("github.com/rook/rook", "admission")
 	validatePath = "/validate"
+	mutatePath   = "/mutate"
 )
 
 type AdmissionController struct {
 	context      *clusterd.Context
 	providerName string
 	validator    admitFunc
+	mutator      mutateFunc
 }
 
-func New(context *clusterd.Context, providerName string, validator admitFunc) *AdmissionController {
+// New creates an admission controller. The mutator is optional, the resources are only validated if it is nil.
+func New(context *clusterd.Context, providerName string, validator admitFunc, mutator mutateFunc) *AdmissionController {
 	return &AdmissionController{
 		context:      context,
 		providerName: providerName,
 		validator:    validator,
+		mutator:      mutator,
 	}
 }
 
@@ -63,6 +67,9 @@ func (a *AdmissionController) StartServer() {
 
 	mux := http.NewServeMux()
 	mux.Handle(validatePath, admitFuncHandler(a))
+	if a.mutator != nil {
+		mux.Handle(mutatePath, mutateFuncHandler(a))
+	}
 
 	var httpServer *http.Server
 	httpServer = &http.Server{
@@ -19,17 +19,32 @@ Original code can be found at https://github.com/openshift/multus-admission-cont
 package admission
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
-	"github.com/pkg/errors"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
+// the interval to check if the certificates mounted from the secret were rotated
+var certificateReloadInterval = time.Minute
+
 type tlsKeypairReloader struct {
 	certMutex sync.RWMutex
 	cert      *tls.Certificate
+	certPEM   []byte
 	certPath  string
 	keyPath   string
 }
@@ -39,13 +54,33 @@ func (keyPair *tlsKeypairReloader) maybeReload() error {
 	if err != nil {
 		return errors.Wrap(err, "failed to load the certificate key pair")
 	}
+	certPEM, err := ioutil.ReadFile(keyPair.certPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the certificate")
+	}
 	logger.Info("certificate reloaded")
 	keyPair.certMutex.Lock()
 	defer keyPair.certMutex.Unlock()
 	keyPair.cert = &newCert
+	keyPair.certPEM = certPEM
 	return nil
 }
 
+// reloadIfChanged reloads the key pair if the certificate file changed since it was loaded
+func (keyPair *tlsKeypairReloader) reloadIfChanged() error {
+	certPEM, err := ioutil.ReadFile(keyPair.certPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the certificate")
+	}
+	keyPair.certMutex.RLock()
+	changed := !bytes.Equal(certPEM, keyPair.certPEM)
+	keyPair.certMutex.RUnlock()
+	if !changed {
+		return nil
+	}
+	return keyPair.maybeReload()
+}
+
 // GetCertificateFunc will fetch the tls certificate
 func (keyPair *tlsKeypairReloader) GetCertificateFunc() func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
 	return func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
@@ -61,11 +96,9 @@ func NewTlsKeypairReloader(certPath, keyPath string) (*tlsKeypairReloader, error
 		certPath: certPath,
 		keyPath:  keyPath,
 	}
-	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to load the certificate key pair")
+	if err := result.maybeReload(); err != nil {
+		return nil, err
 	}
-	result.cert = &cert
 
 	go func() {
 		c := make(chan os.Signal, 1)
@@ -76,5 +109,203 @@ func NewTlsKeypairReloader(certPath, keyPath string) (*tlsKeypairReloader, error
 			}
 		}
 	}()
+
+	// the certificates generated by the operator are rotated in the mounted secret
+	go func() {
+		for range time.Tick(certificateReloadInterval) {
+			if err := result.reloadIfChanged(); err != nil {
+				logger.Errorf("failed to reload certificate. %v", err)
+			}
+		}
+	}()
 	return result, nil
 }
+
+// GenerateCA creates a self-signed certificate authority to sign the serving certificates generated by the operator
+func GenerateCA(commonName string, validity time.Duration) ([]byte, []byte, error) {
+	template, err := newCertificateTemplate(commonName, validity)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate the key of the certificate authority")
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create the certificate authority")
+	}
+	return encodeCertificate(der, key)
+}
+
+// GenerateServingCertificate creates the certificate of a server for the DNS names of its service, signed
+// by a certificate authority
+func GenerateServingCertificate(caCertPEM, caKeyPEM []byte, dnsNames []string, validity time.Duration) ([]byte, []byte, error) {
+	ca, err := tls.X509KeyPair(caCertPEM, caKeyPEM)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to load the certificate authority")
+	}
+	caCert, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse the certificate authority")
+	}
+
+	template, err := newCertificateTemplate(dnsNames[0], validity)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.DNSNames = dnsNames
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate the key of the serving certificate")
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, key.Public(), ca.PrivateKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create the serving certificate")
+	}
+	return encodeCertificate(der, key)
+}
+
+// CertificateExpiration returns the time the first certificate of a PEM bundle expires
+func CertificateExpiration(certPEM []byte) (time.Time, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return time.Time{}, errors.New("failed to decode the certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to parse the certificate")
+	}
+	return cert.NotAfter, nil
+}
+
+func newCertificateTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate the serial number of the certificate")
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		// tolerate the clock skew between the nodes
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(validity),
+	}, nil
+}
+
+func encodeCertificate(der []byte, key *ecdsa.PrivateKey) ([]byte, []byte, error) {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal the private key")
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+// The keys of the secrets holding the certificates generated by the operator
+const (
+	CACertKey         = "ca.crt"
+	CAKeyKey          = "ca.key"
+	PreviousCACertKey = "ca-previous.crt"
+	TLSCertKey        = "tls.crt"
+	TLSKeyKey         = "tls.key"
+	// the certificates are renewed when they expire in less than a fifth of their validity
+	renewBeforeExpirationRatio = 5
+)
+
+// GeneratedCertificates generates a certificate authority and a serving certificate signed by it in the data of a
+// secret, and renews them before they expire
+type GeneratedCertificates struct {
+	// CAName is the common name of the certificate authority
+	CAName string
+	// DNSNames are the names the serving certificate is valid for
+	DNSNames []string
+	// CAValidity is how long the certificate authority is valid
+	CAValidity time.Duration
+	// CertValidity is how long the serving certificate is valid
+	CertValidity time.Duration
+}
+
+// RenewIfExpiring renews the certificate authority and the serving certificate when they expire soon, and removes the
+// previous certificate authority once it expired. It returns whether the data was updated.
+func (g *GeneratedCertificates) RenewIfExpiring(data map[string][]byte, now time.Time) (bool, error) {
+	caExpiration, err := CertificateExpiration(data[CACertKey])
+	if err != nil {
+		logger.Warningf("failed to read the certificate authority %q, generating a new one. %v", g.CAName, err)
+		return true, g.Renew(data, now)
+	}
+	if caExpiration.Before(now.Add(g.CAValidity / renewBeforeExpirationRatio)) {
+		return true, g.Renew(data, now)
+	}
+
+	certExpiration, err := CertificateExpiration(data[TLSCertKey])
+	if err != nil || certExpiration.Before(now.Add(g.CertValidity/renewBeforeExpirationRatio)) {
+		cert, key, err := GenerateServingCertificate(data[CACertKey], data[CAKeyKey], g.DNSNames, g.CertValidity)
+		if err != nil {
+			return false, err
+		}
+		data[TLSCertKey] = cert
+		data[TLSKeyKey] = key
+		return true, nil
+	}
+
+	// the previous certificate authority is trusted until it expires
+	if previous, ok := data[PreviousCACertKey]; ok {
+		if expiration, err := CertificateExpiration(previous); err != nil || expiration.Before(now) {
+			delete(data, PreviousCACertKey)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Renew generates a new certificate authority and a new serving certificate. The previous certificate authority
+// remains in the CA bundle until it expires, so the clients trust both while the servers load the new certificate.
+func (g *GeneratedCertificates) Renew(data map[string][]byte, now time.Time) error {
+	caCert, caKey, err := GenerateCA(g.CAName, g.CAValidity)
+	if err != nil {
+		return err
+	}
+	cert, key, err := GenerateServingCertificate(caCert, caKey, g.DNSNames, g.CertValidity)
+	if err != nil {
+		return err
+	}
+
+	if previous, ok := data[CACertKey]; ok {
+		if expiration, err := CertificateExpiration(previous); err == nil && expiration.After(now) {
+			data[PreviousCACertKey] = previous
+		}
+	}
+	data[CACertKey] = caCert
+	data[CAKeyKey] = caKey
+	data[TLSCertKey] = cert
+	data[TLSKeyKey] = key
+	return nil
+}
+
+// NextRenewal returns the time the certificates must be checked again to renew them or to remove the previous
+// certificate authority
+func (g *GeneratedCertificates) NextRenewal(data map[string][]byte, now time.Time) time.Time {
+	next := now.Add(g.CertValidity / renewBeforeExpirationRatio)
+	if expiration, err := CertificateExpiration(data[TLSCertKey]); err == nil {
+		next = expiration.Add(-g.CertValidity / renewBeforeExpirationRatio)
+	}
+	if expiration, err := CertificateExpiration(data[PreviousCACertKey]); err == nil && expiration.Before(next) {
+		next = expiration
+	}
+	return next
+}
+
+// CABundle returns the current and the previous certificate authorities of the generated certificates
+func CABundle(data map[string][]byte) []byte {
+	return bytes.Join([][]byte{data[CACertKey], data[PreviousCACertKey]}, nil)
+}
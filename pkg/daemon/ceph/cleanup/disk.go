@@ -23,6 +23,7 @@ import (
 	"sync"
 
 	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/osd"
 	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
@@ -45,36 +46,67 @@ type DiskSanitizer struct {
 	context     *clusterd.Context
 	clusterName string
 	clusterFSID string
+	// osdIDs restricts the sanitizing to the disks of these OSDs, all the OSDs of the cluster are sanitized if empty
+	osdIDs []int
 }
 
 // NewDiskSanitizer is function that returns a full filled DiskSanitizer object
-func NewDiskSanitizer(context *clusterd.Context, clusterName, clusterFSID string) *DiskSanitizer {
+func NewDiskSanitizer(context *clusterd.Context, clusterName, clusterFSID string, osdIDs []int) *DiskSanitizer {
 	return &DiskSanitizer{
 		context:     context,
 		clusterName: clusterName,
 		clusterFSID: clusterFSID,
+		osdIDs:      osdIDs,
 	}
 }
 
-// StartSanitizeDisks main entrypoint of the cleanup package
-func StartSanitizeDisks(sanitizer *DiskSanitizer) {
+// StartSanitizeDisks main entrypoint of the cleanup package. The OSDs that fail to be listed are only skipped when
+// all the OSDs of the cluster are sanitized, the sanitizing of given OSDs fails so that it is retried.
+func StartSanitizeDisks(sanitizer *DiskSanitizer) error {
+	var listErr error
+
 	// LVM based OSDs
 	osdLVMList, err := osd.GetCephVolumeLVMOSDs(sanitizer.context, sanitizer.clusterName, sanitizer.clusterFSID, "", false, false)
 	if err != nil {
 		logger.Errorf("failed to list lvm osd(s). %v", err)
+		listErr = errors.Wrap(err, "failed to list lvm osd(s)")
 	} else {
 		// Start the sanitizing sequence
-		sanitizer.sanitizeLVMDisk(osdLVMList)
+		sanitizer.sanitizeLVMDisk(sanitizer.filterOSDs(osdLVMList))
 	}
 
 	// Raw based OSDs
 	osdRawList, err := osd.GetCephVolumeRawOSDs(sanitizer.context, sanitizer.clusterName, sanitizer.clusterFSID, "", "", false)
 	if err != nil {
 		logger.Errorf("failed to list raw osd(s). %v", err)
+		listErr = errors.Wrap(err, "failed to list raw osd(s)")
 	} else {
 		// Start the sanitizing sequence
-		sanitizer.sanitizeRawDisk(osdRawList)
+		sanitizer.sanitizeRawDisk(sanitizer.filterOSDs(osdRawList))
+	}
+
+	if len(sanitizer.osdIDs) > 0 {
+		return listErr
+	}
+	return nil
+}
+
+// filterOSDs returns the OSDs to sanitize
+func (s *DiskSanitizer) filterOSDs(osds []oposd.OSDInfo) []oposd.OSDInfo {
+	if len(s.osdIDs) == 0 {
+		return osds
+	}
+
+	filtered := []oposd.OSDInfo{}
+	for _, osd := range osds {
+		for _, id := range s.osdIDs {
+			if osd.ID == id {
+				filtered = append(filtered, osd)
+				break
+			}
+		}
 	}
+	return filtered
 }
 
 func (s *DiskSanitizer) sanitizeRawDisk(osdRawList []oposd.OSDInfo) {
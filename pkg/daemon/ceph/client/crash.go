@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// the formats of the time of the crashes, which changed between the ceph releases
+var crashTimeLayouts = []string{"2006-01-02 15:04:05.999999Z", "2006-01-02T15:04:05.999999Z", "2006-01-02 15:04:05.999999"}
+
+// CrashInfo is the metadata of a crash of a ceph daemon reported to the mgr crash module
+type CrashInfo struct {
+	ID          string   `json:"crash_id"`
+	Timestamp   string   `json:"timestamp"`
+	Entity      string   `json:"entity_name"`
+	Process     string   `json:"process_name,omitempty"`
+	Hostname    string   `json:"utsname_hostname,omitempty"`
+	CephVersion string   `json:"ceph_version,omitempty"`
+	AssertMsg   string   `json:"assert_msg,omitempty"`
+	Backtrace   []string `json:"backtrace,omitempty"`
+	// Archived is the time the crash was archived, empty if the crash is new
+	Archived string `json:"archived,omitempty"`
+}
+
+// Time returns the time of the crash
+func (c CrashInfo) Time() (time.Time, error) {
+	for _, layout := range crashTimeLayouts {
+		if t, err := time.Parse(layout, c.Timestamp); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Errorf("failed to parse time %q of crash %q", c.Timestamp, c.ID)
+}
+
+// ListCrashes lists the crashes reported to the mgr crash module, archived or not
+func ListCrashes(context *clusterd.Context, clusterName string) ([]CrashInfo, error) {
+	args := []string{"crash", "ls"}
+	output, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list crashes")
+	}
+
+	var crashes []CrashInfo
+	if err := json.Unmarshal(output, &crashes); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal crashes. %s", string(output))
+	}
+	return crashes, nil
+}
+
+// ArchiveCrash archives a crash so that it does not raise the RECENT_CRASH health warning anymore
+func ArchiveCrash(context *clusterd.Context, clusterName, crashID string) error {
+	args := []string{"crash", "archive", crashID}
+	output, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to archive crash %q. %s", crashID, string(output))
+	}
+	return nil
+}
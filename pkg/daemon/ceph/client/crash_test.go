@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListCrashes(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		if args[0] == "crash" && args[1] == "ls" {
+			return `[{"crash_id":"2020-07-13_16:38:26.196543Z_5f2c","timestamp":"2020-07-13 16:38:26.196543Z","entity_name":"osd.1",` +
+				`"backtrace":["(()+0x12dd0) [0x7f]","abort()"],"archived":"2020-07-14 10:00:00.000000"},` +
+				`{"crash_id":"2020-07-15T01:02:03.000001Z_a1b2","timestamp":"2020-07-15T01:02:03.000001Z","entity_name":"mon.a"}]`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	crashes, err := ListCrashes(&clusterd.Context{Executor: executor}, "rook-ceph")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(crashes))
+	assert.Equal(t, "osd.1", crashes[0].Entity)
+	assert.Equal(t, 2, len(crashes[0].Backtrace))
+	assert.NotEmpty(t, crashes[0].Archived)
+	assert.Empty(t, crashes[1].Archived)
+
+	// both time formats are parsed
+	crashTime, err := crashes[0].Time()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 7, 13, 16, 38, 26, 196543000, time.UTC), crashTime)
+	crashTime, err = crashes[1].Time()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 7, 15, 1, 2, 3, 1000, time.UTC), crashTime)
+
+	_, err = CrashInfo{ID: "foo", Timestamp: "yesterday"}.Time()
+	assert.Error(t, err)
+}
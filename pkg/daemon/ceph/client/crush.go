@@ -19,6 +19,9 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -27,6 +30,9 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 )
 
+// the bucket types declared in a decompiled CRUSH map
+var crushMapTypesRegex = regexp.MustCompile(`(?m)^type [0-9]+ \S+\n`)
+
 // CrushMap is the go representation of a CRUSH map
 type CrushMap struct {
 	Devices []struct {
@@ -184,3 +190,128 @@ func GetOSDOnHost(context *clusterd.Context, clusterName, node string) (string,
 
 	return string(buf), nil
 }
+
+// MoveCrushBucket moves a bucket of the CRUSH map to the given location, the missing buckets of the location are
+// created
+func MoveCrushBucket(context *clusterd.Context, clusterName, name string, location []string) error {
+	args := append([]string{"osd", "crush", "move", name}, location...)
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to move crush bucket %q to %v. %s", name, location, string(buf))
+	}
+
+	return nil
+}
+
+// SetCrushMapTypes replaces the bucket types of the CRUSH map with the given types, ordered from the lowest to the
+// highest. Ceph has no command to add a type, the CRUSH map is decompiled, updated and compiled with crushtool.
+func SetCrushMapTypes(context *clusterd.Context, clusterName string, types []string) error {
+	err := updateCrushMap(context, clusterName, func(crushMap string) (string, error) {
+		return replaceCrushMapTypes(crushMap, types)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the crush map types to %v", types)
+	}
+
+	logger.Infof("crush map types set to %v", types)
+	return nil
+}
+
+// CreateStretchCrushRule creates a replicated rule placing two copies in each of two buckets of the failure domain type
+// under the root, on different hosts, for the stretch mode of ceph. Ceph has no command to create a rule with two
+// steps, the rule is added to the decompiled CRUSH map.
+func CreateStretchCrushRule(context *clusterd.Context, clusterName, ruleName, root, failureDomain string, ruleID int) error {
+	err := updateCrushMap(context, clusterName, func(crushMap string) (string, error) {
+		return addCrushMapRule(crushMap, buildStretchCrushRule(ruleName, root, failureDomain, ruleID))
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create crush rule %q", ruleName)
+	}
+
+	logger.Infof("created crush rule %q with two copies in each %s", ruleName, failureDomain)
+	return nil
+}
+
+// updateCrushMap decompiles the CRUSH map, updates it and compiles it back with crushtool, then sets it
+func updateCrushMap(context *clusterd.Context, clusterName string, update func(crushMap string) (string, error)) error {
+	dir, err := ioutil.TempDir("", "crushmap")
+	if err != nil {
+		return errors.Wrap(err, "failed to create the crush map directory")
+	}
+	defer os.RemoveAll(dir)
+	compiledPath := path.Join(dir, "crushmap")
+	decompiledPath := path.Join(dir, "crushmap.txt")
+
+	buf, err := NewCephCommand(context, clusterName, []string{"osd", "getcrushmap"}).Run()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the crush map")
+	}
+	if err := ioutil.WriteFile(compiledPath, buf, 0600); err != nil {
+		return errors.Wrap(err, "failed to write the crush map")
+	}
+	if output, err := context.Executor.ExecuteCommandWithCombinedOutput(CrushTool, "-d", compiledPath, "-o", decompiledPath); err != nil {
+		return errors.Wrapf(err, "failed to decompile the crush map. %s", output)
+	}
+
+	crushMap, err := ioutil.ReadFile(decompiledPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the decompiled crush map")
+	}
+	updated, err := update(string(crushMap))
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(decompiledPath, []byte(updated), 0600); err != nil {
+		return errors.Wrap(err, "failed to write the decompiled crush map")
+	}
+
+	if output, err := context.Executor.ExecuteCommandWithCombinedOutput(CrushTool, "-c", decompiledPath, "-o", compiledPath); err != nil {
+		return errors.Wrapf(err, "failed to compile the crush map. %s", output)
+	}
+	buf, err = NewCephCommand(context, clusterName, []string{"osd", "setcrushmap", "-i", compiledPath}).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the crush map. %s", string(buf))
+	}
+
+	return nil
+}
+
+// buildStretchCrushRule returns the decompiled rule placing two copies in each bucket of the failure domain type
+func buildStretchCrushRule(ruleName, root, failureDomain string, ruleID int) string {
+	return fmt.Sprintf(`rule %s {
+	id %d
+	type replicated
+	min_size 1
+	max_size 10
+	step take %s
+	step choose firstn 0 type %s
+	step chooseleaf firstn 2 type host
+	step emit
+}
+`, ruleName, ruleID, root, failureDomain)
+}
+
+// addCrushMapRule adds a rule at the end of the rules of a decompiled CRUSH map
+func addCrushMapRule(crushMap, rule string) (string, error) {
+	const endMarker = "# end crush map"
+	i := strings.Index(crushMap, endMarker)
+	if i < 0 {
+		return "", errors.New("end of the crush map not found")
+	}
+	return crushMap[:i] + rule + "\n" + crushMap[i:], nil
+}
+
+// replaceCrushMapTypes replaces the type declarations of a decompiled CRUSH map
+func replaceCrushMapTypes(crushMap string, types []string) (string, error) {
+	location := crushMapTypesRegex.FindStringIndex(crushMap)
+	if location == nil {
+		return "", errors.New("no type found in the crush map")
+	}
+
+	declarations := ""
+	for i, crushType := range types {
+		declarations += fmt.Sprintf("type %d %s\n", i, crushType)
+	}
+	// the new declarations replace the first one, the others are removed
+	return crushMap[:location[0]] + declarations + crushMapTypesRegex.ReplaceAllString(crushMap[location[0]:], ""), nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+const (
+	defaultCrushRoot = "default"
+	// the separator of the shadow buckets of a device class, such as "default~ssd"
+	deviceClassShadowSeparator = "~"
+)
+
+// CrushRulePlacement is where a replicated CRUSH rule places the replicas of a pool
+type CrushRulePlacement struct {
+	Root          string
+	FailureDomain string
+	DeviceClass   string
+}
+
+// String returns a short description of the placement, such as "root=default failureDomain=host deviceClass=ssd"
+func (p CrushRulePlacement) String() string {
+	s := fmt.Sprintf("root=%s failureDomain=%s", p.Root, p.FailureDomain)
+	if p.DeviceClass != "" {
+		s += fmt.Sprintf(" deviceClass=%s", p.DeviceClass)
+	}
+	return s
+}
+
+// DesiredCrushRulePlacement returns the placement of the replicas of a pool requested by its spec
+func DesiredCrushRulePlacement(pool cephv1.PoolSpec) CrushRulePlacement {
+	placement := CrushRulePlacement{
+		Root:          pool.CrushRoot,
+		FailureDomain: pool.FailureDomain,
+		DeviceClass:   pool.DeviceClass,
+	}
+	if placement.Root == "" {
+		placement.Root = defaultCrushRoot
+	}
+	if placement.FailureDomain == "" {
+		placement.FailureDomain = cephv1.DefaultFailureDomain
+	}
+	return placement
+}
+
+// CrushRuleName returns the name of the rule of a pool for a placement. The rule created with the pool
+// is named after the pool, the rules created when the placement of the pool changes have the placement
+// in their name.
+func CrushRuleName(poolName string, placement CrushRulePlacement) string {
+	name := fmt.Sprintf("%s_%s_%s", poolName, placement.Root, placement.FailureDomain)
+	if placement.DeviceClass != "" {
+		name += "_" + placement.DeviceClass
+	}
+	return name
+}
+
+// GetCrushRulePlacement returns the name and the placement of a replicated rule of the CRUSH map
+func GetCrushRulePlacement(crushMap CrushMap, ruleID int) (string, CrushRulePlacement, error) {
+	for _, rule := range crushMap.Rules {
+		if rule.ID != ruleID {
+			continue
+		}
+
+		placement := CrushRulePlacement{}
+		for _, step := range rule.Steps {
+			switch step.Operation {
+			case "take":
+				parts := strings.SplitN(step.ItemName, deviceClassShadowSeparator, 2)
+				placement.Root = parts[0]
+				if len(parts) == 2 {
+					placement.DeviceClass = parts[1]
+				}
+			case "chooseleaf_firstn", "chooseleaf_indep", "choose_firstn", "choose_indep":
+				placement.FailureDomain = step.Type
+			}
+		}
+		if placement.Root == "" || placement.FailureDomain == "" {
+			return rule.Name, placement, errors.Errorf("failed to find the root and the failure domain of crush rule %q", rule.Name)
+		}
+		return rule.Name, placement, nil
+	}
+
+	return "", CrushRulePlacement{}, errors.Errorf("crush rule %d not found", ruleID)
+}
+
+// CountFailureDomains returns the number of buckets of the failure domain of a placement that hold
+// at least one OSD of the placement
+func CountFailureDomains(crushMap CrushMap, placement CrushRulePlacement) int {
+	root := placement.Root
+	if placement.DeviceClass != "" {
+		root += deviceClassShadowSeparator + placement.DeviceClass
+	}
+
+	buckets := map[int]int{}
+	rootID := 0
+	found := false
+	for i, bucket := range crushMap.Buckets {
+		buckets[bucket.ID] = i
+		if bucket.Name == root {
+			rootID = bucket.ID
+			found = true
+		}
+	}
+	if !found {
+		return 0
+	}
+
+	// hasOSD returns whether an item is an OSD or a bucket holding at least one OSD
+	var hasOSD func(id int) bool
+	hasOSD = func(id int) bool {
+		if id >= 0 {
+			return true
+		}
+		i, ok := buckets[id]
+		if !ok {
+			return false
+		}
+		for _, item := range crushMap.Buckets[i].Items {
+			if hasOSD(item.ID) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var count func(id int) int
+	count = func(id int) int {
+		if id >= 0 {
+			if placement.FailureDomain == "osd" {
+				return 1
+			}
+			return 0
+		}
+		i, ok := buckets[id]
+		if !ok {
+			return 0
+		}
+		bucket := crushMap.Buckets[i]
+		if bucket.TypeName == placement.FailureDomain {
+			if hasOSD(id) {
+				return 1
+			}
+			return 0
+		}
+		total := 0
+		for _, item := range bucket.Items {
+			total += count(item.ID)
+		}
+		return total
+	}
+
+	return count(rootID)
+}
+
+// DeleteCrushRule deletes a CRUSH rule. Ceph refuses to delete a rule still used by a pool.
+func DeleteCrushRule(context *clusterd.Context, namespace, ruleName string) error {
+	args := []string{"osd", "crush", "rule", "rm", ruleName}
+	output, err := NewCephCommand(context, namespace, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete crush rule %q. %s", ruleName, string(output))
+	}
+	return nil
+}
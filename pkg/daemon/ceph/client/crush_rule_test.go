@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+// a root with two racks: rack1 holds host1 (osd.0 ssd, osd.1 hdd) and host2 (osd.2 hdd), rack2 holds the empty host3
+const testRuleCrushMap = `{
+	"buckets":[
+		{"id":-1,"name":"default","type_name":"root","items":[{"id":-2},{"id":-3}]},
+		{"id":-2,"name":"rack1","type_name":"rack","items":[{"id":-4},{"id":-5}]},
+		{"id":-3,"name":"rack2","type_name":"rack","items":[{"id":-6}]},
+		{"id":-4,"name":"host1","type_name":"host","items":[{"id":0},{"id":1}]},
+		{"id":-5,"name":"host2","type_name":"host","items":[{"id":2}]},
+		{"id":-6,"name":"host3","type_name":"host","items":[]},
+		{"id":-7,"name":"default~hdd","type_name":"root","items":[{"id":-8}]},
+		{"id":-8,"name":"rack1~hdd","type_name":"rack","items":[{"id":-9},{"id":-10}]},
+		{"id":-9,"name":"host1~hdd","type_name":"host","items":[{"id":1}]},
+		{"id":-10,"name":"host2~hdd","type_name":"host","items":[{"id":2}]}
+	],
+	"rules":[
+		{"rule_id":0,"rule_name":"replicated_rule","steps":[{"op":"take","item_name":"default"},{"op":"chooseleaf_firstn","type":"host"},{"op":"emit"}]},
+		{"rule_id":1,"rule_name":"mypool_default_rack_hdd","steps":[{"op":"take","item_name":"default~hdd"},{"op":"chooseleaf_firstn","type":"rack"},{"op":"emit"}]},
+		{"rule_id":2,"rule_name":"broken","steps":[{"op":"emit"}]}
+	]}`
+
+func TestCrushRulePlacement(t *testing.T) {
+	var crushMap CrushMap
+	assert.NoError(t, json.Unmarshal([]byte(testRuleCrushMap), &crushMap))
+
+	name, placement, err := GetCrushRulePlacement(crushMap, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "replicated_rule", name)
+	assert.Equal(t, CrushRulePlacement{Root: "default", FailureDomain: "host"}, placement)
+	assert.Equal(t, DesiredCrushRulePlacement(cephv1.PoolSpec{}), placement)
+
+	name, placement, err = GetCrushRulePlacement(crushMap, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "mypool_default_rack_hdd", name)
+	assert.Equal(t, CrushRulePlacement{Root: "default", FailureDomain: "rack", DeviceClass: "hdd"}, placement)
+	assert.Equal(t, "root=default failureDomain=rack deviceClass=hdd", placement.String())
+	assert.Equal(t, name, CrushRuleName("mypool", placement))
+
+	_, _, err = GetCrushRulePlacement(crushMap, 2)
+	assert.Error(t, err)
+	_, _, err = GetCrushRulePlacement(crushMap, 3)
+	assert.Error(t, err)
+}
+
+func TestCountFailureDomains(t *testing.T) {
+	var crushMap CrushMap
+	assert.NoError(t, json.Unmarshal([]byte(testRuleCrushMap), &crushMap))
+
+	// the empty host and rack are not counted
+	assert.Equal(t, 2, CountFailureDomains(crushMap, CrushRulePlacement{Root: "default", FailureDomain: "host"}))
+	assert.Equal(t, 1, CountFailureDomains(crushMap, CrushRulePlacement{Root: "default", FailureDomain: "rack"}))
+	assert.Equal(t, 3, CountFailureDomains(crushMap, CrushRulePlacement{Root: "default", FailureDomain: "osd"}))
+	// only the buckets with OSDs of the device class are counted
+	assert.Equal(t, 2, CountFailureDomains(crushMap, CrushRulePlacement{Root: "default", FailureDomain: "host", DeviceClass: "hdd"}))
+	assert.Equal(t, 0, CountFailureDomains(crushMap, CrushRulePlacement{Root: "default", FailureDomain: "host", DeviceClass: "nvme"}))
+	assert.Equal(t, 0, CountFailureDomains(crushMap, CrushRulePlacement{Root: "other", FailureDomain: "host"}))
+}
@@ -17,6 +17,7 @@ package client
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -294,3 +295,74 @@ func TestCrushName(t *testing.T) {
 		}
 	}
 }
+
+const testDecompiledCrushMap = `# begin crush map
+tunable choose_total_tries 50
+
+# devices
+device 0 osd.0 class hdd
+
+# types
+type 0 osd
+type 1 host
+type 2 rack
+type 11 root
+
+# buckets
+host node1 {
+	id -3		# do not change unnecessarily
+	alg straw2
+	hash 0	# rjenkins1
+	item osd.0 weight 0.010
+}
+
+# rules
+rule replicated_rule {
+	id 0
+	type replicated
+	step take default
+	step chooseleaf firstn 0 type host
+	step emit
+}
+
+# end crush map
+`
+
+func TestReplaceCrushMapTypes(t *testing.T) {
+	crushMap, err := replaceCrushMapTypes(testDecompiledCrushMap, []string{"osd", "host", "rack", "hall", "root"})
+	assert.NoError(t, err)
+	assert.Contains(t, crushMap, "# types\ntype 0 osd\ntype 1 host\ntype 2 rack\ntype 3 hall\ntype 4 root\n\n# buckets\n")
+	assert.NotContains(t, crushMap, "type 11 root")
+	// the rules are not changed
+	assert.Contains(t, crushMap, "\tstep chooseleaf firstn 0 type host\n")
+	assert.Contains(t, crushMap, "\ttype replicated\n")
+
+	_, err = replaceCrushMapTypes("# begin crush map\n", []string{"osd"})
+	assert.Error(t, err)
+}
+
+func TestMoveCrushBucket(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[1] == "crush" && args[2] == "move" {
+			assert.Equal(t, []string{"node1", "hall=hall1", "root=default"}, args[3:6])
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected ceph command '%v'", args)
+	}
+
+	err := MoveCrushBucket(&clusterd.Context{Executor: executor}, "rook-ceph", "node1", []string{"hall=hall1", "root=default"})
+	assert.NoError(t, err)
+}
+
+func TestAddStretchCrushRule(t *testing.T) {
+	crushMap, err := addCrushMapRule(testDecompiledCrushMap, buildStretchCrushRule("stretch_rule", "myroot", "zone", 1))
+	assert.NoError(t, err)
+	assert.Contains(t, crushMap, "}\n\nrule stretch_rule {\n\tid 1\n")
+	assert.Contains(t, crushMap, "\tstep take myroot\n\tstep choose firstn 0 type zone\n\tstep chooseleaf firstn 2 type host\n")
+	assert.True(t, strings.HasSuffix(crushMap, "}\n\n# end crush map\n"))
+
+	_, err = addCrushMapRule("# begin crush map\n", buildStretchCrushRule("stretch_rule", "default", "zone", 1))
+	assert.Error(t, err)
+}
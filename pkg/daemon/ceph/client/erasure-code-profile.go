@@ -31,6 +31,7 @@ type CephErasureCodeProfile struct {
 	Technique        string `json:"technique"`
 	FailureDomain    string `json:"crush-failure-domain"`
 	CrushRoot        string `json:"crush-root"`
+	DeviceClass      string `json:"crush-device-class"`
 }
 
 func ListErasureCodeProfiles(context *clusterd.Context, namespace string) ([]string, error) {
@@ -99,6 +100,39 @@ func CreateErasureCodeProfile(context *clusterd.Context, namespace, profileName
 	return nil
 }
 
+// ErasureCodeProfileDrift returns the erasure code settings of a pool spec that differ from the profile of an existing
+// erasure coded pool, such as "dataChunks: 2 -> 4". The profile of a pool can't change after the pool is created.
+func ErasureCodeProfileDrift(pool cephv1.PoolSpec, profile CephErasureCodeProfile) []string {
+	var drift []string
+	if pool.ErasureCoded.DataChunks != profile.DataChunkCount {
+		drift = append(drift, fmt.Sprintf("dataChunks: %d -> %d", profile.DataChunkCount, pool.ErasureCoded.DataChunks))
+	}
+	if pool.ErasureCoded.CodingChunks != profile.CodingChunkCount {
+		drift = append(drift, fmt.Sprintf("codingChunks: %d -> %d", profile.CodingChunkCount, pool.ErasureCoded.CodingChunks))
+	}
+
+	// the settings not set in the spec are not set in the profile either, ceph applies its defaults
+	desired := DesiredCrushRulePlacement(pool)
+	current := CrushRulePlacement{Root: profile.CrushRoot, FailureDomain: profile.FailureDomain, DeviceClass: profile.DeviceClass}
+	if current.Root == "" {
+		current.Root = defaultCrushRoot
+	}
+	if current.FailureDomain == "" {
+		current.FailureDomain = cephv1.DefaultFailureDomain
+	}
+	if desired.FailureDomain != current.FailureDomain {
+		drift = append(drift, fmt.Sprintf("failureDomain: %s -> %s", current.FailureDomain, desired.FailureDomain))
+	}
+	if desired.Root != current.Root {
+		drift = append(drift, fmt.Sprintf("crushRoot: %s -> %s", current.Root, desired.Root))
+	}
+	if desired.DeviceClass != current.DeviceClass {
+		drift = append(drift, fmt.Sprintf("deviceClass: %q -> %q", current.DeviceClass, desired.DeviceClass))
+	}
+
+	return drift
+}
+
 func DeleteErasureCodeProfile(context *clusterd.Context, namespace, profileName string) error {
 	args := []string{"osd", "erasure-code-profile", "rm", profileName}
 
@@ -87,3 +87,22 @@ func testCreateProfile(t *testing.T, failureDomain, crushRoot, deviceClass strin
 	err := CreateErasureCodeProfile(context, "myns", "myapp", spec)
 	assert.Nil(t, err)
 }
+
+func TestErasureCodeProfileDrift(t *testing.T) {
+	spec := cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}
+	profile := CephErasureCodeProfile{DataChunkCount: 2, CodingChunkCount: 1, FailureDomain: "host", CrushRoot: "default"}
+	assert.Nil(t, ErasureCodeProfileDrift(spec, profile))
+
+	// the defaults of ceph match the settings not set in the spec
+	assert.Nil(t, ErasureCodeProfileDrift(spec, CephErasureCodeProfile{DataChunkCount: 2, CodingChunkCount: 1}))
+
+	spec.ErasureCoded = cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2}
+	spec.FailureDomain = "osd"
+	spec.DeviceClass = "ssd"
+	assert.Equal(t, []string{
+		"dataChunks: 2 -> 4",
+		"codingChunks: 1 -> 2",
+		"failureDomain: host -> osd",
+		`deviceClass: "" -> "ssd"`,
+	}, ErasureCodeProfileDrift(spec, profile))
+}
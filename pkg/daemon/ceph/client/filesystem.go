@@ -47,6 +47,8 @@ type CephFilesystem struct {
 type CephFilesystemDetails struct {
 	ID     int    `json:"id"`
 	MDSMap MDSMap `json:"mdsmap"`
+	// MirrorInfo is only reported when the snapshot mirroring of the filesystem is enabled
+	MirrorInfo *FilesystemMirrorInfo `json:"mirror_info,omitempty"`
 }
 
 // MDSMap is a representation of the mds map sub-structure returned by 'ceph fs get'
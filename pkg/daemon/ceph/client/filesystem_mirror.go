@@ -0,0 +1,262 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+const (
+	// FilesystemMirroringModuleName is the name of the mgr module managing the filesystem mirroring
+	FilesystemMirroringModuleName = "mirroring"
+	// SnapshotScheduleModuleName is the name of the mgr module scheduling the filesystem snapshots
+	SnapshotScheduleModuleName = "snap_schedule"
+	// FilesystemMirrorPeerUser is the user of the remote sites to access a mirrored filesystem
+	FilesystemMirrorPeerUser = "client.mirror_remote"
+)
+
+// BootstrapPeerToken is a representation of the json structure returned by 'ceph fs snapshot mirror peer_bootstrap create'
+type BootstrapPeerToken struct {
+	Token string `json:"token"`
+}
+
+// FSMirrorBootstrapPeer is a representation of the json structure encoded in a bootstrap peer token
+type FSMirrorBootstrapPeer struct {
+	SiteName       string `json:"site_name"`
+	FilesystemName string `json:"filesystem"`
+}
+
+// FilesystemMirrorInfo is a representation of the mirror info sub-structure returned by 'ceph fs get'
+type FilesystemMirrorInfo struct {
+	Peers []FilesystemMirrorPeer `json:"peers"`
+}
+
+// FilesystemMirrorPeer is a representation of a peer of a mirrored filesystem returned by 'ceph fs get'
+type FilesystemMirrorPeer struct {
+	UUID   string `json:"uuid"`
+	Remote struct {
+		ClientName  string `json:"client_name"`
+		ClusterName string `json:"cluster_name"`
+		FsName      string `json:"fs_name"`
+	} `json:"remote"`
+}
+
+// EnableFilesystemSnapshotMirror enables the snapshot mirroring of a filesystem
+func EnableFilesystemSnapshotMirror(context *clusterd.Context, clusterName, fsName string) error {
+	logger.Infof("enabling snapshot mirroring of filesystem %q", fsName)
+	args := []string{"fs", "snapshot", "mirror", "enable", fsName}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable snapshot mirroring of filesystem %q", fsName)
+	}
+
+	return nil
+}
+
+// DisableFilesystemSnapshotMirror disables the snapshot mirroring of a filesystem
+func DisableFilesystemSnapshotMirror(context *clusterd.Context, clusterName, fsName string) error {
+	logger.Infof("disabling snapshot mirroring of filesystem %q", fsName)
+	args := []string{"fs", "snapshot", "mirror", "disable", fsName}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to disable snapshot mirroring of filesystem %q", fsName)
+	}
+
+	return nil
+}
+
+// CreateFSMirrorBootstrapPeer creates the bootstrap peer token of a filesystem, for the remote sites to add the
+// local site as a peer. The site name identifies the local site on the remote sites.
+func CreateFSMirrorBootstrapPeer(context *clusterd.Context, clusterName, fsName, siteName string) (string, error) {
+	logger.Infof("creating bootstrap peer token of filesystem %q", fsName)
+	args := []string{"fs", "snapshot", "mirror", "peer_bootstrap", "create", fsName, FilesystemMirrorPeerUser, siteName}
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create bootstrap peer token of filesystem %q", fsName)
+	}
+
+	var token BootstrapPeerToken
+	err = json.Unmarshal(buf, &token)
+	if err != nil {
+		return "", errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	return token.Token, nil
+}
+
+// ImportFSMirrorBootstrapPeer adds the site of a bootstrap peer token as a peer of a filesystem. Importing an
+// existing peer is a no-op.
+func ImportFSMirrorBootstrapPeer(context *clusterd.Context, clusterName, fsName, token string) error {
+	logger.Infof("importing bootstrap peer token of filesystem %q", fsName)
+	args := []string{"fs", "snapshot", "mirror", "peer_bootstrap", "import", fsName, token}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.EEXIST) {
+			logger.Debugf("peer of filesystem %q already exists", fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to import bootstrap peer token of filesystem %q", fsName)
+	}
+
+	return nil
+}
+
+// DecodeFSMirrorBootstrapPeer returns the site and the filesystem of a bootstrap peer token
+func DecodeFSMirrorBootstrapPeer(token string) (*FSMirrorBootstrapPeer, error) {
+	decoded, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode bootstrap peer token")
+	}
+
+	var peer FSMirrorBootstrapPeer
+	if err := json.Unmarshal(decoded, &peer); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal bootstrap peer token")
+	}
+
+	return &peer, nil
+}
+
+// RemoveFSMirrorPeer removes a peer of a filesystem
+func RemoveFSMirrorPeer(context *clusterd.Context, clusterName, fsName, uuid string) error {
+	logger.Infof("removing peer %q of filesystem %q", uuid, fsName)
+	args := []string{"fs", "snapshot", "mirror", "peer_remove", fsName, uuid}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("peer %q of filesystem %q is already removed", uuid, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to remove peer %q of filesystem %q", uuid, fsName)
+	}
+
+	return nil
+}
+
+// AddFSMirrorDirectory adds a directory to the mirrored directories of a filesystem. Adding a mirrored directory
+// is a no-op.
+func AddFSMirrorDirectory(context *clusterd.Context, clusterName, fsName, path string) error {
+	logger.Infof("adding directory %q to the mirrored directories of filesystem %q", path, fsName)
+	args := []string{"fs", "snapshot", "mirror", "add", fsName, path}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.EEXIST) {
+			logger.Debugf("directory %q of filesystem %q is already mirrored", path, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to add directory %q to the mirrored directories of filesystem %q", path, fsName)
+	}
+
+	return nil
+}
+
+// RemoveFSMirrorDirectory removes a directory from the mirrored directories of a filesystem. Removing a directory
+// that is not mirrored is a no-op.
+func RemoveFSMirrorDirectory(context *clusterd.Context, clusterName, fsName, path string) error {
+	logger.Infof("removing directory %q from the mirrored directories of filesystem %q", path, fsName)
+	args := []string{"fs", "snapshot", "mirror", "remove", fsName, path}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("directory %q of filesystem %q is not mirrored", path, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to remove directory %q from the mirrored directories of filesystem %q", path, fsName)
+	}
+
+	return nil
+}
+
+// AddSnapshotSchedule schedules the snapshots of a directory of a filesystem. The start time is optional.
+// Adding an existing schedule is a no-op.
+func AddSnapshotSchedule(context *clusterd.Context, clusterName, fsName, path, interval, startTime string) error {
+	logger.Infof("adding snapshot schedule every %q of directory %q of filesystem %q", interval, path, fsName)
+	args := []string{"fs", "snap-schedule", "add", path, interval}
+	if startTime != "" {
+		args = append(args, startTime)
+	}
+	args = append(args, "--fs", fsName)
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.EEXIST) {
+			logger.Debugf("snapshot schedule every %q of directory %q of filesystem %q already exists", interval, path, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to add snapshot schedule every %q of directory %q of filesystem %q", interval, path, fsName)
+	}
+
+	return nil
+}
+
+// AddSnapshotScheduleRetention sets the retention of the scheduled snapshots of a directory of a filesystem
+func AddSnapshotScheduleRetention(context *clusterd.Context, clusterName, fsName, path, duration string) error {
+	logger.Infof("adding snapshot retention %q of directory %q of filesystem %q", duration, path, fsName)
+	args := []string{"fs", "snap-schedule", "retention", "add", path, duration, "--fs", fsName}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.EEXIST) {
+			logger.Debugf("snapshot retention %q of directory %q of filesystem %q already exists", duration, path, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to add snapshot retention %q of directory %q of filesystem %q", duration, path, fsName)
+	}
+
+	return nil
+}
+
+// RemoveSnapshotSchedule removes a snapshot schedule of a directory of a filesystem. The start time is optional.
+// Removing a missing schedule is a no-op.
+func RemoveSnapshotSchedule(context *clusterd.Context, clusterName, fsName, path, interval, startTime string) error {
+	logger.Infof("removing snapshot schedule every %q of directory %q of filesystem %q", interval, path, fsName)
+	args := []string{"fs", "snap-schedule", "remove", path, interval}
+	if startTime != "" {
+		args = append(args, startTime)
+	}
+	args = append(args, "--fs", fsName)
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("snapshot schedule every %q of directory %q of filesystem %q is already removed", interval, path, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to remove snapshot schedule every %q of directory %q of filesystem %q", interval, path, fsName)
+	}
+
+	return nil
+}
+
+// RemoveSnapshotScheduleRetention removes a retention of the scheduled snapshots of a directory of a filesystem.
+// Removing a missing retention is a no-op.
+func RemoveSnapshotScheduleRetention(context *clusterd.Context, clusterName, fsName, path, duration string) error {
+	logger.Infof("removing snapshot retention %q of directory %q of filesystem %q", duration, path, fsName)
+	args := []string{"fs", "snap-schedule", "retention", "remove", path, duration, "--fs", fsName}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("snapshot retention %q of directory %q of filesystem %q is already removed", duration, path, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to remove snapshot retention %q of directory %q of filesystem %q", duration, path, fsName)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateFSMirrorBootstrapPeer(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "fs" && args[1] == "snapshot" && args[2] == "mirror" && args[3] == "peer_bootstrap" && args[4] == "create" {
+			assert.Equal(t, []string{"myfs", FilesystemMirrorPeerUser, "site-a"}, args[5:8])
+			return `{"token":"eyJmc2lkIjogIjgxNGQ1In0="}`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	token, err := CreateFSMirrorBootstrapPeer(context, "myns", "myfs", "site-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "eyJmc2lkIjogIjgxNGQ1In0=", token)
+}
+
+func TestAddSnapshotSchedule(t *testing.T) {
+	var scheduleArgs []string
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "fs" && args[1] == "snap-schedule" && args[2] == "add" {
+			scheduleArgs = args[3:]
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	err := AddSnapshotSchedule(context, "myns", "myfs", "/", "24h", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/", "24h", "--fs", "myfs"}, scheduleArgs[:4])
+
+	// the start time is passed after the interval
+	err = AddSnapshotSchedule(context, "myns", "myfs", "/", "24h", "2020-01-01T00:00:00")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/", "24h", "2020-01-01T00:00:00", "--fs", "myfs"}, scheduleArgs[:5])
+}
+
+func TestDecodeFSMirrorBootstrapPeer(t *testing.T) {
+	peer, err := DecodeFSMirrorBootstrapPeer("eyJmc2lkIjogIjgxNGQ1IiwgImZpbGVzeXN0ZW0iOiAiYmFja3VwX2ZzIiwgInVzZXIiOiAiY2xpZW50Lm1pcnJvcl9yZW1vdGUiLCAic2l0ZV9uYW1lIjogInNpdGUtYiIsICJrZXkiOiAiQVFCIiwgIm1vbl9ob3N0IjogIlt2MjoxMC4wLjAuMTozMzAwXSJ9")
+	assert.NoError(t, err)
+	assert.Equal(t, &FSMirrorBootstrapPeer{SiteName: "site-b", FilesystemName: "backup_fs"}, peer)
+
+	_, err = DecodeFSMirrorBootstrapPeer("not-a-token")
+	assert.Error(t, err)
+}
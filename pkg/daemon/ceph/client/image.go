@@ -18,6 +18,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"syscall"
 
 	"strconv"
@@ -196,3 +197,82 @@ func UnMapImage(context *clusterd.Context, imageName, poolName, id, keyring, clu
 func getImageSpec(name, poolName string) string {
 	return fmt.Sprintf("%s/%s", poolName, name)
 }
+
+// CephBlockImageInfo is the layout of an image as reported by "rbd info"
+type CephBlockImageInfo struct {
+	Name string `json:"name"`
+	// DataPool is the pool holding the data of the image, empty if the data is in the pool of the image
+	DataPool string `json:"data_pool,omitempty"`
+}
+
+// CephBlockImageStatus is the state of an image as reported by "rbd status"
+type CephBlockImageStatus struct {
+	Watchers []struct {
+		Address string `json:"address"`
+	} `json:"watchers"`
+	Migration *struct {
+		// State is the state of the live migration: prepared, executing or executed
+		State string `json:"state"`
+	} `json:"migration,omitempty"`
+}
+
+// GetImageInfo returns the layout of an image
+func GetImageInfo(context *clusterd.Context, clusterName, name, poolName string) (CephBlockImageInfo, error) {
+	args := []string{"info", getImageSpec(name, poolName)}
+	cmd := NewRBDCommand(context, clusterName, args)
+	cmd.JsonOutput = true
+	buf, err := cmd.Run()
+	if err != nil {
+		return CephBlockImageInfo{}, errors.Wrapf(err, "failed to get info of image %q in pool %q. %s", name, poolName, string(buf))
+	}
+
+	var info CephBlockImageInfo
+	if err := json.Unmarshal(buf, &info); err != nil {
+		return CephBlockImageInfo{}, errors.Wrapf(err, "unmarshal failed, raw buffer response: %s", string(buf))
+	}
+	return info, nil
+}
+
+// GetImageStatus returns the clients watching an image and the state of its live migration
+func GetImageStatus(context *clusterd.Context, clusterName, name, poolName string) (CephBlockImageStatus, error) {
+	args := []string{"status", getImageSpec(name, poolName)}
+	cmd := NewRBDCommand(context, clusterName, args)
+	cmd.JsonOutput = true
+	buf, err := cmd.Run()
+	if err != nil {
+		return CephBlockImageStatus{}, errors.Wrapf(err, "failed to get status of image %q in pool %q. %s", name, poolName, string(buf))
+	}
+
+	var status CephBlockImageStatus
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return CephBlockImageStatus{}, errors.Wrapf(err, "unmarshal failed, raw buffer response: %s", string(buf))
+	}
+	return status, nil
+}
+
+// PrepareImageMigration starts the live migration of the data of an image to another data pool. The image must not
+// be in use while the migration is prepared, it can be used again once prepared.
+func PrepareImageMigration(context *clusterd.Context, clusterName, name, poolName, dataPoolName string) error {
+	args := []string{"migration", "prepare", "--data-pool", dataPoolName, getImageSpec(name, poolName)}
+	return runImageMigrationCommand(context, clusterName, args)
+}
+
+// ExecuteImageMigration copies the data of an image prepared for a live migration to its new data pool
+func ExecuteImageMigration(context *clusterd.Context, clusterName, name, poolName string) error {
+	args := []string{"migration", "execute", getImageSpec(name, poolName)}
+	return runImageMigrationCommand(context, clusterName, args)
+}
+
+// CommitImageMigration completes the live migration of an image, the data of the image in the source pool is removed
+func CommitImageMigration(context *clusterd.Context, clusterName, name, poolName string) error {
+	args := []string{"migration", "commit", getImageSpec(name, poolName)}
+	return runImageMigrationCommand(context, clusterName, args)
+}
+
+func runImageMigrationCommand(context *clusterd.Context, clusterName string, args []string) error {
+	buf, err := NewRBDCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to run rbd %s, output: %s", strings.Join(args, " "), string(buf))
+	}
+	return nil
+}
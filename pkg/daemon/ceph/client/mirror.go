@@ -0,0 +1,233 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+const (
+	// RBDMirrorPeerDirection is the direction of the imported peers, the rbd-mirror daemons of both sites replay
+	// the images of the other site
+	RBDMirrorPeerDirection = "rx-tx"
+)
+
+// PoolMirroringInfo is a representation of the json structure returned by 'rbd mirror pool info'
+type PoolMirroringInfo struct {
+	Mode     string      `json:"mode"`
+	SiteName string      `json:"site_name"`
+	Peers    []PeersSpec `json:"peers"`
+}
+
+// PeersSpec is a representation of a peer in 'rbd mirror pool info'
+type PeersSpec struct {
+	UUID       string `json:"uuid"`
+	Direction  string `json:"direction"`
+	SiteName   string `json:"site_name"`
+	MirrorUUID string `json:"mirror_uuid"`
+	ClientName string `json:"client_name"`
+}
+
+// RBDMirrorSnapshotSchedule is a representation of a schedule returned by 'rbd mirror snapshot schedule ls'
+type RBDMirrorSnapshotSchedule struct {
+	Interval  string `json:"interval"`
+	StartTime string `json:"start_time"`
+}
+
+// PoolMirroringStatus is a representation of the json structure returned by 'rbd mirror pool status'
+type PoolMirroringStatus struct {
+	Summary PoolMirroringStatusSummary `json:"summary"`
+}
+
+// PoolMirroringStatusSummary is the summary of the mirroring status of a pool
+type PoolMirroringStatusSummary struct {
+	Health       string         `json:"health"`
+	DaemonHealth string         `json:"daemon_health"`
+	ImageHealth  string         `json:"image_health"`
+	States       map[string]int `json:"states"`
+}
+
+// EnablePoolMirroring enables the mirroring of a pool in the given mode, "image" or "pool"
+func EnablePoolMirroring(context *clusterd.Context, clusterName, poolName, mode string) error {
+	logger.Infof("enabling mirroring of pool %q in %q mode", poolName, mode)
+	args := []string{"mirror", "pool", "enable", poolName, mode}
+	_, err := NewRBDCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable mirroring of pool %q in %q mode", poolName, mode)
+	}
+
+	return nil
+}
+
+// DisablePoolMirroring disables the mirroring of a pool
+func DisablePoolMirroring(context *clusterd.Context, clusterName, poolName string) error {
+	logger.Infof("disabling mirroring of pool %q", poolName)
+	args := []string{"mirror", "pool", "disable", poolName}
+	_, err := NewRBDCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to disable mirroring of pool %q", poolName)
+	}
+
+	return nil
+}
+
+// CreateRBDMirrorBootstrapPeer creates the bootstrap peer token of a pool, for the remote sites to add the local
+// site as a peer. The site name identifies the local site on the remote sites.
+func CreateRBDMirrorBootstrapPeer(context *clusterd.Context, clusterName, poolName, siteName string) (string, error) {
+	logger.Infof("creating bootstrap peer token of pool %q", poolName)
+	args := []string{"mirror", "pool", "peer", "bootstrap", "create", poolName, "--site-name", siteName}
+	buf, err := NewRBDCommand(context, clusterName, args).Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create bootstrap peer token of pool %q", poolName)
+	}
+
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// ImportRBDMirrorBootstrapPeer adds the site of a bootstrap peer token as a peer of a pool. Importing an existing
+// peer is a no-op.
+func ImportRBDMirrorBootstrapPeer(context *clusterd.Context, clusterName, poolName, token string) error {
+	logger.Infof("importing bootstrap peer token of pool %q", poolName)
+
+	// The token is only read from a file
+	tokenFile, err := ioutil.TempFile("", "rbd-mirror-token")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary bootstrap peer token file")
+	}
+	defer os.Remove(tokenFile.Name())
+	if _, err := tokenFile.WriteString(token); err != nil {
+		tokenFile.Close()
+		return errors.Wrap(err, "failed to write temporary bootstrap peer token file")
+	}
+	if err := tokenFile.Close(); err != nil {
+		return errors.Wrap(err, "failed to close temporary bootstrap peer token file")
+	}
+
+	args := []string{"mirror", "pool", "peer", "bootstrap", "import", poolName, tokenFile.Name(), "--direction", RBDMirrorPeerDirection}
+	_, err = NewRBDCommand(context, clusterName, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.EEXIST) {
+			logger.Debugf("peer of pool %q already exists", poolName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to import bootstrap peer token of pool %q", poolName)
+	}
+
+	return nil
+}
+
+// GetPoolMirroringInfo returns the mirroring mode and the peers of a pool
+func GetPoolMirroringInfo(context *clusterd.Context, clusterName, poolName string) (*PoolMirroringInfo, error) {
+	args := []string{"mirror", "pool", "info", poolName}
+	cmd := NewRBDCommand(context, clusterName, args)
+	cmd.JsonOutput = true
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get mirroring info of pool %q", poolName)
+	}
+
+	var info PoolMirroringInfo
+	if err := json.Unmarshal(buf, &info); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	return &info, nil
+}
+
+// GetPoolMirroringStatus returns the mirroring health of a pool
+func GetPoolMirroringStatus(context *clusterd.Context, clusterName, poolName string) (*PoolMirroringStatus, error) {
+	args := []string{"mirror", "pool", "status", poolName}
+	cmd := NewRBDCommand(context, clusterName, args)
+	cmd.JsonOutput = true
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get mirroring status of pool %q", poolName)
+	}
+
+	var status PoolMirroringStatus
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	return &status, nil
+}
+
+// AddRBDMirrorSnapshotSchedule schedules the mirror snapshots of the images of a pool. The start time is optional.
+// Adding an existing schedule is a no-op.
+func AddRBDMirrorSnapshotSchedule(context *clusterd.Context, clusterName, poolName, interval, startTime string) error {
+	logger.Infof("adding mirror snapshot schedule every %q of pool %q", interval, poolName)
+	args := []string{"mirror", "snapshot", "schedule", "add", "--pool", poolName, interval}
+	if startTime != "" {
+		args = append(args, startTime)
+	}
+	buf, err := NewRBDCommand(context, clusterName, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); (ok && code == int(syscall.EEXIST)) || strings.Contains(string(buf), "already exists") {
+			logger.Debugf("mirror snapshot schedule every %q of pool %q already exists", interval, poolName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to add mirror snapshot schedule every %q of pool %q", interval, poolName)
+	}
+
+	return nil
+}
+
+// ListRBDMirrorSnapshotSchedules returns the mirror snapshot schedules of the images of a pool
+func ListRBDMirrorSnapshotSchedules(context *clusterd.Context, clusterName, poolName string) ([]RBDMirrorSnapshotSchedule, error) {
+	args := []string{"mirror", "snapshot", "schedule", "ls", "--pool", poolName}
+	cmd := NewRBDCommand(context, clusterName, args)
+	cmd.JsonOutput = true
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list mirror snapshot schedules of pool %q", poolName)
+	}
+
+	var schedules []RBDMirrorSnapshotSchedule
+	if err := json.Unmarshal(buf, &schedules); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	return schedules, nil
+}
+
+// RemoveRBDMirrorSnapshotSchedule removes a mirror snapshot schedule of the images of a pool. The start time is
+// optional.
+func RemoveRBDMirrorSnapshotSchedule(context *clusterd.Context, clusterName, poolName, interval, startTime string) error {
+	logger.Infof("removing mirror snapshot schedule every %q of pool %q", interval, poolName)
+	args := []string{"mirror", "snapshot", "schedule", "remove", "--pool", poolName, interval}
+	if startTime != "" {
+		args = append(args, startTime)
+	}
+	_, err := NewRBDCommand(context, clusterName, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("mirror snapshot schedule every %q of pool %q is already removed", interval, poolName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to remove mirror snapshot schedule every %q of pool %q", interval, poolName)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportRBDMirrorBootstrapPeer(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if command == RBDTool && args[0] == "mirror" && args[3] == "bootstrap" && args[4] == "import" {
+			assert.Equal(t, "mypool", args[5])
+			// the token is passed in a file
+			token, err := ioutil.ReadFile(args[6])
+			assert.NoError(t, err)
+			assert.Equal(t, "eyJmc2lkIjoiYzZiMDg", string(token))
+			assert.Equal(t, []string{"--direction", "rx-tx"}, args[7:9])
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected rbd command %q", args)
+	}
+
+	err := ImportRBDMirrorBootstrapPeer(context, "myns", "mypool", "eyJmc2lkIjoiYzZiMDg")
+	assert.NoError(t, err)
+}
+
+func TestGetPoolMirroringStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if command == RBDTool && args[0] == "mirror" && args[1] == "pool" && args[2] == "status" {
+			assert.Equal(t, "mypool", args[3])
+			assert.Contains(t, args, "json")
+			return `{"summary":{"health":"WARNING","daemon_health":"OK","image_health":"WARNING","states":{"replaying":2,"starting_replay":1}}}`, nil
+		}
+		return "", errors.Errorf("unexpected rbd command %q", args)
+	}
+
+	status, err := GetPoolMirroringStatus(context, "myns", "mypool")
+	assert.NoError(t, err)
+	assert.Equal(t, "WARNING", status.Summary.Health)
+	assert.Equal(t, "OK", status.Summary.DaemonHealth)
+	assert.Equal(t, "WARNING", status.Summary.ImageHealth)
+	assert.Equal(t, map[string]int{"replaying": 2, "starting_replay": 1}, status.Summary.States)
+}
+
+func TestAddRBDMirrorSnapshotSchedule(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if command == RBDTool && args[0] == "mirror" && args[1] == "snapshot" && args[3] == "add" {
+			assert.Equal(t, []string{"--pool", "mypool", "24h"}, args[4:7])
+			return "rbd: failed to add snapshot schedule: (17) File exists\nschedule already exists", errors.New("exit status 17")
+		}
+		return "", errors.Errorf("unexpected rbd command %q", args)
+	}
+
+	// an existing schedule is not an error
+	err := AddRBDMirrorSnapshotSchedule(context, "myns", "mypool", "24h", "")
+	assert.NoError(t, err)
+}
+
+func TestListRBDMirrorSnapshotSchedules(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if command == RBDTool && args[0] == "mirror" && args[1] == "snapshot" && args[3] == "ls" {
+			assert.Contains(t, args, "json")
+			return `[{"interval":"1d","start_time":null},{"interval":"12h","start_time":"14:00:00"}]`, nil
+		}
+		return "", errors.Errorf("unexpected rbd command %q", args)
+	}
+
+	schedules, err := ListRBDMirrorSnapshotSchedules(context, "myns", "mypool")
+	assert.NoError(t, err)
+	assert.Equal(t, []RBDMirrorSnapshotSchedule{{Interval: "1d"}, {Interval: "12h", StartTime: "14:00:00"}}, schedules)
+}
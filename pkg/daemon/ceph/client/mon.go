@@ -85,3 +85,72 @@ func GetMonQuorumStatusHealth(context *clusterd.Context, clusterName, userName s
 
 	return resp, nil
 }
+
+// MonDump represents the response from a mon dump command (subset of all available fields, only marshal ones we care
+// about)
+type MonDump struct {
+	StretchMode   bool   `json:"stretch_mode"`
+	TiebreakerMon string `json:"tiebreaker_mon"`
+}
+
+// GetMonDump calls mon dump to get the monitor map
+func GetMonDump(context *clusterd.Context, clusterName string) (MonDump, error) {
+	args := []string{"mon", "dump"}
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return MonDump{}, errors.Wrap(err, "mon dump failed")
+	}
+
+	var resp MonDump
+	err = json.Unmarshal(buf, &resp)
+	if err != nil {
+		return MonDump{}, errors.Wrapf(err, "unmarshal failed. raw buffer response: %s", buf)
+	}
+
+	return resp, nil
+}
+
+// SetMonElectionStrategy sets the strategy of the mon elections (classic, disallow or connectivity)
+func SetMonElectionStrategy(context *clusterd.Context, clusterName, strategy string) error {
+	args := []string{"mon", "set", "election_strategy", strategy}
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the mon election strategy to %q. %s", strategy, string(buf))
+	}
+
+	return nil
+}
+
+// SetMonLocation sets the CRUSH location of a mon, such as "zone=a"
+func SetMonLocation(context *clusterd.Context, clusterName, name, location string) error {
+	args := []string{"mon", "set_location", name, location}
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the location of mon %q to %q. %s", name, location, string(buf))
+	}
+
+	return nil
+}
+
+// EnableStretchMode enables the stretch mode of the cluster, the data is replicated across the two buckets of the
+// dividing bucket type with the CRUSH rule and the tiebreaker mon breaks the ties between the mons of the two buckets
+func EnableStretchMode(context *clusterd.Context, clusterName, tiebreakerMon, ruleName, dividingBucketType string) error {
+	args := []string{"mon", "enable_stretch_mode", tiebreakerMon, ruleName, dividingBucketType}
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable the stretch mode. %s", string(buf))
+	}
+
+	return nil
+}
+
+// SetNewTiebreakerMon replaces the tiebreaker mon of a cluster in stretch mode
+func SetNewTiebreakerMon(context *clusterd.Context, clusterName, name string) error {
+	args := []string{"mon", "set_new_tiebreaker", name}
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the tiebreaker mon to %q. %s", name, string(buf))
+	}
+
+	return nil
+}
@@ -19,6 +19,9 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -67,3 +70,32 @@ func TestCephArgs(t *testing.T) {
 	assert.Equal(t, 2, len(args))
 	assert.Equal(t, "myarg", args[0])
 }
+
+func TestStretchMode(t *testing.T) {
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] != "mon" {
+			return "", errors.Errorf("unexpected ceph command '%v'", args)
+		}
+		if args[1] == "dump" {
+			return `{"epoch": 3, "stretch_mode": true, "tiebreaker_mon": "e", "mons": []}`, nil
+		}
+		commands = append(commands, args[1:5])
+		return "", nil
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	dump, err := GetMonDump(context, "rook-ceph")
+	assert.NoError(t, err)
+	assert.True(t, dump.StretchMode)
+	assert.Equal(t, "e", dump.TiebreakerMon)
+
+	assert.NoError(t, SetMonElectionStrategy(context, "rook-ceph", "connectivity"))
+	assert.NoError(t, SetMonLocation(context, "rook-ceph", "a", "zone=a"))
+	assert.NoError(t, EnableStretchMode(context, "rook-ceph", "e", "stretch_rule", "zone"))
+	assert.Equal(t, []string{"set", "election_strategy", "connectivity", "--connect-timeout=15"}, commands[0])
+	assert.Equal(t, []string{"set_location", "a", "zone=a", "--connect-timeout=15"}, commands[1])
+	assert.Equal(t, []string{"enable_stretch_mode", "e", "stretch_rule", "zone"}, commands[2])
+}
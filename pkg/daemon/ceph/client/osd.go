@@ -171,6 +171,14 @@ type OsdTree struct {
 	} `json:"stray"`
 }
 
+// OSDMetadata is the metadata reported by an OSD
+type OSDMetadata struct {
+	ID       int    `json:"id"`
+	Hostname string `json:"hostname"`
+	// Devices is the comma separated list of the names of the devices of the OSD, such as "sdb"
+	Devices string `json:"devices"`
+}
+
 // OsdList returns the list of OSD by their IDs
 type OsdList []int
 
@@ -252,6 +260,42 @@ func OSDOut(context *clusterd.Context, clusterName string, osdID int) (string, e
 	return string(buf), err
 }
 
+// OSDDown marks an OSD down
+func OSDDown(context *clusterd.Context, clusterName string, osdID int) error {
+	args := []string{"osd", "down", strconv.Itoa(osdID)}
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to mark osd.%d down. %s", osdID, string(buf))
+	}
+	return nil
+}
+
+// PurgeOSD removes an OSD from the CRUSH map, deletes its auth key and removes it from the OSD map. The OSD must be
+// down.
+func PurgeOSD(context *clusterd.Context, clusterName string, osdID int) error {
+	args := []string{"osd", "purge", strconv.Itoa(osdID), "--yes-i-really-mean-it"}
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to purge osd.%d. %s", osdID, string(buf))
+	}
+	return nil
+}
+
+// GetOSDMetadata returns the metadata reported by the OSDs
+func GetOSDMetadata(context *clusterd.Context, clusterName string) ([]OSDMetadata, error) {
+	args := []string{"osd", "metadata"}
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get osd metadata")
+	}
+
+	var metadata []OSDMetadata
+	if err := json.Unmarshal(buf, &metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal osd metadata response")
+	}
+	return metadata, nil
+}
+
 func OsdSafeToDestroy(context *clusterd.Context, clusterName string, osdID int) (bool, error) {
 	args := []string{"osd", "safe-to-destroy", strconv.Itoa(osdID)}
 	cmd := NewCephCommand(context, clusterName, args)
@@ -110,3 +110,36 @@ func TestOsdListNum(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, 0, len(list))
 }
+
+func TestGetOSDMetadata(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		switch {
+		case args[0] == "osd" && args[1] == "metadata":
+			return `[{"id": 0, "hostname": "node1", "devices": "sdb", "osd_objectstore": "bluestore"},
+				{"id": 1, "hostname": "node2", "devices": "dm-0,sdc"}]`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	metadata, err := GetOSDMetadata(&clusterd.Context{Executor: executor}, "rook")
+	assert.NoError(t, err)
+	assert.Equal(t, []OSDMetadata{{ID: 0, Hostname: "node1", Devices: "sdb"}, {ID: 1, Hostname: "node2", Devices: "dm-0,sdc"}}, metadata)
+}
+
+func TestPurgeOSD(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		switch {
+		case args[0] == "osd" && args[1] == "purge":
+			assert.Equal(t, "3", args[2])
+			assert.Equal(t, "--yes-i-really-mean-it", args[3])
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	assert.NoError(t, PurgeOSD(&clusterd.Context{Executor: executor}, "rook", 3))
+}
@@ -54,12 +54,33 @@ type CephStoragePoolDetails struct {
 	RequireSafeReplicaSize bool    `json:"requireSafeReplicaSize,omitempty"`
 }
 
+// CephStoragePoolLsDetail is the state of a pool as reported by "ceph osd pool ls detail"
+type CephStoragePoolLsDetail struct {
+	Number              int                          `json:"pool"`
+	Name                string                       `json:"pool_name"`
+	Type                int                          `json:"type"`
+	Size                uint                         `json:"size"`
+	MinSize             uint                         `json:"min_size"`
+	CrushRule           int                          `json:"crush_rule"`
+	PgNum               int                          `json:"pg_num"`
+	ErasureCodeProfile  string                       `json:"erasure_code_profile"`
+	ApplicationMetadata map[string]map[string]string `json:"application_metadata,omitempty"`
+	// the number of CRUSH buckets the PGs of the pool must span to peer, only set in stretch mode
+	PeeringCrushBucketCount int `json:"peering_crush_bucket_count,omitempty"`
+}
+
+// IsStretchPool returns whether the stretch mode of the cluster took over the size and the CRUSH rule of the pool
+func (p *CephStoragePoolLsDetail) IsStretchPool() bool {
+	return p.PeeringCrushBucketCount > 0
+}
+
 type CephStoragePoolStats struct {
 	Pools []struct {
 		Name  string `json:"name"`
 		ID    int    `json:"id"`
 		Stats struct {
 			BytesUsed    float64 `json:"bytes_used"`
+			Stored       float64 `json:"stored"`
 			RawBytesUsed float64 `json:"raw_bytes_used"`
 			MaxAvail     float64 `json:"max_avail"`
 			Objects      float64 `json:"objects"`
@@ -68,6 +89,8 @@ type CephStoragePoolStats struct {
 			ReadBytes    float64 `json:"rd_bytes"`
 			WriteIO      float64 `json:"wr"`
 			WriteBytes   float64 `json:"wr_bytes"`
+			QuotaObjects float64 `json:"quota_objects"`
+			QuotaBytes   float64 `json:"quota_bytes"`
 		} `json:"stats"`
 	} `json:"pools"`
 }
@@ -101,6 +124,37 @@ func ListPoolSummaries(context *clusterd.Context, namespace string) ([]CephStora
 	return pools, nil
 }
 
+// ListPoolDetails lists the state of all the pools
+func ListPoolDetails(context *clusterd.Context, namespace string) ([]CephStoragePoolLsDetail, error) {
+	args := []string{"osd", "pool", "ls", "detail"}
+	output, err := NewCephCommand(context, namespace, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pool details")
+	}
+
+	var pools []CephStoragePoolLsDetail
+	err = json.Unmarshal(output, &pools)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(output))
+	}
+
+	return pools, nil
+}
+
+// isStretchPool returns whether an existing pool is in stretch mode
+func isStretchPool(context *clusterd.Context, namespace, poolName string) (bool, error) {
+	pools, err := ListPoolDetails(context, namespace)
+	if err != nil {
+		return false, err
+	}
+	for _, pool := range pools {
+		if pool.Name == poolName {
+			return pool.IsStretchPool(), nil
+		}
+	}
+	return false, nil
+}
+
 func GetPoolNamesByID(context *clusterd.Context, namespace string) (map[int]string, error) {
 	pools, err := ListPoolSummaries(context, namespace)
 	if err != nil {
@@ -157,9 +211,12 @@ func CreatePoolWithProfile(context *clusterd.Context, namespace, poolName string
 		return fmt.Errorf("pool %q type is not defined as replicated or erasure coded", poolName)
 	}
 
-	// create a new erasure code profile for the new pool
+	// the profile of an existing pool can't change, ceph refuses to override it with different settings
 	ecProfileName := GetErasureCodeProfileForPool(poolName)
-	if err := CreateErasureCodeProfile(context, namespace, ecProfileName, pool); err != nil {
+	if details, err := GetPoolDetails(context, namespace, poolName); err == nil && details.ErasureCodeProfile != "" {
+		ecProfileName = details.ErasureCodeProfile
+	} else if err := CreateErasureCodeProfile(context, namespace, ecProfileName, pool); err != nil {
+		// create a new erasure code profile for the new pool
 		return errors.Wrapf(err, "failed to create erasure code profile for pool %q", poolName)
 	}
 
@@ -235,7 +292,8 @@ func givePoolAppTag(context *clusterd.Context, namespace string, poolName string
 	return nil
 }
 
-func setCommonPoolProperties(context *clusterd.Context, pool cephv1.PoolSpec, namespace, poolName, appName string) error {
+// SetCommonPoolProperties applies the parameters, the quotas and the application of a pool spec to an existing pool
+func SetCommonPoolProperties(context *clusterd.Context, pool cephv1.PoolSpec, namespace, poolName, appName string) error {
 	if len(pool.Parameters) == 0 {
 		pool.Parameters = make(map[string]string)
 	}
@@ -256,6 +314,11 @@ func setCommonPoolProperties(context *clusterd.Context, pool cephv1.PoolSpec, na
 		}
 	}
 
+	// the quotas removed from the spec are removed from the pool as well
+	if err := SetPoolQuota(context, namespace, poolName, pool.Quotas); err != nil {
+		return errors.Wrapf(err, "failed to set quotas on pool %q", poolName)
+	}
+
 	// ensure that the newly created pool gets an application tag
 	if appName != "" {
 		err := givePoolAppTag(context, namespace, poolName, appName)
@@ -284,7 +347,7 @@ func CreateECPoolForApp(context *clusterd.Context, namespace, poolName, ecProfil
 		}
 	}
 
-	if err = setCommonPoolProperties(context, pool, namespace, poolName, appName); err != nil {
+	if err = SetCommonPoolProperties(context, pool, namespace, poolName, appName); err != nil {
 		return err
 	}
 
@@ -293,8 +356,18 @@ func CreateECPoolForApp(context *clusterd.Context, namespace, poolName, ecProfil
 }
 
 func CreateReplicatedPoolForApp(context *clusterd.Context, namespace, poolName string, pool cephv1.PoolSpec, pgCount, appName string) error {
+	// the size and the crush rule of a pool in stretch mode are set by ceph, applying the spec would undo them
+	stretched, err := isStretchPool(context, namespace, poolName)
+	if err != nil {
+		return err
+	}
+	if stretched {
+		logger.Debugf("pool %q is in stretch mode, only updating its properties", poolName)
+		return SetCommonPoolProperties(context, pool, namespace, poolName, appName)
+	}
+
 	// create a crush rule for a replicated pool, if a failure domain is specified
-	if err := createReplicationCrushRule(context, namespace, poolName, pool); err != nil {
+	if err := CreateReplicationCrushRule(context, namespace, poolName, pool); err != nil {
 		return err
 	}
 
@@ -309,7 +382,7 @@ func CreateReplicatedPoolForApp(context *clusterd.Context, namespace, poolName s
 		return errors.Wrapf(err, "failed to set size property to replicated pool %q to %d", poolName, pool.Replicated.Size)
 	}
 
-	if err = setCommonPoolProperties(context, pool, namespace, poolName, appName); err != nil {
+	if err = SetCommonPoolProperties(context, pool, namespace, poolName, appName); err != nil {
 		return err
 	}
 
@@ -317,7 +390,8 @@ func CreateReplicatedPoolForApp(context *clusterd.Context, namespace, poolName s
 	return nil
 }
 
-func createReplicationCrushRule(context *clusterd.Context, namespace, ruleName string, pool cephv1.PoolSpec) error {
+// CreateReplicationCrushRule creates a replicated CRUSH rule with the root, the failure domain and the device class of a pool spec
+func CreateReplicationCrushRule(context *clusterd.Context, namespace, ruleName string, pool cephv1.PoolSpec) error {
 	failureDomain := pool.FailureDomain
 	if failureDomain == "" {
 		failureDomain = cephv1.DefaultFailureDomain
@@ -371,6 +445,33 @@ func SetPoolReplicatedSizeProperty(context *clusterd.Context, namespace, poolNam
 	return nil
 }
 
+// SetPoolQuota sets the quotas of a pool, a quota that is not defined in the spec or set to 0 is removed
+func SetPoolQuota(context *clusterd.Context, namespace, poolName string, quotas cephv1.QuotaSpec) error {
+	var maxBytes, maxObjects uint64
+	if quotas.MaxBytes != nil {
+		maxBytes = *quotas.MaxBytes
+	}
+	if quotas.MaxObjects != nil {
+		maxObjects = *quotas.MaxObjects
+	}
+
+	if err := setPoolQuota(context, namespace, poolName, "max_bytes", maxBytes); err != nil {
+		return err
+	}
+	return setPoolQuota(context, namespace, poolName, "max_objects", maxObjects)
+}
+
+func setPoolQuota(context *clusterd.Context, namespace, poolName, quotaName string, value uint64) error {
+	args := []string{"osd", "pool", "set-quota", poolName, quotaName, strconv.FormatUint(value, 10)}
+	logger.Infof("setting quota %q to %d on pool %q", quotaName, value, poolName)
+	_, err := NewCephCommand(context, namespace, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set quota %q on pool %q", quotaName, poolName)
+	}
+
+	return nil
+}
+
 func GetPoolStats(context *clusterd.Context, namespace string) (*CephStoragePoolStats, error) {
 	args := []string{"df", "detail"}
 	output, err := NewCephCommand(context, namespace, args).Run()
@@ -402,3 +503,52 @@ func GetPoolStatistics(context *clusterd.Context, name, namespace string) (*Pool
 
 	return &poolStats, nil
 }
+
+// PoolRecoveryStats is the recovery state of a pool as reported by "ceph osd pool stats"
+type PoolRecoveryStats struct {
+	DegradedObjects  uint64 `json:"degraded_objects"`
+	DegradedTotal    uint64 `json:"degraded_total"`
+	MisplacedObjects uint64 `json:"misplaced_objects"`
+	MisplacedTotal   uint64 `json:"misplaced_total"`
+}
+
+// GetPoolRecoveryStats returns the degraded and misplaced objects of a pool. They are all 0 when the pool is not
+// recovering or rebalancing.
+func GetPoolRecoveryStats(context *clusterd.Context, namespace, poolName string) (PoolRecoveryStats, error) {
+	args := []string{"osd", "pool", "stats", poolName}
+	output, err := NewCephCommand(context, namespace, args).Run()
+	if err != nil {
+		return PoolRecoveryStats{}, errors.Wrapf(err, "failed to get the recovery stats of pool %q", poolName)
+	}
+
+	var stats []struct {
+		Name     string            `json:"pool_name"`
+		Recovery PoolRecoveryStats `json:"recovery"`
+	}
+	if err := json.Unmarshal(output, &stats); err != nil {
+		return PoolRecoveryStats{}, errors.Wrapf(err, "failed to unmarshal the recovery stats of pool %q. %s", poolName, string(output))
+	}
+	for _, pool := range stats {
+		if pool.Name == poolName {
+			return pool.Recovery, nil
+		}
+	}
+
+	return PoolRecoveryStats{}, errors.Errorf("pool %q not found in the recovery stats", poolName)
+}
+
+// SetPoolCrushRule switches a pool to a CRUSH rule, the data of the pool is moved to follow the new rule
+func SetPoolCrushRule(context *clusterd.Context, namespace, poolName, ruleName string) error {
+	return SetPoolProperty(context, namespace, poolName, "crush_rule", ruleName)
+}
+
+// RenamePool renames a pool. The clients refer to the pools by their ID, the data of the pool is not affected.
+func RenamePool(context *clusterd.Context, namespace, poolName, newName string) error {
+	args := []string{"osd", "pool", "rename", poolName, newName}
+	output, err := NewCephCommand(context, namespace, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to rename pool %q to %q. %s", poolName, newName, string(output))
+	}
+	logger.Infof("renamed pool %q to %q", poolName, newName)
+	return nil
+}
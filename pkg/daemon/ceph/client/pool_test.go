@@ -17,6 +17,7 @@ package client
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -80,6 +81,11 @@ func testCreateECPool(t *testing.T, overwrite bool, compressionMode string) {
 				assert.Equal(t, "myapp", args[5])
 				return "", nil
 			}
+			if args[2] == "set-quota" {
+				assert.Equal(t, "mypool", args[3])
+				assert.Equal(t, "0", args[5])
+				return "", nil
+			}
 		}
 		return "", errors.Errorf("unexpected ceph command %q", args)
 	}
@@ -117,6 +123,9 @@ func testCreateReplicaPool(t *testing.T, failureDomain, crushRoot, deviceClass,
 	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
 		logger.Infof("Command: %s %v", command, args)
 		if args[1] == "pool" {
+			if args[2] == "ls" {
+				return "[]", nil
+			}
 			if args[2] == "create" {
 				assert.Equal(t, "mypool", args[3])
 				assert.Equal(t, "replicated", args[5])
@@ -141,6 +150,11 @@ func testCreateReplicaPool(t *testing.T, failureDomain, crushRoot, deviceClass,
 				assert.Equal(t, "myapp", args[5])
 				return "", nil
 			}
+			if args[2] == "set-quota" {
+				assert.Equal(t, "mypool", args[3])
+				assert.Equal(t, "0", args[5])
+				return "", nil
+			}
 		}
 		if args[1] == "crush" {
 			crushRuleCreated = true
@@ -184,6 +198,26 @@ func testCreateReplicaPool(t *testing.T, failureDomain, crushRoot, deviceClass,
 	}
 }
 
+func TestCreateStretchReplicaPool(t *testing.T) {
+	executed := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outputFile string, args ...string) (string, error) {
+			executed = append(executed, strings.Join(args[:3], " "))
+			if strings.Join(args[:4], " ") == "osd pool ls detail" {
+				return `[{"pool_name":"mypool","size":4,"crush_rule":1,"peering_crush_bucket_count":2}]`, nil
+			}
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	// the size and the crush rule of the pool are left to the stretch mode
+	p := cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}
+	err := CreateReplicatedPoolForApp(context, "myns", "mypool", p, DefaultPGCount, "myapp")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"osd pool ls", "osd pool set-quota", "osd pool set-quota", "osd pool application"}, executed)
+}
+
 func testIsStringInSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
@@ -266,3 +300,60 @@ func TestSetPoolReplicatedSizeProperty(t *testing.T) {
 	err = SetPoolReplicatedSizeProperty(context, "myns", poolName, "1")
 	assert.NoError(t, err)
 }
+
+func TestSetPoolQuota(t *testing.T) {
+	quotas := map[string]string{}
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "osd" && args[1] == "pool" && args[2] == "set-quota" {
+			assert.Equal(t, "mypool", args[3])
+			quotas[args[4]] = args[5]
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	// the quotas that are not in the spec are removed
+	maxBytes := uint64(1024)
+	err := SetPoolQuota(context, "myns", "mypool", cephv1.QuotaSpec{MaxBytes: &maxBytes})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"max_bytes": "1024", "max_objects": "0"}, quotas)
+
+	// a quota of 0 removes it
+	maxObjects := uint64(10)
+	err = SetPoolQuota(context, "myns", "mypool", cephv1.QuotaSpec{MaxObjects: &maxObjects})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"max_bytes": "0", "max_objects": "10"}, quotas)
+
+	// removing all the quotas clears them
+	err = SetPoolQuota(context, "myns", "mypool", cephv1.QuotaSpec{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"max_bytes": "0", "max_objects": "0"}, quotas)
+}
+
+func TestCreatePoolWithProfileKeepsExistingProfile(t *testing.T) {
+	profileSet := false
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "osd" && args[1] == "pool" && args[2] == "get" {
+			return `{"pool":"mypool","pool_id":2}{"pool":"mypool","erasure_code_profile":"mypool_ecprofile"}`, nil
+		}
+		if args[0] == "osd" && args[1] == "erasure-code-profile" && args[2] == "set" {
+			profileSet = true
+		}
+		if args[0] == "osd" && args[1] == "pool" && args[2] == "create" {
+			assert.Equal(t, "mypool_ecprofile", args[6])
+		}
+		return "", nil
+	}
+
+	// the profile of an existing pool is not overridden with the settings of the spec
+	p := cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2}}
+	err := CreatePoolWithProfile(context, "myns", "mypool", p, "myapp")
+	assert.NoError(t, err)
+	assert.False(t, profileSet)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+const (
+	// SubVolumeGroupNoQuota is the size of a subvolume group without quota
+	SubVolumeGroupNoQuota = "infinite"
+)
+
+// SubVolume is a representation of the json structure returned by 'ceph fs subvolume ls'
+type SubVolume struct {
+	Name string `json:"name"`
+}
+
+// CreateSubVolumeGroup creates a subvolume group in a filesystem. The files of the group are stored in the given
+// data pool, or in the default data pool of the filesystem if empty. Creating an existing group is a no-op.
+func CreateSubVolumeGroup(context *clusterd.Context, clusterName, fsName, groupName, poolName string) error {
+	logger.Infof("creating subvolume group %q in filesystem %q", groupName, fsName)
+	args := []string{"fs", "subvolumegroup", "create", fsName, groupName}
+	if poolName != "" {
+		args = append(args, "--pool_layout", poolName)
+	}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create subvolume group %q in filesystem %q", groupName, fsName)
+	}
+
+	return nil
+}
+
+// ResizeSubVolumeGroup sets the quota of a subvolume group. The size is a number of bytes, or
+// SubVolumeGroupNoQuota to remove the quota.
+func ResizeSubVolumeGroup(context *clusterd.Context, clusterName, fsName, groupName, size string) error {
+	logger.Infof("resizing subvolume group %q in filesystem %q to %q", groupName, fsName, size)
+	args := []string{"fs", "subvolumegroup", "resize", fsName, groupName, size}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to resize subvolume group %q in filesystem %q to %q", groupName, fsName, size)
+	}
+
+	return nil
+}
+
+// PinSubVolumeGroup pins a subvolume group to the MDS ranks of the filesystem. The pin type is "export",
+// "distributed" or "random".
+func PinSubVolumeGroup(context *clusterd.Context, clusterName, fsName, groupName, pinType, pinSetting string) error {
+	logger.Infof("setting %s pin %q on subvolume group %q in filesystem %q", pinType, pinSetting, groupName, fsName)
+	args := []string{"fs", "subvolumegroup", "pin", fsName, groupName, pinType, pinSetting}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set %s pin %q on subvolume group %q in filesystem %q", pinType, pinSetting, groupName, fsName)
+	}
+
+	return nil
+}
+
+// GetSubVolumeGroupPath returns the path of a subvolume group in the filesystem
+func GetSubVolumeGroupPath(context *clusterd.Context, clusterName, fsName, groupName string) (string, error) {
+	args := []string{"fs", "subvolumegroup", "getpath", fsName, groupName}
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get path of subvolume group %q in filesystem %q", groupName, fsName)
+	}
+
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// ListSubVolumes lists the subvolumes of a subvolume group
+func ListSubVolumes(context *clusterd.Context, clusterName, fsName, groupName string) ([]SubVolume, error) {
+	args := []string{"fs", "subvolume", "ls", fsName, "--group_name", groupName}
+	buf, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list subvolumes of group %q in filesystem %q", groupName, fsName)
+	}
+
+	var subVolumes []SubVolume
+	err = json.Unmarshal(buf, &subVolumes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	return subVolumes, nil
+}
+
+// DeleteSubVolumeGroup deletes a subvolume group from a filesystem. The group must not have any subvolume.
+// Deleting a group that does not exist is a no-op.
+func DeleteSubVolumeGroup(context *clusterd.Context, clusterName, fsName, groupName string) error {
+	logger.Infof("deleting subvolume group %q from filesystem %q", groupName, fsName)
+	args := []string{"fs", "subvolumegroup", "rm", fsName, groupName, "--force"}
+	_, err := NewCephCommand(context, clusterName, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete subvolume group %q from filesystem %q", groupName, fsName)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSubVolumeGroup(t *testing.T) {
+	var createArgs []string
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "fs" && args[1] == "subvolumegroup" && args[2] == "create" {
+			createArgs = args[3:]
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	err := CreateSubVolumeGroup(context, "myns", "myfs", "group-a", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"myfs", "group-a"}, createArgs[:2])
+	assert.NotContains(t, createArgs, "--pool_layout")
+
+	// the data pool is set as the layout of the group
+	err = CreateSubVolumeGroup(context, "myns", "myfs", "group-a", "myfs-data1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"myfs", "group-a", "--pool_layout", "myfs-data1"}, createArgs[:4])
+}
+
+func TestListSubVolumes(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "fs" && args[1] == "subvolume" && args[2] == "ls" {
+			assert.Equal(t, []string{"myfs", "--group_name", "group-a"}, args[3:6])
+			return `[{"name":"csi-vol-0f4c1ac2"},{"name":"csi-vol-8a9f5d1e"}]`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	subVolumes, err := ListSubVolumes(context, "myns", "myfs", "group-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []SubVolume{{Name: "csi-vol-0f4c1ac2"}, {Name: "csi-vol-8a9f5d1e"}}, subVolumes)
+}
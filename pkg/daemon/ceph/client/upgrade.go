@@ -18,6 +18,7 @@ package client
 
 import (
 	"encoding/json"
+	"strconv"
 	"strings"
 	"time"
 
@@ -173,6 +174,24 @@ func OkToStop(context *clusterd.Context, namespace, deployment, daemonType, daem
 	return nil
 }
 
+// OSDsOkToStop determines if several OSDs can be stopped together during an upgrade
+func OSDsOkToStop(context *clusterd.Context, namespace string, osdIDs []int) error {
+	if osdDoNothing(context, namespace) {
+		return nil
+	}
+
+	args := []string{"osd", "ok-to-stop"}
+	for _, id := range osdIDs {
+		args = append(args, strconv.Itoa(id))
+	}
+	buf, err := NewCephCommand(context, namespace, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "osds %v cannot be stopped together", osdIDs)
+	}
+	logger.Debugf("osds %v are ok to be updated together. %s", osdIDs, string(buf))
+	return nil
+}
+
 // OkToContinue determines if it's ok to continue an upgrade
 func OkToContinue(context *clusterd.Context, namespace, deployment, daemonType, daemonName string) error {
 	// the mon case is handled directly in the deployment where the mon checks for quorum
@@ -120,6 +120,30 @@ func TestOkToStopDaemon(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestOSDsOkToStop(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	mockCommand := func(command string, args ...string) (string, error) {
+		switch {
+		case args[0] == "osd" && args[1] == "ls":
+			return "[0,1,2,3,4,5]", nil
+		case args[0] == "osd" && args[1] == "tree":
+			return fakeOsdTree, nil
+		case args[0] == "osd" && args[1] == "ok-to-stop":
+			assert.Equal(t, []string{"3", "4", "5"}, args[2:5])
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	executor.MockExecuteCommandWithOutput = mockCommand
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		return mockCommand(command, args...)
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := OSDsOkToStop(context, "rook-ceph", []int{3, 4, 5})
+	assert.NoError(t, err)
+}
+
 func TestOkToContinue(t *testing.T) {
 	executor := &exectest.MockExecutor{}
 	context := &clusterd.Context{Executor: executor}
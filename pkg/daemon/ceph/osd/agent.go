@@ -17,6 +17,7 @@ limitations under the License.
 package osd
 
 import (
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephconfig "github.com/rook/rook/pkg/daemon/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
@@ -37,8 +38,10 @@ type OsdAgent struct {
 	storeConfig    config.StoreConfig
 	kv             *k8sutil.ConfigMapKVStore
 	pvcBacked      bool
-	configCounter  int32
-	osdsCompleted  chan struct{}
+	// the rules assigning the device classes of the devices without one
+	deviceClassRules []cephv1.DeviceClassRule
+	configCounter    int32
+	osdsCompleted    chan struct{}
 }
 
 type device struct {
@@ -48,17 +51,19 @@ type device struct {
 
 // NewAgent is the instantiation of the OSD agent
 func NewAgent(context *clusterd.Context, devices []DesiredDevice, metadataDevice string, forceFormat bool,
-	storeConfig config.StoreConfig, cluster *cephconfig.ClusterInfo, nodeName string, kv *k8sutil.ConfigMapKVStore, pvcBacked bool) *OsdAgent {
+	storeConfig config.StoreConfig, cluster *cephconfig.ClusterInfo, nodeName string, kv *k8sutil.ConfigMapKVStore, pvcBacked bool,
+	deviceClassRules []cephv1.DeviceClassRule) *OsdAgent {
 
 	return &OsdAgent{
-		devices:        devices,
-		metadataDevice: metadataDevice,
-		forceFormat:    forceFormat,
-		storeConfig:    storeConfig,
-		cluster:        cluster,
-		nodeName:       nodeName,
-		kv:             kv,
-		pvcBacked:      pvcBacked,
+		devices:          devices,
+		metadataDevice:   metadataDevice,
+		forceFormat:      forceFormat,
+		storeConfig:      storeConfig,
+		cluster:          cluster,
+		nodeName:         nodeName,
+		kv:               kv,
+		pvcBacked:        pvcBacked,
+		deviceClassRules: deviceClassRules,
 	}
 }
 
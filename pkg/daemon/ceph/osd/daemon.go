@@ -429,6 +429,13 @@ func getAvailableDevices(context *clusterd.Context, agent *OsdAgent) (*DeviceOsd
 			logger.Infof("skipping device %q until the admin specifies it can be used by an osd", device.Name)
 		}
 
+		if deviceInfo != nil && deviceInfo.Metadata == nil && deviceInfo.Config.DeviceClass == "" {
+			deviceInfo.Config.DeviceClass = deviceClassFromRules(agent.deviceClassRules, device)
+			if deviceInfo.Config.DeviceClass != "" {
+				logger.Infof("device %q is assigned device class %q by the device class rules", device.Name, deviceInfo.Config.DeviceClass)
+			}
+		}
+
 		if deviceInfo != nil {
 			// When running on PVC, we typically have a single device only
 			// So it's fine to name the first entry of the map "data" instead of the PVC name
@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/util/sys"
+)
+
+// deviceClassFromRules returns the device class of the first rule matching the device, or an empty string if no rule
+// matches
+func deviceClassFromRules(rules []cephv1.DeviceClassRule, device *sys.LocalDisk) string {
+	for _, rule := range rules {
+		if deviceClassRuleMatches(rule, device) {
+			return rule.DeviceClass
+		}
+	}
+	return ""
+}
+
+// deviceClassRuleMatches checks if the device has all the properties set in the rule
+func deviceClassRuleMatches(rule cephv1.DeviceClassRule, device *sys.LocalDisk) bool {
+	if rule.Rotational != nil && *rule.Rotational != device.Rotational {
+		return false
+	}
+	if rule.NVMe != nil && *rule.NVMe != isNVMeDevice(device) {
+		return false
+	}
+	if rule.MinSize != nil && device.Size < uint64(rule.MinSize.Value()) {
+		return false
+	}
+	if rule.MaxSize != nil && device.Size > uint64(rule.MaxSize.Value()) {
+		return false
+	}
+	if rule.Model != "" {
+		matched, err := regexp.MatchString(rule.Model, device.Model)
+		if err != nil {
+			logger.Errorf("regex failed on device %q model %q and rule %q. %v", device.Name, device.Model, rule.Model, err)
+			return false
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// isNVMeDevice checks if the device, or the device behind it when running on a PVC, is an NVMe device
+func isNVMeDevice(device *sys.LocalDisk) bool {
+	name := device.Name
+	if device.RealPath != "" {
+		name = filepath.Base(device.RealPath)
+	}
+	return strings.HasPrefix(name, "nvme")
+}
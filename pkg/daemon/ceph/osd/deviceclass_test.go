@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/util/sys"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestDeviceClassFromRules(t *testing.T) {
+	yes, no := true, false
+	minSize := resource.MustParse("1Ti")
+	maxSize := resource.MustParse("100Gi")
+	rules := []cephv1.DeviceClassRule{
+		{DeviceClass: "fastnvme", NVMe: &yes, Model: "^Samsung"},
+		{DeviceClass: "nvme", NVMe: &yes},
+		{DeviceClass: "small", Rotational: &no, MaxSize: &maxSize},
+		{DeviceClass: "archive", Rotational: &yes, MinSize: &minSize},
+	}
+
+	tests := []struct {
+		device   sys.LocalDisk
+		expected string
+	}{
+		{sys.LocalDisk{Name: "nvme0n1", Model: "Samsung SSD 970"}, "fastnvme"},
+		{sys.LocalDisk{Name: "nvme1n1", Model: "INTEL SSDPE2KX"}, "nvme"},
+		// the device behind a pvc is checked
+		{sys.LocalDisk{Name: "data", RealPath: "/dev/nvme2n1"}, "nvme"},
+		{sys.LocalDisk{Name: "sdb", Size: 50 * 1024 * 1024 * 1024}, "small"},
+		{sys.LocalDisk{Name: "sdc", Size: 500 * 1024 * 1024 * 1024}, ""},
+		{sys.LocalDisk{Name: "sdd", Rotational: true, Size: 4 * 1024 * 1024 * 1024 * 1024}, "archive"},
+		{sys.LocalDisk{Name: "sde", Rotational: true, Size: 50 * 1024 * 1024 * 1024}, ""},
+	}
+	for _, test := range tests {
+		device := test.device
+		assert.Equal(t, test.expected, deviceClassFromRules(rules, &device), device.Name)
+	}
+
+	assert.Equal(t, "", deviceClassFromRules(nil, &sys.LocalDisk{Name: "sdb"}))
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/util/sys"
+)
+
+const (
+	cryptsetupBinary = "cryptsetup"
+	luksType         = "luks2"
+)
+
+// openEncryptedBlock gets the dm-crypt key of the OSD from the KMS (a new one is generated on the first run) and
+// opens the block. The block of a new OSD is formatted with LUKS if this was not done yet and the block is empty, the
+// block of an existing OSD must already be formatted. The path of the clear device is returned.
+func (a *OsdAgent) openEncryptedBlock(context *clusterd.Context, block string, newOSD bool) (string, error) {
+	kmsConfig := kms.NewConfigFromEnv(context, a.cluster.Name)
+	key, err := kmsConfig.GetOrCreateEncryptionKey(a.nodeName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get encryption key for pvc %q", a.nodeName)
+	}
+
+	keyFile, err := writeKeyFile(key)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(keyFile)
+
+	// "isLuks" returns an error if the device has no LUKS header
+	if err := context.Executor.ExecuteCommand(cryptsetupBinary, "isLuks", block); err != nil {
+		// an OSD created in clear on the block would be destroyed by the formatting
+		if !newOSD {
+			return "", errors.Errorf("block %q of the existing OSD has no LUKS header, an OSD created without encryption cannot be encrypted", block)
+		}
+		fs, err := sys.GetDeviceFilesystems(block, context.Executor)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to check that block %q is empty before formatting it with dm-crypt", block)
+		}
+		if fs != "" {
+			return "", errors.Errorf("refusing to format block %q with dm-crypt, it has a %q filesystem", block, fs)
+		}
+
+		logger.Infof("formatting block %q with dm-crypt", block)
+		err = context.Executor.ExecuteCommand(cryptsetupBinary, "--batch-mode", "--verbose", "--type", luksType, "--key-file", keyFile, "luksFormat", block)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to format block %q with dm-crypt", block)
+		}
+	}
+
+	dmName := oposd.EncryptionDMName(a.nodeName, oposd.DmcryptBlockType)
+	dmPath := oposd.EncryptionDMPath(a.nodeName, oposd.DmcryptBlockType)
+	if _, err := os.Stat(dmPath); err == nil {
+		logger.Infof("encrypted block %q already opened on %q", block, dmPath)
+		return dmPath, nil
+	}
+
+	err = context.Executor.ExecuteCommand(cryptsetupBinary, "--verbose", "--key-file", keyFile, "luksOpen", block, dmName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open encrypted block %q", block)
+	}
+	logger.Infof("opened encrypted block %q on %q", block, dmPath)
+
+	return dmPath, nil
+}
+
+// closeEncryptedBlock closes the dm-crypt mapping, the osd pod opens it again on its own
+func (a *OsdAgent) closeEncryptedBlock(context *clusterd.Context) {
+	dmName := oposd.EncryptionDMName(a.nodeName, oposd.DmcryptBlockType)
+	if err := context.Executor.ExecuteCommand(cryptsetupBinary, "--verbose", "luksClose", dmName); err != nil {
+		logger.Warningf("failed to close encrypted block %q. %v", dmName, err)
+	}
+}
+
+func writeKeyFile(key string) (string, error) {
+	f, err := ioutil.TempFile("", "luks-key")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create temporary key file")
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(key); err != nil {
+		os.Remove(f.Name())
+		return "", errors.Wrap(err, "failed to write temporary key file")
+	}
+
+	return f.Name(), nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	cephconfig "github.com/rook/rook/pkg/daemon/ceph/config"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newEncryptionAgent returns an agent of an encrypted OSD on PVC, recording the cryptsetup commands it runs
+func newEncryptionAgent(luks bool, commands *[]string) (*OsdAgent, *clusterd.Context) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommand: func(command string, args ...string) error {
+			*commands = append(*commands, strings.Join(append([]string{command}, args...), " "))
+			if args[0] == "isLuks" && !luks {
+				return errors.New("not a luks device")
+			}
+			return nil
+		},
+	}
+	context := &clusterd.Context{Clientset: fake.NewSimpleClientset(), Executor: executor}
+	a := &OsdAgent{
+		cluster:     &cephconfig.ClusterInfo{Name: "rook-ceph", CephVersion: cephver.CephVersion{Major: 14, Minor: 2, Extra: 8}},
+		nodeName:    "set1-data-0",
+		storeConfig: config.StoreConfig{EncryptedDevice: true},
+		pvcBacked:   true,
+	}
+	return a, context
+}
+
+func TestOpenEncryptedBlock(t *testing.T) {
+	// a new device is formatted and opened with a new key
+	var commands []string
+	a, context := newEncryptionAgent(false, &commands)
+	dmPath, err := a.openEncryptedBlock(context, "/mnt/set1-data-0", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "/dev/mapper/set1-data-0-block-dmcrypt", dmPath)
+	assert.Equal(t, 3, len(commands))
+	assert.Equal(t, "cryptsetup isLuks /mnt/set1-data-0", commands[0])
+	assert.Contains(t, commands[1], "luksFormat /mnt/set1-data-0")
+	assert.Contains(t, commands[2], "luksOpen /mnt/set1-data-0 set1-data-0-block-dmcrypt")
+	_, err = context.Clientset.CoreV1().Secrets("rook-ceph").Get(kms.GenerateOSDEncryptionSecretName("set1-data-0"), metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// an already formatted device is only opened
+	commands = nil
+	a, context = newEncryptionAgent(true, &commands)
+	_, err = a.openEncryptedBlock(context, "/mnt/set1-data-0", false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(commands))
+	assert.Contains(t, commands[1], "luksOpen")
+
+	// an existing OSD created in clear is never formatted
+	commands = nil
+	a, context = newEncryptionAgent(false, &commands)
+	_, err = a.openEncryptedBlock(context, "/mnt/set1-data-0", false)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"cryptsetup isLuks /mnt/set1-data-0"}, commands)
+
+	// a new OSD is not formatted when the block is not empty
+	commands = nil
+	a, context = newEncryptionAgent(false, &commands)
+	context.Executor.(*exectest.MockExecutor).MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		assert.Equal(t, "udevadm", command)
+		return "DEVNAME=/dev/sdb\nID_FS_TYPE=ceph_bluestore", nil
+	}
+	_, err = a.openEncryptedBlock(context, "/mnt/set1-data-0", true)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"cryptsetup isLuks /mnt/set1-data-0"}, commands)
+}
+
+func TestCloseEncryptedBlock(t *testing.T) {
+	var commands []string
+	a, context := newEncryptionAgent(true, &commands)
+	a.closeEncryptedBlock(context)
+	assert.Equal(t, []string{"cryptsetup --verbose luksClose set1-data-0-block-dmcrypt"}, commands)
+}
+
+func TestInitializeEncryptedBlockPVC(t *testing.T) {
+	var commands []string
+	a, context := newEncryptionAgent(true, &commands)
+	context.Executor.(*exectest.MockExecutor).MockExecuteCommandWithCombinedOutput = func(command string, args ...string) (string, error) {
+		return "", errors.New("failed to prepare")
+	}
+	devices := &DeviceOsdMapping{
+		Entries: map[string]*DeviceOsdIDEntry{
+			"data": {Data: -1, Config: DesiredDevice{Name: "/mnt/set1-data-0"}},
+		},
+	}
+
+	// the mapping is closed when the prepare fails
+	_, _, err := a.initializeBlockPVC(context, devices, false)
+	assert.Error(t, err)
+	assert.Contains(t, commands[len(commands)-1], "luksClose set1-data-0-block-dmcrypt")
+
+	// the metadata pvc cannot be encrypted
+	commands = nil
+	devices.Entries["metadata"] = &DeviceOsdIDEntry{Data: -1, Config: DesiredDevice{Name: "/srv/set1-metadata-0"}}
+	_, _, err = a.initializeBlockPVC(context, devices, false)
+	assert.Error(t, err)
+	assert.Empty(t, commands)
+}
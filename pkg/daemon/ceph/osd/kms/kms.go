@@ -0,0 +1,235 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kms manages the OSD encryption keys in a Key Management System
+package kms
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Provider is the key of the connection details used to select the KMS backend
+	Provider = "KMS_PROVIDER"
+	// SecretsProviderName is the Kubernetes Secrets backend, used by default
+	SecretsProviderName = "secrets"
+	// VaultProviderName is the HashiCorp Vault backend
+	VaultProviderName = "vault"
+
+	// OsdEncryptionSecretNameKeyName is the key of the secret containing the OSD dm-crypt key
+	OsdEncryptionSecretNameKeyName = "dmcrypt-key"
+	osdEncryptionSecretNameFmt     = "rook-ceph-osd-encryption-key-%s"
+	// KMSTokenSecretNameKeyName is the key of the secret containing the KMS token
+	KMSTokenSecretNameKeyName = "token"
+	// the size of the dm-crypt key in bytes, same as what ceph-volume uses
+	encryptionKeySize = 128
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", "op-kms")
+
+// Config is the KMS configuration of a cluster
+type Config struct {
+	Provider  string
+	context   *clusterd.Context
+	namespace string
+	// connection details of the KMS, keys are the ones understood by the backend (VAULT_ADDR etc)
+	connectionDetails map[string]string
+}
+
+// NewConfig returns a KMS configuration from the connection details of the cluster spec
+func NewConfig(context *clusterd.Context, namespace string, connectionDetails map[string]string) *Config {
+	config := &Config{
+		context:           context,
+		namespace:         namespace,
+		connectionDetails: connectionDetails,
+		Provider:          connectionDetails[Provider],
+	}
+
+	// Kubernetes Secrets is the default backend
+	if config.Provider == "" {
+		config.Provider = SecretsProviderName
+	}
+
+	return config
+}
+
+// NewConfigFromEnv returns a KMS configuration built from the environment variables set by the operator
+// on the OSD prepare pods
+func NewConfigFromEnv(context *clusterd.Context, namespace string) *Config {
+	connectionDetails := make(map[string]string)
+	for _, key := range knownConnectionDetails() {
+		if val := os.Getenv(key); val != "" {
+			connectionDetails[key] = val
+		}
+	}
+
+	return NewConfig(context, namespace, connectionDetails)
+}
+
+// PutSecret stores the secret in the KMS
+func (c *Config) PutSecret(secretName, secretValue string) error {
+	switch c.Provider {
+	case SecretsProviderName:
+		return c.putSecretInKubernetes(secretName, secretValue)
+	case VaultProviderName:
+		return c.newVaultClient().putSecret(secretName, secretValue)
+	}
+
+	return errors.Errorf("unsupported kms provider %q", c.Provider)
+}
+
+// GetSecret returns the secret stored in the KMS
+func (c *Config) GetSecret(secretName string) (string, error) {
+	switch c.Provider {
+	case SecretsProviderName:
+		return c.getSecretFromKubernetes(secretName)
+	case VaultProviderName:
+		return c.newVaultClient().getSecret(secretName)
+	}
+
+	return "", errors.Errorf("unsupported kms provider %q", c.Provider)
+}
+
+// DeleteSecret removes the secret from the KMS
+func (c *Config) DeleteSecret(secretName string) error {
+	switch c.Provider {
+	case SecretsProviderName:
+		return c.deleteSecretFromKubernetes(secretName)
+	case VaultProviderName:
+		return c.newVaultClient().deleteSecret(secretName)
+	}
+
+	return errors.Errorf("unsupported kms provider %q", c.Provider)
+}
+
+// GetOrCreateEncryptionKey returns the OSD dm-crypt key stored in the KMS for the given PVC. If there is none yet a
+// new key is generated and stored.
+func (c *Config) GetOrCreateEncryptionKey(pvcName string) (string, error) {
+	secretName := GenerateOSDEncryptionSecretName(pvcName)
+
+	key, err := c.GetSecret(secretName)
+	if err == nil {
+		// an empty key cannot be replaced silently since the existing secret must never be overwritten
+		if key == "" {
+			return "", errors.Errorf("encryption key %q in kms %q is empty, delete it so that a new key is generated for pvc %q", secretName, c.Provider, pvcName)
+		}
+		logger.Infof("found existing encryption key for pvc %q in kms %q", pvcName, c.Provider)
+		return key, nil
+	}
+	if !isNotFound(err) {
+		return "", errors.Wrapf(err, "failed to fetch encryption key %q from kms %q", secretName, c.Provider)
+	}
+
+	key, err = generateOSDEncryptionKey()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate encryption key")
+	}
+
+	err = c.PutSecret(secretName, key)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to store encryption key %q in kms %q", secretName, c.Provider)
+	}
+	logger.Infof("stored new encryption key for pvc %q in kms %q", pvcName, c.Provider)
+
+	return key, nil
+}
+
+// ValidateConnectionDetails validates the KMS configuration of the cluster spec
+func ValidateConnectionDetails(context *clusterd.Context, securitySpec cephv1.SecuritySpec, namespace string) error {
+	kms := securitySpec.KeyManagementService
+	if !kms.IsEnabled() {
+		return nil
+	}
+
+	provider := kms.ConnectionDetails[Provider]
+	switch provider {
+	case "", SecretsProviderName:
+		return nil
+	case VaultProviderName:
+		if !kms.IsTokenAuthEnabled() {
+			return errors.New("failed to validate vault connection details, tokenSecretName must be set")
+		}
+		secret, err := context.Clientset.CoreV1().Secrets(namespace).Get(kms.TokenSecretName, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch kms token secret %q", kms.TokenSecretName)
+		}
+		if len(secret.Data[KMSTokenSecretNameKeyName]) == 0 {
+			return errors.Errorf("failed to read k8s kms secret %q key %q (not found or empty)", kms.TokenSecretName, KMSTokenSecretNameKeyName)
+		}
+		return validateVaultConnectionDetails(kms.ConnectionDetails)
+	}
+
+	return errors.Errorf("unsupported kms provider %q", provider)
+}
+
+// ConfigToEnvVar returns the environment variables needed to reach the KMS from a pod
+func ConfigToEnvVar(securitySpec cephv1.SecuritySpec) []v1.EnvVar {
+	kms := securitySpec.KeyManagementService
+	envs := []v1.EnvVar{}
+
+	// sort the keys so the pod spec does not change from one reconcile to another
+	keys := make([]string, 0, len(kms.ConnectionDetails))
+	for k := range kms.ConnectionDetails {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		envs = append(envs, v1.EnvVar{Name: k, Value: kms.ConnectionDetails[k]})
+	}
+
+	if kms.IsTokenAuthEnabled() {
+		envs = append(envs, v1.EnvVar{
+			Name: vaultTokenEnvVarName,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: kms.TokenSecretName},
+					Key:                  KMSTokenSecretNameKeyName,
+				},
+			},
+		})
+	}
+
+	return envs
+}
+
+// GenerateOSDEncryptionSecretName returns the name of the secret holding the dm-crypt key of an OSD on PVC
+func GenerateOSDEncryptionSecretName(pvcName string) string {
+	return fmt.Sprintf(osdEncryptionSecretNameFmt, pvcName)
+}
+
+func generateOSDEncryptionKey() (string, error) {
+	key := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func knownConnectionDetails() []string {
+	return append([]string{Provider}, vaultConnectionDetails...)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKubernetesSecretsBackend(t *testing.T) {
+	context := &clusterd.Context{Clientset: fake.NewSimpleClientset()}
+	config := NewConfig(context, "rook-ceph", map[string]string{})
+	assert.Equal(t, SecretsProviderName, config.Provider)
+
+	// no key yet, one is generated
+	key, err := config.GetOrCreateEncryptionKey("set1-data-0")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, key)
+
+	secret, err := context.Clientset.CoreV1().Secrets("rook-ceph").Get("rook-ceph-osd-encryption-key-set1-data-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, key, secret.StringData[OsdEncryptionSecretNameKeyName])
+
+	// the fake clientset does not convert StringData to Data
+	secret.Data = map[string][]byte{OsdEncryptionSecretNameKeyName: []byte(key)}
+	_, err = context.Clientset.CoreV1().Secrets("rook-ceph").Update(secret)
+	assert.NoError(t, err)
+
+	// the existing key is returned
+	existingKey, err := config.GetOrCreateEncryptionKey("set1-data-0")
+	assert.NoError(t, err)
+	assert.Equal(t, key, existingKey)
+
+	// an existing key is never overwritten
+	err = config.PutSecret(GenerateOSDEncryptionSecretName("set1-data-0"), "foo")
+	assert.Error(t, err)
+
+	assert.NoError(t, config.DeleteSecret(GenerateOSDEncryptionSecretName("set1-data-0")))
+	_, err = config.GetSecret(GenerateOSDEncryptionSecretName("set1-data-0"))
+	assert.True(t, isNotFound(err))
+
+	// an existing empty key is reported rather than failing to create a new one
+	err = config.PutSecret(GenerateOSDEncryptionSecretName("set1-data-1"), "")
+	assert.NoError(t, err)
+	_, err = config.GetOrCreateEncryptionKey("set1-data-1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is empty")
+
+	// deleting twice is fine
+	assert.NoError(t, config.DeleteSecret(GenerateOSDEncryptionSecretName("set1-data-0")))
+}
+
+func TestUnsupportedProvider(t *testing.T) {
+	config := NewConfig(&clusterd.Context{}, "rook-ceph", map[string]string{Provider: "foo"})
+	assert.Error(t, config.PutSecret("a", "b"))
+	_, err := config.GetSecret("a")
+	assert.Error(t, err)
+	assert.Error(t, config.DeleteSecret("a"))
+}
+
+func TestValidateConnectionDetails(t *testing.T) {
+	context := &clusterd.Context{Clientset: fake.NewSimpleClientset()}
+	ns := "rook-ceph"
+
+	// kms not enabled
+	assert.NoError(t, ValidateConnectionDetails(context, cephv1.SecuritySpec{}, ns))
+
+	// kubernetes secrets
+	security := cephv1.SecuritySpec{KeyManagementService: cephv1.KeyManagementServiceSpec{ConnectionDetails: map[string]string{Provider: SecretsProviderName}}}
+	assert.NoError(t, ValidateConnectionDetails(context, security, ns))
+
+	// unknown provider
+	security.KeyManagementService.ConnectionDetails[Provider] = "foo"
+	assert.Error(t, ValidateConnectionDetails(context, security, ns))
+
+	// vault without token
+	security.KeyManagementService.ConnectionDetails[Provider] = VaultProviderName
+	assert.Error(t, ValidateConnectionDetails(context, security, ns))
+
+	// vault with a missing token secret
+	security.KeyManagementService.TokenSecretName = "vault-token"
+	assert.Error(t, ValidateConnectionDetails(context, security, ns))
+
+	// vault with an empty token secret
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "vault-token", Namespace: ns}}
+	_, err := context.Clientset.CoreV1().Secrets(ns).Create(secret)
+	assert.NoError(t, err)
+	assert.Error(t, ValidateConnectionDetails(context, security, ns))
+
+	// vault without address
+	secret.Data = map[string][]byte{KMSTokenSecretNameKeyName: []byte("myt-otkenbenvqrev")}
+	_, err = context.Clientset.CoreV1().Secrets(ns).Update(secret)
+	assert.NoError(t, err)
+	assert.Error(t, ValidateConnectionDetails(context, security, ns))
+
+	// vault with an invalid backend
+	security.KeyManagementService.ConnectionDetails[vaultAddressEnvVarName] = "https://1.1.1.1:8200"
+	security.KeyManagementService.ConnectionDetails[vaultBackendEnvVarName] = "v3"
+	assert.Error(t, ValidateConnectionDetails(context, security, ns))
+
+	// valid vault config
+	security.KeyManagementService.ConnectionDetails[vaultBackendEnvVarName] = "v1"
+	assert.NoError(t, ValidateConnectionDetails(context, security, ns))
+}
+
+func TestConfigToEnvVar(t *testing.T) {
+	security := cephv1.SecuritySpec{KeyManagementService: cephv1.KeyManagementServiceSpec{
+		ConnectionDetails: map[string]string{Provider: VaultProviderName, vaultAddressEnvVarName: "https://1.1.1.1:8200"},
+		TokenSecretName:   "vault-token",
+	}}
+
+	envs := ConfigToEnvVar(security)
+	assert.Equal(t, 3, len(envs))
+	assert.Equal(t, Provider, envs[0].Name)
+	assert.Equal(t, vaultAddressEnvVarName, envs[1].Name)
+	assert.Equal(t, vaultTokenEnvVarName, envs[2].Name)
+	assert.Equal(t, "vault-token", envs[2].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, KMSTokenSecretNameKeyName, envs[2].ValueFrom.SecretKeyRef.Key)
+
+	// no kms, no env
+	assert.Equal(t, 0, len(ConfigToEnvVar(cephv1.SecuritySpec{})))
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// errSecretNotFound is returned by the KMS backends when the requested secret does not exist
+var errSecretNotFound = errors.New("secret not found")
+
+func isNotFound(err error) bool {
+	return kerrors.IsNotFound(errors.Cause(err)) || errors.Cause(err) == errSecretNotFound
+}
+
+func (c *Config) putSecretInKubernetes(secretName, secretValue string) error {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: c.namespace,
+			Labels: map[string]string{
+				k8sutil.AppAttr: "rook-ceph-osd",
+			},
+		},
+		StringData: map[string]string{
+			OsdEncryptionSecretNameKeyName: secretValue,
+		},
+		Type: k8sutil.RookType,
+	}
+
+	_, err := c.context.Clientset.CoreV1().Secrets(c.namespace).Create(secret)
+	if err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create secret %q", secretName)
+		}
+		// never silently replace an existing key, the device might already be encrypted with it
+		return errors.Errorf("secret %q already exists", secretName)
+	}
+
+	logger.Infof("created kubernetes secret %q", secretName)
+	return nil
+}
+
+func (c *Config) getSecretFromKubernetes(secretName string) (string, error) {
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.namespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get secret %q", secretName)
+	}
+
+	return string(secret.Data[OsdEncryptionSecretNameKeyName]), nil
+}
+
+func (c *Config) deleteSecretFromKubernetes(secretName string) error {
+	err := c.context.Clientset.CoreV1().Secrets(c.namespace).Delete(secretName, &metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete secret %q", secretName)
+	}
+
+	return nil
+}
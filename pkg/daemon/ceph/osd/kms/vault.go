@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	vaultAddressEnvVarName     = "VAULT_ADDR"
+	vaultBackendPathEnvVarName = "VAULT_BACKEND_PATH"
+	vaultBackendEnvVarName     = "VAULT_BACKEND"
+	vaultNamespaceEnvVarName   = "VAULT_NAMESPACE"
+	vaultSkipVerifyEnvVarName  = "VAULT_SKIP_VERIFY"
+	vaultTokenEnvVarName       = "VAULT_TOKEN"
+
+	defaultVaultBackendPath = "secret"
+	vaultKVv1               = "v1"
+	vaultKVv2               = "v2"
+	vaultRequestTimeout     = 30 * time.Second
+)
+
+var vaultConnectionDetails = []string{
+	vaultAddressEnvVarName,
+	vaultBackendPathEnvVarName,
+	vaultBackendEnvVarName,
+	vaultNamespaceEnvVarName,
+	vaultSkipVerifyEnvVarName,
+}
+
+// vaultClient talks to the key/value secret engine of a Vault compatible API
+type vaultClient struct {
+	address     string
+	backendPath string
+	kvVersion   string
+	namespace   string
+	token       string
+	httpClient  *http.Client
+}
+
+type vaultKVResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+func (c *Config) newVaultClient() *vaultClient {
+	client := &vaultClient{
+		address:     strings.TrimSuffix(c.connectionDetails[vaultAddressEnvVarName], "/"),
+		backendPath: strings.Trim(c.connectionDetails[vaultBackendPathEnvVarName], "/"),
+		kvVersion:   c.connectionDetails[vaultBackendEnvVarName],
+		namespace:   c.connectionDetails[vaultNamespaceEnvVarName],
+		// the token is injected by the operator from the token secret
+		token: os.Getenv(vaultTokenEnvVarName),
+	}
+	if client.backendPath == "" {
+		client.backendPath = defaultVaultBackendPath
+	}
+	if client.kvVersion == "" {
+		client.kvVersion = vaultKVv2
+	}
+
+	transport := &http.Transport{}
+	if c.connectionDetails[vaultSkipVerifyEnvVarName] == "true" {
+		// #nosec G402 the user explicitly asked to skip the verification
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	client.httpClient = &http.Client{Transport: transport, Timeout: vaultRequestTimeout}
+
+	return client
+}
+
+func validateVaultConnectionDetails(connectionDetails map[string]string) error {
+	if connectionDetails[vaultAddressEnvVarName] == "" {
+		return errors.Errorf("failed to validate vault connection details, %q is not set", vaultAddressEnvVarName)
+	}
+
+	backend := connectionDetails[vaultBackendEnvVarName]
+	if backend != "" && backend != vaultKVv1 && backend != vaultKVv2 {
+		return errors.Errorf("failed to validate vault connection details, invalid %q %q, must be %q or %q", vaultBackendEnvVarName, backend, vaultKVv1, vaultKVv2)
+	}
+
+	return nil
+}
+
+// dataURL returns the url of the secret, the kv v2 engine nests the secrets under "data"
+func (v *vaultClient) dataURL(secretName string) string {
+	if v.kvVersion == vaultKVv1 {
+		return fmt.Sprintf("%s/v1/%s/%s", v.address, v.backendPath, secretName)
+	}
+	return fmt.Sprintf("%s/v1/%s/data/%s", v.address, v.backendPath, secretName)
+}
+
+// metadataURL returns the url to use to delete all the versions of a secret
+func (v *vaultClient) metadataURL(secretName string) string {
+	if v.kvVersion == vaultKVv1 {
+		return v.dataURL(secretName)
+	}
+	return fmt.Sprintf("%s/v1/%s/metadata/%s", v.address, v.backendPath, secretName)
+}
+
+func (v *vaultClient) putSecret(secretName, secretValue string) error {
+	data := map[string]interface{}{secretName: secretValue}
+	if v.kvVersion == vaultKVv2 {
+		data = map[string]interface{}{"data": data}
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal vault request")
+	}
+
+	_, err = v.do(http.MethodPut, v.dataURL(secretName), body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to put secret %q in vault", secretName)
+	}
+
+	logger.Infof("stored secret %q in vault", secretName)
+	return nil
+}
+
+func (v *vaultClient) getSecret(secretName string) (string, error) {
+	body, err := v.do(http.MethodGet, v.dataURL(secretName), nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get secret %q from vault", secretName)
+	}
+
+	var response vaultKVResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal vault response")
+	}
+
+	data := response.Data
+	if v.kvVersion == vaultKVv2 {
+		nested, ok := data["data"].(map[string]interface{})
+		if !ok {
+			return "", errors.Wrapf(errSecretNotFound, "no data in vault secret %q", secretName)
+		}
+		data = nested
+	}
+
+	value, ok := data[secretName].(string)
+	if !ok {
+		return "", errors.Wrapf(errSecretNotFound, "key %q not found in vault secret", secretName)
+	}
+
+	return value, nil
+}
+
+func (v *vaultClient) deleteSecret(secretName string) error {
+	_, err := v.do(http.MethodDelete, v.metadataURL(secretName), nil)
+	if err != nil && !isNotFound(err) {
+		return errors.Wrapf(err, "failed to delete secret %q from vault", secretName)
+	}
+
+	return nil
+}
+
+func (v *vaultClient) do(method, url string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build vault request")
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	req.Header.Set("Content-Type", "application/json")
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to reach vault at %q", v.address)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read vault response")
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errSecretNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, errors.Errorf("vault returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return respBody, nil
+}
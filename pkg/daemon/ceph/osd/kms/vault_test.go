@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVault is a minimal in-memory kv engine
+func fakeVault(t *testing.T, kvVersion string) *httptest.Server {
+	store := map[string]map[string]interface{}{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "mytoken", r.Header.Get("X-Vault-Token"))
+		name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if kvVersion == vaultKVv2 && r.Method != http.MethodDelete {
+			assert.True(t, strings.HasPrefix(r.URL.Path, "/v1/rook/data/"))
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := ioutil.ReadAll(r.Body)
+			var data map[string]interface{}
+			assert.NoError(t, json.Unmarshal(body, &data))
+			store[name] = data
+		case http.MethodGet:
+			data, ok := store[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			b, _ := json.Marshal(map[string]interface{}{"data": data})
+			_, _ = w.Write(b)
+		case http.MethodDelete:
+			if _, ok := store[name]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(store, name)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestVaultBackend(t *testing.T) {
+	os.Setenv(vaultTokenEnvVarName, "mytoken")
+	defer os.Unsetenv(vaultTokenEnvVarName)
+
+	for _, kvVersion := range []string{vaultKVv1, vaultKVv2} {
+		server := fakeVault(t, kvVersion)
+
+		config := NewConfig(&clusterd.Context{}, "rook-ceph", map[string]string{
+			Provider:                   VaultProviderName,
+			vaultAddressEnvVarName:     server.URL,
+			vaultBackendPathEnvVarName: "rook",
+			vaultBackendEnvVarName:     kvVersion,
+		})
+
+		_, err := config.GetSecret("foo")
+		assert.True(t, isNotFound(err), kvVersion)
+
+		key, err := config.GetOrCreateEncryptionKey("set1-data-0")
+		assert.NoError(t, err, kvVersion)
+		assert.NotEmpty(t, key)
+
+		existingKey, err := config.GetOrCreateEncryptionKey("set1-data-0")
+		assert.NoError(t, err, kvVersion)
+		assert.Equal(t, key, existingKey)
+
+		assert.NoError(t, config.DeleteSecret(GenerateOSDEncryptionSecretName("set1-data-0")))
+		_, err = config.GetSecret(GenerateOSDEncryptionSecretName("set1-data-0"))
+		assert.True(t, isNotFound(err), kvVersion)
+
+		server.Close()
+	}
+}
@@ -127,6 +127,15 @@ func (a *OsdAgent) configureCVDevices(context *clusterd.Context, devices *Device
 				// I'm leaving this code with an empty metadata device for now
 				metadataBlock = ""
 
+				// The OSD lives inside the LUKS device
+				if a.storeConfig.EncryptedDevice {
+					block, err = a.openEncryptedBlock(context, block, false)
+					if err != nil {
+						return nil, errors.Wrap(err, "failed to open encrypted block")
+					}
+					defer a.closeEncryptedBlock(context)
+				}
+
 				rawOsds, err = GetCephVolumeRawOSDs(context, a.cluster.Name, a.cluster.FSID, block, metadataBlock, lvBackedPV)
 				if err != nil {
 					logger.Infof("failed to get device already provisioned by ceph-volume raw. %v", err)
@@ -204,6 +213,11 @@ func (a *OsdAgent) configureCVDevices(context *clusterd.Context, devices *Device
 	// List THE configured OSD with ceph-volume raw mode
 	if a.cluster.CephVersion.IsAtLeast(cephVolumeRawModeMinCephVersion) && !lvBackedPV {
 		block = fmt.Sprintf("/mnt/%s", a.nodeName)
+		// The encrypted block was opened by initializeBlockPVC, the OSD pod opens it again by itself
+		if a.pvcBacked && a.storeConfig.EncryptedDevice {
+			block = oposd.EncryptionDMPath(a.nodeName, oposd.DmcryptBlockType)
+			defer a.closeEncryptedBlock(context)
+		}
 		rawOsds, err = GetCephVolumeRawOSDs(context, a.cluster.Name, a.cluster.FSID, block, metadataBlock, lvBackedPV)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get devices already provisioned by ceph-volume raw")
@@ -214,7 +228,7 @@ func (a *OsdAgent) configureCVDevices(context *clusterd.Context, devices *Device
 	return osds, err
 }
 
-func (a *OsdAgent) initializeBlockPVC(context *clusterd.Context, devices *DeviceOsdMapping, lvBackedPV bool) (string, string, error) {
+func (a *OsdAgent) initializeBlockPVC(context *clusterd.Context, devices *DeviceOsdMapping, lvBackedPV bool) (blockPath, metadataBlockPath string, err error) {
 
 	// we need to return the block if raw mode is used and the lv if lvm mode
 	baseCommand := "stdbuf"
@@ -225,10 +239,20 @@ func (a *OsdAgent) initializeBlockPVC(context *clusterd.Context, devices *Device
 		cephVolumeMode = "raw"
 	}
 
+	// Rook manages the dm-crypt key of OSDs on PVC, this is only possible with the raw mode
+	if a.storeConfig.EncryptedDevice && cephVolumeMode != "raw" {
+		return "", "", errors.Errorf("encryption of OSDs on PVC requires ceph-volume raw mode (ceph %s or newer) and a PV not backed by an LV", cephVolumeRawModeMinCephVersion.String())
+	}
+
+	// Only the main block is encrypted, the metadata device would hold the RocksDB database in clear
+	if _, ok := devices.Entries["metadata"]; ok && a.storeConfig.EncryptedDevice {
+		return "", "", errors.New("encryption of OSDs on PVC does not support a metadata PVC")
+	}
+
 	// Create a specific log directory so that each prepare command will have its own log
 	// Only do this if nothing is present so that we don't override existing logs
 	cvLogDir = path.Join(cephLogDir, a.nodeName)
-	err := os.MkdirAll(cvLogDir, 0755)
+	err = os.MkdirAll(cvLogDir, 0755)
 	if err != nil {
 		logger.Errorf("failed to create ceph-volume log directory %q, continue with default %q. %v", cvLogDir, cephLogDir, err)
 		baseArgs = []string{"-oL", cephVolumeCmd, cephVolumeMode, "prepare", "--bluestore"}
@@ -239,7 +263,6 @@ func (a *OsdAgent) initializeBlockPVC(context *clusterd.Context, devices *Device
 
 	var metadataArg []string
 	var metadataDev bool
-	var blockPath, metadataBlockPath string
 
 	// Problem: map is an unordered collection
 	// therefore the iteration order of a map is not guaranteed to be the same every time you iterate over it.
@@ -267,7 +290,6 @@ func (a *OsdAgent) initializeBlockPVC(context *clusterd.Context, devices *Device
 
 		if device.Data == -1 {
 			logger.Infof("configuring new device %q", device.Config.Name)
-			var err error
 			var deviceArg string
 
 			if lvBackedPV {
@@ -280,12 +302,30 @@ func (a *OsdAgent) initializeBlockPVC(context *clusterd.Context, devices *Device
 				deviceArg = device.Config.Name
 			}
 
+			// Prepare the OSD on top of the clear device
+			if a.storeConfig.EncryptedDevice {
+				deviceArg, err = a.openEncryptedBlock(context, deviceArg, true)
+				if err != nil {
+					return "", "", errors.Wrapf(err, "failed to setup encryption on device %q", device.Config.Name)
+				}
+				// The mapping stays open to list the prepared OSD, it is only closed here if the prepare fails
+				defer func() {
+					if err != nil {
+						a.closeEncryptedBlock(context)
+					}
+				}()
+			}
+
 			immediateExecuteArgs := append(baseArgs, []string{
 				"--data",
 				deviceArg,
 			}...)
 
 			crushDeviceClass := os.Getenv(oposd.CrushDeviceClassVarName)
+			if crushDeviceClass == "" {
+				// the device class set by the device class rules of the cluster CR
+				crushDeviceClass = device.Config.DeviceClass
+			}
 			if crushDeviceClass != "" {
 				immediateExecuteArgs = append(immediateExecuteArgs, []string{crushDeviceClassFlag, crushDeviceClass}...)
 			}
@@ -17,84 +17,188 @@ limitations under the License.
 package operator
 
 import (
+	"reflect"
+	"strconv"
+
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
-	rookclient "github.com/rook/rook/pkg/client/clientset/versioned"
+	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
-	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/file"
+	"github.com/rook/rook/pkg/operator/ceph/nfs"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/ceph/pool"
 	"k8s.io/api/admission/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
-	"strconv"
+)
+
+const (
+	// the minimum number of mons of a cluster once it has been created with at least as many mons
+	minSafeMonCount = 3
 )
 
 var (
-	cephclusterResource   = metav1.GroupVersionResource{Group: cephv1.CustomResourceGroup, Version: cephv1.Version, Resource: opcontroller.ClusterResource.Plural}
-	rookClientSet         rookclient.Interface
 	universalDeserializer = serializer.NewCodecFactory(runtime.NewScheme()).UniversalDeserializer()
 )
 
-func validateUpdatedCephCluster(updatedCephCluster cephv1.CephCluster, found *cephv1.CephCluster) error {
-	if updatedCephCluster.Spec.DataDirHostPath != found.Spec.DataDirHostPath {
-		return errors.Errorf("invalid update : DataDirHostPath change from %q to %q is not allowed", found.Spec.DataDirHostPath, updatedCephCluster.Spec.DataDirHostPath)
+// ValidateCephResource validates the creation and the update of the ceph resources. The settings requiring the ceph
+// cluster are validated by the operator when the resources are reconciled.
+func ValidateCephResource(request *v1beta1.AdmissionRequest, context *clusterd.Context) error {
+	if request.Operation != v1beta1.Create && request.Operation != v1beta1.Update {
+		return nil
 	}
 
-	if updatedCephCluster.Spec.Network.HostNetwork != found.Spec.Network.HostNetwork {
-		return errors.Errorf("invalid update : HostNetwork change from %q to %q is not allowed", strconv.FormatBool(found.Spec.Network.HostNetwork), strconv.FormatBool(updatedCephCluster.Spec.Network.HostNetwork))
-	}
+	switch request.Resource.Resource {
+	case "cephclusters":
+		cluster, oldCluster := &cephv1.CephCluster{}, &cephv1.CephCluster{}
+		if skip, err := decodeAdmissionRequest(request, cluster, oldCluster); skip || err != nil {
+			return err
+		}
+		if request.Operation == v1beta1.Update {
+			if err := validateUpdatedCephCluster(*cluster, oldCluster); err != nil {
+				return errors.Wrap(err, "failed to validate updated cephcluster")
+			}
+			return validateCephCluster(cluster)
+		}
+		if err := validateCreatedCephCluster(cluster); err != nil {
+			return err
+		}
+		return validateCephCluster(cluster)
 
-	if updatedCephCluster.Spec.Network.Provider != found.Spec.Network.Provider {
-		return errors.Errorf("invalid update : Provider change from %q to %q is not allowed", found.Spec.Network.Provider, updatedCephCluster.Spec.Network.Provider)
+	case "cephblockpools":
+		p, oldPool := &cephv1.CephBlockPool{}, &cephv1.CephBlockPool{}
+		if skip, err := decodeAdmissionRequest(request, p, oldPool); skip || err != nil {
+			return err
+		}
+		if request.Operation == v1beta1.Update && oldPool.Spec.IsReplicated() != p.Spec.IsReplicated() {
+			return errors.Errorf("invalid update : pool %q cannot change between replicated and erasure coded", p.Name)
+		}
+		return pool.ValidatePool(nil, p)
+
+	case "cephfilesystems":
+		f, oldFilesystem := &cephv1.CephFilesystem{}, &cephv1.CephFilesystem{}
+		if skip, err := decodeAdmissionRequest(request, f, oldFilesystem); skip || err != nil {
+			return err
+		}
+		return file.ValidateFilesystem(nil, f)
+
+	case "cephobjectstores":
+		s, oldStore := &cephv1.CephObjectStore{}, &cephv1.CephObjectStore{}
+		if skip, err := decodeAdmissionRequest(request, s, oldStore); skip || err != nil {
+			return err
+		}
+		return object.ValidateStore(nil, s)
+
+	case "cephnfses":
+		n, oldNFS := &cephv1.CephNFS{}, &cephv1.CephNFS{}
+		if skip, err := decodeAdmissionRequest(request, n, oldNFS); skip || err != nil {
+			return err
+		}
+		return nfs.ValidateGanesha(nil, n)
+
+	case "cephclients":
+		c, oldClient := &cephv1.CephClient{}, &cephv1.CephClient{}
+		if skip, err := decodeAdmissionRequest(request, c, oldClient); skip || err != nil {
+			return err
+		}
+		return client.ValidateClient(nil, c)
 	}
 
 	return nil
 }
 
-func ValidateCephResource(request *v1beta1.AdmissionRequest, context *clusterd.Context) error {
-	rookClientSet = context.RookClientset
-	raw := request.Object.Raw
-	reqCephCluster := cephv1.CephCluster{}
-	// Deserializing the incoming CephCluster object
-	if _, _, err := universalDeserializer.Decode(raw, nil, &reqCephCluster); err != nil {
-		return errors.Wrap(err, "failed to deserialize pod object")
-	}
-	// Checks if CephCluster is being updated and rejects the request if the dataDirHostPath is changed from initial value
-	if isCephClusterUpdate(request) {
-		// Fetch the existing ceph cluster object
-		found, err := fetchExistingCephCluster(request)
-		if err != nil {
-			return errors.Wrap(err, "failed to fetch existing cephcluster")
-		}
-		err = validateUpdatedCephCluster(reqCephCluster, found)
-		if err != nil {
-			return errors.Wrap(err, "failed to validate updated cephcluster")
-		}
+// decodeAdmissionRequest deserializes the object of the request, and the previous object of an update. The update
+// is skipped when the spec did not change, such as when the operator adds a finalizer or updates the status.
+func decodeAdmissionRequest(request *v1beta1.AdmissionRequest, obj, oldObj runtime.Object) (bool, error) {
+	if _, _, err := universalDeserializer.Decode(request.Object.Raw, nil, obj); err != nil {
+		return false, errors.Wrapf(err, "failed to deserialize %s object", request.Resource.Resource)
 	}
-	if isCephClusterCreate(request) {
-		//If external mode enabled, then check if other fields are empty
-		if reqCephCluster.Spec.External.Enable {
-			if reqCephCluster.Spec.Mon != (cephv1.MonSpec{}) || reqCephCluster.Spec.Dashboard != (cephv1.DashboardSpec{}) || reqCephCluster.Spec.Monitoring != (cephv1.MonitoringSpec{}) || reqCephCluster.Spec.DisruptionManagement != (cephv1.DisruptionManagementSpec{}) || len(reqCephCluster.Spec.Mgr.Modules) > 0 || len(reqCephCluster.Spec.Network.Provider) > 0 || len(reqCephCluster.Spec.Network.Selectors) > 0 {
-				return errors.New("invalid create : external mode enabled cannot have mon,dashboard,monitoring,network,disruptionManagement,storage fields in CR")
-			}
+	// the namespace of the request is not always set in the object, such as when the object is created in the
+	// current namespace of kubectl
+	if o, ok := obj.(metav1.Object); ok && o.GetNamespace() == "" {
+		o.SetNamespace(request.Namespace)
+	}
+	if request.Operation != v1beta1.Update {
+		return false, nil
+	}
+	if _, _, err := universalDeserializer.Decode(request.OldObject.Raw, nil, oldObj); err != nil {
+		return false, errors.Wrapf(err, "failed to deserialize previous %s object", request.Resource.Resource)
+	}
+	spec := reflect.ValueOf(obj).Elem().FieldByName("Spec").Interface()
+	oldSpec := reflect.ValueOf(oldObj).Elem().FieldByName("Spec").Interface()
+	return reflect.DeepEqual(spec, oldSpec), nil
+}
 
+func validateCephCluster(cluster *cephv1.CephCluster) error {
+	for who := range cluster.Spec.CephConfig {
+		if err := config.ValidateCephConfigWho(who); err != nil {
+			return errors.Wrap(err, "invalid cephConfig")
 		}
 	}
 	return nil
 }
 
-func isCephClusterCreate(request *v1beta1.AdmissionRequest) bool {
-	return request.Resource == cephclusterResource && request.Operation == v1beta1.Create
+func validateCreatedCephCluster(cluster *cephv1.CephCluster) error {
+	//If external mode enabled, then check if other fields are empty
+	if cluster.Spec.External.Enable {
+		if !reflect.DeepEqual(cluster.Spec.Mon, cephv1.MonSpec{}) || cluster.Spec.Dashboard != (cephv1.DashboardSpec{}) || !reflect.DeepEqual(cluster.Spec.Monitoring, cephv1.MonitoringSpec{}) || cluster.Spec.DisruptionManagement != (cephv1.DisruptionManagementSpec{}) || len(cluster.Spec.Mgr.Modules) > 0 || len(cluster.Spec.Network.Provider) > 0 || len(cluster.Spec.Network.Selectors) > 0 {
+			return errors.New("invalid create : external mode enabled cannot have mon,dashboard,monitoring,network,disruptionManagement,storage fields in CR")
+		}
+	}
+	if directories := storageDirectories(cluster.Spec.Storage); len(directories) > 0 {
+		return errors.Errorf("invalid create : running osds on directories %v is not supported anymore, use devices instead", directories)
+	}
+	return nil
 }
 
-func fetchExistingCephCluster(request *v1beta1.AdmissionRequest) (*cephv1.CephCluster, error) {
-	found, err := rookClientSet.CephV1().CephClusters(request.Namespace).Get(request.Name, metav1.GetOptions{})
-	if err != nil {
-		return &cephv1.CephCluster{}, errors.Wrap(err, "failed to find existing cephcluster object")
+// storageDirectories returns the paths of the directories in the storage settings of the cluster and its nodes
+func storageDirectories(storage rookv1.StorageScopeSpec) []string {
+	directories := []string{}
+	for _, d := range storage.Directories {
+		directories = append(directories, d.Path)
+	}
+	for _, node := range storage.Nodes {
+		for _, d := range node.Directories {
+			directories = append(directories, node.Name+":"+d.Path)
+		}
 	}
-	return found, nil
+	return directories
 }
 
-func isCephClusterUpdate(request *v1beta1.AdmissionRequest) bool {
-	return request.Resource == cephclusterResource && request.Operation == v1beta1.Update
+func validateUpdatedCephCluster(updatedCephCluster cephv1.CephCluster, found *cephv1.CephCluster) error {
+	if updatedCephCluster.Spec.DataDirHostPath != found.Spec.DataDirHostPath {
+		return errors.Errorf("invalid update : DataDirHostPath change from %q to %q is not allowed", found.Spec.DataDirHostPath, updatedCephCluster.Spec.DataDirHostPath)
+	}
+
+	if updatedCephCluster.Spec.Network.HostNetwork != found.Spec.Network.HostNetwork {
+		return errors.Errorf("invalid update : HostNetwork change from %q to %q is not allowed", strconv.FormatBool(found.Spec.Network.HostNetwork), strconv.FormatBool(updatedCephCluster.Spec.Network.HostNetwork))
+	}
+
+	if updatedCephCluster.Spec.Network.Provider != found.Spec.Network.Provider {
+		return errors.Errorf("invalid update : Provider change from %q to %q is not allowed", found.Spec.Network.Provider, updatedCephCluster.Spec.Network.Provider)
+	}
+
+	// the directories of the clusters created before they were deprecated are ignored by the operator, new ones are
+	// rejected
+	existing := map[string]bool{}
+	for _, d := range storageDirectories(found.Spec.Storage) {
+		existing[d] = true
+	}
+	for _, d := range storageDirectories(updatedCephCluster.Spec.Storage) {
+		if !existing[d] {
+			return errors.Errorf("invalid update : running osds on directory %q is not supported anymore, use devices instead", d)
+		}
+	}
+
+	// the quorum of a cluster is not reduced below the safe number of mons
+	count, previousCount := updatedCephCluster.Spec.Mon.Count, found.Spec.Mon.Count
+	if !updatedCephCluster.Spec.External.Enable && count < previousCount && count < minSafeMonCount {
+		return errors.Errorf("invalid update : mon count change from %d to %d is not allowed, a cluster must keep at least %d mons", previousCount, count, minSafeMonCount)
+	}
+
+	return nil
 }
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"encoding/json"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newAdmissionRequest(t *testing.T, resource string, operation v1beta1.Operation, obj, oldObj interface{}) *v1beta1.AdmissionRequest {
+	request := &v1beta1.AdmissionRequest{
+		Resource:  metav1.GroupVersionResource{Group: cephv1.CustomResourceGroup, Version: cephv1.Version, Resource: resource},
+		Operation: operation,
+		Namespace: "rook-ceph",
+	}
+	raw, err := json.Marshal(obj)
+	assert.NoError(t, err)
+	request.Object = runtime.RawExtension{Raw: raw}
+	if oldObj != nil {
+		raw, err = json.Marshal(oldObj)
+		assert.NoError(t, err)
+		request.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	return request
+}
+
+func TestValidateCephCluster(t *testing.T) {
+	old := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "rook-ceph"},
+		Spec: cephv1.ClusterSpec{
+			DataDirHostPath: "/var/lib/rook",
+			Mon:             cephv1.MonSpec{Count: 3},
+		},
+	}
+
+	// the spec did not change
+	cluster := old.DeepCopy()
+	cluster.Finalizers = []string{"cephcluster.ceph.rook.io"}
+	assert.NoError(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Update, cluster, old), nil))
+
+	// dataDirHostPath cannot change
+	cluster = old.DeepCopy()
+	cluster.Spec.DataDirHostPath = "/var/lib/rook2"
+	assert.Error(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Update, cluster, old), nil))
+
+	// the mons cannot be reduced below 3
+	cluster = old.DeepCopy()
+	cluster.Spec.Mon.Count = 1
+	assert.Error(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Update, cluster, old), nil))
+	cluster.Spec.Mon.Count = 5
+	assert.NoError(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Update, cluster, old), nil))
+
+	// a test cluster can still be created with a single mon
+	cluster = old.DeepCopy()
+	cluster.Spec.Mon.Count = 1
+	assert.NoError(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Create, cluster, nil), nil))
+
+	// invalid cephConfig entity
+	cluster.Spec.CephConfig = map[string]map[string]string{"foo": {"debug_ms": "1"}}
+	assert.Error(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Create, cluster, nil), nil))
+
+	// external cluster with mon settings
+	cluster = old.DeepCopy()
+	cluster.Spec.External.Enable = true
+	assert.Error(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Create, cluster, nil), nil))
+
+	// the osds on directories are rejected
+	cluster = old.DeepCopy()
+	cluster.Spec.Storage.Nodes = []rookv1.Node{{Name: "node1", Selection: rookv1.Selection{Directories: []rookv1.Directory{{Path: "/rook/osd"}}}}}
+	assert.Error(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Create, cluster, nil), nil))
+	assert.Error(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Update, cluster, old), nil))
+
+	// the directories of an existing cluster are kept on the updates of other settings
+	updated := cluster.DeepCopy()
+	updated.Spec.Mon.Count = 5
+	assert.NoError(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Update, updated, cluster), nil))
+	updated.Spec.Storage.Nodes[0].Directories = append(updated.Spec.Storage.Nodes[0].Directories, rookv1.Directory{Path: "/rook/osd2"})
+	assert.Error(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Update, updated, cluster), nil))
+
+	// the deletion is not validated
+	assert.NoError(t, ValidateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Delete, old, nil), nil))
+}
+
+func TestValidateCephPoolResources(t *testing.T) {
+	old := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"},
+		Spec:       cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}},
+	}
+	p := old.DeepCopy()
+	assert.NoError(t, ValidateCephResource(newAdmissionRequest(t, "cephblockpools", v1beta1.Create, p, nil), nil))
+
+	p.Spec.CompressionMode = "foo"
+	assert.Error(t, ValidateCephResource(newAdmissionRequest(t, "cephblockpools", v1beta1.Create, p, nil), nil))
+
+	// a replicated pool cannot become erasure coded
+	p = old.DeepCopy()
+	p.Spec.Replicated.Size = 0
+	p.Spec.ErasureCoded = cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}
+	assert.Error(t, ValidateCephResource(newAdmissionRequest(t, "cephblockpools", v1beta1.Update, p, old), nil))
+
+	// the filesystem needs an active mds
+	fs := &cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: "rook-ceph"},
+		Spec: cephv1.FilesystemSpec{
+			MetadataPool: cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}},
+			DataPools:    []cephv1.PoolSpec{{Replicated: cephv1.ReplicatedSpec{Size: 3}}},
+		},
+	}
+	assert.Error(t, ValidateCephResource(newAdmissionRequest(t, "cephfilesystems", v1beta1.Create, fs, nil), nil))
+	fs.Spec.MetadataServer.ActiveCount = 1
+	assert.NoError(t, ValidateCephResource(newAdmissionRequest(t, "cephfilesystems", v1beta1.Create, fs, nil), nil))
+
+	// the client needs caps
+	client := &cephv1.CephClient{ObjectMeta: metav1.ObjectMeta{Name: "client1"}}
+	assert.Error(t, ValidateCephResource(newAdmissionRequest(t, "cephclients", v1beta1.Create, client, nil), nil))
+	client.Spec.Caps = map[string]string{"mon": "allow r"}
+	assert.NoError(t, ValidateCephResource(newAdmissionRequest(t, "cephclients", v1beta1.Create, client, nil), nil))
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"reflect"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileCephConfig applies the cephConfig settings of the cluster CR to the centralized mon config database
+// and reports the applied and rejected options in the status of the cluster CR. The options dropped from the
+// settings since the last reconcile are removed.
+func (c *cluster) reconcileCephConfig(spec *cephv1.ClusterSpec) {
+	if c.context.Client == nil {
+		return
+	}
+
+	cephCluster := &cephv1.CephCluster{}
+	namespacedName := types.NamespacedName{Name: c.crdName, Namespace: c.Namespace}
+	if err := c.context.Client.Get(context.TODO(), namespacedName, cephCluster); err != nil {
+		logger.Errorf("failed to get ceph cluster %q to reconcile the ceph config options. %v", namespacedName.Name, err)
+		return
+	}
+
+	status := config.ReconcileCephConfig(c.context, c.Namespace, spec.CephConfig, cephCluster.Status.CephConfig)
+	if status == nil && cephCluster.Status.CephConfig == nil {
+		return
+	}
+	if status != nil && cephCluster.Status.CephConfig != nil &&
+		reflect.DeepEqual(status.Applied, cephCluster.Status.CephConfig.Applied) &&
+		reflect.DeepEqual(status.Rejected, cephCluster.Status.CephConfig.Rejected) {
+		// nothing changed, avoid updating the status with the new time only
+		return
+	}
+
+	cephCluster.Status.CephConfig = status
+	if err := opcontroller.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		logger.Errorf("failed to report the ceph config options in the status of cluster %q. %v", namespacedName.Name, err)
+	}
+}
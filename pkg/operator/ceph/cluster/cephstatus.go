@@ -20,6 +20,7 @@ package cluster
 import (
 	"context"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
@@ -27,15 +28,28 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	opmetrics "github.com/rook/rook/pkg/operator/ceph/metrics"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	// defaultStatusCheckInterval is the interval to check the status of the ceph cluster
 	defaultStatusCheckInterval = 60 * time.Second
+
+	// maxHealthHistory is the number of health transitions kept in the status of the CephCluster CR
+	maxHealthHistory = 20
+
+	healthOK = "HEALTH_OK"
+
+	healthChangedReason      = "CephHealthChanged"
+	healthCheckRaisedReason  = "CephHealthCheckRaised"
+	healthCheckClearedReason = "CephHealthCheckCleared"
 )
 
 // cephStatusChecker aggregates the mon/cluster info needed to check the health of the monitors
@@ -46,6 +60,7 @@ type cephStatusChecker struct {
 	cephUser       string
 	client         client.Client
 	namespacedName types.NamespacedName
+	recorder       record.EventRecorder
 }
 
 // newCephStatusChecker creates a new HealthChecker object
@@ -102,6 +117,7 @@ func (c *cephStatusChecker) checkStatus() {
 	}
 
 	logger.Debugf("Cluster status: %+v", status)
+	opmetrics.SetClusterHealth(c.namespacedName.Namespace, status.Health.Status)
 	if err := c.updateCephStatus(&status); err != nil {
 		logger.Errorf("failed to query cluster status in namespace %q. %v", c.namespacedName.Namespace, err)
 	}
@@ -119,15 +135,63 @@ func (c *cephStatusChecker) updateCephStatus(status *cephclient.CephStatus) erro
 		return errors.Wrapf(err, "failed to retrieve ceph cluster %q to update status to %+v", c.namespacedName.Name, status)
 	}
 
+	previousStatus := cephCluster.Status.CephStatus
 	cephCluster.Status.CephStatus = toCustomResourceStatus(cephCluster.Status, status)
 	if err := opcontroller.UpdateStatus(c.client, cephCluster); err != nil {
 		return errors.Wrapf(err, "failed to update cluster %q status", c.namespacedName.Namespace)
 	}
 
+	// the events are only recorded once the transition is saved, so that a failed update does not report it twice
+	c.recordHealthEvents(cephCluster, previousStatus, cephCluster.Status.CephStatus)
+
 	logger.Debugf("ceph cluster %q status updated to %+v", c.namespacedName.Name, status)
 	return nil
 }
 
+// recordHealthEvents records an event on the CephCluster CR for a change of the health and for each health
+// check raised or cleared since the previous status
+func (c *cephStatusChecker) recordHealthEvents(cephCluster *cephv1.CephCluster, previousStatus, newStatus *cephv1.CephStatus) {
+	transition := healthTransition(previousStatus, newStatus)
+	if transition == nil {
+		return
+	}
+
+	ref := clusterReference(cephCluster)
+	if transition.Health != transition.PreviousHealth {
+		eventType := v1.EventTypeWarning
+		if transition.Health == healthOK {
+			eventType = v1.EventTypeNormal
+		}
+		c.eventRecorder().Eventf(ref, eventType, healthChangedReason, "ceph health changed from %s to %s", transition.PreviousHealth, transition.Health)
+	}
+	for _, code := range transition.RaisedChecks {
+		check := newStatus.Details[code]
+		c.eventRecorder().Eventf(ref, v1.EventTypeWarning, healthCheckRaisedReason, "%s %s: %s", check.Severity, code, check.Message)
+	}
+	for _, code := range transition.ClearedChecks {
+		c.eventRecorder().Eventf(ref, v1.EventTypeNormal, healthCheckClearedReason, "%s cleared", code)
+	}
+}
+
+func (c *cephStatusChecker) eventRecorder() record.EventRecorder {
+	if c.recorder == nil {
+		c.recorder = k8sutil.NewEventRecorder(c.context.Clientset, "rook-ceph-status")
+	}
+	return c.recorder
+}
+
+// clusterReference returns the reference of the cluster CR for the events
+func clusterReference(cephCluster *cephv1.CephCluster) *v1.ObjectReference {
+	return &v1.ObjectReference{
+		APIVersion:      cephv1.SchemeGroupVersion.String(),
+		Kind:            "CephCluster",
+		Name:            cephCluster.Name,
+		Namespace:       cephCluster.Namespace,
+		UID:             cephCluster.UID,
+		ResourceVersion: cephCluster.ResourceVersion,
+	}
+}
+
 // toCustomResourceStatus converts the ceph status to the struct expected for the CephCluster CR status
 func toCustomResourceStatus(currentStatus cephv1.ClusterStatus, newStatus *cephclient.CephStatus) *cephv1.CephStatus {
 	s := &cephv1.CephStatus{
@@ -148,10 +212,48 @@ func toCustomResourceStatus(currentStatus cephv1.ClusterStatus, newStatus *cephc
 			s.PreviousHealth = currentStatus.CephStatus.Health
 			s.LastChanged = s.LastChecked
 		}
+		s.History = currentStatus.CephStatus.History
+		if transition := healthTransition(currentStatus.CephStatus, s); transition != nil {
+			s.History = append(s.History, *transition)
+		}
+		// the history is a ring buffer of the most recent transitions
+		if len(s.History) > maxHealthHistory {
+			s.History = s.History[len(s.History)-maxHealthHistory:]
+		}
 	}
 	return s
 }
 
+// healthTransition returns the change of the health and of the health checks between two statuses, or nil
+// if nothing changed. There is no transition from an empty status since the previous health is unknown.
+func healthTransition(previousStatus, newStatus *cephv1.CephStatus) *cephv1.CephHealthTransition {
+	if previousStatus == nil || newStatus == nil {
+		return nil
+	}
+
+	transition := &cephv1.CephHealthTransition{
+		Time:           newStatus.LastChecked,
+		Health:         newStatus.Health,
+		PreviousHealth: previousStatus.Health,
+	}
+	for code := range newStatus.Details {
+		if _, ok := previousStatus.Details[code]; !ok {
+			transition.RaisedChecks = append(transition.RaisedChecks, code)
+		}
+	}
+	for code := range previousStatus.Details {
+		if _, ok := newStatus.Details[code]; !ok {
+			transition.ClearedChecks = append(transition.ClearedChecks, code)
+		}
+	}
+	if transition.Health == transition.PreviousHealth && len(transition.RaisedChecks) == 0 && len(transition.ClearedChecks) == 0 {
+		return nil
+	}
+	sort.Strings(transition.RaisedChecks)
+	sort.Strings(transition.ClearedChecks)
+	return transition
+}
+
 func formatTime(t time.Time) string {
 	return t.Format(time.RFC3339)
 }
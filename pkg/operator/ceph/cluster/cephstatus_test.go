@@ -18,12 +18,20 @@ limitations under the License.
 package cluster
 
 import (
+	ctx "context"
+	"fmt"
 	"testing"
 	"time"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestCephStatus(t *testing.T) {
@@ -88,3 +96,99 @@ func TestCephStatus(t *testing.T) {
 	assert.Equal(t, pgAvailMsg.Summary.Message, aggregateStatus.Details["PG_AVAILABILITY"].Message)
 	assert.Equal(t, pgAvailMsg.Severity, aggregateStatus.Details["PG_AVAILABILITY"].Severity)
 }
+
+func TestCephStatusHistory(t *testing.T) {
+	newStatus := &client.CephStatus{
+		Health: client.HealthStatus{Status: "HEALTH_OK"},
+	}
+
+	// no transition is recorded without a previous status
+	currentStatus := cephv1.ClusterStatus{}
+	currentStatus.CephStatus = toCustomResourceStatus(currentStatus, newStatus)
+	assert.Equal(t, 0, len(currentStatus.CephStatus.History))
+
+	// no transition is recorded while nothing changes
+	currentStatus.CephStatus = toCustomResourceStatus(currentStatus, newStatus)
+	assert.Equal(t, 0, len(currentStatus.CephStatus.History))
+
+	// a new health check with a health change
+	osdDownMsg := client.CheckMessage{Severity: "HEALTH_WARN"}
+	osdDownMsg.Summary.Message = "1 osd down"
+	newStatus.Health.Status = "HEALTH_WARN"
+	newStatus.Health.Checks = map[string]client.CheckMessage{"OSD_DOWN": osdDownMsg}
+	currentStatus.CephStatus = toCustomResourceStatus(currentStatus, newStatus)
+	assert.Equal(t, 1, len(currentStatus.CephStatus.History))
+	transition := currentStatus.CephStatus.History[0]
+	assert.Equal(t, currentStatus.CephStatus.LastChecked, transition.Time)
+	assert.Equal(t, "HEALTH_WARN", transition.Health)
+	assert.Equal(t, "HEALTH_OK", transition.PreviousHealth)
+	assert.Equal(t, []string{"OSD_DOWN"}, transition.RaisedChecks)
+	assert.Equal(t, 0, len(transition.ClearedChecks))
+
+	// a health check replaced by another one without a health change
+	newStatus.Health.Checks = map[string]client.CheckMessage{"PG_DEGRADED": osdDownMsg}
+	currentStatus.CephStatus = toCustomResourceStatus(currentStatus, newStatus)
+	assert.Equal(t, 2, len(currentStatus.CephStatus.History))
+	transition = currentStatus.CephStatus.History[1]
+	assert.Equal(t, "HEALTH_WARN", transition.Health)
+	assert.Equal(t, "HEALTH_WARN", transition.PreviousHealth)
+	assert.Equal(t, []string{"PG_DEGRADED"}, transition.RaisedChecks)
+	assert.Equal(t, []string{"OSD_DOWN"}, transition.ClearedChecks)
+
+	// only the most recent transitions are kept
+	for i := 0; i < maxHealthHistory; i++ {
+		newStatus.Health.Checks = map[string]client.CheckMessage{fmt.Sprintf("CHECK_%d", i): osdDownMsg}
+		currentStatus.CephStatus = toCustomResourceStatus(currentStatus, newStatus)
+	}
+	assert.Equal(t, maxHealthHistory, len(currentStatus.CephStatus.History))
+	assert.Equal(t, []string{"CHECK_0"}, currentStatus.CephStatus.History[0].RaisedChecks)
+	assert.Equal(t, []string{fmt.Sprintf("CHECK_%d", maxHealthHistory-1)}, currentStatus.CephStatus.History[maxHealthHistory-1].RaisedChecks)
+}
+
+func TestUpdateCephStatusEvents(t *testing.T) {
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "ns"},
+		Status: cephv1.ClusterStatus{
+			CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{})
+	recorder := record.NewFakeRecorder(10)
+	c := &cephStatusChecker{
+		context:        &clusterd.Context{},
+		client:         fake.NewFakeClientWithScheme(s, cephCluster),
+		namespacedName: types.NamespacedName{Name: "rook-ceph", Namespace: "ns"},
+		recorder:       recorder,
+	}
+
+	// the health change and the new health check are reported
+	osdDownMsg := client.CheckMessage{Severity: "HEALTH_WARN"}
+	osdDownMsg.Summary.Message = "1 osd down"
+	status := &client.CephStatus{Health: client.HealthStatus{
+		Status: "HEALTH_WARN",
+		Checks: map[string]client.CheckMessage{"OSD_DOWN": osdDownMsg},
+	}}
+	err := c.updateCephStatus(status)
+	assert.NoError(t, err)
+	assert.Equal(t, "Warning CephHealthChanged ceph health changed from HEALTH_OK to HEALTH_WARN", <-recorder.Events)
+	assert.Equal(t, "Warning CephHealthCheckRaised HEALTH_WARN OSD_DOWN: 1 osd down", <-recorder.Events)
+
+	updated := &cephv1.CephCluster{}
+	err = c.client.Get(ctx.TODO(), c.namespacedName, updated)
+	assert.NoError(t, err)
+	assert.Equal(t, "HEALTH_WARN", updated.Status.CephStatus.Health)
+	assert.Equal(t, 1, len(updated.Status.CephStatus.History))
+
+	// no event while nothing changes
+	err = c.updateCephStatus(status)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(recorder.Events))
+
+	// the cleared health check and the health change are reported
+	status.Health = client.HealthStatus{Status: "HEALTH_OK"}
+	err = c.updateCephStatus(status)
+	assert.NoError(t, err)
+	assert.Equal(t, "Normal CephHealthChanged ceph health changed from HEALTH_WARN to HEALTH_OK", <-recorder.Events)
+	assert.Equal(t, "Normal CephHealthCheckCleared OSD_DOWN cleared", <-recorder.Events)
+}
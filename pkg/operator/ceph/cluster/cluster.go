@@ -29,6 +29,7 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	cephconfig "github.com/rook/rook/pkg/daemon/ceph/config"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
 	cephclient "github.com/rook/rook/pkg/operator/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/crash"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
@@ -136,6 +137,9 @@ func (c *cluster) doOrchestration(rookImage string, cephVersion cephver.CephVers
 		return errors.Wrap(err, "failed to execute post actions after all the ceph monitors started")
 	}
 
+	// Apply the ceph config options of the cluster CR before starting the other daemons
+	c.reconcileCephConfig(spec)
+
 	// If this is an upgrade, notify all the child controllers
 	if c.isUpgrade {
 		logger.Info("upgrade in progress, notifying child CRs")
@@ -155,15 +159,36 @@ func (c *cluster) doOrchestration(rookImage string, cephVersion cephver.CephVers
 		return errors.Wrap(err, "failed to start ceph mgr")
 	}
 
+	// Validate the key management service settings before encrypting any OSD
+	err = kms.ValidateConnectionDetails(c.context, spec.Security, c.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate kms connection details")
+	}
+
+	err = osd.ValidateUpgradeStrategy(spec.UpgradeStrategy.OSD, spec.Crush)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate the osd upgrade strategy")
+	}
+
+	err = osd.ValidateCrushSpec(spec.Crush)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate the crush settings")
+	}
+
 	// Start the OSDs
 	osds := osd.New(c.Info, c.context, c.Namespace, rookImage, spec.CephVersion, spec.Storage, spec.DataDirHostPath,
 		cephv1.GetOSDPlacement(spec.Placement), cephv1.GetOSDAnnotations(spec.Annotations), spec.Network,
-		cephv1.GetOSDResources(spec.Resources), cephv1.GetPrepareOSDResources(spec.Resources), cephv1.GetOSDPriorityClassName(spec.PriorityClassNames), c.ownerRef, c.Spec.SkipUpgradeChecks, c.Spec.ContinueUpgradeAfterChecksEvenIfNotHealthy)
+		cephv1.GetOSDResources(spec.Resources), cephv1.GetPrepareOSDResources(spec.Resources), cephv1.GetOSDPriorityClassName(spec.PriorityClassNames), c.ownerRef, c.Spec.SkipUpgradeChecks, c.Spec.ContinueUpgradeAfterChecksEvenIfNotHealthy, spec.Security, spec.UpgradeStrategy.OSD, spec.Crush)
 	err = osds.Start()
 	if err != nil {
 		return errors.Wrap(err, "failed to start ceph osds")
 	}
 
+	// The stretch mode can only be enabled once the OSDs of the data zones are running
+	if err := c.mons.ConfigureStretchMode(); err != nil {
+		return errors.Wrap(err, "failed to configure the stretch mode")
+	}
+
 	logger.Infof("done reconciling ceph cluster in namespace %q", c.Namespace)
 
 	// We should be done updating by now
@@ -250,6 +275,9 @@ func (c *ClusterController) startClusterMonitoring(cluster *cluster, cephUser st
 	//   bucket library's `NewProvisioner` function
 	bucketController, _ := bucket.NewBucketController(c.context.KubeConfig, bucketProvisioner)
 	go bucketController.Run(cluster.stopCh)
+	if err := bucketProvisioner.StartClaimWatch(cluster.stopCh); err != nil {
+		logger.Errorf("failed to watch object bucket claims for bucket configuration changes. %v", err)
+	}
 
 	// Start mon health checker
 	healthChecker := mon.NewHealthChecker(cluster.mons, cluster.Spec)
@@ -259,6 +287,10 @@ func (c *ClusterController) startClusterMonitoring(cluster *cluster, cephUser st
 		// Start the osd health checker only if running OSDs in the local ceph cluster
 		c.osdChecker = osd.NewOSDHealthMonitor(c.context, cluster.Namespace, cluster.Spec.RemoveOSDsIfOutAndSafeToRemove)
 		go c.osdChecker.Start(cluster.stopCh)
+
+		// Start the crash checker archiving and exporting the crashes of the ceph daemons
+		crashChecker := crash.NewCrashChecker(c.context, c.namespacedName)
+		go crashChecker.Start(cluster.stopCh)
 	}
 
 	// Start the ceph status checker
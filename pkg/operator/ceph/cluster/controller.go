@@ -33,6 +33,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/csi"
+	opmetrics "github.com/rook/rook/pkg/operator/ceph/metrics"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -323,7 +324,7 @@ func NewClusterController(context *clusterd.Context, rookImage string, volumeAtt
 		clusterMap:              make(map[string]*cluster),
 		operatorConfigCallbacks: operatorConfigCallbacks,
 		addClusterCallbacks:     addClusterCallbacks,
-		csiConfigMutex:          &sync.Mutex{},
+		csiConfigMutex:          csi.ConfigMutex,
 	}
 }
 
@@ -384,6 +385,7 @@ func (c *ClusterController) requestClusterDelete(cluster *cephv1.CephCluster) (r
 	if cluster, ok := c.clusterMap[cluster.Namespace]; ok {
 		delete(c.clusterMap, cluster.Namespace)
 	}
+	opmetrics.DeleteClusterMetrics(cluster.Namespace)
 
 	// Only valid when the cluster is not external
 	if cluster.Spec.External.Enable {
@@ -0,0 +1,395 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object/bucket"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CrashReportsName is the name of the ConfigMap or Secret bundling the reports of the most recent crashes
+	CrashReportsName = "rook-ceph-crash-reports"
+	// BundleConfigMap stores the crash reports in a ConfigMap
+	BundleConfigMap = "configMap"
+	// BundleSecret stores the crash reports in a Secret
+	BundleSecret = "secret"
+
+	// the number of reports kept in the bundle, which must fit in the size limit of a ConfigMap or a Secret
+	maxBundledCrashes = 50
+	// the number of frames of the backtrace in the crash events
+	maxEventBacktraceFrames = 5
+	crashReason             = "CephDaemonCrashed"
+	s3AccessKeyName         = "AWS_ACCESS_KEY_ID"
+	s3SecretKeyName         = "AWS_SECRET_ACCESS_KEY"
+	defaultS3Region         = "us-east-1"
+)
+
+var (
+	crashCheckInterval = 5 * time.Minute
+)
+
+// CrashChecker archives the old crashes reported to the mgr crash module, exports the new crashes, and reports a summary
+// of the crashes in the status of the CephCluster CR
+type CrashChecker struct {
+	context     *clusterd.Context
+	client      client.Client
+	clusterName types.NamespacedName
+	recorder    record.EventRecorder
+}
+
+// NewCrashChecker creates a crash checker for a cluster
+func NewCrashChecker(context *clusterd.Context, clusterName types.NamespacedName) *CrashChecker {
+	return &CrashChecker{
+		context:     context,
+		client:      context.Client,
+		clusterName: clusterName,
+	}
+}
+
+// Start checks the crashes at set intervals
+func (c *CrashChecker) Start(stopCh chan struct{}) {
+	for {
+		select {
+		case <-time.After(crashCheckInterval):
+			logger.Debug("checking the crashes of the ceph daemons")
+			if err := c.checkCrashes(); err != nil {
+				logger.Warningf("failed to check the crashes of the ceph daemons. %v", err)
+			}
+
+		case <-stopCh:
+			logger.Infof("stopping monitoring of the crashes in namespace %q", c.clusterName.Namespace)
+			return
+		}
+	}
+}
+
+// checkCrashes applies the crash policy of the cluster
+func (c *CrashChecker) checkCrashes() error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.client.Get(context.TODO(), c.clusterName, cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get cluster %q", c.clusterName)
+	}
+	spec := cephCluster.Spec.CrashCollector
+	if spec.Disable {
+		return nil
+	}
+
+	crashes, err := cephclient.ListCrashes(c.context, c.clusterName.Namespace)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	sort.Slice(crashes, func(i, j int) bool { return crashTime(crashes[i]).Before(crashTime(crashes[j])) })
+
+	// the old crashes are archived to clear the RECENT_CRASH health warning
+	if spec.DaysToArchive > 0 {
+		archiveBefore := now.Add(-time.Duration(spec.DaysToArchive) * 24 * time.Hour)
+		for i, crash := range crashes {
+			if crash.Archived != "" || !crashTime(crash).Before(archiveBefore) {
+				continue
+			}
+			logger.Infof("archiving crash %q of %q older than %d days", crash.ID, crash.Entity, spec.DaysToArchive)
+			if err := cephclient.ArchiveCrash(c.context, c.clusterName.Namespace, crash.ID); err != nil {
+				logger.Warningf("failed to archive crash %q. %v", crash.ID, err)
+				continue
+			}
+			crashes[i].Archived = now.Format(time.RFC3339)
+		}
+	}
+
+	status := &cephv1.CrashStatus{LastChecked: opcontroller.FormatStatusTime(now)}
+	if cephCluster.Status.Crashes != nil {
+		status.LastExported = cephCluster.Status.Crashes.LastExported
+	}
+	for _, crash := range crashes {
+		if crash.Archived == "" {
+			status.NewCrashes++
+		} else {
+			status.ArchivedCrashes++
+		}
+	}
+	if len(crashes) > 0 {
+		status.LastCrash = crashTime(crashes[len(crashes)-1]).Format(time.RFC3339Nano)
+	}
+
+	// the crashes are exported once, the time of the last crash exported is kept in the status
+	if isExportEnabled(spec.Export) {
+		var lastExported time.Time
+		if status.LastExported != "" {
+			if lastExported, err = time.Parse(time.RFC3339Nano, status.LastExported); err != nil {
+				logger.Warningf("failed to parse the time of the last crash exported %q. %v", status.LastExported, err)
+			}
+		}
+		newCrashes := []cephclient.CrashInfo{}
+		for _, crash := range crashes {
+			if crashTime(crash).After(lastExported) {
+				newCrashes = append(newCrashes, crash)
+			}
+		}
+		if len(newCrashes) > 0 {
+			exported, err := c.exportCrashes(cephCluster, spec.Export, newCrashes)
+			if exported > 0 {
+				status.LastExported = crashTime(newCrashes[exported-1]).Format(time.RFC3339Nano)
+			}
+			if err != nil {
+				logger.Warningf("failed to export the crashes of cluster %q. %v", c.clusterName, err)
+			}
+		}
+	}
+
+	return c.updateCrashStatus(status)
+}
+
+// exportCrashes exports the new crashes in the order they happened. It returns the number of crashes exported before
+// a failure, so that the next check exports the remaining crashes.
+func (c *CrashChecker) exportCrashes(cephCluster *cephv1.CephCluster, export cephv1.CrashExportSpec, crashes []cephclient.CrashInfo) (int, error) {
+	var s3Agent *bucket.S3Agent
+	if export.S3 != nil {
+		var err error
+		if s3Agent, err = c.newS3Agent(export.S3); err != nil {
+			return 0, err
+		}
+	}
+
+	reports := map[string][]byte{}
+	exported := 0
+	var exportErr error
+	for _, crash := range crashes {
+		report, err := json.MarshalIndent(crash, "", "  ")
+		if err != nil {
+			exportErr = errors.Wrapf(err, "failed to marshal crash %q", crash.ID)
+			break
+		}
+		if s3Agent != nil {
+			key := path.Join(export.S3.Prefix, crashReportKey(crash.ID))
+			if err := s3Agent.PutObject(export.S3.Bucket, key, report); err != nil {
+				exportErr = err
+				break
+			}
+		}
+		reports[crashReportKey(crash.ID)] = report
+		exported++
+	}
+
+	if export.Bundle != "" && len(reports) > 0 {
+		if err := c.bundleCrashReports(cephCluster, export.Bundle, reports); err != nil {
+			return 0, err
+		}
+	}
+	if export.Events {
+		for _, crash := range crashes[:exported] {
+			c.recordCrashEvent(cephCluster, crash)
+		}
+	}
+	return exported, exportErr
+}
+
+// bundleCrashReports adds the crash reports to the ConfigMap or the Secret of the crash reports, which keeps the most
+// recent reports
+func (c *CrashChecker) bundleCrashReports(cephCluster *cephv1.CephCluster, bundle string, reports map[string][]byte) error {
+	name := types.NamespacedName{Name: CrashReportsName, Namespace: c.clusterName.Namespace}
+	meta := metav1.ObjectMeta{
+		Name:            name.Name,
+		Namespace:       name.Namespace,
+		Labels:          map[string]string{k8sutil.AppAttr: AppName},
+		OwnerReferences: []metav1.OwnerReference{clusterOwnerRef(cephCluster.Name, string(cephCluster.UID))},
+	}
+
+	switch bundle {
+	case BundleConfigMap:
+		configMap := &corev1.ConfigMap{}
+		err := c.client.Get(context.TODO(), name, configMap)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get configmap %q", name)
+		}
+		exists := err == nil
+		if !exists {
+			configMap = &corev1.ConfigMap{ObjectMeta: meta}
+		}
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		for key, report := range reports {
+			configMap.Data[key] = string(report)
+		}
+		keys := make([]string, 0, len(configMap.Data))
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		for _, key := range prunedReportKeys(keys) {
+			delete(configMap.Data, key)
+		}
+		if exists {
+			err = c.client.Update(context.TODO(), configMap)
+		} else {
+			err = c.client.Create(context.TODO(), configMap)
+		}
+		return errors.Wrapf(err, "failed to save the crash reports in configmap %q", name)
+
+	case BundleSecret:
+		secret := &corev1.Secret{}
+		err := c.client.Get(context.TODO(), name, secret)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get secret %q", name)
+		}
+		exists := err == nil
+		if !exists {
+			secret = &corev1.Secret{ObjectMeta: meta}
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		for key, report := range reports {
+			secret.Data[key] = report
+		}
+		keys := make([]string, 0, len(secret.Data))
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		for _, key := range prunedReportKeys(keys) {
+			delete(secret.Data, key)
+		}
+		if exists {
+			err = c.client.Update(context.TODO(), secret)
+		} else {
+			err = c.client.Create(context.TODO(), secret)
+		}
+		return errors.Wrapf(err, "failed to save the crash reports in secret %q", name)
+	}
+
+	return errors.Errorf("invalid crash report bundle %q, must be %q or %q", bundle, BundleConfigMap, BundleSecret)
+}
+
+// prunedReportKeys returns the keys of the oldest reports beyond the reports kept in the bundle. The keys start with
+// the time of the crash, so they sort in the order the crashes happened.
+func prunedReportKeys(keys []string) []string {
+	if len(keys) <= maxBundledCrashes {
+		return nil
+	}
+	sort.Strings(keys)
+	return keys[:len(keys)-maxBundledCrashes]
+}
+
+// recordCrashEvent records a warning event on the CephCluster CR for a crash
+func (c *CrashChecker) recordCrashEvent(cephCluster *cephv1.CephCluster, crash cephclient.CrashInfo) {
+	if c.recorder == nil {
+		c.recorder = k8sutil.NewEventRecorder(c.context.Clientset, "rook-ceph-crash-collector")
+	}
+	ref := &corev1.ObjectReference{
+		APIVersion:      cephv1.SchemeGroupVersion.String(),
+		Kind:            "CephCluster",
+		Name:            cephCluster.Name,
+		Namespace:       cephCluster.Namespace,
+		UID:             cephCluster.UID,
+		ResourceVersion: cephCluster.ResourceVersion,
+	}
+
+	message := fmt.Sprintf("%s crashed on host %q at %s (crash id %s)", crash.Entity, crash.Hostname, crash.Timestamp, crash.ID)
+	if crash.AssertMsg != "" {
+		message += fmt.Sprintf(": %s", strings.TrimSpace(crash.AssertMsg))
+	}
+	if len(crash.Backtrace) > 0 {
+		frames := crash.Backtrace
+		if len(frames) > maxEventBacktraceFrames {
+			frames = frames[:maxEventBacktraceFrames]
+		}
+		message += fmt.Sprintf("\nbacktrace:\n%s", strings.Join(frames, "\n"))
+	}
+	c.recorder.Event(ref, corev1.EventTypeWarning, crashReason, message)
+}
+
+// newS3Agent creates the client of the bucket the crash reports are uploaded to
+func (c *CrashChecker) newS3Agent(spec *cephv1.CrashS3ExportSpec) (*bucket.S3Agent, error) {
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.clusterName.Namespace).Get(spec.CredentialsSecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the credentials of bucket %q", spec.Bucket)
+	}
+	accessKey, secretKey := string(secret.Data[s3AccessKeyName]), string(secret.Data[s3SecretKeyName])
+	if accessKey == "" || secretKey == "" {
+		return nil, errors.Errorf("secret %q must have the %q and %q keys", spec.CredentialsSecret, s3AccessKeyName, s3SecretKeyName)
+	}
+	region := spec.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	agent, err := bucket.NewS3AgentForRegion(accessKey, secretKey, spec.Endpoint, region)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the client of bucket %q", spec.Bucket)
+	}
+	return agent, nil
+}
+
+// updateCrashStatus reports the summary of the crashes in the status of the CephCluster CR
+func (c *CrashChecker) updateCrashStatus(status *cephv1.CrashStatus) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.client.Get(context.TODO(), c.clusterName, cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve ceph cluster %q to report its crashes", c.clusterName)
+	}
+
+	cephCluster.Status.Crashes = status
+	if err := opcontroller.UpdateStatus(c.client, cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to report the crashes of cluster %q", c.clusterName)
+	}
+	return nil
+}
+
+// isExportEnabled returns whether the crashes are exported somewhere
+func isExportEnabled(export cephv1.CrashExportSpec) bool {
+	return export.Events || export.Bundle != "" || export.S3 != nil
+}
+
+// crashReportKey returns the name of the report of a crash, which is a valid key of a ConfigMap
+func crashReportKey(crashID string) string {
+	return strings.Replace(crashID, ":", "-", -1) + ".json"
+}
+
+// crashTime returns the time of a crash, or the zero time if it cannot be parsed
+func crashTime(crash cephclient.CrashInfo) time.Time {
+	t, err := crash.Time()
+	if err != nil {
+		logger.Debugf("%v", err)
+	}
+	return t
+}
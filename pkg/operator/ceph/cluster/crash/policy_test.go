@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestCrash(id string, t time.Time) cephclient.CrashInfo {
+	return cephclient.CrashInfo{
+		ID:        fmt.Sprintf("%s_%s", t.Format("2006-01-02_15:04:05.000000Z"), id),
+		Timestamp: t.Format("2006-01-02 15:04:05.000000Z"),
+		Entity:    "osd." + id,
+		Hostname:  "node1",
+		Backtrace: []string{"(()+0x12dd0) [0x7f]", "abort()"},
+	}
+}
+
+func TestCheckCrashes(t *testing.T) {
+	now := time.Now().UTC()
+	crashes := []cephclient.CrashInfo{
+		newTestCrash("1", now.Add(-10*24*time.Hour)),
+		newTestCrash("2", now.Add(-time.Hour)),
+	}
+	archived := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			switch strings.Join(args[:2], " ") {
+			case "crash ls":
+				output, _ := json.Marshal(crashes)
+				return string(output), nil
+			case "crash archive":
+				archived = append(archived, args[2])
+				for i := range crashes {
+					if crashes[i].ID == args[2] {
+						crashes[i].Archived = now.Format("2006-01-02 15:04:05.000000")
+					}
+				}
+				return "", nil
+			}
+			return "", fmt.Errorf("unexpected ceph command %q", args)
+		},
+	}
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "rook-ceph", UID: "uid"},
+		Spec: cephv1.ClusterSpec{
+			CrashCollector: cephv1.CrashCollectorSpec{
+				DaysToArchive: 7,
+				Export:        cephv1.CrashExportSpec{Events: true, Bundle: BundleConfigMap},
+			},
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{cephCluster}...)
+	clusterName := types.NamespacedName{Name: cephCluster.Name, Namespace: cephCluster.Namespace}
+	recorder := record.NewFakeRecorder(10)
+	c := NewCrashChecker(&clusterd.Context{Executor: executor, Client: cl}, clusterName)
+	c.recorder = recorder
+
+	// the old crash is archived and both crashes are exported
+	assert.NoError(t, c.checkCrashes())
+	assert.Equal(t, []string{crashes[0].ID}, archived)
+	updated := &cephv1.CephCluster{}
+	assert.NoError(t, cl.Get(context.TODO(), clusterName, updated))
+	status := updated.Status.Crashes
+	assert.Equal(t, 1, status.NewCrashes)
+	assert.Equal(t, 1, status.ArchivedCrashes)
+	assert.Equal(t, status.LastCrash, status.LastExported)
+	assert.NotEmpty(t, status.LastChecked)
+	assert.Equal(t, 2, len(recorder.Events))
+	event := <-recorder.Events
+	assert.Contains(t, event, crashReason)
+	assert.Contains(t, event, "osd.1 crashed on host \"node1\"")
+	<-recorder.Events
+
+	configMap := &corev1.ConfigMap{}
+	assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: CrashReportsName, Namespace: "rook-ceph"}, configMap))
+	assert.Equal(t, 2, len(configMap.Data))
+	report := cephclient.CrashInfo{}
+	assert.NoError(t, json.Unmarshal([]byte(configMap.Data[crashReportKey(crashes[1].ID)]), &report))
+	assert.Equal(t, crashes[1].Backtrace, report.Backtrace)
+	assert.Equal(t, "rook-ceph", configMap.OwnerReferences[0].Name)
+
+	// only the new crashes are exported
+	crashes = append(crashes, newTestCrash("3", now.Add(-time.Minute)))
+	assert.NoError(t, c.checkCrashes())
+	assert.Equal(t, 1, len(recorder.Events))
+	assert.Contains(t, <-recorder.Events, "osd.3")
+	configMap = &corev1.ConfigMap{}
+	assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: CrashReportsName, Namespace: "rook-ceph"}, configMap))
+	assert.Equal(t, 3, len(configMap.Data))
+	updated = &cephv1.CephCluster{}
+	assert.NoError(t, cl.Get(context.TODO(), clusterName, updated))
+	assert.Equal(t, 2, updated.Status.Crashes.NewCrashes)
+	assert.Equal(t, 1, len(archived))
+
+	// nothing is checked when the crash collector is disabled
+	updated.Spec.CrashCollector.Disable = true
+	assert.NoError(t, cl.Update(context.TODO(), updated))
+	crashes = append(crashes, newTestCrash("4", now))
+	assert.NoError(t, c.checkCrashes())
+	assert.Equal(t, 0, len(recorder.Events))
+}
+
+func TestPrunedReportKeys(t *testing.T) {
+	keys := []string{}
+	for i := 0; i < maxBundledCrashes; i++ {
+		keys = append(keys, fmt.Sprintf("2020-07-%02d_crash.json", i+10))
+	}
+	assert.Nil(t, prunedReportKeys(keys))
+
+	keys = append(keys, "2020-07-01_crash.json", "2020-07-02_crash.json")
+	assert.Equal(t, []string{"2020-07-01_crash.json", "2020-07-02_crash.json"}, prunedReportKeys(keys))
+	assert.Equal(t, "2020-07-13_16-38-26.196543Z_5f2c.json", crashReportKey("2020-07-13_16:38:26.196543Z_5f2c"))
+}
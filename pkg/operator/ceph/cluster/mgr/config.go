@@ -19,6 +19,7 @@ package mgr
 import (
 	"fmt"
 
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -43,16 +44,20 @@ type mgrConfig struct {
 }
 
 func (c *Cluster) dashboardPort() int {
-	if c.dashboard.Port == 0 {
+	return DashboardPort(c.dashboard)
+}
+
+// DashboardPort returns the port of the dashboard, the default port for HTTP/HTTPS if it is not set
+func DashboardPort(dashboard cephv1.DashboardSpec) int {
+	if dashboard.Port == 0 {
 		// default port for HTTP/HTTPS
-		if c.dashboard.SSL {
+		if dashboard.SSL {
 			return dashboardPortHTTPS
-		} else {
-			return dashboardPortHTTP
 		}
+		return dashboardPortHTTP
 	}
 	// crd validates port >= 0
-	return c.dashboard.Port
+	return dashboard.Port
 }
 
 func (c *Cluster) generateKeyring(m *mgrConfig) (string, error) {
@@ -214,6 +214,9 @@ func (c *Cluster) Start() error {
 			logger.Errorf("failed to enable service monitor. %v", err)
 		} else {
 			logger.Infof("servicemonitor enabled")
+			if err := c.configureOptionalServiceMonitors(service); err != nil {
+				logger.Errorf("failed to configure the optional service monitors. %v", err)
+			}
 		}
 		// namespace in which the prometheusRule should be deployed
 		// if left empty, it will be deployed in current namespace
@@ -370,23 +373,42 @@ func wellKnownModule(name string) bool {
 
 // add a servicemonitor that allows prometheus to scrape from the monitoring endpoint of the cluster
 func (c *Cluster) enableServiceMonitor(service *v1.Service) error {
-	name := service.GetName()
-	namespace := service.GetNamespace()
 	serviceMonitor, err := k8sutil.GetServiceMonitor(path.Join(monitoringPath, serviceMonitorFile))
 	if err != nil {
 		return errors.Wrap(err, "service monitor could not be enabled")
 	}
-	serviceMonitor.SetName(name)
-	serviceMonitor.SetNamespace(namespace)
-	k8sutil.SetOwnerRef(&serviceMonitor.ObjectMeta, &c.ownerRef)
-	serviceMonitor.Spec.NamespaceSelector.MatchNames = []string{namespace}
-	serviceMonitor.Spec.Selector.MatchLabels = service.GetLabels()
+	c.configureServiceMonitor(serviceMonitor, service.GetName(), service.GetNamespace(), service.GetLabels())
+	c.dropOptionalMetrics(serviceMonitor)
 	if _, err := k8sutil.CreateOrUpdateServiceMonitor(serviceMonitor); err != nil {
 		return errors.Wrap(err, "service monitor could not be enabled")
 	}
 	return nil
 }
 
+// add or remove the optional servicemonitors scraping the metrics of a single type of daemon
+func (c *Cluster) configureOptionalServiceMonitors(service *v1.Service) error {
+	for _, optional := range c.optionalServiceMonitors() {
+		if !optional.enabled {
+			if err := k8sutil.DeleteServiceMonitor(service.GetNamespace(), optional.name); err != nil {
+				return errors.Wrapf(err, "service monitor %q could not be removed", optional.name)
+			}
+			continue
+		}
+
+		serviceMonitor, err := k8sutil.GetServiceMonitor(path.Join(monitoringPath, serviceMonitorFile))
+		if err != nil {
+			return errors.Wrapf(err, "service monitor %q could not be enabled", optional.name)
+		}
+		c.configureServiceMonitor(serviceMonitor, optional.name, service.GetNamespace(), service.GetLabels())
+		keepOptionalMetrics(serviceMonitor, optional)
+		if _, err := k8sutil.CreateOrUpdateServiceMonitor(serviceMonitor); err != nil {
+			return errors.Wrapf(err, "service monitor %q could not be enabled", optional.name)
+		}
+		logger.Infof("servicemonitor %q enabled", optional.name)
+	}
+	return nil
+}
+
 // deploy prometheusRule that adds alerting and/or recording rules to the cluster
 func (c *Cluster) deployPrometheusRule(name, namespace string) error {
 	version := strconv.Itoa(c.clusterInfo.CephVersion.Major)
@@ -397,8 +419,12 @@ func (c *Cluster) deployPrometheusRule(name, namespace string) error {
 	if err != nil {
 		return errors.Wrap(err, "prometheus rule could not be deployed")
 	}
+	if err := applyAlerts(prometheusRule, c.monitoringSpec.Alerts); err != nil {
+		return errors.Wrap(err, "failed to set the thresholds of the prometheus alerts")
+	}
 	prometheusRule.SetName(name)
 	prometheusRule.SetNamespace(namespace)
+	prometheusRule.SetLabels(mergeLabels(prometheusRule.GetLabels(), c.monitoringSpec.Labels))
 	owners := append(prometheusRule.GetOwnerReferences(), c.ownerRef)
 	k8sutil.SetOwnerRefs(&prometheusRule.ObjectMeta, owners)
 	if _, err := k8sutil.CreateOrUpdatePrometheusRule(prometheusRule); err != nil {
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// the daemons report their metrics to the mgr, so the optional service monitors scrape the mgr metrics
+	// service and only keep the metrics of their daemons
+	rgwServiceMonitorName       = "rook-ceph-rgw"
+	rbdMirrorServiceMonitorName = "rook-ceph-rbd-mirror"
+	rgwMetricsRegex             = "ceph_rgw_.*"
+	rbdMirrorMetricsRegex       = "ceph_rbd_mirror_.*"
+
+	// prometheus alerts with a threshold that can be overridden in the cluster CR
+	osdNearFullAlert     = "CephOSDNearFull"
+	osdFullAlert         = "CephOSDCriticallyFull"
+	monQuorumAtRiskAlert = "CephMonQuorumAtRisk"
+	pgDegradedAlert      = "CephDataRecoveryTakingTooLong"
+)
+
+var (
+	alertExprThresholdRegex    = regexp.MustCompile(`[0-9.]+(\s*)$`)
+	alertDescriptionRatioRegex = regexp.MustCompile(`[0-9]+%`)
+	prometheusDurationRegex    = regexp.MustCompile(`^([0-9]+(ms|s|m|h|d|w|y))+$`)
+)
+
+// optionalServiceMonitor is a service monitor keeping the metrics of a single type of daemon
+type optionalServiceMonitor struct {
+	name         string
+	enabled      bool
+	metricsRegex string
+}
+
+func (c *Cluster) optionalServiceMonitors() []optionalServiceMonitor {
+	return []optionalServiceMonitor{
+		{name: rgwServiceMonitorName, enabled: c.monitoringSpec.ServiceMonitors.RGW, metricsRegex: rgwMetricsRegex},
+		{name: rbdMirrorServiceMonitorName, enabled: c.monitoringSpec.ServiceMonitors.RBDMirror, metricsRegex: rbdMirrorMetricsRegex},
+	}
+}
+
+// configureServiceMonitor sets the fields of a service monitor loaded from the template
+func (c *Cluster) configureServiceMonitor(serviceMonitor *monitoringv1.ServiceMonitor, name, namespace string, selector map[string]string) {
+	serviceMonitor.SetName(name)
+	serviceMonitor.SetNamespace(namespace)
+	serviceMonitor.SetLabels(mergeLabels(serviceMonitor.GetLabels(), c.monitoringSpec.Labels))
+	k8sutil.SetOwnerRef(&serviceMonitor.ObjectMeta, &c.ownerRef)
+	serviceMonitor.Spec.NamespaceSelector.MatchNames = []string{namespace}
+	serviceMonitor.Spec.Selector.MatchLabels = selector
+}
+
+// dropOptionalMetrics drops from the mgr service monitor the metrics scraped by the enabled optional
+// service monitors, so that the same series are not ingested twice
+func (c *Cluster) dropOptionalMetrics(serviceMonitor *monitoringv1.ServiceMonitor) {
+	for _, optional := range c.optionalServiceMonitors() {
+		if !optional.enabled {
+			continue
+		}
+		for i := range serviceMonitor.Spec.Endpoints {
+			serviceMonitor.Spec.Endpoints[i].MetricRelabelConfigs = append(serviceMonitor.Spec.Endpoints[i].MetricRelabelConfigs,
+				&monitoringv1.RelabelConfig{SourceLabels: []string{"__name__"}, Regex: optional.metricsRegex, Action: "drop"})
+		}
+	}
+}
+
+// keepOptionalMetrics keeps only the metrics of the daemon of an optional service monitor, and labels them
+// with the job of the daemon
+func keepOptionalMetrics(serviceMonitor *monitoringv1.ServiceMonitor, optional optionalServiceMonitor) {
+	for i := range serviceMonitor.Spec.Endpoints {
+		serviceMonitor.Spec.Endpoints[i].MetricRelabelConfigs = append(serviceMonitor.Spec.Endpoints[i].MetricRelabelConfigs,
+			&monitoringv1.RelabelConfig{SourceLabels: []string{"__name__"}, Regex: optional.metricsRegex, Action: "keep"})
+		serviceMonitor.Spec.Endpoints[i].RelabelConfigs = append(serviceMonitor.Spec.Endpoints[i].RelabelConfigs,
+			&monitoringv1.RelabelConfig{TargetLabel: "job", Replacement: optional.name, Action: "replace"})
+	}
+}
+
+// validateAlerts checks the thresholds of the prometheus alerts
+func validateAlerts(alerts cephv1.AlertsSpec) error {
+	if alerts.OSDNearFullRatio < 0 || alerts.OSDNearFullRatio > 1 {
+		return errors.Errorf("invalid osdNearFullRatio %v. must be between 0 and 1", alerts.OSDNearFullRatio)
+	}
+	if alerts.OSDFullRatio < 0 || alerts.OSDFullRatio > 1 {
+		return errors.Errorf("invalid osdFullRatio %v. must be between 0 and 1", alerts.OSDFullRatio)
+	}
+	if alerts.OSDNearFullRatio != 0 && alerts.OSDFullRatio != 0 && alerts.OSDNearFullRatio > alerts.OSDFullRatio {
+		return errors.Errorf("invalid osdNearFullRatio %v. must not be greater than osdFullRatio %v", alerts.OSDNearFullRatio, alerts.OSDFullRatio)
+	}
+	if alerts.MonQuorumLossDuration != "" && !prometheusDurationRegex.MatchString(alerts.MonQuorumLossDuration) {
+		return errors.Errorf("invalid monQuorumLossDuration %q. must be a prometheus duration such as 15m", alerts.MonQuorumLossDuration)
+	}
+	if alerts.PGDegradedDuration != "" && !prometheusDurationRegex.MatchString(alerts.PGDegradedDuration) {
+		return errors.Errorf("invalid pgDegradedDuration %q. must be a prometheus duration such as 2h", alerts.PGDegradedDuration)
+	}
+	return nil
+}
+
+// applyAlerts overrides the thresholds of the alerts of a prometheus rule with the thresholds set in the cluster CR
+func applyAlerts(prometheusRule *monitoringv1.PrometheusRule, alerts cephv1.AlertsSpec) error {
+	if err := validateAlerts(alerts); err != nil {
+		return err
+	}
+
+	for i := range prometheusRule.Spec.Groups {
+		for j := range prometheusRule.Spec.Groups[i].Rules {
+			rule := &prometheusRule.Spec.Groups[i].Rules[j]
+			switch rule.Alert {
+			case osdNearFullAlert:
+				setAlertRatio(rule, alerts.OSDNearFullRatio)
+			case osdFullAlert:
+				setAlertRatio(rule, alerts.OSDFullRatio)
+			case monQuorumAtRiskAlert:
+				setAlertDuration(rule, alerts.MonQuorumLossDuration)
+			case pgDegradedAlert:
+				setAlertDuration(rule, alerts.PGDegradedDuration)
+			}
+		}
+	}
+	return nil
+}
+
+// setAlertRatio replaces the threshold ending the expression of an alert, and the percentage in its description
+func setAlertRatio(rule *monitoringv1.Rule, ratio float64) {
+	if ratio == 0 {
+		return
+	}
+	threshold := strconv.FormatFloat(ratio, 'f', -1, 64)
+	rule.Expr = intstr.FromString(alertExprThresholdRegex.ReplaceAllString(rule.Expr.String(), threshold+"$1"))
+	if description, ok := rule.Annotations["description"]; ok {
+		percent := strconv.FormatFloat(math.Round(ratio*10000)/100, 'f', -1, 64)
+		rule.Annotations["description"] = alertDescriptionRatioRegex.ReplaceAllString(description, percent+"%")
+	}
+}
+
+func setAlertDuration(rule *monitoringv1.Rule, duration string) {
+	if duration == "" {
+		return
+	}
+	rule.For = duration
+}
+
+// mergeLabels returns the labels of the template overridden by the labels of the cluster CR
+func mergeLabels(template, labels map[string]string) map[string]string {
+	if len(template) == 0 && len(labels) == 0 {
+		return nil
+	}
+	merged := map[string]string{}
+	for key, value := range template {
+		merged[key] = value
+	}
+	for key, value := range labels {
+		merged[key] = value
+	}
+	return merged
+}
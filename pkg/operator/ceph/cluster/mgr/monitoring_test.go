@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"testing"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func testPrometheusRule() *monitoringv1.PrometheusRule {
+	return &monitoringv1.PrometheusRule{
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name: "ceph.rules",
+					Rules: []monitoringv1.Rule{
+						{
+							Alert: "CephMonQuorumAtRisk",
+							Expr:  intstr.FromString("count(ceph_mon_quorum_status == 1) <= 1\n"),
+							For:   "15m",
+						},
+					},
+				},
+				{
+					Name: "osd-alert.rules",
+					Rules: []monitoringv1.Rule{
+						{
+							Alert:       "CephOSDNearFull",
+							Expr:        intstr.FromString("(ceph_osd_stat_bytes_used / ceph_osd_stat_bytes) >= 0.75\n"),
+							For:         "40s",
+							Annotations: map[string]string{"description": "Utilization has crossed 75% on host"},
+						},
+						{
+							Alert: "CephDataRecoveryTakingTooLong",
+							Expr:  intstr.FromString("ceph_pg_undersized > 0\n"),
+							For:   "2h",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestApplyAlerts(t *testing.T) {
+	// no thresholds keep the defaults
+	rule := testPrometheusRule()
+	err := applyAlerts(rule, cephv1.AlertsSpec{})
+	assert.NoError(t, err)
+	assert.Equal(t, testPrometheusRule(), rule)
+
+	// override all the thresholds
+	alerts := cephv1.AlertsSpec{OSDNearFullRatio: 0.7, MonQuorumLossDuration: "5m", PGDegradedDuration: "30m"}
+	err = applyAlerts(rule, alerts)
+	assert.NoError(t, err)
+	assert.Equal(t, "5m", rule.Spec.Groups[0].Rules[0].For)
+	assert.Equal(t, "count(ceph_mon_quorum_status == 1) <= 1\n", rule.Spec.Groups[0].Rules[0].Expr.String())
+	assert.Equal(t, "(ceph_osd_stat_bytes_used / ceph_osd_stat_bytes) >= 0.7\n", rule.Spec.Groups[1].Rules[0].Expr.String())
+	assert.Equal(t, "Utilization has crossed 70% on host", rule.Spec.Groups[1].Rules[0].Annotations["description"])
+	assert.Equal(t, "40s", rule.Spec.Groups[1].Rules[0].For)
+	assert.Equal(t, "30m", rule.Spec.Groups[1].Rules[1].For)
+
+	// invalid thresholds
+	err = applyAlerts(testPrometheusRule(), cephv1.AlertsSpec{OSDNearFullRatio: 1.5})
+	assert.Error(t, err)
+	err = applyAlerts(testPrometheusRule(), cephv1.AlertsSpec{OSDNearFullRatio: 0.9, OSDFullRatio: 0.8})
+	assert.Error(t, err)
+	err = applyAlerts(testPrometheusRule(), cephv1.AlertsSpec{MonQuorumLossDuration: "15 minutes"})
+	assert.Error(t, err)
+	err = applyAlerts(testPrometheusRule(), cephv1.AlertsSpec{PGDegradedDuration: "1h30m"})
+	assert.NoError(t, err)
+}
+
+func TestConfigureServiceMonitor(t *testing.T) {
+	c := &Cluster{
+		monitoringSpec: cephv1.MonitoringSpec{
+			Labels:          map[string]string{"release": "prometheus", "team": "storage"},
+			ServiceMonitors: cephv1.ServiceMonitorsSpec{RGW: true},
+		},
+		ownerRef: metav1.OwnerReference{Name: "rook-ceph"},
+	}
+	newServiceMonitor := func() *monitoringv1.ServiceMonitor {
+		return &monitoringv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mgr", Labels: map[string]string{"team": "rook"}},
+			Spec: monitoringv1.ServiceMonitorSpec{
+				Endpoints: []monitoringv1.Endpoint{{Port: "http-metrics", Path: "/metrics"}},
+			},
+		}
+	}
+	selector := map[string]string{"app": "rook-ceph-mgr", "rook_cluster": "ns"}
+
+	// the mgr service monitor drops the metrics of the enabled optional service monitors
+	serviceMonitor := newServiceMonitor()
+	c.configureServiceMonitor(serviceMonitor, "rook-ceph-mgr", "ns", selector)
+	c.dropOptionalMetrics(serviceMonitor)
+	assert.Equal(t, "ns", serviceMonitor.Namespace)
+	assert.Equal(t, map[string]string{"release": "prometheus", "team": "storage"}, serviceMonitor.Labels)
+	assert.Equal(t, []string{"ns"}, serviceMonitor.Spec.NamespaceSelector.MatchNames)
+	assert.Equal(t, selector, serviceMonitor.Spec.Selector.MatchLabels)
+	assert.Equal(t, 1, len(serviceMonitor.OwnerReferences))
+	assert.Equal(t, 1, len(serviceMonitor.Spec.Endpoints[0].MetricRelabelConfigs))
+	assert.Equal(t, "drop", serviceMonitor.Spec.Endpoints[0].MetricRelabelConfigs[0].Action)
+	assert.Equal(t, rgwMetricsRegex, serviceMonitor.Spec.Endpoints[0].MetricRelabelConfigs[0].Regex)
+
+	// the optional service monitor keeps the metrics of its daemon
+	optional := c.optionalServiceMonitors()[0]
+	assert.True(t, optional.enabled)
+	serviceMonitor = newServiceMonitor()
+	c.configureServiceMonitor(serviceMonitor, optional.name, "ns", selector)
+	keepOptionalMetrics(serviceMonitor, optional)
+	assert.Equal(t, rgwServiceMonitorName, serviceMonitor.Name)
+	assert.Equal(t, "keep", serviceMonitor.Spec.Endpoints[0].MetricRelabelConfigs[0].Action)
+	assert.Equal(t, rgwServiceMonitorName, serviceMonitor.Spec.Endpoints[0].RelabelConfigs[0].Replacement)
+	assert.Equal(t, "job", serviceMonitor.Spec.Endpoints[0].RelabelConfigs[0].TargetLabel)
+	assert.False(t, c.optionalServiceMonitors()[1].enabled)
+}
@@ -27,10 +27,20 @@ import (
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	cephconfig "github.com/rook/rook/pkg/daemon/ceph/config"
 	cephutil "github.com/rook/rook/pkg/daemon/ceph/util"
+	"github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/controller"
+	opmetrics "github.com/rook/rook/pkg/operator/ceph/metrics"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	monFailoverSkippedReason = "MonFailoverSkipped"
+	monFailoverClearedReason = "MonFailoverCleared"
 )
 
 var (
@@ -67,7 +77,7 @@ func (hc *HealthChecker) Check(stopCh chan struct{}) {
 			logger.Infof("Stopping monitoring of mons in namespace %s", hc.monCluster.Namespace)
 			return
 
-		case <-time.After(HealthCheckInterval):
+		case <-time.After(hc.monCluster.healthCheckInterval()):
 			logger.Debugf("checking health of mons")
 			err := hc.monCluster.checkHealth()
 			if err != nil {
@@ -103,6 +113,8 @@ func (c *Cluster) checkHealth() error {
 		return c.handleExternalMonStatus(quorumStatus)
 	}
 
+	monOutTimeout := c.monOutTimeout()
+
 	// connect to the mons
 	// get the status and check for quorum
 	quorumStatus, err := client.GetMonQuorumStatus(c.context, c.ClusterInfo.Name)
@@ -125,6 +137,7 @@ func (c *Cluster) checkHealth() error {
 	// first handle mons that are not in quorum but in the ceph mon map
 	// failover the unhealthy mons
 	allMonsInQuorum := true
+	failoverSkipped := false
 	for _, mon := range quorumStatus.MonMap.Mons {
 		inQuorum := monInQuorum(mon, quorumStatus.Quorum)
 		// if the mon is in quorum remove it from our check for "existence"
@@ -170,8 +183,8 @@ func (c *Cluster) checkHealth() error {
 
 		// when the timeout for the mon has been reached, continue to the
 		// normal failover/delete mon pod part of the code
-		if time.Since(c.monTimeoutList[mon.Name]) <= MonOutTimeout {
-			timeToFailover := int(MonOutTimeout.Seconds() - time.Since(c.monTimeoutList[mon.Name]).Seconds())
+		if time.Since(c.monTimeoutList[mon.Name]) <= monOutTimeout {
+			timeToFailover := int(monOutTimeout.Seconds() - time.Since(c.monTimeoutList[mon.Name]).Seconds())
 			logger.Warningf("mon %q not found in quorum, waiting for timeout (%d seconds left) before failover", mon.Name, timeToFailover)
 
 			// Restart the mon if it is stuck on a failed node
@@ -179,6 +192,10 @@ func (c *Cluster) checkHealth() error {
 			continue
 		}
 
+		if c.skipFailover(mon.Name, fmt.Sprintf("out of quorum for more than %s", monOutTimeout)) {
+			failoverSkipped = true
+			continue
+		}
 		logger.Warningf("mon %q NOT found in quorum and timeout exceeded, mon will be failed over", mon.Name)
 		c.failMon(len(quorumStatus.MonMap.Mons), desiredMonCount, mon.Name)
 		// only deal with one unhealthy mon per health check
@@ -188,12 +205,22 @@ func (c *Cluster) checkHealth() error {
 	// after all unhealthy mons have been removed or failed over
 	// handle all mons that haven't been in the Ceph mon map
 	for mon := range monsNotFound {
+		if c.skipFailover(mon, "not found in the ceph mon map") {
+			failoverSkipped = true
+			continue
+		}
 		logger.Warningf("mon %s NOT found in ceph mon map, failover", mon)
 		c.failMon(len(c.ClusterInfo.Monitors), desiredMonCount, mon)
 		// only deal with one "not found in ceph mon map" mon per health check
 		return nil
 	}
 
+	// do not change the mons while a failover is pending
+	if failoverSkipped {
+		return nil
+	}
+	c.clearReportedFailovers()
+
 	// create/start new mons when there are fewer mons than the desired count in the CRD
 	if len(quorumStatus.MonMap.Mons) < desiredMonCount {
 		logger.Infof("adding mons. currently %d mons are in quorum and the desired count is %d.", len(quorumStatus.MonMap.Mons), desiredMonCount)
@@ -219,6 +246,86 @@ func (c *Cluster) checkHealth() error {
 	return nil
 }
 
+// healthCheckInterval returns the interval of the mon health checks set in the cluster CR, or else on the operator
+func (c *Cluster) healthCheckInterval() time.Duration {
+	if interval := c.spec.Mon.HealthCheck.Interval; interval != nil && interval.Duration > 0 {
+		return interval.Duration
+	}
+	return HealthCheckInterval
+}
+
+// monOutTimeout returns the duration a mon can be out of quorum before it is failed over set in the cluster CR,
+// or else on the operator
+func (c *Cluster) monOutTimeout() time.Duration {
+	if timeout := c.spec.Mon.HealthCheck.Timeout; timeout != nil && timeout.Duration > 0 {
+		return timeout.Duration
+	}
+	return MonOutTimeout
+}
+
+// skipFailover returns whether the failover of an unhealthy mon is disabled in the cluster CR. In report only mode,
+// the failover is reported with an event and a condition on the cluster CR instead.
+func (c *Cluster) skipFailover(name, reason string) bool {
+	healthCheck := c.spec.Mon.HealthCheck
+	if healthCheck.ReportOnly {
+		message := fmt.Sprintf("mon %q is %s and would be failed over, but the mon health check is in report only mode", name, reason)
+		logger.Warning(message)
+		c.reportFailover(name, message)
+		return true
+	}
+	if healthCheck.DisableFailover {
+		logger.Warningf("mon %q is %s, but the mon failover is disabled", name, reason)
+		return true
+	}
+	return false
+}
+
+// reportFailover reports a skipped mon failover with an event and a condition on the cluster CR, once per mon
+// and reason
+func (c *Cluster) reportFailover(name, message string) {
+	if c.reportedFailovers[name] == message {
+		return
+	}
+	c.reportedFailovers[name] = message
+
+	c.eventRecorder().Event(c.clusterReference(), v1.EventTypeWarning, monFailoverSkippedReason, message)
+	config.ConditionExport(c.context, c.clusterNamespacedName(), cephv1.ConditionMonFailoverPending, v1.ConditionTrue, monFailoverSkippedReason, message)
+}
+
+// clearReportedFailovers clears the condition of the skipped mon failovers once no failover is pending anymore
+func (c *Cluster) clearReportedFailovers() {
+	if len(c.reportedFailovers) == 0 {
+		return
+	}
+	c.reportedFailovers = map[string]string{}
+
+	message := "no mon failover is pending"
+	c.eventRecorder().Event(c.clusterReference(), v1.EventTypeNormal, monFailoverClearedReason, message)
+	config.ConditionExport(c.context, c.clusterNamespacedName(), cephv1.ConditionMonFailoverPending, v1.ConditionFalse, monFailoverClearedReason, message)
+}
+
+func (c *Cluster) eventRecorder() record.EventRecorder {
+	if c.recorder == nil {
+		c.recorder = k8sutil.NewEventRecorder(c.context.Clientset, "rook-ceph-mon-health")
+	}
+	return c.recorder
+}
+
+// clusterReference returns the reference of the cluster CR for the events
+func (c *Cluster) clusterReference() *v1.ObjectReference {
+	return &v1.ObjectReference{
+		APIVersion: c.ownerRef.APIVersion,
+		Kind:       c.ownerRef.Kind,
+		Name:       c.ownerRef.Name,
+		Namespace:  c.Namespace,
+		UID:        c.ownerRef.UID,
+	}
+}
+
+func (c *Cluster) clusterNamespacedName() types.NamespacedName {
+	return types.NamespacedName{Namespace: c.Namespace, Name: c.ownerRef.Name}
+}
+
 // failMon compares the monCount against desiredMonCount
 func (c *Cluster) failMon(monCount, desiredMonCount int, name string) {
 	if monCount > desiredMonCount {
@@ -239,6 +346,22 @@ func (c *Cluster) failoverMon(name string) error {
 
 	// Start a new monitor
 	m := c.newMonConfig(c.maxMonID + 1)
+
+	// The new mon of a stretch cluster replaces the failed mon in its zone, or else goes to the zone with the
+	// fewest mons
+	if c.isStretchCluster() {
+		m.Zone = c.mapping.Zone[name]
+		if m.Zone == "" {
+			zone, err := c.zoneWithFewestMons(name)
+			if err != nil {
+				return errors.Wrapf(err, "failed to find the zone of the mon replacing mon %q", name)
+			}
+			m.Zone = zone
+		}
+		if err := c.assignMonZones([]*monConfig{m}); err != nil {
+			return errors.Wrap(err, "failed to assign the new mon to a zone")
+		}
+	}
 	logger.Infof("starting new mon: %+v", m)
 
 	mConf := []*monConfig{m}
@@ -271,6 +394,19 @@ func (c *Cluster) failoverMon(name string) error {
 
 	// Only increment the max mon id if the new pod started successfully
 	c.maxMonID++
+	opmetrics.MonFailovers.WithLabelValues(c.Namespace).Inc()
+
+	if c.isStretchCluster() {
+		if err := client.SetMonLocation(c.context, c.Namespace, m.DaemonName, c.monCrushLocation(m)); err != nil {
+			logger.Warningf("failed to set the location of the new mon %q. %v", m.DaemonName, err)
+		}
+		// The tiebreaker mon must be replaced before it is removed from the quorum
+		if m.Zone == stretchArbiterZone(c.spec.Mon.StretchCluster) {
+			if err := c.setNewTiebreakerMon(m.DaemonName); err != nil {
+				logger.Warningf("failed to set the new tiebreaker mon %q. %v", m.DaemonName, err)
+			}
+		}
+	}
 
 	return c.removeMon(name)
 }
@@ -302,6 +438,7 @@ func (c *Cluster) removeMon(daemonName string) error {
 	if _, ok := c.mapping.Node[daemonName]; ok {
 		delete(c.mapping.Node, daemonName)
 	}
+	delete(c.mapping.Zone, daemonName)
 
 	// Remove the service endpoint
 	if err := c.context.Clientset.CoreV1().Services(c.Namespace).Delete(resourceName, options); err != nil {
@@ -450,7 +587,7 @@ func (c *Cluster) restartMonIfStuckTerminating(monName string) error {
 		return errors.Wrapf(err, "failed to get pod for mon %q", monName)
 	}
 	for _, pod := range pods.Items {
-		if err := k8sutil.ForceDeletePodIfStuck(c.context, pod); err != nil {
+		if _, err := k8sutil.ForceDeletePodIfStuck(c.context, pod); err != nil {
 			logger.Warningf("skipping forced restart of mon %q. %v", monName, err)
 		}
 	}
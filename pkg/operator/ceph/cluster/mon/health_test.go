@@ -17,6 +17,7 @@ limitations under the License.
 package mon
 
 import (
+	ctx "context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -38,7 +39,11 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestCheckHealth(t *testing.T) {
@@ -152,6 +157,91 @@ func TestCheckHealthNotFound(t *testing.T) {
 	}
 }
 
+func TestCheckHealthReportOnly(t *testing.T) {
+	var deploymentsUpdated *[]*apps.Deployment
+	updateDeploymentAndWait, deploymentsUpdated = testopk8s.UpdateDeploymentAndWaitStub()
+
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command string, outFileArg string, args ...string) (string, error) {
+			return clienttest.MonInQuorumResponse(), nil
+		},
+	}
+	clientset := test.New(t, 1)
+	configDir, _ := ioutil.TempDir("", "")
+	defer os.RemoveAll(configDir)
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "ns"}}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{})
+	context := &clusterd.Context{
+		Clientset: clientset,
+		Client:    fake.NewFakeClientWithScheme(s, cephCluster),
+		ConfigDir: configDir,
+		Executor:  executor,
+	}
+	c := New(context, "ns", "", cephv1.NetworkSpec{}, metav1.OwnerReference{Name: "rook-ceph", Kind: "CephCluster"}, &sync.Mutex{})
+	setCommonMonProperties(c, 2, cephv1.MonSpec{Count: 3, AllowMultiplePerNode: true}, "myversion")
+	c.spec.Mon.HealthCheck.ReportOnly = true
+	c.waitForStart = false
+	recorder := record.NewFakeRecorder(10)
+	c.recorder = recorder
+	c.mapping.Node["a"] = &NodeInfo{Name: "node0"}
+	c.maxMonID = 4
+	c.saveMonConfig()
+
+	// the mon b isn't in the MonInQuorumResponse(), but it is not failed over in report only mode
+	err := c.checkHealth()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{}, testopk8s.DeploymentNamesUpdated(deploymentsUpdated))
+	assert.Equal(t, 2, len(c.ClusterInfo.Monitors))
+	_, ok := c.ClusterInfo.Monitors["f"]
+	assert.False(t, ok)
+	assert.Equal(t, 1, len(recorder.Events))
+	event := <-recorder.Events
+	assert.Contains(t, event, monFailoverSkippedReason)
+	assert.Contains(t, event, `mon "b"`)
+
+	err = context.Client.Get(ctx.TODO(), types.NamespacedName{Namespace: "ns", Name: "rook-ceph"}, cephCluster)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(cephCluster.Status.Conditions))
+	assert.Equal(t, cephv1.ConditionMonFailoverPending, cephCluster.Status.Conditions[0].Type)
+	assert.Equal(t, v1.ConditionTrue, cephCluster.Status.Conditions[0].Status)
+	assert.Equal(t, cephv1.ConditionType(""), cephCluster.Status.Phase)
+
+	// the same failover is only reported once
+	err = c.checkHealth()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(recorder.Events))
+
+	// failover disabled
+	c.spec.Mon.HealthCheck.ReportOnly = false
+	c.spec.Mon.HealthCheck.DisableFailover = true
+	err = c.checkHealth()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(c.ClusterInfo.Monitors))
+	assert.Equal(t, 0, len(recorder.Events))
+
+	// the condition is cleared when no failover is pending anymore
+	c.clearReportedFailovers()
+	assert.Equal(t, 1, len(recorder.Events))
+	assert.Contains(t, <-recorder.Events, monFailoverClearedReason)
+	err = context.Client.Get(ctx.TODO(), types.NamespacedName{Namespace: "ns", Name: "rook-ceph"}, cephCluster)
+	assert.NoError(t, err)
+	assert.Equal(t, v1.ConditionFalse, cephCluster.Status.Conditions[0].Status)
+	c.clearReportedFailovers()
+	assert.Equal(t, 0, len(recorder.Events))
+}
+
+func TestHealthCheckSettings(t *testing.T) {
+	c := New(&clusterd.Context{}, "ns", "", cephv1.NetworkSpec{}, metav1.OwnerReference{}, &sync.Mutex{})
+	assert.Equal(t, HealthCheckInterval, c.healthCheckInterval())
+	assert.Equal(t, MonOutTimeout, c.monOutTimeout())
+
+	c.spec.Mon.HealthCheck.Interval = &metav1.Duration{Duration: time.Minute}
+	c.spec.Mon.HealthCheck.Timeout = &metav1.Duration{Duration: time.Hour}
+	assert.Equal(t, time.Minute, c.healthCheckInterval())
+	assert.Equal(t, time.Hour, c.monOutTimeout())
+}
+
 func TestAddRemoveMons(t *testing.T) {
 	var deploymentsUpdated *[]*apps.Deployment
 	updateDeploymentAndWait, deploymentsUpdated = testopk8s.UpdateDeploymentAndWaitStub()
@@ -46,6 +46,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
@@ -125,6 +126,8 @@ type Cluster struct {
 	monPodRetryInterval time.Duration
 	monPodTimeout       time.Duration
 	monTimeoutList      map[string]time.Time
+	reportedFailovers   map[string]string
+	recorder            record.EventRecorder
 	mapping             *Mapping
 	ownerRef            metav1.OwnerReference
 	csiConfigMutex      *sync.Mutex
@@ -144,11 +147,15 @@ type monConfig struct {
 	// DataPathMap is the mapping relationship between mon data stored on the host and mon data
 	// stored in containers.
 	DataPathMap *config.DataPathMap
+	// Zone is the stretch cluster zone of the mon, empty if the cluster is not stretched
+	Zone string
 }
 
 // Mapping is mon node and port mapping
 type Mapping struct {
 	Node map[string]*NodeInfo `json:"node"`
+	// Zone is the stretch cluster zone of each mon
+	Zone map[string]string `json:"zone,omitempty"`
 }
 
 // NodeInfo contains name and address of a node
@@ -175,6 +182,7 @@ func New(context *clusterd.Context, namespace, dataDirHostPath string, network c
 		monPodRetryInterval: 6 * time.Second,
 		monPodTimeout:       5 * time.Minute,
 		monTimeoutList:      map[string]time.Time{},
+		reportedFailovers:   map[string]string{},
 		Network:             network,
 		mapping: &Mapping{
 			Node: map[string]*NodeInfo{},
@@ -206,6 +214,10 @@ func (c *Cluster) Start(clusterInfo *cephconfig.ClusterInfo, rookVersion string,
 		return nil, errors.Wrap(err, "error checking pod memory")
 	}
 
+	if err := validateStretchCluster(c.spec.Mon, cephVersion); err != nil {
+		return nil, errors.Wrap(err, "invalid stretch cluster settings")
+	}
+
 	logger.Infof("start running mons")
 
 	logger.Debugf("establishing ceph cluster info")
@@ -223,6 +235,13 @@ func (c *Cluster) startMons(targetCount int) error {
 	// init the mon config
 	existingCount, mons := c.initMonConfig(targetCount)
 
+	// Assign the mons to the zones of a stretch cluster before scheduling them
+	if c.isStretchCluster() {
+		if err := c.assignMonZones(mons); err != nil {
+			return errors.Wrap(err, "failed to assign mons to zones")
+		}
+	}
+
 	// Assign the mons to nodes
 	if err := c.assignMons(mons); err != nil {
 		return errors.Wrap(err, "failed to assign pods to mons")
@@ -284,6 +303,12 @@ func (c *Cluster) startMons(targetCount int) error {
 		}
 	}
 
+	if c.isStretchCluster() {
+		if err := c.configureStretchMons(mons); err != nil {
+			return errors.Wrap(err, "failed to configure the stretch cluster mons")
+		}
+	}
+
 	logger.Debugf("mon endpoints used are: %s", FlattenMonEndpoints(c.ClusterInfo.Monitors))
 	return nil
 }
@@ -373,6 +398,7 @@ func (c *Cluster) initMonConfig(size int) (int, []*monConfig) {
 			Port:         cephutil.GetPortFromEndpoint(monitor.Endpoint),
 			DataPathMap: config.NewStatefulDaemonDataPathMap(
 				c.dataDirHostPath, dataDirRelativeHostPath(monitor.Name), config.MonType, monitor.Name, c.Namespace),
+			Zone: c.mapping.Zone[monitor.Name],
 		})
 	}
 
@@ -432,7 +458,7 @@ func realScheduleMonitor(c *Cluster, mon *monConfig) (SchedulingResult, error) {
 	d.Spec.Template.Spec.Containers[0].LivenessProbe = nil
 
 	// setup affinity settings for pod scheduling
-	p := cephv1.GetMonPlacement(c.spec.Placement)
+	p := c.monPlacement(mon)
 	k8sutil.SetNodeAntiAffinityForPod(&d.Spec.Template.Spec, p, requiredDuringScheduling(&c.spec), PreferredDuringScheduling,
 		map[string]string{k8sutil.AppAttr: AppName}, nil)
 
@@ -884,7 +910,7 @@ func (c *Cluster) startMon(m *monConfig, node *NodeInfo) error {
 	}
 
 	// placement settings from the CRD
-	p := cephv1.GetMonPlacement(c.spec.Placement)
+	p := c.monPlacement(m)
 
 	if deploymentExists {
 		// the existing deployment may have a node selector. if the cluster
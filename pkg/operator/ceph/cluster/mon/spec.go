@@ -286,6 +286,11 @@ func (c *Cluster) makeMonDaemonContainer(monConfig *monConfig) v1.Container {
 			config.NewFlag("public-bind-addr", controller.ContainerEnvVarReference(podIPEnvVar)))
 	}
 
+	// The mons of a stretch cluster need their location to join the quorum with the connectivity election strategy
+	if c.isStretchCluster() && monConfig.Zone != "" {
+		container.Args = append(container.Args, config.NewFlag("set-crush-location", c.monCrushLocation(monConfig)))
+	}
+
 	// Add messenger 2 port
 	addContainerPort(container, "tcp-msgr2", 3300)
 
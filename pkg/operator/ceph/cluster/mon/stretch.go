@@ -0,0 +1,357 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// the number of mons of a stretch cluster: two in each data zone and the tiebreaker in the arbiter zone
+	stretchClusterMonCount = 5
+	// the number of mons running in each data zone of a stretch cluster
+	stretchClusterMonsPerDataZone = 2
+	// the node label of the zones when the failure domain label is not set
+	defaultStretchFailureDomainLabel = v1.LabelZoneFailureDomainStable
+	// the CRUSH root of the zones when the crush root is not set
+	defaultStretchCrushRoot = "default"
+	// the CRUSH rule replicating the data across the two data zones
+	stretchCrushRuleName = "stretch_rule"
+	// the mon election strategy required by the stretch mode
+	stretchElectionStrategy = "connectivity"
+)
+
+// validateStretchCluster checks the settings of a stretch cluster
+func validateStretchCluster(spec cephv1.MonSpec, cephVersion cephver.CephVersion) error {
+	if spec.StretchCluster == nil {
+		return nil
+	}
+	if !cephVersion.IsAtLeastPacific() {
+		return errors.Errorf("stretch clusters require ceph pacific or newer, found ceph %q", cephVersion.String())
+	}
+	if spec.Count != stretchClusterMonCount {
+		return errors.Errorf("stretch clusters require %d mons, found %d", stretchClusterMonCount, spec.Count)
+	}
+	if spec.AllowMultiplePerNode {
+		return errors.New("stretch clusters do not allow multiple mons per node")
+	}
+
+	zones := spec.StretchCluster.Zones
+	if len(zones) != 3 {
+		return errors.Errorf("stretch clusters require two data zones and an arbiter zone, found %d zones", len(zones))
+	}
+	names := map[string]bool{}
+	arbiters := 0
+	for _, zone := range zones {
+		if zone.Name == "" {
+			return errors.New("the name of a stretch cluster zone must not be empty")
+		}
+		if names[zone.Name] {
+			return errors.Errorf("the stretch cluster zone %q is listed more than once", zone.Name)
+		}
+		names[zone.Name] = true
+		if zone.Arbiter {
+			arbiters++
+		}
+	}
+	if arbiters != 1 {
+		return errors.Errorf("stretch clusters require exactly one arbiter zone, found %d", arbiters)
+	}
+
+	return nil
+}
+
+// stretchFailureDomainLabel returns the node label of the zones of a stretch cluster
+func stretchFailureDomainLabel(spec *cephv1.StretchClusterSpec) string {
+	if spec.FailureDomainLabel == "" {
+		return defaultStretchFailureDomainLabel
+	}
+	return spec.FailureDomainLabel
+}
+
+// stretchFailureDomainType returns the CRUSH bucket type of the zones of a stretch cluster, which is the type of
+// the level of the crush hierarchy with the failure domain label, or else the name of the label without its prefix
+func stretchFailureDomainType(spec cephv1.ClusterSpec) string {
+	label := stretchFailureDomainLabel(spec.Mon.StretchCluster)
+	for _, level := range spec.Crush.Hierarchy {
+		if level.Label == label {
+			return level.Type
+		}
+	}
+	return label[strings.LastIndex(label, "/")+1:]
+}
+
+// stretchCrushRoot returns the CRUSH root holding the zones of a stretch cluster
+func stretchCrushRoot(spec *cephv1.StretchClusterSpec) string {
+	if spec.CrushRoot == "" {
+		return defaultStretchCrushRoot
+	}
+	return spec.CrushRoot
+}
+
+// validateStretchCrushMap checks that the bucket type of the zones and the root of a stretch cluster exist in the
+// CRUSH map
+func validateStretchCrushMap(crushMap client.CrushMap, failureDomain, root string) error {
+	typeExists := false
+	for _, crushType := range crushMap.Types {
+		if crushType.Name == failureDomain {
+			typeExists = true
+			break
+		}
+	}
+	if !typeExists {
+		return errors.Errorf("the crush type %q of the stretch cluster zones is not in the crush map, set the failureDomainLabel to a label of the crush hierarchy or to a label named after a crush type", failureDomain)
+	}
+
+	for _, bucket := range crushMap.Buckets {
+		if bucket.Name == root && bucket.TypeName == "root" {
+			return nil
+		}
+	}
+	return errors.Errorf("the crush root %q of the stretch cluster is not in the crush map", root)
+}
+
+// stretchArbiterZone returns the name of the arbiter zone of a stretch cluster
+func stretchArbiterZone(spec *cephv1.StretchClusterSpec) string {
+	for _, zone := range spec.Zones {
+		if zone.Arbiter {
+			return zone.Name
+		}
+	}
+	return ""
+}
+
+func (c *Cluster) isStretchCluster() bool {
+	return c.spec.Mon.StretchCluster != nil
+}
+
+// assignMonZones assigns a zone to the mons that are not assigned to a zone yet. The arbiter zone
+// runs a single mon and each data zone runs two mons.
+func (c *Cluster) assignMonZones(mons []*monConfig) error {
+	if c.mapping.Zone == nil {
+		c.mapping.Zone = map[string]string{}
+	}
+
+	count := map[string]int{}
+	for _, mon := range mons {
+		if mon.Zone == "" {
+			mon.Zone = c.mapping.Zone[mon.DaemonName]
+		}
+		if mon.Zone != "" {
+			count[mon.Zone]++
+		}
+	}
+
+	for _, mon := range mons {
+		if mon.Zone != "" {
+			c.mapping.Zone[mon.DaemonName] = mon.Zone
+			continue
+		}
+		for _, zone := range c.spec.Mon.StretchCluster.Zones {
+			capacity := stretchClusterMonsPerDataZone
+			if zone.Arbiter {
+				capacity = 1
+			}
+			if count[zone.Name] < capacity {
+				mon.Zone = zone.Name
+				count[zone.Name]++
+				break
+			}
+		}
+		if mon.Zone == "" {
+			return errors.Errorf("no stretch cluster zone left for mon %q", mon.DaemonName)
+		}
+		logger.Infof("mon %q assigned to zone %q", mon.DaemonName, mon.Zone)
+		c.mapping.Zone[mon.DaemonName] = mon.Zone
+	}
+
+	return nil
+}
+
+// zoneWithFewestMons returns the zone running the fewest mons that has room for another mon, ignoring the
+// given mon. It places the replacement of a failed mon whose zone is unknown.
+func (c *Cluster) zoneWithFewestMons(ignoredMon string) (string, error) {
+	count := map[string]int{}
+	if c.ClusterInfo != nil {
+		for name := range c.ClusterInfo.Monitors {
+			if zone := c.mapping.Zone[name]; name != ignoredMon && zone != "" {
+				count[zone]++
+			}
+		}
+	}
+
+	selected := ""
+	for _, zone := range c.spec.Mon.StretchCluster.Zones {
+		capacity := stretchClusterMonsPerDataZone
+		if zone.Arbiter {
+			capacity = 1
+		}
+		if count[zone.Name] >= capacity {
+			continue
+		}
+		if selected == "" || count[zone.Name] < count[selected] {
+			selected = zone.Name
+		}
+	}
+	if selected == "" {
+		return "", errors.New("no stretch cluster zone left for a new mon")
+	}
+	return selected, nil
+}
+
+// monPlacement returns the placement of a mon, restricted to the nodes of its zone in a stretch cluster
+func (c *Cluster) monPlacement(mon *monConfig) rookv1.Placement {
+	p := cephv1.GetMonPlacement(c.spec.Placement)
+	if !c.isStretchCluster() || mon.Zone == "" {
+		return p
+	}
+	return zonePlacement(p, stretchFailureDomainLabel(c.spec.Mon.StretchCluster), mon.Zone)
+}
+
+// zonePlacement adds the requirement of the zone label to all the required node selector terms of the placement
+func zonePlacement(p rookv1.Placement, label, zone string) rookv1.Placement {
+	requirement := v1.NodeSelectorRequirement{
+		Key:      label,
+		Operator: v1.NodeSelectorOpIn,
+		Values:   []string{zone},
+	}
+
+	if p.NodeAffinity == nil {
+		p.NodeAffinity = &v1.NodeAffinity{}
+	} else {
+		p.NodeAffinity = p.NodeAffinity.DeepCopy()
+	}
+	if p.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		p.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &v1.NodeSelector{}
+	}
+
+	selector := p.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(selector.NodeSelectorTerms) == 0 {
+		selector.NodeSelectorTerms = []v1.NodeSelectorTerm{{}}
+	}
+	for i := range selector.NodeSelectorTerms {
+		selector.NodeSelectorTerms[i].MatchExpressions = append(selector.NodeSelectorTerms[i].MatchExpressions, requirement)
+	}
+
+	return p
+}
+
+// monCrushLocation returns the CRUSH location of a mon of a stretch cluster, such as "zone=a"
+func (c *Cluster) monCrushLocation(mon *monConfig) string {
+	return fmt.Sprintf("%s=%s", stretchFailureDomainType(c.spec), mon.Zone)
+}
+
+// configureStretchMons sets the election strategy and the location of the mons of a stretch cluster
+func (c *Cluster) configureStretchMons(mons []*monConfig) error {
+	if err := client.SetMonElectionStrategy(c.context, c.Namespace, stretchElectionStrategy); err != nil {
+		return errors.Wrap(err, "failed to set the election strategy of the stretch cluster")
+	}
+
+	for _, mon := range mons {
+		if err := client.SetMonLocation(c.context, c.Namespace, mon.DaemonName, c.monCrushLocation(mon)); err != nil {
+			return errors.Wrapf(err, "failed to set the location of mon %q", mon.DaemonName)
+		}
+	}
+
+	return nil
+}
+
+// stretchTiebreakerMon returns the mon running in the arbiter zone
+func (c *Cluster) stretchTiebreakerMon() (string, error) {
+	arbiter := stretchArbiterZone(c.spec.Mon.StretchCluster)
+	for name, zone := range c.mapping.Zone {
+		if zone == arbiter {
+			if _, ok := c.ClusterInfo.Monitors[name]; ok {
+				return name, nil
+			}
+		}
+	}
+	return "", errors.Errorf("no mon found in the arbiter zone %q", arbiter)
+}
+
+// ConfigureStretchMode enables the stretch mode of a stretch cluster once the OSDs are running.
+// The stretch mode cannot be disabled once enabled.
+func (c *Cluster) ConfigureStretchMode() error {
+	if !c.isStretchCluster() {
+		return nil
+	}
+
+	monDump, err := client.GetMonDump(c.context, c.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the mon map")
+	}
+	if monDump.StretchMode {
+		logger.Debugf("stretch mode already enabled with the tiebreaker mon %q", monDump.TiebreakerMon)
+		return nil
+	}
+
+	crushMap, err := client.GetCrushMap(c.context, c.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the crush map")
+	}
+	ruleExists := false
+	ruleID := 0
+	for _, rule := range crushMap.Rules {
+		if rule.Name == stretchCrushRuleName {
+			ruleExists = true
+			break
+		}
+		if rule.ID >= ruleID {
+			ruleID = rule.ID + 1
+		}
+	}
+
+	failureDomain := stretchFailureDomainType(c.spec)
+	if err := validateStretchCrushMap(crushMap, failureDomain, stretchCrushRoot(c.spec.Mon.StretchCluster)); err != nil {
+		return err
+	}
+	if !ruleExists {
+		logger.Infof("creating the crush rule %q of the stretch cluster", stretchCrushRuleName)
+		if err := client.CreateStretchCrushRule(c.context, c.Namespace, stretchCrushRuleName, stretchCrushRoot(c.spec.Mon.StretchCluster), failureDomain, ruleID); err != nil {
+			return errors.Wrap(err, "failed to create the stretch crush rule")
+		}
+	}
+
+	tiebreaker, err := c.stretchTiebreakerMon()
+	if err != nil {
+		return err
+	}
+	logger.Infof("enabling the stretch mode with the tiebreaker mon %q", tiebreaker)
+	return client.EnableStretchMode(c.context, c.Namespace, tiebreaker, stretchCrushRuleName, failureDomain)
+}
+
+// setNewTiebreakerMon replaces the tiebreaker mon if the stretch mode is already enabled
+func (c *Cluster) setNewTiebreakerMon(name string) error {
+	monDump, err := client.GetMonDump(c.context, c.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the mon map")
+	}
+	if !monDump.StretchMode {
+		return nil
+	}
+
+	logger.Infof("replacing the tiebreaker mon %q with mon %q", monDump.TiebreakerMon, name)
+	return client.SetNewTiebreakerMon(c.context, c.Namespace, name)
+}
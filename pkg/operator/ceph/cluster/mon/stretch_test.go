@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	cephconfig "github.com/rook/rook/pkg/daemon/ceph/config"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func stretchZones() []cephv1.StretchClusterZoneSpec {
+	return []cephv1.StretchClusterZoneSpec{
+		{Name: "a"},
+		{Name: "b"},
+		{Name: "arb", Arbiter: true},
+	}
+}
+
+func TestValidateStretchCluster(t *testing.T) {
+	spec := cephv1.MonSpec{Count: 5, StretchCluster: &cephv1.StretchClusterSpec{Zones: stretchZones()}}
+	assert.NoError(t, validateStretchCluster(spec, cephver.Pacific))
+
+	// not a stretch cluster
+	assert.NoError(t, validateStretchCluster(cephv1.MonSpec{Count: 3}, cephver.Nautilus))
+
+	// ceph version too old
+	assert.Error(t, validateStretchCluster(spec, cephver.Octopus))
+
+	// wrong mon count
+	spec.Count = 3
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+	spec.Count = 5
+
+	// multiple mons per node
+	spec.AllowMultiplePerNode = true
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+	spec.AllowMultiplePerNode = false
+
+	// missing zone
+	spec.StretchCluster.Zones = stretchZones()[1:]
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+
+	// duplicate zone
+	spec.StretchCluster.Zones = stretchZones()
+	spec.StretchCluster.Zones[1].Name = "a"
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+
+	// empty zone name
+	spec.StretchCluster.Zones[1].Name = ""
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+
+	// two arbiters
+	spec.StretchCluster.Zones = stretchZones()
+	spec.StretchCluster.Zones[0].Arbiter = true
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+
+	// no arbiter
+	spec.StretchCluster.Zones = stretchZones()
+	spec.StretchCluster.Zones[2].Arbiter = false
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+}
+
+func TestStretchFailureDomain(t *testing.T) {
+	spec := &cephv1.StretchClusterSpec{}
+	clusterSpec := cephv1.ClusterSpec{Mon: cephv1.MonSpec{StretchCluster: spec}}
+	assert.Equal(t, "topology.kubernetes.io/zone", stretchFailureDomainLabel(spec))
+	assert.Equal(t, "zone", stretchFailureDomainType(clusterSpec))
+	assert.Equal(t, "default", stretchCrushRoot(spec))
+
+	spec.FailureDomainLabel = "datacenter"
+	assert.Equal(t, "datacenter", stretchFailureDomainLabel(spec))
+	assert.Equal(t, "datacenter", stretchFailureDomainType(clusterSpec))
+
+	// the type of the crush hierarchy level with the label is used
+	spec.FailureDomainLabel = "example.com/site"
+	assert.Equal(t, "site", stretchFailureDomainType(clusterSpec))
+	clusterSpec.Crush.Hierarchy = []cephv1.CrushLevelSpec{{Type: "datacenter", Label: "example.com/site"}}
+	assert.Equal(t, "datacenter", stretchFailureDomainType(clusterSpec))
+
+	spec.CrushRoot = "stretched"
+	assert.Equal(t, "stretched", stretchCrushRoot(spec))
+
+	spec.Zones = stretchZones()
+	assert.Equal(t, "arb", stretchArbiterZone(spec))
+}
+
+func TestValidateStretchCrushMap(t *testing.T) {
+	var crushMap client.CrushMap
+	err := json.Unmarshal([]byte(`{
+		"types":[{"type_id":0,"name":"osd"},{"type_id":1,"name":"host"},{"type_id":9,"name":"zone"},{"type_id":11,"name":"root"}],
+		"buckets":[{"id":-1,"name":"default","type_name":"root"},{"id":-2,"name":"a","type_name":"zone"}]}`), &crushMap)
+	assert.NoError(t, err)
+
+	assert.NoError(t, validateStretchCrushMap(crushMap, "zone", "default"))
+	// the type is not in the crush map
+	assert.Error(t, validateStretchCrushMap(crushMap, "site", "default"))
+	// the root is not in the crush map or is not a root
+	assert.Error(t, validateStretchCrushMap(crushMap, "zone", "stretched"))
+	assert.Error(t, validateStretchCrushMap(crushMap, "zone", "a"))
+}
+
+func TestAssignMonZones(t *testing.T) {
+	c := New(&clusterd.Context{}, "ns", "", cephv1.NetworkSpec{}, metav1.OwnerReference{}, &sync.Mutex{})
+	c.spec.Mon = cephv1.MonSpec{Count: 5, StretchCluster: &cephv1.StretchClusterSpec{Zones: stretchZones()}}
+
+	// mon b was previously assigned to the arbiter zone
+	c.mapping.Zone = map[string]string{"b": "arb"}
+	mons := []*monConfig{}
+	for i := 0; i < 5; i++ {
+		mons = append(mons, c.newMonConfig(i))
+	}
+	assert.NoError(t, c.assignMonZones(mons))
+	assert.Equal(t, "a", mons[0].Zone)
+	assert.Equal(t, "arb", mons[1].Zone)
+	assert.Equal(t, "a", mons[2].Zone)
+	assert.Equal(t, "b", mons[3].Zone)
+	assert.Equal(t, "b", mons[4].Zone)
+	assert.Equal(t, 5, len(c.mapping.Zone))
+	assert.Equal(t, "b", c.mapping.Zone["e"])
+
+	// a sixth mon cannot be placed
+	assert.Error(t, c.assignMonZones(append(mons, c.newMonConfig(5))))
+
+	// the mon replacing a failed mon keeps its zone
+	delete(c.mapping.Zone, "b")
+	m := c.newMonConfig(6)
+	m.Zone = "arb"
+	assert.NoError(t, c.assignMonZones([]*monConfig{m}))
+	assert.Equal(t, "arb", c.mapping.Zone["g"])
+	assert.Equal(t, "zone=arb", c.monCrushLocation(m))
+}
+
+func TestZoneWithFewestMons(t *testing.T) {
+	c := New(&clusterd.Context{}, "ns", "", cephv1.NetworkSpec{}, metav1.OwnerReference{}, &sync.Mutex{})
+	c.spec.Mon = cephv1.MonSpec{Count: 5, StretchCluster: &cephv1.StretchClusterSpec{Zones: stretchZones()}}
+	c.ClusterInfo = &cephconfig.ClusterInfo{Monitors: map[string]*cephconfig.MonInfo{}}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		c.ClusterInfo.Monitors[name] = cephconfig.NewMonInfo(name, "", 0)
+	}
+
+	// the zone of failed mon e is unknown, zone b has a single mon left
+	c.mapping.Zone = map[string]string{"a": "a", "b": "a", "c": "arb", "d": "b"}
+	zone, err := c.zoneWithFewestMons("e")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", zone)
+
+	// the zone with the fewest mons is preferred to the first zone with room
+	c.mapping.Zone = map[string]string{"a": "a", "d": "b", "e": "b"}
+	zone, err = c.zoneWithFewestMons("c")
+	assert.NoError(t, err)
+	assert.Equal(t, "arb", zone)
+
+	// all the zones are full
+	c.mapping.Zone = map[string]string{"a": "a", "b": "a", "c": "arb", "d": "b", "e": "b"}
+	_, err = c.zoneWithFewestMons("f")
+	assert.Error(t, err)
+}
+
+func TestZonePlacement(t *testing.T) {
+	// no node affinity
+	p := zonePlacement(rookv1.Placement{}, "zone", "a")
+	terms := p.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	assert.Equal(t, 1, len(terms))
+	assert.Equal(t, []v1.NodeSelectorRequirement{{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a"}}}, terms[0].MatchExpressions)
+
+	// the zone is added to all the terms without modifying the original placement
+	original := rookv1.Placement{NodeAffinity: &v1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{
+				{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "role", Operator: v1.NodeSelectorOpIn, Values: []string{"storage"}}}},
+				{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "role", Operator: v1.NodeSelectorOpIn, Values: []string{"mon"}}}},
+			},
+		},
+	}}
+	p = zonePlacement(original, "zone", "b")
+	terms = p.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	assert.Equal(t, 2, len(terms))
+	for _, term := range terms {
+		assert.Equal(t, 2, len(term.MatchExpressions))
+		assert.Equal(t, "zone", term.MatchExpressions[1].Key)
+		assert.Equal(t, []string{"b"}, term.MatchExpressions[1].Values)
+	}
+	assert.Equal(t, 1, len(original.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reconcileCrushTypes adds the bucket types of the CRUSH hierarchy missing from the CRUSH map, so the OSDs can be
+// created under the buckets of these types
+func (c *Cluster) reconcileCrushTypes() error {
+	if len(c.crush.Hierarchy) == 0 {
+		return nil
+	}
+
+	crushMap, err := client.GetCrushMap(c.context, c.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the crush map types")
+	}
+	sort.Slice(crushMap.Types, func(i, j int) bool { return crushMap.Types[i].ID < crushMap.Types[j].ID })
+	current := []string{}
+	for _, crushType := range crushMap.Types {
+		current = append(current, crushType.Name)
+	}
+
+	types, changed := crushTypesWithHierarchy(current, c.crush.Hierarchy)
+	if !changed {
+		return nil
+	}
+	logger.Infof("adding the crush hierarchy types to the crush map. types: %v", types)
+	return client.SetCrushMapTypes(c.context, c.Namespace, types)
+}
+
+// crushTypesWithHierarchy returns the CRUSH types with the missing types of the hierarchy. A missing type is inserted
+// above the type declared before it in the hierarchy, or above host for the first type. The types are ordered from the
+// lowest to the highest.
+func crushTypesWithHierarchy(current []string, hierarchy []cephv1.CrushLevelSpec) ([]string, bool) {
+	types := append([]string{}, current...)
+	changed := false
+	previous := "host"
+	for _, level := range hierarchy {
+		if indexOf(types, level.Type) < 0 {
+			i := indexOf(types, previous) + 1
+			types = append(types[:i], append([]string{level.Type}, types[i:]...)...)
+			changed = true
+		}
+		previous = level.Type
+	}
+	return types, changed
+}
+
+func indexOf(list []string, value string) int {
+	for i, item := range list {
+		if item == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// reconcileCrushHierarchy moves the host buckets of the nodes under the buckets set by the labels of the nodes, so the
+// CRUSH hierarchy follows the changes of the node labels. The root of a host is not changed.
+func (c *Cluster) reconcileCrushHierarchy() error {
+	if len(c.crush.Hierarchy) == 0 {
+		return nil
+	}
+
+	tree, err := client.HostTree(c.context, c.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the crush hierarchy")
+	}
+	nodes, err := c.context.Clientset.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list the nodes")
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		hostName, err := k8sutil.GetNodeHostNameLabel(node)
+		if err != nil {
+			logger.Warningf("skipping the crush location of node %q. %v", node.Name, err)
+			continue
+		}
+		hostName = client.NormalizeCrushName(hostName)
+		current, found := crushBucketLocation(tree, "host", hostName)
+		if !found {
+			// no osd on the node
+			continue
+		}
+
+		desired := ExtractOSDTopologyFromHierarchy(node.Labels, c.crush.Hierarchy)
+		delete(desired, "host")
+		desired["root"] = "default"
+		if root, ok := current["root"]; ok {
+			desired["root"] = root
+		}
+		if reflect.DeepEqual(current, desired) {
+			continue
+		}
+
+		location := []string{}
+		for crushType, name := range desired {
+			location = append(location, fmt.Sprintf("%s=%s", crushType, name))
+		}
+		sort.Strings(location)
+		logger.Infof("moving crush host %q from %v to %v", hostName, current, location)
+		if err := client.MoveCrushBucket(c.context, c.Namespace, hostName, location); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// crushBucketLocation returns the ancestors of a bucket of the CRUSH hierarchy by type
+func crushBucketLocation(tree client.OsdTree, bucketType, name string) (map[string]string, bool) {
+	parents := map[int]int{}
+	index := map[int]int{}
+	bucket, found := 0, false
+	for i, node := range tree.Nodes {
+		index[node.ID] = i
+		for _, child := range node.Children {
+			parents[child] = node.ID
+		}
+		if node.Type == bucketType && node.Name == name {
+			bucket, found = node.ID, true
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	location := map[string]string{}
+	for id, ok := parents[bucket]; ok; id, ok = parents[id] {
+		node := tree.Nodes[index[id]]
+		location[node.Type] = node.Name
+	}
+	return location, true
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCrushTypesWithHierarchy(t *testing.T) {
+	current := []string{"osd", "host", "chassis", "rack", "row", "root"}
+
+	// no change if the types exist
+	types, changed := crushTypesWithHierarchy(current, []cephv1.CrushLevelSpec{{Type: "rack", Label: "rack"}})
+	assert.False(t, changed)
+	assert.Equal(t, current, types)
+
+	// a missing type is added above the previous type of the hierarchy
+	types, changed = crushTypesWithHierarchy(current, []cephv1.CrushLevelSpec{
+		{Type: "shelf", Label: "shelf"}, {Type: "rack", Label: "rack"}, {Type: "hall", Label: "hall"}})
+	assert.True(t, changed)
+	assert.Equal(t, []string{"osd", "host", "shelf", "chassis", "rack", "hall", "row", "root"}, types)
+	// the current types are not changed
+	assert.Equal(t, []string{"osd", "host", "chassis", "rack", "row", "root"}, current)
+}
+
+func TestCrushBucketLocation(t *testing.T) {
+	var tree client.OsdTree
+	assert.NoError(t, json.Unmarshal([]byte(testOSDTree), &tree))
+
+	location, found := crushBucketLocation(tree, "host", "node2")
+	assert.True(t, found)
+	assert.Equal(t, map[string]string{"rack": "rack1", "root": "default"}, location)
+
+	_, found = crushBucketLocation(tree, "host", "node4")
+	assert.False(t, found)
+}
+
+func TestReconcileCrushHierarchy(t *testing.T) {
+	moves := [][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command string, outFileArg string, args ...string) (string, error) {
+			if args[0] == "osd" && args[1] == "tree" {
+				return testOSDTree, nil
+			}
+			if args[0] == "osd" && args[1] == "crush" && args[2] == "move" {
+				moves = append(moves, args[3:6])
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	clientset := fake.NewSimpleClientset()
+	for name, labels := range map[string]map[string]string{
+		"node1": {"topology.rook.io/rack": "rack1"},
+		"node2": {"topology.rook.io/rack": "rack2", "example.com/hall": "hall1"},
+		"node3": {"topology.rook.io/rack": "rack2"},
+		"node4": {"topology.rook.io/rack": "rack3"},
+	} {
+		labels[v1.LabelHostname] = name
+		_, err := clientset.CoreV1().Nodes().Create(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}})
+		assert.NoError(t, err)
+	}
+	c := &Cluster{context: &clusterd.Context{Clientset: clientset, Executor: executor}, Namespace: "ns"}
+
+	// nothing to do without a hierarchy
+	assert.NoError(t, c.reconcileCrushHierarchy())
+	assert.Equal(t, 0, len(moves))
+
+	// only node2 moved, node4 has no osd
+	c.crush.Hierarchy = []cephv1.CrushLevelSpec{{Type: "hall", Label: "example.com/hall"}}
+	assert.NoError(t, c.reconcileCrushHierarchy())
+	assert.Equal(t, [][]string{{"node2", "hall=hall1", "rack=rack2"}}, moves)
+}
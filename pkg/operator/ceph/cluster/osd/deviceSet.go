@@ -79,6 +79,7 @@ func (c *Cluster) prepareStorageClassDeviceSets(config *provisionConfig) []rookv
 				TuneSlowDeviceClass: storageClassDeviceSet.TuneSlowDeviceClass,
 				SchedulerName:       storageClassDeviceSet.SchedulerName,
 				CrushDeviceClass:    crushDeviceClass,
+				Encrypted:           storageClassDeviceSet.Encrypted,
 			})
 		}
 	}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	opmon "github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	encryptionKeyVolumeName      = "osd-encryption-key"
+	encryptionKeyMountPath       = "/etc/ceph"
+	encryptionKeyFileName        = "luks_key"
+	encryptionDMNameFmt          = "%s-%s-dmcrypt"
+	encryptionBlockTmpName       = "block-tmp"
+	blockEncryptionKMSGetKeyInit = "encryption-kms-get-key"
+	blockEncryptionOpenInit      = "encryption-open"
+	// DmcryptBlockType is the name of the main block device of an encrypted OSD
+	DmcryptBlockType = "block"
+)
+
+const (
+	openEncryptedBlock = `
+set -xe
+
+KEY_FILE_PATH=%s
+BLOCK_PATH=%s
+DM_NAME=%s
+DM_PATH=%s
+OSD_BLOCK=%s
+
+# the device might already be opened if the pod restarted
+if [ -b "$DM_PATH" ]; then
+	echo "encrypted device $DM_PATH already opened"
+else
+	cryptsetup --verbose --key-file "$KEY_FILE_PATH" luksOpen "$BLOCK_PATH" "$DM_NAME"
+fi
+
+# expose the opened device to the osd as its main block
+cp --verbose --archive --remove-destination "$DM_PATH" "$OSD_BLOCK"
+`
+)
+
+// EncryptionDMName returns the name of the dm-crypt mapping of an encrypted OSD block on PVC
+func EncryptionDMName(pvcName, blockType string) string {
+	return fmt.Sprintf(encryptionDMNameFmt, pvcName, blockType)
+}
+
+// EncryptionDMPath returns the path of the dm-crypt mapping of an encrypted OSD block on PVC
+func EncryptionDMPath(pvcName, blockType string) string {
+	return path.Join("/dev/mapper", EncryptionDMName(pvcName, blockType))
+}
+
+func (osdProps osdProperties) isEncrypted() bool {
+	return osdProps.onPVC() && osdProps.storeConfig.EncryptedDevice
+}
+
+// getEncryptionKeyVolume returns the volume holding the dm-crypt key of the OSD. With the default Kubernetes
+// Secrets backend the secret is mounted directly, otherwise an init container fetches the key from the KMS.
+func (c *Cluster) getEncryptionKeyVolume(osdProps osdProperties) v1.Volume {
+	if c.isKMSSecretsBackend() {
+		return v1.Volume{
+			Name: encryptionKeyVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: kms.GenerateOSDEncryptionSecretName(osdProps.pvc.ClaimName),
+					Items: []v1.KeyToPath{
+						{Key: kms.OsdEncryptionSecretNameKeyName, Path: encryptionKeyFileName},
+					},
+				},
+			},
+		}
+	}
+
+	return v1.Volume{
+		Name: encryptionKeyVolumeName,
+		VolumeSource: v1.VolumeSource{
+			EmptyDir: &v1.EmptyDirVolumeSource{Medium: "Memory"},
+		},
+	}
+}
+
+func (c *Cluster) isKMSSecretsBackend() bool {
+	provider := c.security.KeyManagementService.ConnectionDetails[kms.Provider]
+	return provider == "" || provider == kms.SecretsProviderName
+}
+
+func getEncryptionKeyMount() v1.VolumeMount {
+	return v1.VolumeMount{Name: encryptionKeyVolumeName, MountPath: encryptionKeyMountPath}
+}
+
+// getKMSGetKeyInitContainer fetches the dm-crypt key from the KMS and writes it into the key volume
+func (c *Cluster) getKMSGetKeyInitContainer(osdProps osdProperties) v1.Container {
+	envVars := append(kms.ConfigToEnvVar(c.security), opmon.ClusterNameEnvVar(c.Namespace))
+
+	return v1.Container{
+		Name:  blockEncryptionKMSGetKeyInit,
+		Image: k8sutil.MakeRookImage(c.rookVersion),
+		Args: []string{
+			"ceph", "osd", "encryption-key",
+			"--pvc-name", osdProps.pvc.ClaimName,
+			"--key-file-path", path.Join(encryptionKeyMountPath, encryptionKeyFileName),
+		},
+		Env:             envVars,
+		VolumeMounts:    []v1.VolumeMount{getEncryptionKeyMount()},
+		SecurityContext: opmon.PodSecurityContext(),
+		Resources:       osdProps.resources,
+	}
+}
+
+// getEncryptionOpenInitContainer opens the LUKS device and replaces the OSD block with the dm-crypt device
+func (c *Cluster) getEncryptionOpenInitContainer(mountPath string, osdProps osdProperties) v1.Container {
+	return v1.Container{
+		Name:  blockEncryptionOpenInit,
+		Image: c.cephVersion.Image,
+		Command: []string{
+			"/bin/bash",
+			"-c",
+			fmt.Sprintf(openEncryptedBlock,
+				path.Join(encryptionKeyMountPath, encryptionKeyFileName),
+				path.Join(mountPath, encryptionBlockTmpName),
+				EncryptionDMName(osdProps.pvc.ClaimName, DmcryptBlockType),
+				EncryptionDMPath(osdProps.pvc.ClaimName, DmcryptBlockType),
+				path.Join(mountPath, "block"),
+			),
+		},
+		VolumeMounts: []v1.VolumeMount{
+			getPvcOSDBridgeMountActivate(mountPath, osdProps.pvc.ClaimName),
+			getEncryptionKeyMount(),
+			{Name: "devices", MountPath: "/dev"},
+		},
+		SecurityContext: PrivilegedContext(),
+		Resources:       osdProps.resources,
+	}
+}
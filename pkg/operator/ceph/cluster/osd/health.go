@@ -23,6 +23,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
+	opmetrics "github.com/rook/rook/pkg/operator/ceph/metrics"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -144,6 +145,7 @@ func (m *OSDHealthMonitor) removeOSDDeploymentIfSafeToDestroy(outOSDid int) erro
 				if err := k8sutil.DeleteDeployment(m.context.Clientset, dp.Items[0].Namespace, dp.Items[0].Name); err != nil {
 					return errors.Wrapf(err, "failed to delete osd deployment %s", dp.Items[0].Name)
 				}
+				opmetrics.OSDRemovals.WithLabelValues(m.namespace).Inc()
 			}
 		}
 	}
@@ -160,8 +162,13 @@ func (m *OSDHealthMonitor) restartOSDIfStuck(osdID int) error {
 		return errors.Wrapf(err, "failed to get OSD with ID %d", osdID)
 	}
 	for _, pod := range pods.Items {
-		if err := k8sutil.ForceDeletePodIfStuck(m.context, pod); err != nil {
+		deleted, err := k8sutil.ForceDeletePodIfStuck(m.context, pod)
+		if err != nil {
 			logger.Warningf("skipping restart of OSD %d. %v", osdID, err)
+			continue
+		}
+		if deleted {
+			opmetrics.OSDRestarts.WithLabelValues(m.namespace).Inc()
 		}
 	}
 	return nil
@@ -133,7 +133,9 @@ func TestOSDRestartIfStuck(t *testing.T) {
 
 	m := NewOSDHealthMonitor(context, namespace, false)
 
-	assert.NoError(t, k8sutil.ForceDeletePodIfStuck(m.context, pod))
+	deleted, err := k8sutil.ForceDeletePodIfStuck(m.context, pod)
+	assert.NoError(t, err)
+	assert.False(t, deleted)
 
 	// The pod should still exist since it wasn't in a deleted state
 	p, err := context.Clientset.CoreV1().Pods(namespace).Get(pod.Name, metav1.GetOptions{})
@@ -145,7 +147,9 @@ func TestOSDRestartIfStuck(t *testing.T) {
 	_, err = context.Clientset.CoreV1().Pods(namespace).Update(&pod)
 	assert.NoError(t, err)
 
-	assert.NoError(t, k8sutil.ForceDeletePodIfStuck(m.context, pod))
+	deleted, err = k8sutil.ForceDeletePodIfStuck(m.context, pod)
+	assert.NoError(t, err)
+	assert.False(t, deleted)
 
 	// The pod should still exist since the node is ready
 	p, err = context.Clientset.CoreV1().Pods(namespace).Get(pod.Name, metav1.GetOptions{})
@@ -161,7 +165,9 @@ func TestOSDRestartIfStuck(t *testing.T) {
 		assert.NoError(t, err)
 	}
 
-	assert.NoError(t, k8sutil.ForceDeletePodIfStuck(m.context, pod))
+	deleted, err = k8sutil.ForceDeletePodIfStuck(m.context, pod)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
 
 	// The pod should be deleted since the pod is marked as deleted and the node is not ready
 	_, err = context.Clientset.CoreV1().Pods(namespace).Get(pod.Name, metav1.GetOptions{})
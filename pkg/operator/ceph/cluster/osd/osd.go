@@ -35,7 +35,6 @@ import (
 	cephconfig "github.com/rook/rook/pkg/daemon/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	osdconfig "github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
-	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/controller"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/k8sutil"
@@ -94,6 +93,9 @@ type Cluster struct {
 	kv                                         *k8sutil.ConfigMapKVStore
 	skipUpgradeChecks                          bool
 	continueUpgradeAfterChecksEvenIfNotHealthy bool
+	security                                   cephv1.SecuritySpec
+	upgradeStrategy                            cephv1.OSDUpgradeStrategySpec
+	crush                                      cephv1.CrushSpec
 }
 
 // New creates an instance of the OSD manager
@@ -114,6 +116,9 @@ func New(
 	ownerRef metav1.OwnerReference,
 	skipUpgradeChecks bool,
 	continueUpgradeAfterChecksEvenIfNotHealthy bool,
+	security cephv1.SecuritySpec,
+	upgradeStrategy cephv1.OSDUpgradeStrategySpec,
+	crush cephv1.CrushSpec,
 ) *Cluster {
 	return &Cluster{
 		clusterInfo:       clusterInfo,
@@ -133,6 +138,9 @@ func New(
 		kv:                k8sutil.NewConfigMapKVStore(namespace, context.Clientset, ownerRef),
 		skipUpgradeChecks: skipUpgradeChecks,
 		continueUpgradeAfterChecksEvenIfNotHealthy: continueUpgradeAfterChecksEvenIfNotHealthy,
+		security:        security,
+		upgradeStrategy: upgradeStrategy,
+		crush:           crush,
 	}
 }
 
@@ -202,6 +210,11 @@ func (c *Cluster) Start() error {
 		logger.Warningf("useAllNodes is set to false and no nodes, storageClassDevicesets or volumeSources are specified, no OSD pods are going to be created")
 	}
 
+	// the types of the crush hierarchy must exist before the osds are created under them
+	if err := c.reconcileCrushTypes(); err != nil {
+		return errors.Wrap(err, "failed to add the crush hierarchy types")
+	}
+
 	// start the jobs to provision the OSD devices
 	logger.Infof("start provisioning the osds on pvcs, if needed")
 	c.startProvisioningOverPVCs(config)
@@ -209,6 +222,14 @@ func (c *Cluster) Start() error {
 	logger.Infof("start provisioning the osds on nodes, if needed")
 	c.startProvisioningOverNodes(config)
 
+	// the existing osds are updated once all of them are known, to restart them by failure domain
+	c.updateOSDsByFailureDomain(config)
+
+	// move the hosts under the buckets of their node labels
+	if err := c.reconcileCrushHierarchy(); err != nil {
+		config.addError("failed to reconcile the crush hierarchy. %v", err)
+	}
+
 	if len(config.errorMessages) > 0 {
 		return errors.Errorf("%d failures encountered while running osds in namespace %s: %+v",
 			len(config.errorMessages), c.Namespace, strings.Join(config.errorMessages, "\n"))
@@ -271,6 +292,7 @@ func (c *Cluster) startProvisioningOverPVCs(config *provisionConfig) {
 			portable:         volume.Portable,
 			crushDeviceClass: volume.CrushDeviceClass,
 			schedulerName:    volume.SchedulerName,
+			storeConfig:      osdconfig.StoreConfig{EncryptedDevice: volume.Encrypted},
 		}
 
 		logger.Debugf("osdProps are %+v", osdProps)
@@ -468,9 +490,7 @@ func (c *Cluster) startOSDDaemonsOnPVC(pvcName string, config *provisionConfig,
 		if createErr != nil {
 			if kerrors.IsAlreadyExists(createErr) {
 				logger.Infof("deployment for osd %d already exists. updating if needed", osd.ID)
-				if err = updateDeploymentAndWait(c.context, dp, c.Namespace, opconfig.OsdType, strconv.Itoa(osd.ID), c.skipUpgradeChecks, c.continueUpgradeAfterChecksEvenIfNotHealthy); err != nil {
-					logger.Errorf("failed to update osd deployment %d. %v", osd.ID, err)
-				}
+				c.updateOSD(dp, osd.ID, config)
 			} else {
 				// we failed to create job, update the orchestration status for this pvc
 				logger.Warningf("failed to create osd deployment for pvc %q, osd %v. %v", osdProps.pvc.ClaimName, osd, createErr)
@@ -479,9 +499,7 @@ func (c *Cluster) startOSDDaemonsOnPVC(pvcName string, config *provisionConfig,
 		}
 
 		if createErr != nil && kerrors.IsAlreadyExists(createErr) {
-			if err = updateDeploymentAndWait(c.context, dp, c.Namespace, opconfig.OsdType, strconv.Itoa(osd.ID), c.skipUpgradeChecks, c.continueUpgradeAfterChecksEvenIfNotHealthy); err != nil {
-				logger.Errorf("failed to update osd deployment %d. %v", osd.ID, err)
-			}
+			c.updateOSD(dp, osd.ID, config)
 		}
 		logger.Infof("started deployment for osd %d on pvc", osd.ID)
 	}
@@ -542,9 +560,7 @@ func (c *Cluster) startOSDDaemonsOnNode(nodeName string, config *provisionConfig
 		if createErr != nil {
 			if kerrors.IsAlreadyExists(createErr) {
 				logger.Debugf("deployment for osd %d already exists. updating if needed", osd.ID)
-				if err = updateDeploymentAndWait(c.context, dp, c.Namespace, opconfig.OsdType, strconv.Itoa(osd.ID), c.skipUpgradeChecks, c.continueUpgradeAfterChecksEvenIfNotHealthy); err != nil {
-					logger.Errorf("failed to update osd deployment %d. %v", osd.ID, err)
-				}
+				c.updateOSD(dp, osd.ID, config)
 			} else {
 				// we failed to create job, update the orchestration status for this pvc
 				logger.Warningf("failed to create osd deployment for node %q, osd %+v. %v", n.Name, osd, createErr)
@@ -627,6 +643,7 @@ func (c *Cluster) getOSDPropsForPVC(pvcName string) (osdProperties, error) {
 				tuneSlowDeviceClass: volumeSource.TuneSlowDeviceClass,
 				pvcSize:             volumeSource.Size,
 				schedulerName:       volumeSource.SchedulerName,
+				storeConfig:         osdconfig.StoreConfig{EncryptedDevice: volumeSource.Encrypted},
 			}
 			// If OSD isn't portable, we're getting the host name either from the osd deployment that was already initialized
 			// or from the osd prepare job from initial creation.
@@ -735,7 +752,7 @@ func (c *Cluster) getOSDInfo(d *apps.Deployment) ([]OSDInfo, error) {
 	}
 
 	if !locationFound {
-		location, err := getLocationFromPod(c.context.Clientset, d)
+		location, err := getLocationFromPod(c.context.Clientset, d, c.crush.Hierarchy)
 		if err != nil {
 			logger.Errorf("failed to get location. %v", err)
 		} else {
@@ -750,7 +767,7 @@ func (c *Cluster) getOSDInfo(d *apps.Deployment) ([]OSDInfo, error) {
 	return []OSDInfo{osd}, nil
 }
 
-func getLocationFromPod(clientset kubernetes.Interface, d *apps.Deployment) (string, error) {
+func getLocationFromPod(clientset kubernetes.Interface, d *apps.Deployment, hierarchy []cephv1.CrushLevelSpec) (string, error) {
 	pods, err := clientset.CoreV1().Pods(d.Namespace).List(metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", OsdIdLabelKey, d.Labels[OsdIdLabelKey])})
 	if err != nil || len(pods.Items) == 0 {
 		return "", err
@@ -767,10 +784,10 @@ func getLocationFromPod(clientset kubernetes.Interface, d *apps.Deployment) (str
 			hostName = pvcName
 		}
 	}
-	return GetLocationWithNode(clientset, nodeName, hostName)
+	return GetLocationWithNode(clientset, nodeName, hostName, hierarchy)
 }
 
-func GetLocationWithNode(clientset kubernetes.Interface, nodeName string, crushHostname string) (string, error) {
+func GetLocationWithNode(clientset kubernetes.Interface, nodeName string, crushHostname string, hierarchy []cephv1.CrushLevelSpec) (string, error) {
 
 	node, err := getNode(clientset, nodeName)
 	if err != nil {
@@ -792,7 +809,7 @@ func GetLocationWithNode(clientset kubernetes.Interface, nodeName string, crushH
 	locArgs := []string{"root=default", fmt.Sprintf("host=%s", hostName)}
 
 	nodeLabels := node.GetLabels()
-	UpdateLocationWithNodeLabels(&locArgs, nodeLabels)
+	UpdateLocationWithNodeLabels(&locArgs, nodeLabels, hierarchy)
 
 	loc := strings.Join(locArgs, " ")
 	logger.Infof("CRUSH location=%s", loc)
@@ -820,9 +837,9 @@ func getNode(clientset kubernetes.Interface, nodeName string) (*corev1.Node, err
 	return node, nil
 }
 
-func UpdateLocationWithNodeLabels(location *[]string, nodeLabels map[string]string) {
+func UpdateLocationWithNodeLabels(location *[]string, nodeLabels map[string]string, hierarchy []cephv1.CrushLevelSpec) {
 
-	topology := ExtractOSDTopologyFromLabels(nodeLabels)
+	topology := ExtractOSDTopologyFromHierarchy(nodeLabels, hierarchy)
 
 	keys := make([]string, 0, len(topology))
 	for k := range topology {
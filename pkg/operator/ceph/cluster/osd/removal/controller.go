@@ -0,0 +1,256 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package removal to remove the OSDs of a rook cluster.
+package removal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/disruption/controllerconfig"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-osd-removal-controller"
+)
+
+// the removal is checked periodically while the data of the OSDs is migrating and their disks are wiped
+var waitForRequeueIfRemovalInProgress = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephOSDRemovalKind = reflect.TypeOf(cephv1.CephOSDRemoval{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephOSDRemovalKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+var _ reconcile.Reconciler = &ReconcileCephOSDRemoval{}
+
+// ReconcileCephOSDRemoval reconciles a CephOSDRemoval object
+type ReconcileCephOSDRemoval struct {
+	client  client.Client
+	scheme  *runtime.Scheme
+	context *controllerconfig.Context
+}
+
+// Add creates a new CephOSDRemoval Controller and adds it to the Manager. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *controllerconfig.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *controllerconfig.Context) reconcile.Reconciler {
+	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
+	mgrScheme := mgr.GetScheme()
+	cephv1.AddToScheme(mgr.GetScheme())
+
+	return &ReconcileCephOSDRemoval{
+		client:  mgr.GetClient(),
+		scheme:  mgrScheme,
+		context: context,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephOSDRemoval CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephOSDRemoval{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephOSDRemoval object and makes changes based on the state read
+// and what is in the CephOSDRemoval.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephOSDRemoval) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephOSDRemoval) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephOSDRemoval instance
+	removal := &cephv1.CephOSDRemoval{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, removal)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephOSDRemoval resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephOSDRemoval")
+	}
+
+	// The CR was just created, initializing status fields
+	if removal.Status == nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil, nil)
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	cephCluster, isReadyToReconcile, _, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context.ClusterdContext, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		return reconcileResponse, nil
+	}
+
+	// Deleting the CR stops the removal, the OSDs already removed are not restored
+	if !removal.GetDeletionTimestamp().IsZero() {
+		logger.Debugf("removal %q deleted, ignoring", request.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+
+	// The OSDs were removed
+	if removal.Status != nil && removal.Status.Phase == k8sutil.ReadyStatus {
+		logger.Debugf("the osds of removal %q are removed", request.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+
+	// validate the removal settings
+	if err := validateRemoval(removal); err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid osd removal CR %q spec", request.NamespacedName)
+	}
+
+	// The OSDs to remove are resolved once, the spec is not read again during the removal
+	var osds []cephv1.OSDRemovalOSDStatus
+	if removal.Status != nil {
+		osds = removal.Status.OSDs
+	}
+	if len(osds) == 0 {
+		osds, err = r.resolveOSDs(removal)
+		if err != nil {
+			updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err, nil)
+			return reconcile.Result{}, errors.Wrapf(err, "failed to find the osds of removal %q", request.NamespacedName)
+		}
+		logger.Infof("removing osds %v", osdIDs(osds))
+	}
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReconcilingStatus, nil, osds)
+
+	// The OSDs are removed in parallel, each OSD moves to the next step when it is ready
+	removed := true
+	for i := range osds {
+		if err := r.removeOSD(removal, &cephCluster, &osds[i]); err != nil {
+			logger.Errorf("failed to remove osd %d. %v", osds[i].ID, err)
+			osds[i].Message = err.Error()
+		}
+		if osds[i].State != cephv1.OSDRemovalRemoved {
+			removed = false
+		}
+	}
+
+	if !removed {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcilingStatus, nil, osds)
+		return waitForRequeueIfRemovalInProgress, nil
+	}
+
+	// Set Ready status, the OSDs are removed
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, nil, osds)
+	logger.Infof("successfully removed osds %v", osdIDs(osds))
+
+	// Return and do not requeue
+	return reconcile.Result{}, nil
+}
+
+// validateRemoval validates the spec of an OSD removal
+func validateRemoval(removal *cephv1.CephOSDRemoval) error {
+	if len(removal.Spec.OSDIDs) == 0 && len(removal.Spec.Devices) == 0 {
+		return errors.New("no osd to remove, osdIDs or devices must be set")
+	}
+	for _, id := range removal.Spec.OSDIDs {
+		if id < 0 {
+			return errors.Errorf("invalid osd id %d", id)
+		}
+	}
+	for _, device := range removal.Spec.Devices {
+		if device.Node == "" || device.Device == "" {
+			return errors.Errorf("invalid device %+v, node and device must be set", device)
+		}
+	}
+	return nil
+}
+
+// osdIDs returns the IDs of the OSDs of a removal
+func osdIDs(osds []cephv1.OSDRemovalOSDStatus) []int {
+	ids := []int{}
+	for _, osd := range osds {
+		ids = append(ids, osd.ID)
+	}
+	return ids
+}
+
+// updateStatus updates an OSD removal CR with the given status and the matching conditions. The progress of the
+// removal of the OSDs is updated when given.
+func updateStatus(client client.Client, name types.NamespacedName, status string, reconcileErr error, osds []cephv1.OSDRemovalOSDStatus) {
+	removal := &cephv1.CephOSDRemoval{}
+	if err := client.Get(context.TODO(), name, removal); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephOSDRemoval resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve osd removal %q to update status to %q. %v", name, status, err)
+		return
+	}
+
+	if removal.Status == nil {
+		removal.Status = &cephv1.OSDRemovalStatus{}
+	}
+
+	removal.Status.Phase = status
+	opcontroller.SetPhaseConditions(&removal.Status.Conditions, status, reconcileErr)
+	if osds != nil {
+		removal.Status.OSDs = osds
+	}
+	if err := opcontroller.UpdateStatus(client, removal); err != nil {
+		logger.Warningf("failed to set osd removal %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("osd removal %q status updated to %q", name, status)
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package removal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/operator/ceph/disruption/controllerconfig"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testOSDMetadata = `[
+	{"id": 0, "hostname": "rook-ceph-osd-0-abc", "devices": "sdb"},
+	{"id": 1, "hostname": "rook-ceph-osd-1-def", "devices": "dm-0,sdc"},
+	{"id": 2, "hostname": "rook-ceph-osd-2-ghi", "devices": "sdb"}
+]`
+
+func TestValidateRemoval(t *testing.T) {
+	removal := &cephv1.CephOSDRemoval{}
+	assert.Error(t, validateRemoval(removal))
+
+	removal.Spec.OSDIDs = []int{1}
+	assert.NoError(t, validateRemoval(removal))
+	removal.Spec.OSDIDs = []int{-1}
+	assert.Error(t, validateRemoval(removal))
+
+	removal.Spec.OSDIDs = nil
+	removal.Spec.Devices = []cephv1.OSDRemovalDevice{{Device: "sdb"}}
+	assert.Error(t, validateRemoval(removal))
+	removal.Spec.Devices[0].Node = "node1"
+	assert.NoError(t, validateRemoval(removal))
+}
+
+func TestFindOSDOfDevice(t *testing.T) {
+	metadata := []cephclient.OSDMetadata{
+		{ID: 0, Hostname: "node1", Devices: "sdb"},
+		{ID: 1, Hostname: "rook-ceph-osd-1-def", Devices: "sdb,sdc"},
+	}
+	deployments := map[int]osdDeploymentInfo{1: {node: "node2"}}
+
+	id, ok := findOSDOfDevice(metadata, deployments, cephv1.OSDRemovalDevice{Node: "node1", Device: "/dev/sdb"})
+	assert.True(t, ok)
+	assert.Equal(t, 0, id)
+
+	// the node of the deployment is preferred over the hostname of the metadata
+	id, ok = findOSDOfDevice(metadata, deployments, cephv1.OSDRemovalDevice{Node: "node2", Device: "sdc"})
+	assert.True(t, ok)
+	assert.Equal(t, 1, id)
+
+	_, ok = findOSDOfDevice(metadata, deployments, cephv1.OSDRemovalDevice{Node: "node2", Device: "sdd"})
+	assert.False(t, ok)
+}
+
+func TestCephOSDRemovalController(t *testing.T) {
+	var (
+		name      = "remove-osds"
+		namespace = "rook-ceph"
+	)
+	removal := &cephv1.CephOSDRemoval{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: cephv1.OSDRemovalSpec{
+			OSDIDs:    []int{1},
+			Devices:   []cephv1.OSDRemovalDevice{{Node: "node1", Device: "/dev/sdb"}},
+			WipeDisks: true,
+		},
+		Status: &cephv1.OSDRemovalStatus{},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:       k8sutil.ReadyStatus,
+			CephVersion: &cephv1.ClusterVersion{Version: "15.2.4-0"},
+			CephStatus:  &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+
+	safeToDestroy := false
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] != "osd" {
+				return "", nil
+			}
+			switch args[1] {
+			case "ls":
+				return "[0,1,2]", nil
+			case "metadata":
+				return testOSDMetadata, nil
+			case "safe-to-destroy":
+				if safeToDestroy {
+					return fmt.Sprintf(`{"safe_to_destroy":[%s],"active":[],"missing_stats":[],"stored_pgs":[]}`, args[2]), nil
+				}
+				return `{"safe_to_destroy":[],"active":[],"missing_stats":[],"stored_pgs":[]}`, nil
+			case "out", "down", "purge":
+				commands = append(commands, args[1:3])
+			}
+			return "", nil
+		},
+	}
+	clientset := test.New(t, 3)
+	c := &clusterd.Context{
+		Executor:      executor,
+		RookClientset: rookclient.NewSimpleClientset(),
+		Clientset:     clientset,
+	}
+
+	// Mock clusterInfo
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			"cluster-name": []byte("foo-cluster"),
+			"fsid":         []byte("fsid"),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := clientset.CoreV1().Secrets(namespace).Create(secret)
+	assert.NoError(t, err)
+
+	// osd 0 runs on a disk of node1 and osd 1 on a pvc
+	for id, labels := range map[int]map[string]string{0: {}, 1: {osd.OSDOverPVCLabelKey: "set1-data-0"}} {
+		labels["app"] = osd.AppName
+		labels[osd.OsdIdLabelKey] = fmt.Sprintf("%d", id)
+		d := &apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("rook-ceph-osd-%d", id), Namespace: namespace, Labels: labels}}
+		if id == 0 {
+			d.Spec.Template.Spec.NodeSelector = map[string]string{v1.LabelHostname: "node1"}
+		}
+		_, err = clientset.AppsV1().Deployments(namespace).Create(d)
+		assert.NoError(t, err)
+	}
+	_, err = clientset.CoreV1().PersistentVolumeClaims(namespace).Create(&v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "set1-data-0", Namespace: namespace}})
+	assert.NoError(t, err)
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephOSDRemoval{}, &cephv1.CephOSDRemovalList{}, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{removal, cephCluster}...)
+	r := &ReconcileCephOSDRemoval{client: cl, scheme: s, context: &controllerconfig.Context{ClusterdContext: c, RookImage: "rook/ceph:master"}}
+
+	//
+	// The osds are marked out and their data is migrating
+	//
+	res, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, [][]string{{"out", "0"}, {"out", "1"}}, commands)
+
+	err = cl.Get(context.TODO(), req.NamespacedName, removal)
+	assert.NoError(t, err)
+	assert.Equal(t, k8sutil.ReconcilingStatus, removal.Status.Phase)
+	assert.Equal(t, 2, len(removal.Status.OSDs))
+	assert.Equal(t, cephv1.OSDRemovalOSDStatus{ID: 0, State: cephv1.OSDRemovalDraining, Node: "node1", Device: "/dev/sdb",
+		Message: "waiting for the data of the osd to migrate to the other osds"}, removal.Status.OSDs[0])
+	assert.Equal(t, "set1-data-0", removal.Status.OSDs[1].PVC)
+	assert.Equal(t, cephv1.OSDRemovalDraining, removal.Status.OSDs[1].State)
+
+	//
+	// The osds are safe to destroy, they are purged, the pvc is deleted and the disk is wiped
+	//
+	commands = [][]string{}
+	safeToDestroy = true
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, [][]string{{"down", "0"}, {"purge", "0"}, {"down", "1"}, {"purge", "1"}}, commands)
+
+	_, err = clientset.AppsV1().Deployments(namespace).Get("rook-ceph-osd-0", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	_, err = clientset.CoreV1().PersistentVolumeClaims(namespace).Get("set1-data-0", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	job, err := clientset.BatchV1().Jobs(namespace).Get("rook-ceph-osd-wipe-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "node1", job.Spec.Template.Spec.NodeSelector[v1.LabelHostname])
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Env, v1.EnvVar{Name: "ROOK_OSD_IDS", Value: "0"})
+
+	err = cl.Get(context.TODO(), req.NamespacedName, removal)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.OSDRemovalWiping, removal.Status.OSDs[0].State)
+	assert.Equal(t, cephv1.OSDRemovalRemoved, removal.Status.OSDs[1].State)
+
+	//
+	// SUCCESS! The disk is wiped
+	//
+	job.Status.Succeeded = 1
+	_, err = clientset.BatchV1().Jobs(namespace).Update(job)
+	assert.NoError(t, err)
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+
+	err = cl.Get(context.TODO(), req.NamespacedName, removal)
+	assert.NoError(t, err)
+	assert.Equal(t, k8sutil.ReadyStatus, removal.Status.Phase)
+	assert.Equal(t, cephv1.OSDRemovalRemoved, removal.Status.OSDs[0].State)
+	_, err = clientset.BatchV1().Jobs(namespace).Get("rook-ceph-osd-wipe-0", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
+
+func TestRemovePurgedOSD(t *testing.T) {
+	namespace := "rook-ceph"
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] != "osd" {
+				return "", nil
+			}
+			if args[1] == "ls" {
+				return "[0,2]", nil
+			}
+			commands = append(commands, args[1:3])
+			return "", nil
+		},
+	}
+	clientset := test.New(t, 1)
+	c := &clusterd.Context{Executor: executor, Clientset: clientset}
+	d := &apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-1", Namespace: namespace}}
+	_, err := clientset.AppsV1().Deployments(namespace).Create(d)
+	assert.NoError(t, err)
+	r := &ReconcileCephOSDRemoval{context: &controllerconfig.Context{ClusterdContext: c}}
+	removal := &cephv1.CephOSDRemoval{ObjectMeta: metav1.ObjectMeta{Name: "remove-osds", Namespace: namespace}}
+
+	// an osd missing from the osd map is not drained anymore but considered purged
+	osdStatus := &cephv1.OSDRemovalOSDStatus{ID: 1, State: cephv1.OSDRemovalDraining, Message: "waiting"}
+	err = r.removeOSD(removal, &cephv1.CephCluster{}, osdStatus)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.OSDRemovalRemoved, osdStatus.State)
+	assert.Empty(t, commands)
+	_, err = clientset.AppsV1().Deployments(namespace).Get("rook-ceph-osd-1", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
@@ -0,0 +1,355 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package removal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// wipeAppName is the name of the jobs wiping the disks of the removed OSDs
+	wipeAppName = "rook-ceph-osd-wipe"
+)
+
+// osdDeploymentInfo is the node and PVC of the deployment of an OSD
+type osdDeploymentInfo struct {
+	node string
+	pvc  string
+}
+
+// resolveOSDs finds the OSDs to remove from their IDs and devices
+func (r *ReconcileCephOSDRemoval) resolveOSDs(removal *cephv1.CephOSDRemoval) ([]cephv1.OSDRemovalOSDStatus, error) {
+	ctx := r.context.ClusterdContext
+	deployments, err := r.osdDeployments(removal.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[int]string{}
+	for _, id := range removal.Spec.OSDIDs {
+		ids[id] = ""
+	}
+
+	if len(removal.Spec.Devices) > 0 {
+		metadata, err := cephclient.GetOSDMetadata(ctx, removal.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, device := range removal.Spec.Devices {
+			id, ok := findOSDOfDevice(metadata, deployments, device)
+			if !ok {
+				return nil, errors.Errorf("no osd found on device %q of node %q", device.Device, device.Node)
+			}
+			ids[id] = device.Device
+		}
+	}
+
+	existing, err := cephclient.OsdListNum(ctx, removal.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	osds := []cephv1.OSDRemovalOSDStatus{}
+	for id, device := range ids {
+		found := false
+		for _, existingID := range existing {
+			if existingID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("osd %d not found in the cluster", id)
+		}
+
+		info := deployments[id]
+		osds = append(osds, cephv1.OSDRemovalOSDStatus{
+			ID:     id,
+			State:  cephv1.OSDRemovalPending,
+			Node:   info.node,
+			Device: device,
+			PVC:    info.pvc,
+		})
+	}
+	sort.Slice(osds, func(i, j int) bool { return osds[i].ID < osds[j].ID })
+	return osds, nil
+}
+
+// osdDeployments returns the deployment info of the OSDs of the cluster by OSD ID
+func (r *ReconcileCephOSDRemoval) osdDeployments(namespace string) (map[int]osdDeploymentInfo, error) {
+	deployments, err := k8sutil.GetDeployments(r.context.ClusterdContext.Clientset, namespace, fmt.Sprintf("app=%s", osd.AppName))
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to list osd deployments")
+	}
+
+	infos := map[int]osdDeploymentInfo{}
+	if deployments == nil {
+		return infos, nil
+	}
+	for _, d := range deployments.Items {
+		id, err := strconv.Atoi(d.Labels[osd.OsdIdLabelKey])
+		if err != nil {
+			logger.Warningf("failed to get the osd id of deployment %q. %v", d.Name, err)
+			continue
+		}
+		infos[id] = osdDeploymentInfo{
+			node: d.Spec.Template.Spec.NodeSelector[v1.LabelHostname],
+			pvc:  d.Labels[osd.OSDOverPVCLabelKey],
+		}
+	}
+	return infos, nil
+}
+
+// findOSDOfDevice returns the ID of the OSD running on a device. The node of an OSD is the node of its deployment,
+// or the hostname in its metadata if the deployment is not found.
+func findOSDOfDevice(metadata []cephclient.OSDMetadata, deployments map[int]osdDeploymentInfo, device cephv1.OSDRemovalDevice) (int, bool) {
+	deviceName := strings.TrimPrefix(device.Device, "/dev/")
+	for _, m := range metadata {
+		node := m.Hostname
+		if info, ok := deployments[m.ID]; ok && info.node != "" {
+			node = info.node
+		}
+		if node != device.Node {
+			continue
+		}
+		for _, name := range strings.Split(m.Devices, ",") {
+			if name == deviceName {
+				return m.ID, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// removeOSD moves the removal of an OSD forward as far as possible. The OSD is marked out, and once its data migrated
+// to the other OSDs its deployment is deleted and it is purged from the cluster. Its PVC is deleted or its disk is
+// wiped so the OSD can be replaced.
+func (r *ReconcileCephOSDRemoval) removeOSD(removal *cephv1.CephOSDRemoval, cephCluster *cephv1.CephCluster, osdStatus *cephv1.OSDRemovalOSDStatus) error {
+	ctx := r.context.ClusterdContext
+	namespace := removal.Namespace
+	id := osdStatus.ID
+
+	// The OSD may have been purged by a previous reconcile that failed to update the status, or by hand
+	if osdStatus.State == cephv1.OSDRemovalPending || osdStatus.State == cephv1.OSDRemovalDraining {
+		found, err := osdExists(ctx, namespace, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			if err := k8sutil.DeleteDeployment(ctx.Clientset, namespace, osdDeploymentName(id)); err != nil {
+				return errors.Wrapf(err, "failed to delete the deployment of osd %d", id)
+			}
+			logger.Infof("osd %d is not in the osd map anymore, it is already purged", id)
+			osdStatus.State = cephv1.OSDRemovalPurged
+			osdStatus.Message = ""
+		}
+	}
+
+	switch osdStatus.State {
+	case cephv1.OSDRemovalPending:
+		if output, err := cephclient.OSDOut(ctx, namespace, id); err != nil {
+			return errors.Wrapf(err, "failed to mark osd %d out. %s", id, output)
+		}
+		logger.Infof("osd %d marked out, waiting for its data to migrate", id)
+		osdStatus.State = cephv1.OSDRemovalDraining
+		osdStatus.Message = ""
+		fallthrough
+
+	case cephv1.OSDRemovalDraining:
+		safe, err := cephclient.OsdSafeToDestroy(ctx, namespace, id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check if osd %d is safe to destroy", id)
+		}
+		if !safe {
+			osdStatus.Message = "waiting for the data of the osd to migrate to the other osds"
+			return nil
+		}
+
+		if err := k8sutil.DeleteDeployment(ctx.Clientset, namespace, osdDeploymentName(id)); err != nil {
+			return errors.Wrapf(err, "failed to delete the deployment of osd %d", id)
+		}
+		if err := cephclient.OSDDown(ctx, namespace, id); err != nil {
+			return err
+		}
+		if err := cephclient.PurgeOSD(ctx, namespace, id); err != nil {
+			return err
+		}
+		logger.Infof("osd %d purged", id)
+		osdStatus.State = cephv1.OSDRemovalPurged
+		osdStatus.Message = ""
+		fallthrough
+
+	case cephv1.OSDRemovalPurged:
+		if osdStatus.PVC != "" && !removal.Spec.PreservePVC {
+			err := ctx.Clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(osdStatus.PVC, &metav1.DeleteOptions{})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to delete pvc %q of osd %d", osdStatus.PVC, id)
+			}
+			logger.Infof("deleted pvc %q of osd %d", osdStatus.PVC, id)
+		}
+
+		if removal.Spec.WipeDisks && osdStatus.PVC == "" {
+			if osdStatus.Node == "" {
+				return errors.Errorf("cannot wipe the disk of osd %d, its node is unknown", id)
+			}
+			if err := r.runWipeJob(removal, cephCluster, osdStatus); err != nil {
+				return errors.Wrapf(err, "failed to start the job wiping the disk of osd %d", id)
+			}
+			osdStatus.State = cephv1.OSDRemovalWiping
+			osdStatus.Message = fmt.Sprintf("wiping the disk of the osd on node %q", osdStatus.Node)
+			return nil
+		}
+
+		osdStatus.State = cephv1.OSDRemovalRemoved
+		osdStatus.Message = ""
+
+	case cephv1.OSDRemovalWiping:
+		job, err := ctx.Clientset.BatchV1().Jobs(namespace).Get(wipeJobName(id), metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				// start the job again
+				osdStatus.State = cephv1.OSDRemovalPurged
+			}
+			return errors.Wrapf(err, "failed to get the job wiping the disk of osd %d", id)
+		}
+		if job.Status.Succeeded == 0 {
+			if job.Status.Failed > 0 {
+				osdStatus.Message = fmt.Sprintf("the job wiping the disk of the osd failed %d times", job.Status.Failed)
+			}
+			return nil
+		}
+
+		if err := k8sutil.DeleteBatchJob(ctx.Clientset, namespace, job.Name, false); err != nil {
+			logger.Warningf("failed to delete the job wiping the disk of osd %d. %v", id, err)
+		}
+		logger.Infof("wiped the disk of osd %d", id)
+		osdStatus.State = cephv1.OSDRemovalRemoved
+		osdStatus.Message = ""
+	}
+
+	return nil
+}
+
+// osdExists returns whether an OSD is in the OSD map
+func osdExists(context *clusterd.Context, namespace string, id int) (bool, error) {
+	existing, err := cephclient.OsdListNum(context, namespace)
+	if err != nil {
+		return false, err
+	}
+	for _, existingID := range existing {
+		if existingID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// runWipeJob starts the job wiping the disk of a removed OSD on its node
+func (r *ReconcileCephOSDRemoval) runWipeJob(removal *cephv1.CephOSDRemoval, cephCluster *cephv1.CephCluster, osdStatus *cephv1.OSDRemovalOSDStatus) error {
+	clusterInfo, _, _, err := mon.LoadClusterInfo(r.context.ClusterdContext, removal.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get cluster info")
+	}
+
+	job := wipeJob(removal, cephCluster, r.context.RookImage, clusterInfo.FSID, osdStatus)
+	if err := controllerutil.SetControllerReference(removal, job, r.scheme); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference of job %q", job.Name)
+	}
+
+	logger.Infof("starting job wiping the disk of osd %d on node %q", osdStatus.ID, osdStatus.Node)
+	return k8sutil.RunReplaceableJob(r.context.ClusterdContext.Clientset, job, true)
+}
+
+// wipeJob returns the job wiping the disk of a removed OSD with the cleanup command of the cluster
+func wipeJob(removal *cephv1.CephOSDRemoval, cephCluster *cephv1.CephCluster, rookImage, clusterFSID string, osdStatus *cephv1.OSDRemovalOSDStatus) *batch.Job {
+	labels := opcontroller.AppLabels(wipeAppName, removal.Namespace)
+	labels[osd.OsdIdLabelKey] = strconv.Itoa(osdStatus.ID)
+
+	container := v1.Container{
+		Name:            "wipe",
+		Image:           rookImage,
+		SecurityContext: osd.PrivilegedContext(),
+		VolumeMounts:    []v1.VolumeMount{{Name: "devices", MountPath: "/dev"}},
+		Env: []v1.EnvVar{
+			{Name: "ROOK_CLUSTER_FSID", Value: clusterFSID},
+			{Name: "ROOK_CLUSTER_NAME", Value: cephCluster.Name},
+			{Name: "ROOK_OSD_IDS", Value: strconv.Itoa(osdStatus.ID)},
+		},
+		Args:      []string{"ceph", "clean"},
+		Resources: cephv1.GetCleanupResources(cephCluster.Spec.Resources),
+	}
+
+	podSpec := v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   wipeAppName,
+			Labels: labels,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{container},
+			Volumes: []v1.Volume{
+				{Name: "devices", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/dev"}}},
+			},
+			RestartPolicy:     v1.RestartPolicyOnFailure,
+			PriorityClassName: cephv1.GetCleanupPriorityClassName(cephCluster.Spec.PriorityClassNames),
+		},
+	}
+
+	// Apply the cleanup placement, the job must run on the node of the OSD
+	rookPlacement := rookv1.Placement(cephv1.GetCleanupPlacement(cephCluster.Spec.Placement))
+	rookPlacement.ApplyToPodSpec(&podSpec.Spec)
+	podSpec.Spec.NodeSelector = map[string]string{v1.LabelHostname: osdStatus.Node}
+
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      wipeJobName(osdStatus.ID),
+			Namespace: removal.Namespace,
+			Labels:    labels,
+		},
+		Spec: batch.JobSpec{
+			Template: podSpec,
+		},
+	}
+
+	// Apply annotations
+	cephv1.GetCleanupAnnotations(cephCluster.Spec.Annotations).ApplyToObjectMeta(&job.ObjectMeta)
+	return job
+}
+
+func osdDeploymentName(id int) string {
+	return fmt.Sprintf("%s-%d", osd.AppName, id)
+}
+
+func wipeJobName(id int) string {
+	return fmt.Sprintf("%s-%d", wipeAppName, id)
+}
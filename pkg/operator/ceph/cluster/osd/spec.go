@@ -18,6 +18,7 @@ limitations under the License.
 package osd
 
 import (
+	"encoding/json"
 	"fmt"
 	"path"
 	"strconv"
@@ -25,6 +26,7 @@ import (
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
 	opmon "github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
 	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
@@ -49,6 +51,8 @@ const (
 	cvModeVarName                       = "ROOK_CV_MODE"
 	lvBackedPVVarName                   = "ROOK_LV_BACKED_PV"
 	CrushDeviceClassVarName             = "ROOK_OSD_CRUSH_DEVICE_CLASS"
+	crushHierarchyVarName               = "ROOK_CRUSH_HIERARCHY"
+	deviceClassRulesVarName             = "ROOK_DEVICE_CLASS_RULES"
 	rookBinariesMountPath               = "/rook"
 	rookBinariesVolumeName              = "rook-binaries"
 	activateOSDVolumeName               = "activate-osd"
@@ -378,6 +382,15 @@ func (c *Cluster) makeDeployment(osdProps osdProperties, osd OSDInfo, provisionC
 		if osdProps.onPVCWithMetadata() {
 			initContainers = append(initContainers, c.getPVCMetadataInitContainerActivate(osdDataDirPath, osdProps))
 		}
+		if osdProps.isEncrypted() {
+			// The encryption init containers need the key and access to the device mapper
+			volumes = append(volumes, c.getEncryptionKeyVolume(osdProps))
+			volumes = addDevicesVolume(volumes)
+			if !c.isKMSSecretsBackend() {
+				initContainers = append(initContainers, c.getKMSGetKeyInitContainer(osdProps))
+			}
+			initContainers = append(initContainers, c.getEncryptionOpenInitContainer(osdDataDirPath, osdProps))
+		}
 		initContainers = append(initContainers, c.getActivatePVCInitContainer(osdProps, osdID))
 		initContainers = append(initContainers, c.getExpandPVCInitContainer(osdProps, osdID))
 	}
@@ -513,6 +526,16 @@ func (c *Cluster) getCopyBinariesContainer() (v1.Volume, *v1.Container) {
 	}
 }
 
+// addDevicesVolume adds the volume of the devices of the host, unless the pod already has it
+func addDevicesVolume(volumes []v1.Volume) []v1.Volume {
+	for _, volume := range volumes {
+		if volume.Name == "devices" {
+			return volumes
+		}
+	}
+	return append(volumes, v1.Volume{Name: "devices", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/dev"}}})
+}
+
 // This container runs all the actions needed to activate an OSD before we can run the OSD process
 func (c *Cluster) getActivateOSDInitContainer(osdID string, osdInfo OSDInfo, osdProps osdProperties) (v1.Volume, *v1.Container) {
 	volume := v1.Volume{Name: activateOSDVolumeName, VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}
@@ -645,6 +668,11 @@ func (c *Cluster) getPVCInitContainer(osdProps osdProperties) v1.Container {
 }
 
 func (c *Cluster) getPVCInitContainerActivate(mountPath string, osdProps osdProperties) v1.Container {
+	// An encrypted block is copied aside, the encryption init container opens it and exposes the clear device as "block"
+	blockName := "block"
+	if osdProps.isEncrypted() {
+		blockName = encryptionBlockTmpName
+	}
 
 	return v1.Container{
 		Name:  blockPVCMapperInitContainer,
@@ -652,7 +680,7 @@ func (c *Cluster) getPVCInitContainerActivate(mountPath string, osdProps osdProp
 		Command: []string{
 			"cp",
 		},
-		Args: []string{"-a", fmt.Sprintf("/%s", osdProps.pvc.ClaimName), path.Join(mountPath, "block")},
+		Args: []string{"-a", fmt.Sprintf("/%s", osdProps.pvc.ClaimName), path.Join(mountPath, blockName)},
 		VolumeDevices: []v1.VolumeDevice{
 			{
 				Name:       osdProps.pvc.ClaimName,
@@ -744,6 +772,11 @@ func (c *Cluster) getActivatePVCInitContainer(osdProps osdProperties, osdID stri
 		Resources:       osdProps.resources,
 	}
 
+	// The PVC holds the LUKS device, the clear device was put in place by the encryption init container
+	if osdProps.isEncrypted() {
+		container.VolumeDevices = nil
+	}
+
 	return container
 }
 
@@ -869,6 +902,7 @@ func (c *Cluster) provisionOSDContainer(osdProps osdProperties, copyBinariesMoun
 	if osdProps.metadataDevice != "" {
 		envVars = append(envVars, metadataDeviceEnvVar(osdProps.metadataDevice))
 	}
+	envVars = append(envVars, crushEnvVars(c.crush)...)
 
 	volumeMounts := append(controller.CephVolumeMounts(provisionConfig.DataPathMap, true), []v1.VolumeMount{
 		{Name: "devices", MountPath: "/dev"},
@@ -888,6 +922,10 @@ func (c *Cluster) provisionOSDContainer(osdProps osdProperties, copyBinariesMoun
 		envVars = append(envVars, dataDevicesEnvVar(strings.Join(dev, ",")))
 		envVars = append(envVars, pvcBackedOSDEnvVar("true"))
 		envVars = append(envVars, crushDeviceClassEnvVar(osdProps.crushDeviceClass))
+		// The prepare job generates the dm-crypt key and stores it in the KMS
+		if osdProps.isEncrypted() {
+			envVars = append(envVars, kms.ConfigToEnvVar(c.security)...)
+		}
 	}
 
 	// run privileged always since we always mount /dev
@@ -1032,6 +1070,28 @@ func crushDeviceClassEnvVar(crushDeviceClass string) v1.EnvVar {
 	return v1.EnvVar{Name: CrushDeviceClassVarName, Value: crushDeviceClass}
 }
 
+// crushEnvVars passes the CRUSH hierarchy and the device class rules of the cluster CR to the prepare job as json
+func crushEnvVars(crush cephv1.CrushSpec) []v1.EnvVar {
+	envVars := []v1.EnvVar{}
+	if len(crush.Hierarchy) > 0 {
+		hierarchy, err := json.Marshal(crush.Hierarchy)
+		if err != nil {
+			logger.Errorf("failed to serialize the crush hierarchy. %v", err)
+		} else {
+			envVars = append(envVars, v1.EnvVar{Name: crushHierarchyVarName, Value: string(hierarchy)})
+		}
+	}
+	if len(crush.DeviceClassRules) > 0 {
+		rules, err := json.Marshal(crush.DeviceClassRules)
+		if err != nil {
+			logger.Errorf("failed to serialize the device class rules. %v", err)
+		} else {
+			envVars = append(envVars, v1.EnvVar{Name: deviceClassRulesVarName, Value: string(rules)})
+		}
+	}
+	return envVars
+}
+
 func osdOnSDNFlag(network cephv1.NetworkSpec) []string {
 	var args []string
 	// OSD fails to find the right IP to bind to when running on SDN
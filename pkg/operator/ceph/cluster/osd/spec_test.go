@@ -88,7 +88,7 @@ func testPodDevices(t *testing.T, dataDir, deviceName string, allDevices bool) {
 		CephVersion: cephver.Nautilus,
 	}
 	c := New(clusterInfo, &clusterd.Context{Clientset: clientset, ConfigDir: "/var/lib/rook", Executor: &exectest.MockExecutor{}}, "ns", "rook/rook:myversion", cephVersion,
-		storageSpec, dataDir, rookv1.Placement{}, rookv1.Annotations{}, cephv1.NetworkSpec{}, v1.ResourceRequirements{}, v1.ResourceRequirements{}, "my-priority-class", metav1.OwnerReference{}, false, false)
+		storageSpec, dataDir, rookv1.Placement{}, rookv1.Annotations{}, cephv1.NetworkSpec{}, v1.ResourceRequirements{}, v1.ResourceRequirements{}, "my-priority-class", metav1.OwnerReference{}, false, false, cephv1.SecuritySpec{}, cephv1.OSDUpgradeStrategySpec{}, cephv1.CrushSpec{})
 
 	devMountNeeded := deviceName != "" || allDevices
 
@@ -222,6 +222,101 @@ func testPodDevices(t *testing.T, dataDir, deviceName string, allDevices bool) {
 	assert.Equal(t, 1, len(blkInitCont.VolumeDevices))
 	blkMetaInitCont := deployment.Spec.Template.Spec.InitContainers[2]
 	assert.Equal(t, 1, len(blkMetaInitCont.VolumeDevices))
+
+	// Test encrypted OSD on PVC with RAW, the key is in a kubernetes secret
+	osdProp.metadataPVC = v1.PersistentVolumeClaimVolumeSource{}
+	osdProp.storeConfig.EncryptedDevice = true
+	deployment, err = c.makeDeployment(osdProp, osd, dataPathMap)
+	assert.Nil(t, err)
+	assert.NotNil(t, deployment)
+	assert.True(t, deployment.Spec.Template.Spec.HostIPC)
+	assert.Equal(t, 5, len(deployment.Spec.Template.Spec.InitContainers))
+	assert.Equal(t, "blkdevmapper", deployment.Spec.Template.Spec.InitContainers[0].Name)
+	assert.Equal(t, "/var/lib/ceph/osd/ceph-0/block-tmp", deployment.Spec.Template.Spec.InitContainers[0].Args[2])
+	assert.Equal(t, "encryption-open", deployment.Spec.Template.Spec.InitContainers[1].Name)
+	assert.Equal(t, "activate", deployment.Spec.Template.Spec.InitContainers[2].Name)
+	assert.Equal(t, 0, len(deployment.Spec.Template.Spec.InitContainers[2].VolumeDevices))
+	assert.Equal(t, "expand-bluefs", deployment.Spec.Template.Spec.InitContainers[3].Name)
+	keyVolumeFound := false
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.Name == encryptionKeyVolumeName {
+			keyVolumeFound = true
+			assert.Equal(t, "rook-ceph-osd-encryption-key-mypvc", volume.Secret.SecretName)
+		}
+	}
+	assert.True(t, keyVolumeFound)
+	verifyPodVolumes(t, deployment.Spec.Template.Spec)
+
+	// Test encrypted OSD on PVC with LVM, ceph-volume opens the encrypted device itself
+	osd = OSDInfo{
+		ID:     0,
+		CVMode: "lvm",
+	}
+	deployment, err = c.makeDeployment(osdProp, osd, dataPathMap)
+	assert.Nil(t, err)
+	assert.NotNil(t, deployment)
+	for _, container := range deployment.Spec.Template.Spec.InitContainers {
+		assert.NotEqual(t, "encryption-open", container.Name)
+	}
+	verifyPodVolumes(t, deployment.Spec.Template.Spec)
+}
+
+// verifyPodVolumes checks that the volumes of the pod are unique and that all the mounts of its containers refer to them
+func verifyPodVolumes(t *testing.T, spec v1.PodSpec) {
+	volumes := map[string]bool{}
+	for _, volume := range spec.Volumes {
+		assert.False(t, volumes[volume.Name], "volume %q is defined more than once", volume.Name)
+		volumes[volume.Name] = true
+	}
+	for _, container := range append(spec.InitContainers, spec.Containers...) {
+		for _, mount := range container.VolumeMounts {
+			assert.True(t, volumes[mount.Name], "volume %q mounted by container %q is not defined", mount.Name, container.Name)
+		}
+	}
+}
+
+func TestEncryptedOSDWithVault(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clusterInfo := &cephconfig.ClusterInfo{
+		CephVersion: cephver.Nautilus,
+	}
+	security := cephv1.SecuritySpec{KeyManagementService: cephv1.KeyManagementServiceSpec{
+		ConnectionDetails: map[string]string{"KMS_PROVIDER": "vault", "VAULT_ADDR": "https://1.1.1.1:8200"},
+		TokenSecretName:   "vault-token",
+	}}
+	c := New(clusterInfo, &clusterd.Context{Clientset: clientset, ConfigDir: "/var/lib/rook", Executor: &exectest.MockExecutor{}}, "ns", "rook/rook:myversion", cephv1.CephVersionSpec{},
+		rookv1.StorageScopeSpec{}, "", rookv1.Placement{}, rookv1.Annotations{}, cephv1.NetworkSpec{}, v1.ResourceRequirements{}, v1.ResourceRequirements{}, "my-priority-class", metav1.OwnerReference{}, false, false, security, cephv1.OSDUpgradeStrategySpec{}, cephv1.CrushSpec{})
+
+	osdProp := osdProperties{
+		crushHostname: "node",
+		pvc:           v1.PersistentVolumeClaimVolumeSource{ClaimName: "mypvc"},
+		storeConfig:   config.StoreConfig{EncryptedDevice: true},
+	}
+	dataPathMap := &provisionConfig{
+		DataPathMap: opconfig.NewDatalessDaemonDataPathMap(c.Namespace, "/var/lib/rook"),
+	}
+
+	// the prepare job gets the kms connection details
+	job, err := c.makeJob(osdProp, dataPathMap)
+	assert.Nil(t, err)
+	container := job.Spec.Template.Spec.Containers[0]
+	verifyEnvVar(t, container.Env, "ROOK_ENCRYPTED_DEVICE", "true", true)
+	verifyEnvVar(t, container.Env, "VAULT_ADDR", "https://1.1.1.1:8200", true)
+
+	// the osd fetches its key from vault
+	deployment, err := c.makeDeployment(osdProp, OSDInfo{ID: 0, CVMode: "raw"}, dataPathMap)
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(deployment.Spec.Template.Spec.InitContainers))
+	assert.Equal(t, "blkdevmapper", deployment.Spec.Template.Spec.InitContainers[0].Name)
+	assert.Equal(t, "encryption-kms-get-key", deployment.Spec.Template.Spec.InitContainers[1].Name)
+	assert.Equal(t, "rook/rook:myversion", deployment.Spec.Template.Spec.InitContainers[1].Image)
+	assert.Equal(t, "encryption-open", deployment.Spec.Template.Spec.InitContainers[2].Name)
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.Name == encryptionKeyVolumeName {
+			assert.NotNil(t, volume.EmptyDir)
+		}
+	}
+	verifyPodVolumes(t, deployment.Spec.Template.Spec)
 }
 
 func verifyEnvVar(t *testing.T, envVars []v1.EnvVar, expectedName, expectedValue string, expectedFound bool) {
@@ -267,7 +362,7 @@ func TestStorageSpecConfig(t *testing.T) {
 		CephVersion: cephver.Nautilus,
 	}
 	c := New(clusterInfo, &clusterd.Context{Clientset: clientset, ConfigDir: "/var/lib/rook", Executor: &exectest.MockExecutor{}}, "ns", "rook/rook:myversion", cephv1.CephVersionSpec{},
-		storageSpec, "", rookv1.Placement{}, rookv1.Annotations{}, cephv1.NetworkSpec{}, v1.ResourceRequirements{}, v1.ResourceRequirements{}, "my-priority-class", metav1.OwnerReference{}, false, false)
+		storageSpec, "", rookv1.Placement{}, rookv1.Annotations{}, cephv1.NetworkSpec{}, v1.ResourceRequirements{}, v1.ResourceRequirements{}, "my-priority-class", metav1.OwnerReference{}, false, false, cephv1.SecuritySpec{}, cephv1.OSDUpgradeStrategySpec{}, cephv1.CrushSpec{})
 
 	n := c.DesiredStorage.ResolveNode(storageSpec.Nodes[0].Name)
 	storeConfig := config.ToStoreConfig(storageSpec.Nodes[0].Config)
@@ -317,7 +412,7 @@ func TestHostNetwork(t *testing.T) {
 		CephVersion: cephver.Nautilus,
 	}
 	c := New(clusterInfo, &clusterd.Context{Clientset: clientset, ConfigDir: "/var/lib/rook", Executor: &exectest.MockExecutor{}}, "ns", "myversion", cephv1.CephVersionSpec{},
-		storageSpec, "", rookv1.Placement{}, rookv1.Annotations{}, cephv1.NetworkSpec{HostNetwork: true}, v1.ResourceRequirements{}, v1.ResourceRequirements{}, "my-priority-class", metav1.OwnerReference{}, false, false)
+		storageSpec, "", rookv1.Placement{}, rookv1.Annotations{}, cephv1.NetworkSpec{HostNetwork: true}, v1.ResourceRequirements{}, v1.ResourceRequirements{}, "my-priority-class", metav1.OwnerReference{}, false, false, cephv1.SecuritySpec{}, cephv1.OSDUpgradeStrategySpec{}, cephv1.CrushSpec{})
 
 	n := c.DesiredStorage.ResolveNode(storageSpec.Nodes[0].Name)
 	osd := OSDInfo{
@@ -359,7 +454,7 @@ func TestOsdOnSDNFlag(t *testing.T) {
 func TestOsdPrepareResources(t *testing.T) {
 	clientset := fake.NewSimpleClientset()
 	c := New(&cephconfig.ClusterInfo{}, &clusterd.Context{Clientset: clientset, ConfigDir: "/var/lib/rook", Executor: &exectest.MockExecutor{}}, "ns", "myversion", cephv1.CephVersionSpec{},
-		rookv1.StorageScopeSpec{}, "", rookv1.Placement{}, rookv1.Annotations{}, cephv1.NetworkSpec{}, v1.ResourceRequirements{}, v1.ResourceRequirements{}, "my-priority-class", metav1.OwnerReference{}, false, false)
+		rookv1.StorageScopeSpec{}, "", rookv1.Placement{}, rookv1.Annotations{}, cephv1.NetworkSpec{}, v1.ResourceRequirements{}, v1.ResourceRequirements{}, "my-priority-class", metav1.OwnerReference{}, false, false, cephv1.SecuritySpec{}, cephv1.OSDUpgradeStrategySpec{}, cephv1.CrushSpec{})
 
 	// TEST 2: NOT running on PVC and some prepareResources are specificied
 	rr := v1.ResourceRequirements{
@@ -25,6 +25,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util"
+	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -47,12 +48,14 @@ const (
 
 type provisionConfig struct {
 	errorMessages []string
-	DataPathMap   *config.DataPathMap // location to store data in container
+	DataPathMap   *config.DataPathMap      // location to store data in container
+	osdUpdates    map[int]*apps.Deployment // existing osd deployments to update by failure domain
 }
 
 func (c *Cluster) newProvisionConfig() *provisionConfig {
 	return &provisionConfig{
 		DataPathMap: config.NewDatalessDaemonDataPathMap(c.Namespace, c.dataDirHostPath),
+		osdUpdates:  map[int]*apps.Deployment{},
 	}
 }
 
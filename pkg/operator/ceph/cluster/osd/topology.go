@@ -20,6 +20,10 @@ limitations under the License.
 package osd
 
 import (
+	"regexp"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 )
@@ -35,6 +39,9 @@ var (
 
 	// The list of supported failure domains in the CRUSH map, ordered from lowest to highest
 	CRUSHMapLevelsOrdered = append([]string{"host"}, append(CRUSHTopologyLabels, KubernetesTopologyLabels...)...)
+
+	// The valid names of the CRUSH types declared in the hierarchy of the cluster CR
+	crushTypeRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 )
 
 // ExtractTopologyFromLabels extracts rook topology from labels and returns a map from topology type to value
@@ -47,3 +54,50 @@ func ExtractOSDTopologyFromLabels(labels map[string]string) map[string]string {
 	}
 	return topology
 }
+
+// ExtractOSDTopologyFromHierarchy extracts the rook topology from the labels, then adds the CRUSH bucket types of the
+// hierarchy declared in the cluster CR, set by the labels of the hierarchy
+func ExtractOSDTopologyFromHierarchy(labels map[string]string, hierarchy []cephv1.CrushLevelSpec) map[string]string {
+	topology := ExtractOSDTopologyFromLabels(labels)
+	for _, level := range hierarchy {
+		if value, ok := labels[level.Label]; ok && value != "" {
+			topology[level.Type] = client.NormalizeCrushName(value)
+		}
+	}
+	return topology
+}
+
+// ValidateCrushSpec checks the CRUSH hierarchy and the device class rules of the cluster CR
+func ValidateCrushSpec(crush cephv1.CrushSpec) error {
+	types := map[string]bool{}
+	for _, level := range crush.Hierarchy {
+		if level.Type == "" || level.Label == "" {
+			return errors.Errorf("invalid crush hierarchy level %+v. type and label must be set", level)
+		}
+		if level.Type == "osd" || level.Type == "host" || level.Type == "root" {
+			return errors.Errorf("invalid crush hierarchy type %q. the osd, host and root types are managed by rook", level.Type)
+		}
+		if !crushTypeRegex.MatchString(level.Type) {
+			return errors.Errorf("invalid crush hierarchy type %q. must only contain letters, digits, '-' and '_'", level.Type)
+		}
+		if types[level.Type] {
+			return errors.Errorf("invalid crush hierarchy. type %q is declared more than once", level.Type)
+		}
+		types[level.Type] = true
+	}
+
+	for _, rule := range crush.DeviceClassRules {
+		if rule.DeviceClass == "" {
+			return errors.Errorf("invalid device class rule %+v. deviceClass must be set", rule)
+		}
+		if rule.Model != "" {
+			if _, err := regexp.Compile(rule.Model); err != nil {
+				return errors.Wrapf(err, "invalid model %q of device class rule %q", rule.Model, rule.DeviceClass)
+			}
+		}
+		if rule.MinSize != nil && rule.MaxSize != nil && rule.MinSize.Cmp(*rule.MaxSize) > 0 {
+			return errors.Errorf("invalid device class rule %q. minSize %s is greater than maxSize %s", rule.DeviceClass, rule.MinSize.String(), rule.MaxSize.String())
+		}
+	}
+	return nil
+}
@@ -22,7 +22,9 @@ package osd
 import (
 	"testing"
 
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestOrderedCRUSHLabels(t *testing.T) {
@@ -57,3 +59,45 @@ func TestTopologyLabels(t *testing.T) {
 	assert.Equal(t, "r-row", topology["row"])
 	assert.Equal(t, "d-datacenter", topology["datacenter"])
 }
+
+func TestTopologyFromHierarchy(t *testing.T) {
+	nodeLabels := map[string]string{
+		"kubernetes.io/hostname": "host.name",
+		"topology.rook.io/rack":  "r.rack",
+		"example.com/hall":       "h.hall",
+		"example.com/row":        "r.row",
+	}
+	hierarchy := []cephv1.CrushLevelSpec{
+		{Type: "hall", Label: "example.com/hall"},
+		{Type: "row", Label: "example.com/row"},
+		{Type: "shelf", Label: "example.com/shelf"},
+	}
+	topology := ExtractOSDTopologyFromHierarchy(nodeLabels, hierarchy)
+	assert.Equal(t, map[string]string{"host": "host-name", "rack": "r-rack", "hall": "h-hall", "row": "r-row"}, topology)
+}
+
+func TestValidateCrushSpec(t *testing.T) {
+	assert.NoError(t, ValidateCrushSpec(cephv1.CrushSpec{}))
+
+	crush := cephv1.CrushSpec{Hierarchy: []cephv1.CrushLevelSpec{{Type: "hall", Label: "example.com/hall"}, {Type: "rack", Label: "example.com/rack"}}}
+	assert.NoError(t, ValidateCrushSpec(crush))
+	crush.Hierarchy[1].Type = "hall"
+	assert.Error(t, ValidateCrushSpec(crush))
+	crush.Hierarchy[1].Type = "host"
+	assert.Error(t, ValidateCrushSpec(crush))
+	crush.Hierarchy[1].Type = "my.rack"
+	assert.Error(t, ValidateCrushSpec(crush))
+	crush.Hierarchy[1] = cephv1.CrushLevelSpec{Type: "rack"}
+	assert.Error(t, ValidateCrushSpec(crush))
+
+	minSize := resource.MustParse("1Ti")
+	maxSize := resource.MustParse("100Gi")
+	crush = cephv1.CrushSpec{DeviceClassRules: []cephv1.DeviceClassRule{{DeviceClass: "large", MinSize: &minSize}}}
+	assert.NoError(t, ValidateCrushSpec(crush))
+	crush.DeviceClassRules[0].MaxSize = &maxSize
+	assert.Error(t, ValidateCrushSpec(crush))
+	crush.DeviceClassRules[0] = cephv1.DeviceClassRule{DeviceClass: "fast", Model: "Samsung("}
+	assert.Error(t, ValidateCrushSpec(crush))
+	crush.DeviceClassRules[0] = cephv1.DeviceClassRule{Model: "Samsung"}
+	assert.Error(t, ValidateCrushSpec(crush))
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	apps "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var updateDeploymentsAndWait = k8sutil.UpdateDeploymentsAndWait
+
+// osdUpdateBatch is a set of OSDs of a same failure domain restarted together
+type osdUpdateBatch struct {
+	failureDomain string
+	osdIDs        []int
+	deployments   []*apps.Deployment
+}
+
+// ValidateUpgradeStrategy checks the strategy to update the OSDs. The types of the CRUSH hierarchy are valid failure
+// domains.
+func ValidateUpgradeStrategy(strategy cephv1.OSDUpgradeStrategySpec, crush cephv1.CrushSpec) error {
+	if strategy.MaxInParallel < 0 {
+		return errors.Errorf("invalid osd maxInParallel %d. must not be negative", strategy.MaxInParallel)
+	}
+	if strategy.FailureDomain == "" {
+		return nil
+	}
+	for _, level := range CRUSHMapLevelsOrdered {
+		if strategy.FailureDomain == level {
+			return nil
+		}
+	}
+	for _, level := range crush.Hierarchy {
+		if strategy.FailureDomain == level.Type {
+			return nil
+		}
+	}
+	return errors.Errorf("invalid osd upgrade failure domain %q. must be one of %v or a type of the crush hierarchy", strategy.FailureDomain, CRUSHMapLevelsOrdered)
+}
+
+// updateOSD updates the deployment of an existing OSD, or queues it to be updated later with the other OSDs of its
+// failure domain
+func (c *Cluster) updateOSD(dp *apps.Deployment, osdID int, config *provisionConfig) {
+	if c.upgradeStrategy.FailureDomain != "" {
+		config.osdUpdates[osdID] = dp
+		return
+	}
+
+	if err := updateDeploymentAndWait(c.context, dp, c.Namespace, opconfig.OsdType, strconv.Itoa(osdID), c.skipUpgradeChecks, c.continueUpgradeAfterChecksEvenIfNotHealthy); err != nil {
+		logger.Errorf("failed to update osd deployment %d. %v", osdID, err)
+	}
+}
+
+// updateOSDsByFailureDomain updates the queued OSD deployments one failure domain at a time. The OSDs of a failure
+// domain are stopped together once ceph reports they are ok to stop, and the PGs must be clean before moving to the
+// next failure domain.
+func (c *Cluster) updateOSDsByFailureDomain(config *provisionConfig) {
+	if len(config.osdUpdates) == 0 {
+		return
+	}
+
+	batches, err := c.osdUpdateBatches(config.osdUpdates)
+	if err != nil {
+		config.addError("failed to plan the update of the osds by %s. %v", c.upgradeStrategy.FailureDomain, err)
+		return
+	}
+	if len(batches) == 0 {
+		logger.Debugf("no osd deployment changed, nothing to update")
+		return
+	}
+
+	status := &cephv1.OSDUpgradeStatus{FailureDomain: c.upgradeStrategy.FailureDomain}
+	failureDomains := map[string]bool{}
+	for _, batch := range batches {
+		failureDomains[batch.failureDomain] = true
+		status.TotalOSDs += len(batch.osdIDs)
+	}
+	status.TotalFailureDomains = len(failureDomains)
+	logger.Infof("updating %d osds in %d failure domains of type %q", status.TotalOSDs, status.TotalFailureDomains, status.FailureDomain)
+
+	for i, batch := range batches {
+		status.CurrentFailureDomain = batch.failureDomain
+		c.updateUpgradeStatus(status)
+
+		logger.Infof("updating osds %v in %s %q", batch.osdIDs, status.FailureDomain, batch.failureDomain)
+		if err := updateDeploymentsAndWait(c.context, batch.deployments, c.Namespace, c.osdBatchCallback(batch)); err != nil {
+			// the next failure domains are not updated until the next orchestration
+			config.addError("failed to update osds %v in %s %q. %v", batch.osdIDs, status.FailureDomain, batch.failureDomain, err)
+			return
+		}
+
+		status.UpdatedOSDs += len(batch.osdIDs)
+		if i == len(batches)-1 || batches[i+1].failureDomain != batch.failureDomain {
+			status.UpdatedFailureDomains++
+		}
+	}
+
+	status.CurrentFailureDomain = ""
+	c.updateUpgradeStatus(status)
+	logger.Infof("finished updating %d osds in %d failure domains of type %q", status.TotalOSDs, status.TotalFailureDomains, status.FailureDomain)
+}
+
+// osdUpdateBatches groups the OSD deployments that changed by failure domain, and splits the failure domains in
+// batches of at most maxInParallel OSDs
+func (c *Cluster) osdUpdateBatches(osdUpdates map[int]*apps.Deployment) ([]osdUpdateBatch, error) {
+	tree, err := client.HostTree(c.context, c.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the osd tree")
+	}
+	osdFailureDomains := failureDomainsFromTree(tree, c.upgradeStrategy.FailureDomain)
+
+	byFailureDomain := map[string][]int{}
+	alone := []int{}
+	for id, dp := range osdUpdates {
+		changed, err := k8sutil.DeploymentNeedsUpdate(c.context, dp, c.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			logger.Infof("deployment %q did not change, nothing to update", dp.Name)
+			continue
+		}
+
+		failureDomain, ok := osdFailureDomains[id]
+		if !ok {
+			// the osd is restarted alone after the failure domains if it is not found under a failure domain of the
+			// expected type
+			logger.Warningf("osd %d not found under a %s in the crush map, updating it alone", id, c.upgradeStrategy.FailureDomain)
+			alone = append(alone, id)
+			continue
+		}
+		byFailureDomain[failureDomain] = append(byFailureDomain[failureDomain], id)
+	}
+
+	failureDomains := []string{}
+	for failureDomain := range byFailureDomain {
+		failureDomains = append(failureDomains, failureDomain)
+	}
+	sort.Strings(failureDomains)
+
+	batches := []osdUpdateBatch{}
+	for _, failureDomain := range failureDomains {
+		ids := byFailureDomain[failureDomain]
+		sort.Ints(ids)
+		for len(ids) > 0 {
+			size := len(ids)
+			if c.upgradeStrategy.MaxInParallel > 0 && size > c.upgradeStrategy.MaxInParallel {
+				size = c.upgradeStrategy.MaxInParallel
+			}
+			batch := osdUpdateBatch{failureDomain: failureDomain, osdIDs: ids[:size]}
+			for _, id := range batch.osdIDs {
+				batch.deployments = append(batch.deployments, osdUpdates[id])
+			}
+			batches = append(batches, batch)
+			ids = ids[size:]
+		}
+	}
+
+	sort.Ints(alone)
+	for _, id := range alone {
+		batches = append(batches, osdUpdateBatch{failureDomain: fmt.Sprintf("osd.%d", id), osdIDs: []int{id}, deployments: []*apps.Deployment{osdUpdates[id]}})
+	}
+	return batches, nil
+}
+
+// failureDomainsFromTree returns the name of the failure domain of the given type of each OSD of the CRUSH tree
+func failureDomainsFromTree(tree client.OsdTree, failureDomainType string) map[int]string {
+	parents := map[int]int{}
+	nodes := map[int]int{}
+	for i, node := range tree.Nodes {
+		nodes[node.ID] = i
+		for _, child := range node.Children {
+			parents[child] = node.ID
+		}
+	}
+
+	osdFailureDomains := map[int]string{}
+	for _, node := range tree.Nodes {
+		if node.Type != "osd" {
+			continue
+		}
+		// walk up the tree until the failure domain
+		id := node.ID
+		for {
+			parent, ok := parents[id]
+			if !ok {
+				break
+			}
+			parentNode := tree.Nodes[nodes[parent]]
+			if parentNode.Type == failureDomainType {
+				osdFailureDomains[node.ID] = parentNode.Name
+				break
+			}
+			id = parent
+		}
+	}
+	return osdFailureDomains
+}
+
+// osdBatchCallback returns the callback verifying that a batch of OSDs can be stopped together, and that the PGs are
+// clean before updating the next batch
+func (c *Cluster) osdBatchCallback(batch osdUpdateBatch) func(action string) error {
+	return func(action string) error {
+		if c.skipUpgradeChecks {
+			logger.Warningf("not performing upgrade checks because skipUpgradeChecks is %t", c.skipUpgradeChecks)
+			return nil
+		}
+
+		var err error
+		switch action {
+		case "stop":
+			err = client.OSDsOkToStop(c.context, c.Namespace, batch.osdIDs)
+		case "continue":
+			err = client.OkToContinue(c.context, c.Namespace, batch.deployments[0].Name, opconfig.OsdType, strconv.Itoa(batch.osdIDs[0]))
+		}
+		if err != nil {
+			if c.continueUpgradeAfterChecksEvenIfNotHealthy {
+				logger.Infof("the osds %v cannot %s but 'continueUpgradeAfterChecksEvenIfNotHealthy' is true, so proceeding...", batch.osdIDs, action)
+				return nil
+			}
+			return errors.Wrapf(err, "failed to check if we can %s the osds %v", action, batch.osdIDs)
+		}
+		return nil
+	}
+}
+
+// updateUpgradeStatus reports the progress of the update of the OSDs in the status of the CephCluster CR
+func (c *Cluster) updateUpgradeStatus(status *cephv1.OSDUpgradeStatus) {
+	if c.context.Client == nil {
+		return
+	}
+
+	cephCluster := &cephv1.CephCluster{}
+	namespacedName := types.NamespacedName{Name: c.ownerRef.Name, Namespace: c.Namespace}
+	if err := c.context.Client.Get(context.TODO(), namespacedName, cephCluster); err != nil {
+		logger.Errorf("failed to get ceph cluster %q to report the osd update progress. %v", namespacedName.Name, err)
+		return
+	}
+
+	status.LastUpdated = time.Now().UTC().Format(time.RFC3339)
+	cephCluster.Status.OSDUpgrade = status.DeepCopy()
+	if err := controller.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		logger.Errorf("failed to report the osd update progress of cluster %q. %v", namespacedName.Name, err)
+	}
+}
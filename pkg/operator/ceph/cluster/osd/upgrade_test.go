@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testOSDTree = `{"nodes": [
+	{"id": -1, "name": "default", "type": "root", "children": [-5, -6]},
+	{"id": -5, "name": "rack1", "type": "rack", "children": [-2, -3]},
+	{"id": -6, "name": "rack2", "type": "rack", "children": [-4]},
+	{"id": -2, "name": "node1", "type": "host", "children": [0, 1]},
+	{"id": -3, "name": "node2", "type": "host", "children": [2]},
+	{"id": -4, "name": "node3", "type": "host", "children": [3, 4]},
+	{"id": 0, "name": "osd.0", "type": "osd"},
+	{"id": 1, "name": "osd.1", "type": "osd"},
+	{"id": 2, "name": "osd.2", "type": "osd"},
+	{"id": 3, "name": "osd.3", "type": "osd"},
+	{"id": 4, "name": "osd.4", "type": "osd"}
+], "stray": [{"id": 5, "name": "osd.5", "type": "osd"}]}`
+
+func TestValidateUpgradeStrategy(t *testing.T) {
+	assert.NoError(t, ValidateUpgradeStrategy(cephv1.OSDUpgradeStrategySpec{}, cephv1.CrushSpec{}))
+	assert.NoError(t, ValidateUpgradeStrategy(cephv1.OSDUpgradeStrategySpec{FailureDomain: "host"}, cephv1.CrushSpec{}))
+	assert.NoError(t, ValidateUpgradeStrategy(cephv1.OSDUpgradeStrategySpec{FailureDomain: "zone", MaxInParallel: 10}, cephv1.CrushSpec{}))
+	assert.Error(t, ValidateUpgradeStrategy(cephv1.OSDUpgradeStrategySpec{FailureDomain: "osd"}, cephv1.CrushSpec{}))
+	assert.Error(t, ValidateUpgradeStrategy(cephv1.OSDUpgradeStrategySpec{FailureDomain: "host", MaxInParallel: -1}, cephv1.CrushSpec{}))
+	assert.Error(t, ValidateUpgradeStrategy(cephv1.OSDUpgradeStrategySpec{FailureDomain: "hall"}, cephv1.CrushSpec{}))
+	assert.NoError(t, ValidateUpgradeStrategy(cephv1.OSDUpgradeStrategySpec{FailureDomain: "hall"},
+		cephv1.CrushSpec{Hierarchy: []cephv1.CrushLevelSpec{{Type: "hall", Label: "example.com/hall"}}}))
+}
+
+func testOSDUpgradeCluster(t *testing.T, strategy cephv1.OSDUpgradeStrategySpec) (*Cluster, map[int]*apps.Deployment) {
+	clientset := fake.NewSimpleClientset()
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command string, outFileArg string, args ...string) (string, error) {
+			if args[0] == "osd" && args[1] == "tree" {
+				return testOSDTree, nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	c := &Cluster{
+		context:         &clusterd.Context{Clientset: clientset, Executor: executor},
+		Namespace:       "ns",
+		upgradeStrategy: strategy,
+	}
+
+	// osd 5 is not in the crush map and osd 4 did not change
+	osdUpdates := map[int]*apps.Deployment{}
+	for id := 0; id <= 5; id++ {
+		current := &apps.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("rook-ceph-osd-%d", id), Namespace: "ns"},
+			Spec: apps.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "osd", Image: "ceph/ceph:v15.2.3"}},
+			}}},
+		}
+		assert.NoError(t, patch.DefaultAnnotator.SetLastAppliedAnnotation(current))
+		_, err := clientset.AppsV1().Deployments("ns").Create(current)
+		assert.NoError(t, err)
+
+		modified := current.DeepCopy()
+		if id != 4 {
+			modified.Spec.Template.Spec.Containers[0].Image = "ceph/ceph:v15.2.4"
+		}
+		osdUpdates[id] = modified
+	}
+	return c, osdUpdates
+}
+
+func TestOSDUpdateBatches(t *testing.T) {
+	// the osds are grouped by host
+	c, osdUpdates := testOSDUpgradeCluster(t, cephv1.OSDUpgradeStrategySpec{FailureDomain: "host"})
+	batches, err := c.osdUpdateBatches(osdUpdates)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(batches))
+	assert.Equal(t, "node1", batches[0].failureDomain)
+	assert.Equal(t, []int{0, 1}, batches[0].osdIDs)
+	assert.Equal(t, 2, len(batches[0].deployments))
+	assert.Equal(t, "node2", batches[1].failureDomain)
+	assert.Equal(t, []int{2}, batches[1].osdIDs)
+	assert.Equal(t, "node3", batches[2].failureDomain)
+	assert.Equal(t, []int{3}, batches[2].osdIDs)
+	assert.Equal(t, "osd.5", batches[3].failureDomain)
+	assert.Equal(t, []int{5}, batches[3].osdIDs)
+
+	// the osds are grouped by rack, in batches of at most two osds
+	c, osdUpdates = testOSDUpgradeCluster(t, cephv1.OSDUpgradeStrategySpec{FailureDomain: "rack", MaxInParallel: 2})
+	batches, err = c.osdUpdateBatches(osdUpdates)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(batches))
+	assert.Equal(t, "rack1", batches[0].failureDomain)
+	assert.Equal(t, []int{0, 1}, batches[0].osdIDs)
+	assert.Equal(t, "rack1", batches[1].failureDomain)
+	assert.Equal(t, []int{2}, batches[1].osdIDs)
+	assert.Equal(t, "rack2", batches[2].failureDomain)
+	assert.Equal(t, []int{3}, batches[2].osdIDs)
+	assert.Equal(t, "osd.5", batches[3].failureDomain)
+}
+
+func TestUpdateOSDsByFailureDomain(t *testing.T) {
+	updated := [][]string{}
+	updateDeploymentsAndWait = func(context *clusterd.Context, deployments []*apps.Deployment, namespace string, verifyCallback func(action string) error) error {
+		names := []string{}
+		for _, d := range deployments {
+			names = append(names, d.Name)
+		}
+		updated = append(updated, names)
+		if len(updated) == 2 {
+			return errors.New("pgs not clean")
+		}
+		return nil
+	}
+	defer func() { updateDeploymentsAndWait = k8sutil.UpdateDeploymentsAndWait }()
+
+	c, osdUpdates := testOSDUpgradeCluster(t, cephv1.OSDUpgradeStrategySpec{FailureDomain: "rack"})
+	config := c.newProvisionConfig()
+	config.osdUpdates = osdUpdates
+
+	// the update stops at the first failure domain that fails
+	c.updateOSDsByFailureDomain(config)
+	assert.Equal(t, [][]string{{"rook-ceph-osd-0", "rook-ceph-osd-1", "rook-ceph-osd-2"}, {"rook-ceph-osd-3"}}, updated)
+	assert.Equal(t, 1, len(config.errorMessages))
+}
@@ -210,6 +210,11 @@ func (r *ReconcileCephRBDMirror) reconcileCreateCephRBDMirror(cephRBDMirror *cep
 		return reconcile.Result{}, errors.Wrap(err, "failed to start rbd mirror")
 	}
 
+	err = r.reconcileAddBootstrapPeers(cephRBDMirror)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add rbd mirror peers")
+	}
+
 	return reconcile.Result{}, nil
 }
 
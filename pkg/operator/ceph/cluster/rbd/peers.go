@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbd
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/pool"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reconcileAddBootstrapPeers imports the bootstrap peer tokens of the remote sites in the mirrored pools. Each
+// Secret holds the token of a remote pool and the name of the local pool to add the peer to.
+func (r *ReconcileCephRBDMirror) reconcileAddBootstrapPeers(cephRBDMirror *cephv1.CephRBDMirror) error {
+	for _, secretName := range cephRBDMirror.Spec.Peers.SecretNames {
+		secret, err := r.context.Clientset.CoreV1().Secrets(cephRBDMirror.Namespace).Get(secretName, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get peer secret %q", secretName)
+		}
+
+		token, ok := secret.Data[pool.BootstrapPeerTokenKey]
+		if !ok || len(token) == 0 {
+			return errors.Errorf("peer secret %q has no %q key", secretName, pool.BootstrapPeerTokenKey)
+		}
+		poolName, ok := secret.Data[pool.BootstrapPeerPoolKey]
+		if !ok || len(poolName) == 0 {
+			return errors.Errorf("peer secret %q has no %q key", secretName, pool.BootstrapPeerPoolKey)
+		}
+
+		err = cephclient.ImportRBDMirrorBootstrapPeer(r.context, cephRBDMirror.Namespace, string(poolName), string(token))
+		if err != nil {
+			return errors.Wrapf(err, "failed to import peer from secret %q", secretName)
+		}
+	}
+
+	return nil
+}
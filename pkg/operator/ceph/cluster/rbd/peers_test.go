@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbd
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileAddBootstrapPeers(t *testing.T) {
+	namespace := "rook-ceph"
+	importedPools := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mirror" && args[4] == "import" {
+				importedPools = append(importedPools, args[5])
+			}
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{Executor: executor, Clientset: test.New(t, 1)}
+	r := &ReconcileCephRBDMirror{context: c}
+	rbdMirror := &cephv1.CephRBDMirror{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-rbd-mirror", Namespace: namespace},
+		Spec: cephv1.RBDMirroringSpec{
+			Count: 1,
+			Peers: cephv1.MirroringPeerSpec{SecretNames: []string{"peer-a"}},
+		},
+	}
+
+	// the peer secret does not exist
+	err := r.reconcileAddBootstrapPeers(rbdMirror)
+	assert.Error(t, err)
+
+	// the peer secret must have the pool
+	peer := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "peer-a", Namespace: namespace},
+		Data:       map[string][]byte{"token": []byte("remote-token")},
+	}
+	_, err = c.Clientset.CoreV1().Secrets(namespace).Create(peer)
+	assert.NoError(t, err)
+	err = r.reconcileAddBootstrapPeers(rbdMirror)
+	assert.Error(t, err)
+
+	peer.Data["pool"] = []byte("replicapool")
+	_, err = c.Clientset.CoreV1().Secrets(namespace).Update(peer)
+	assert.NoError(t, err)
+	err = r.reconcileAddBootstrapPeers(rbdMirror)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"replicapool"}, importedPools)
+}
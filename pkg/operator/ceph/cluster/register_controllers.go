@@ -20,6 +20,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/crash"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/removal"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/rbd"
 	"github.com/rook/rook/pkg/operator/ceph/disruption/clusterdisruption"
 	"github.com/rook/rook/pkg/operator/ceph/disruption/controllerconfig"
@@ -27,9 +28,13 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/disruption/machinelabel"
 	"github.com/rook/rook/pkg/operator/ceph/disruption/nodedrain"
 	"github.com/rook/rook/pkg/operator/ceph/file"
+	fsmirror "github.com/rook/rook/pkg/operator/ceph/file/mirror"
+	"github.com/rook/rook/pkg/operator/ceph/file/subvolumegroup"
 	"github.com/rook/rook/pkg/operator/ceph/nfs"
 	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/ceph/object/notification"
 	"github.com/rook/rook/pkg/operator/ceph/object/realm"
+	"github.com/rook/rook/pkg/operator/ceph/object/topic"
 	objectuser "github.com/rook/rook/pkg/operator/ceph/object/user"
 	"github.com/rook/rook/pkg/operator/ceph/object/zone"
 	"github.com/rook/rook/pkg/operator/ceph/object/zonegroup"
@@ -47,6 +52,7 @@ var (
 var AddToManagerFuncsMaintenance = []func(manager.Manager, *controllerconfig.Context) error{
 	nodedrain.Add,
 	clusterdisruption.Add,
+	removal.Add,
 }
 
 // MachineDisruptionBudgetAddToManagerFuncs is a list of fencing related functions to add all Controllers to the Manager (entrypoint for controller)
@@ -64,9 +70,13 @@ var AddToManagerFuncs = []func(manager.Manager, *clusterd.Context) error{
 	zonegroup.Add,
 	zone.Add,
 	object.Add,
+	topic.Add,
+	notification.Add,
 	file.Add,
+	subvolumegroup.Add,
 	nfs.Add,
 	rbd.Add,
+	fsmirror.Add,
 }
 
 // AddToManager adds all the registered controllers to the passed manager.
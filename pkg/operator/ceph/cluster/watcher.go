@@ -19,11 +19,13 @@ package cluster
 
 import (
 	"context"
+	"reflect"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	discoverDaemon "github.com/rook/rook/pkg/daemon/discover"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -163,6 +165,37 @@ func (c *clientCluster) onDeviceCMUpdate(oldObj, newObj runtime.Object) bool {
 	return true
 }
 
+// onK8sNodeTopologyUpdate is triggered when a node is updated, the cluster is reconciled when the labels setting the
+// CRUSH location of the node changed so the CRUSH hierarchy follows the node labels
+func (c *clientCluster) onK8sNodeTopologyUpdate(oldObj, newObj runtime.Object) bool {
+	oldNode, ok := oldObj.(*v1.Node)
+	if !ok {
+		return false
+	}
+	newNode, ok := newObj.(*v1.Node)
+	if !ok {
+		return false
+	}
+
+	cluster := c.getCephCluster()
+	if len(cluster.Spec.Crush.Hierarchy) == 0 {
+		return false
+	}
+	if cluster.Status.Phase != cephv1.ConditionReady {
+		logger.Debugf("node watcher: cluster %q is not ready. skipping crush hierarchy update", cluster.Namespace)
+		return false
+	}
+
+	oldTopology := osd.ExtractOSDTopologyFromHierarchy(oldNode.Labels, cluster.Spec.Crush.Hierarchy)
+	newTopology := osd.ExtractOSDTopologyFromHierarchy(newNode.Labels, cluster.Spec.Crush.Hierarchy)
+	if reflect.DeepEqual(oldTopology, newTopology) {
+		return false
+	}
+
+	logger.Infof("node watcher: crush location of node %q changed from %v to %v", newNode.Name, oldTopology, newTopology)
+	return true
+}
+
 func (c *clientCluster) getCephCluster() *cephv1.CephCluster {
 	clusterList := &cephv1.CephClusterList{}
 
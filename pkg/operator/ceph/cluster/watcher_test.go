@@ -123,3 +123,33 @@ func TestOnDeviceCMUpdate(t *testing.T) {
 	b = clientCluster.onDeviceCMUpdate(oldCM, newCM)
 	assert.True(t, b)
 }
+
+func TestOnK8sNodeTopologyUpdate(t *testing.T) {
+	ns := "rook-ceph"
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: ns, Namespace: ns},
+		Spec: cephv1.ClusterSpec{
+			Crush: cephv1.CrushSpec{Hierarchy: []cephv1.CrushLevelSpec{{Type: "hall", Label: "example.com/hall"}}},
+		},
+		Status: cephv1.ClusterStatus{Phase: cephv1.ConditionReady},
+	}
+	client := fake.NewFakeClientWithScheme(s, cephCluster)
+	clientCluster := newClientCluster(client, ns, &clusterd.Context{})
+
+	oldNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"example.com/hall": "hall1"}}}
+	newNode := oldNode.DeepCopy()
+	newNode.Labels["foo"] = "bar"
+	assert.False(t, clientCluster.onK8sNodeTopologyUpdate(oldNode, newNode))
+
+	// the hall of the node changed
+	newNode.Labels["example.com/hall"] = "hall2"
+	assert.True(t, clientCluster.onK8sNodeTopologyUpdate(oldNode, newNode))
+
+	// the labels are ignored without a crush hierarchy
+	cephCluster.Spec.Crush.Hierarchy = nil
+	clientCluster.client = fake.NewFakeClientWithScheme(s, cephCluster)
+	assert.False(t, clientCluster.onK8sNodeTopologyUpdate(oldNode, newNode))
+}
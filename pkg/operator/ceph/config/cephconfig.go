@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// the entities an option can be set for: global, a daemon type, a daemon, or a daemon type with a mask
+var cephConfigWhoRegex = regexp.MustCompile(`^(global|mon|mgr|osd|mds|client)([./].+)?$`)
+
+// ValidateCephConfigWho checks the entity of options of the cephConfig settings
+func ValidateCephConfigWho(who string) error {
+	if !cephConfigWhoRegex.MatchString(who) {
+		return errors.Errorf("invalid entity %q, expected global, a daemon type (mon, mgr, osd, mds, client) or a daemon such as osd.3", who)
+	}
+	return nil
+}
+
+// ReconcileCephConfig sets the options of the cephConfig settings of a cluster in the centralized mon configuration
+// database, and removes the options previously set by the operator that were dropped from the settings. The previous
+// state is the status returned by the last reconcile. The options that could not be set are reported as rejected.
+func ReconcileCephConfig(context *clusterd.Context, namespace string, desired map[string]map[string]string, previous *cephv1.CephConfigStatus) *cephv1.CephConfigStatus {
+	if len(desired) == 0 && (previous == nil || len(previous.Applied) == 0) {
+		return nil
+	}
+
+	monStore := GetMonStore(context, namespace)
+	status := &cephv1.CephConfigStatus{Applied: map[string]map[string]string{}}
+	previouslyApplied := map[string]map[string]string{}
+	if previous != nil && previous.Applied != nil {
+		previouslyApplied = previous.Applied
+	}
+
+	// the options validated with "ceph config help"
+	validated := map[string]error{}
+	wanted := map[string]map[string]bool{}
+	for _, who := range sortedEntities(desired) {
+		wanted[who] = map[string]bool{}
+		whoErr := ValidateCephConfigWho(who)
+		for _, option := range sortedOptions(desired[who]) {
+			value := desired[who][option]
+			key := normalizeKey(option)
+			wanted[who][key] = true
+
+			err := whoErr
+			if err == nil {
+				var ok bool
+				if err, ok = validated[key]; !ok {
+					err = monStore.ValidateOption(key)
+					validated[key] = err
+				}
+			}
+			if err == nil {
+				err = monStore.Set(who, key, value)
+			}
+			if err != nil {
+				logger.Errorf("rejected ceph config option %q of %q. %v", key, who, err)
+				status.Rejected = append(status.Rejected, cephv1.CephConfigRejectedOption{Who: who, Option: key, Value: value, Reason: err.Error()})
+				// an option set previously is still managed by the operator
+				if previousValue, ok := previouslyApplied[who][key]; ok {
+					setAppliedOption(status, who, key, previousValue)
+				}
+				continue
+			}
+			setAppliedOption(status, who, key, value)
+		}
+	}
+
+	// remove the options dropped from the settings
+	for _, who := range sortedEntities(previouslyApplied) {
+		for _, option := range sortedOptions(previouslyApplied[who]) {
+			if wanted[who][option] {
+				continue
+			}
+			logger.Infof("removing ceph config option %q of %q", option, who)
+			if err := monStore.Delete(who, option); err != nil {
+				// retry the removal with the next reconcile
+				logger.Errorf("failed to remove ceph config option %q of %q. %v", option, who, err)
+				setAppliedOption(status, who, option, previouslyApplied[who][option])
+			}
+		}
+	}
+
+	status.LastUpdated = time.Now().UTC().Format(time.RFC3339)
+	return status
+}
+
+func setAppliedOption(status *cephv1.CephConfigStatus, who, option, value string) {
+	if _, ok := status.Applied[who]; !ok {
+		status.Applied[who] = map[string]string{}
+	}
+	status.Applied[who][option] = value
+}
+
+func sortedEntities(options map[string]map[string]string) []string {
+	entities := []string{}
+	for who := range options {
+		entities = append(entities, who)
+	}
+	sort.Strings(entities)
+	return entities
+}
+
+func sortedOptions(options map[string]string) []string {
+	names := []string{}
+	for option := range options {
+		names = append(names, option)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCephConfigWho(t *testing.T) {
+	for _, who := range []string{"global", "mon", "osd", "osd.3", "client.rgw.my.store", "mds.myfs-a", "osd/class:ssd", "osd/host:node1"} {
+		assert.NoError(t, ValidateCephConfigWho(who), who)
+	}
+	for _, who := range []string{"", "foo", "osd3", "globals", "rgw"} {
+		assert.Error(t, ValidateCephConfigWho(who), who)
+	}
+}
+
+func TestReconcileCephConfig(t *testing.T) {
+	executed := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			// ignore the flags appended to the command
+			cmd := strings.Split(strings.Join(args, " "), " --")[0]
+			executed = append(executed, cmd)
+			if args[0] == "config" && args[1] == "help" && args[2] == "unknown_option" {
+				return "Error ENOENT: unrecognized key 'unknown_option'", errors.New("unknown option")
+			}
+			if args[0] == "config" && args[1] == "set" && args[4] == "invalid" {
+				return "Error EINVAL: invalid value", errors.New("invalid value")
+			}
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	// nothing to reconcile
+	assert.Nil(t, ReconcileCephConfig(context, "ns", nil, nil))
+	assert.Equal(t, 0, len(executed))
+
+	desired := map[string]map[string]string{
+		"global": {"osd-pool-default-size": "2", "unknown_option": "1"},
+		"osd.3":  {"osd_max_backfills": "4"},
+		"foo":    {"debug_ms": "1"},
+	}
+	status := ReconcileCephConfig(context, "ns", desired, nil)
+	assert.Equal(t, map[string]map[string]string{
+		"global": {"osd_pool_default_size": "2"},
+		"osd.3":  {"osd_max_backfills": "4"},
+	}, status.Applied)
+	assert.Equal(t, 2, len(status.Rejected))
+	assert.Equal(t, "foo", status.Rejected[0].Who)
+	assert.Equal(t, "debug_ms", status.Rejected[0].Option)
+	assert.Equal(t, "global", status.Rejected[1].Who)
+	assert.Equal(t, "unknown_option", status.Rejected[1].Option)
+	assert.Contains(t, status.Rejected[1].Reason, "unknown ceph config option")
+	assert.NotEmpty(t, status.LastUpdated)
+	assert.Contains(t, executed, "config set global osd_pool_default_size 2")
+	assert.Contains(t, executed, "config set osd.3 osd_max_backfills 4")
+
+	// the dropped option is removed, and an option that can't be updated is still managed
+	executed = []string{}
+	desired = map[string]map[string]string{
+		"global": {"osd_pool_default_size": "invalid"},
+	}
+	status = ReconcileCephConfig(context, "ns", desired, status)
+	assert.Equal(t, map[string]map[string]string{
+		"global": {"osd_pool_default_size": "2"},
+	}, status.Applied)
+	assert.Equal(t, 1, len(status.Rejected))
+	assert.Contains(t, executed, "config rm osd.3 osd_max_backfills")
+
+	// all the options are removed
+	executed = []string{}
+	status = ReconcileCephConfig(context, "ns", nil, status)
+	assert.Equal(t, 0, len(status.Applied))
+	assert.Equal(t, 0, len(status.Rejected))
+	assert.Equal(t, []string{"config rm global osd_pool_default_size"}, executed)
+
+	// a removal that fails is retried
+	executor.MockExecuteCommandWithOutputFile = func(command, outfile string, args ...string) (string, error) {
+		return "", errors.New("failed")
+	}
+	previous := &cephv1.CephConfigStatus{Applied: map[string]map[string]string{"mon": {"mon_warn_on_pool_no_redundancy": "false"}}}
+	status = ReconcileCephConfig(context, "ns", nil, previous)
+	assert.Equal(t, previous.Applied, status.Applied)
+}
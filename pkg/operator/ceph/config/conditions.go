@@ -33,6 +33,10 @@ import (
 var (
 	conditions   *[]cephv1.Condition
 	conditionMap = make(map[cephv1.ConditionType]v1.ConditionStatus)
+	// informationalConditions are reported on the cluster CR without changing its phase
+	informationalConditions = map[cephv1.ConditionType]struct{}{
+		cephv1.ConditionMonFailoverPending: {},
+	}
 )
 
 // ConditionExport function will export each condition into the cluster custom resource
@@ -80,7 +84,7 @@ func setCondition(c *clusterd.Context, namespaceName types.NamespacedName, newCo
 	}
 	cluster.Status.Conditions = *conditions
 
-	if newCondition.Status == v1.ConditionTrue {
+	if _, informational := informationalConditions[newCondition.Type]; newCondition.Status == v1.ConditionTrue && !informational {
 		cluster.Status.Phase = newCondition.Type
 		if state := translatePhasetoState(newCondition.Type); state != "" {
 			cluster.Status.State = state
@@ -148,3 +148,14 @@ func (m *MonStore) SetAll(options ...Option) error {
 	}
 	return nil
 }
+
+// ValidateOption checks that an option is known by Ceph with "ceph config help".
+func (m *MonStore) ValidateOption(option string) error {
+	args := []string{"config", "help", normalizeKey(option)}
+	cephCmd := client.NewCephCommand(m.context, m.namespace, args)
+	out, err := cephCmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "unknown ceph config option %q. output: %s", option, string(out))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"os"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ReconcileStartedReason is the reason of the conditions set when a reconcile starts
+	ReconcileStartedReason = "ReconcileStarted"
+	// ReconcileSucceededReason is the reason of the conditions set when a reconcile succeeds
+	ReconcileSucceededReason = "ReconcileSucceeded"
+	// ReconcileFailedReason is the reason of the conditions set when a reconcile fails
+	ReconcileFailedReason = "ReconcileFailed"
+
+	defaultStatusRefreshInterval = 60 * time.Second
+)
+
+// SetStatusCondition adds or updates a condition in the list of conditions. The transition time only changes
+// when the status of the condition changes.
+func SetStatusCondition(conditions *[]cephv1.Condition, newCondition cephv1.Condition) {
+	now := metav1.NewTime(time.Now())
+	newCondition.LastHeartbeatTime = now
+
+	for i := range *conditions {
+		existing := &(*conditions)[i]
+		if existing.Type != newCondition.Type {
+			continue
+		}
+		if existing.Status != newCondition.Status {
+			existing.LastTransitionTime = now
+		}
+		existing.Status = newCondition.Status
+		existing.Reason = newCondition.Reason
+		existing.Message = newCondition.Message
+		existing.LastHeartbeatTime = now
+		return
+	}
+
+	newCondition.LastTransitionTime = now
+	*conditions = append(*conditions, newCondition)
+}
+
+// FindStatusCondition returns the condition of the given type, nil if not found
+func FindStatusCondition(conditions []cephv1.Condition, conditionType cephv1.ConditionType) *cephv1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetPhaseConditions translates the phase of a CR into its Progressing, Ready and Failure conditions.
+// The message of the Failure condition is the error of the reconcile, if any.
+func SetPhaseConditions(conditions *[]cephv1.Condition, phase string, reconcileErr error) {
+	switch phase {
+	case k8sutil.Created, k8sutil.ReconcilingStatus:
+		SetStatusCondition(conditions, cephv1.Condition{Type: cephv1.ConditionProgressing, Status: v1.ConditionTrue, Reason: ReconcileStartedReason})
+
+	case k8sutil.ReadyStatus:
+		SetStatusCondition(conditions, cephv1.Condition{Type: cephv1.ConditionProgressing, Status: v1.ConditionFalse, Reason: ReconcileSucceededReason})
+		SetStatusCondition(conditions, cephv1.Condition{Type: cephv1.ConditionFailure, Status: v1.ConditionFalse, Reason: ReconcileSucceededReason})
+		SetStatusCondition(conditions, cephv1.Condition{Type: cephv1.ConditionReady, Status: v1.ConditionTrue, Reason: ReconcileSucceededReason})
+
+	case k8sutil.ReconcileFailedStatus:
+		message := ""
+		if reconcileErr != nil {
+			message = reconcileErr.Error()
+		}
+		SetStatusCondition(conditions, cephv1.Condition{Type: cephv1.ConditionProgressing, Status: v1.ConditionFalse, Reason: ReconcileFailedReason})
+		SetStatusCondition(conditions, cephv1.Condition{Type: cephv1.ConditionFailure, Status: v1.ConditionTrue, Reason: ReconcileFailedReason, Message: message})
+		SetStatusCondition(conditions, cephv1.Condition{Type: cephv1.ConditionReady, Status: v1.ConditionFalse, Reason: ReconcileFailedReason, Message: message})
+	}
+}
+
+// StatusRefresher periodically refreshes the status of the CRs of a controller from Ceph, the same way the health
+// of the cluster is periodically checked
+type StatusRefresher struct {
+	controllerName string
+	interval       time.Duration
+	refresh        func()
+}
+
+// NewStatusRefresher creates a refresher calling the given refresh function at each interval. It must be added to
+// the controller manager so that it is started and stopped with the controllers.
+func NewStatusRefresher(controllerName string, refresh func()) *StatusRefresher {
+	return &StatusRefresher{
+		controllerName: controllerName,
+		interval:       StatusRefreshInterval(),
+		refresh:        refresh,
+	}
+}
+
+// Start runs the refresh loop until the stop channel is closed
+func (r *StatusRefresher) Start(stopCh <-chan struct{}) error {
+	logger.Infof("%s: refreshing status every %s", r.controllerName, r.interval.String())
+	for {
+		select {
+		case <-stopCh:
+			logger.Infof("%s: stopping status refresh", r.controllerName)
+			return nil
+
+		case <-time.After(r.interval):
+			r.refresh()
+		}
+	}
+}
+
+// StatusRefreshInterval returns the interval at which the controllers refresh the status of their CRs from Ceph.
+// It follows the interval of the ceph status check of the cluster.
+func StatusRefreshInterval() time.Duration {
+	if checkInterval := os.Getenv("ROOK_CEPH_STATUS_CHECK_INTERVAL"); checkInterval != "" {
+		if duration, err := time.ParseDuration(checkInterval); err == nil {
+			return duration
+		}
+	}
+	return defaultStatusRefreshInterval
+}
+
+// FormatStatusTime formats a time the way the CR statuses report it
+func FormatStatusTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetStatusCondition(t *testing.T) {
+	var conditions []cephv1.Condition
+
+	SetStatusCondition(&conditions, cephv1.Condition{Type: cephv1.ConditionReady, Status: v1.ConditionFalse, Reason: "a"})
+	assert.Equal(t, 1, len(conditions))
+	assert.False(t, conditions[0].LastTransitionTime.IsZero())
+
+	// the transition time doesn't change if the status doesn't
+	transitionTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	conditions[0].LastTransitionTime = transitionTime
+	SetStatusCondition(&conditions, cephv1.Condition{Type: cephv1.ConditionReady, Status: v1.ConditionFalse, Reason: "b", Message: "msg"})
+	assert.Equal(t, 1, len(conditions))
+	assert.Equal(t, transitionTime, conditions[0].LastTransitionTime)
+	assert.Equal(t, "b", conditions[0].Reason)
+	assert.Equal(t, "msg", conditions[0].Message)
+
+	// the transition time changes with the status
+	SetStatusCondition(&conditions, cephv1.Condition{Type: cephv1.ConditionReady, Status: v1.ConditionTrue, Reason: "c"})
+	assert.NotEqual(t, transitionTime, conditions[0].LastTransitionTime)
+	assert.Equal(t, v1.ConditionTrue, conditions[0].Status)
+
+	// a new type is appended
+	SetStatusCondition(&conditions, cephv1.Condition{Type: cephv1.ConditionFailure, Status: v1.ConditionFalse})
+	assert.Equal(t, 2, len(conditions))
+	assert.NotNil(t, FindStatusCondition(conditions, cephv1.ConditionFailure))
+	assert.Nil(t, FindStatusCondition(conditions, cephv1.ConditionUpgrading))
+}
+
+func TestSetPhaseConditions(t *testing.T) {
+	var conditions []cephv1.Condition
+
+	SetPhaseConditions(&conditions, k8sutil.ReconcilingStatus, nil)
+	assert.Equal(t, v1.ConditionTrue, FindStatusCondition(conditions, cephv1.ConditionProgressing).Status)
+	assert.Nil(t, FindStatusCondition(conditions, cephv1.ConditionReady))
+
+	SetPhaseConditions(&conditions, k8sutil.ReconcileFailedStatus, errors.New("failed to create pool"))
+	assert.Equal(t, v1.ConditionFalse, FindStatusCondition(conditions, cephv1.ConditionProgressing).Status)
+	ready := FindStatusCondition(conditions, cephv1.ConditionReady)
+	assert.Equal(t, v1.ConditionFalse, ready.Status)
+	assert.Equal(t, ReconcileFailedReason, ready.Reason)
+	assert.Equal(t, "failed to create pool", ready.Message)
+	assert.Equal(t, v1.ConditionTrue, FindStatusCondition(conditions, cephv1.ConditionFailure).Status)
+
+	SetPhaseConditions(&conditions, k8sutil.ReadyStatus, nil)
+	ready = FindStatusCondition(conditions, cephv1.ConditionReady)
+	assert.Equal(t, v1.ConditionTrue, ready.Status)
+	assert.Equal(t, ReconcileSucceededReason, ready.Reason)
+	assert.Equal(t, "", ready.Message)
+	assert.Equal(t, v1.ConditionFalse, FindStatusCondition(conditions, cephv1.ConditionFailure).Status)
+	assert.Equal(t, 3, len(conditions))
+}
+
+func TestStatusRefreshInterval(t *testing.T) {
+	assert.Equal(t, defaultStatusRefreshInterval, StatusRefreshInterval())
+
+	os.Setenv("ROOK_CEPH_STATUS_CHECK_INTERVAL", "10s")
+	defer os.Unsetenv("ROOK_CEPH_STATUS_CHECK_INTERVAL")
+	assert.Equal(t, 10*time.Second, StatusRefreshInterval())
+}
@@ -99,6 +99,10 @@ func WatchControllerPredicate() predicate.Funcs {
 				diff := cmp.Diff(objOld.Spec, objNew.Spec, resourceQtyComparer)
 				if diff != "" {
 					logger.Infof("CR has changed for %q. diff=%s", objNew.Name, diff)
+					return true
+				} else if objOld.GetAnnotations()[cephv1.ObjectStoreUserRotateKeysAnnotation] != objNew.GetAnnotations()[cephv1.ObjectStoreUserRotateKeysAnnotation] {
+					logger.Infof("key rotation requested for %q", objNew.Name)
+					return true
 				} else if objOld.GetDeletionTimestamp() != objNew.GetDeletionTimestamp() {
 					logger.Debugf("CR %q is going be deleted", objNew.Name)
 					return true
@@ -254,6 +258,46 @@ func WatchControllerPredicate() predicate.Funcs {
 					return true
 				}
 
+			case *cephv1.CephBucketTopic:
+				objNew := e.ObjectNew.(*cephv1.CephBucketTopic)
+				logger.Debug("update event on CephBucketTopic CR")
+				// If the labels "do_not_reconcile" is set on the object, let's not reconcile that request
+				isDoNotReconcile := isDoNotReconcile(objNew.GetLabels())
+				if isDoNotReconcile {
+					logger.Debugf("object %q matched on update but %q label is set, doing nothing", doNotReconcileLabelName, objNew.Name)
+					return false
+				}
+				diff := cmp.Diff(objOld.Spec, objNew.Spec, resourceQtyComparer)
+				if diff != "" {
+					logger.Infof("CR has changed for %q. diff=%s", objNew.Name, diff)
+					return true
+				} else if objOld.GetDeletionTimestamp() != objNew.GetDeletionTimestamp() {
+					logger.Debugf("CR %q is going be deleted", objNew.Name)
+					return true
+				} else if objOld.GetGeneration() != objNew.GetGeneration() {
+					logger.Debugf("skipping resource %q update with unchanged spec", objNew.Name)
+				}
+
+			case *cephv1.CephBucketNotification:
+				objNew := e.ObjectNew.(*cephv1.CephBucketNotification)
+				logger.Debug("update event on CephBucketNotification CR")
+				// If the labels "do_not_reconcile" is set on the object, let's not reconcile that request
+				isDoNotReconcile := isDoNotReconcile(objNew.GetLabels())
+				if isDoNotReconcile {
+					logger.Debugf("object %q matched on update but %q label is set, doing nothing", doNotReconcileLabelName, objNew.Name)
+					return false
+				}
+				diff := cmp.Diff(objOld.Spec, objNew.Spec, resourceQtyComparer)
+				if diff != "" {
+					logger.Infof("CR has changed for %q. diff=%s", objNew.Name, diff)
+					return true
+				} else if objOld.GetDeletionTimestamp() != objNew.GetDeletionTimestamp() {
+					logger.Debugf("CR %q is going be deleted", objNew.Name)
+					return true
+				} else if objOld.GetGeneration() != objNew.GetGeneration() {
+					logger.Debugf("skipping resource %q update with unchanged spec", objNew.Name)
+				}
+
 			case *cephv1.CephCluster:
 				objNew := e.ObjectNew.(*cephv1.CephCluster)
 				logger.Debug("update event on CephCluster CR")
@@ -26,6 +26,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
 var (
@@ -47,7 +48,7 @@ func TestObjectChanged(t *testing.T) {
 				Size: oldReplicas,
 			},
 		},
-		Status: &cephv1.Status{
+		Status: &cephv1.CephBlockPoolStatus{
 			Phase: "",
 		},
 	}
@@ -62,7 +63,7 @@ func TestObjectChanged(t *testing.T) {
 				Size: oldReplicas,
 			},
 		},
-		Status: &cephv1.Status{
+		Status: &cephv1.CephBlockPoolStatus{
 			Phase: "",
 		},
 	}
@@ -79,6 +80,28 @@ func TestObjectChanged(t *testing.T) {
 	assert.True(t, changed)
 }
 
+func TestObjectStoreUserUpdatePredicate(t *testing.T) {
+	newUser := func(displayName, rotation string) *cephv1.CephObjectStoreUser {
+		return &cephv1.CephObjectStoreUser{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "my-user",
+				Namespace:   namespace,
+				Annotations: map[string]string{cephv1.ObjectStoreUserRotateKeysAnnotation: rotation},
+			},
+			Spec: cephv1.ObjectStoreUserSpec{Store: "my-store", DisplayName: displayName},
+		}
+	}
+	updated := func(objOld, objNew *cephv1.CephObjectStoreUser) bool {
+		return WatchControllerPredicate().Update(event.UpdateEvent{MetaOld: objOld, ObjectOld: objOld, MetaNew: objNew, ObjectNew: objNew})
+	}
+
+	assert.False(t, updated(newUser("user", "1"), newUser("user", "1")))
+	// the spec changed
+	assert.True(t, updated(newUser("user", "1"), newUser("my user", "1")))
+	// a key rotation is requested
+	assert.True(t, updated(newUser("user", "1"), newUser("user", "2")))
+}
+
 func TestIsUpgrade(t *testing.T) {
 	oldLabel := make(map[string]string)
 	newLabel := map[string]string{
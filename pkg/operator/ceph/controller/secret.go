@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// SecretReferences tracks the secrets referenced in the spec of the resources of a controller, so the events of the
+// secrets are filtered and mapped to the resources using them without listing the resources. The references are set
+// when the resources are reconciled, which happens for all the resources when the operator starts.
+type SecretReferences struct {
+	mutex sync.Mutex
+	// the resources referencing each secret
+	owners map[types.NamespacedName]map[types.NamespacedName]bool
+}
+
+// NewSecretReferences returns an empty set of secret references
+func NewSecretReferences() *SecretReferences {
+	return &SecretReferences{owners: map[types.NamespacedName]map[types.NamespacedName]bool{}}
+}
+
+// Set replaces the secrets referenced by a resource. A resource without secrets, such as a deleted one, is removed.
+func (s *SecretReferences) Set(owner types.NamespacedName, secrets ...types.NamespacedName) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for secret, owners := range s.owners {
+		delete(owners, owner)
+		if len(owners) == 0 {
+			delete(s.owners, secret)
+		}
+	}
+	for _, secret := range secrets {
+		if s.owners[secret] == nil {
+			s.owners[secret] = map[types.NamespacedName]bool{}
+		}
+		s.owners[secret][owner] = true
+	}
+}
+
+// IsReferenced returns whether a resource references the secret
+func (s *SecretReferences) IsReferenced(secret types.NamespacedName) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.owners[secret]) > 0
+}
+
+// ToRequests returns the mapper of the events of a secret to the requests of the resources referencing it
+func (s *SecretReferences) ToRequests() handler.Mapper {
+	return handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+		secret := types.NamespacedName{Name: obj.Meta.GetName(), Namespace: obj.Meta.GetNamespace()}
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		requests := []reconcile.Request{}
+		for owner := range s.owners[secret] {
+			logger.Infof("secret %q of %q changed", secret, owner)
+			requests = append(requests, reconcile.Request{NamespacedName: owner})
+		}
+		return requests
+	})
+}
+
+// Predicate filters the events of the secrets to the creations and the changes of the data of the referenced secrets
+func (s *SecretReferences) Predicate() predicate.Funcs {
+	isReferenced := func(namespace, name string) bool {
+		return s.IsReferenced(types.NamespacedName{Name: name, Namespace: namespace})
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isReferenced(e.Meta.GetNamespace(), e.Meta.GetName())
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !isReferenced(e.MetaNew.GetNamespace(), e.MetaNew.GetName()) {
+				return false
+			}
+			oldSecret, ok := e.ObjectOld.(*corev1.Secret)
+			if !ok {
+				return false
+			}
+			newSecret, ok := e.ObjectNew.(*corev1.Secret)
+			if !ok {
+				return false
+			}
+			return !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
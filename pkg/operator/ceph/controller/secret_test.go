@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestSecretReferences(t *testing.T) {
+	refs := NewSecretReferences()
+	owner1 := types.NamespacedName{Name: "owner1", Namespace: "ns"}
+	owner2 := types.NamespacedName{Name: "owner2", Namespace: "ns"}
+	secretName := types.NamespacedName{Name: "my-secret", Namespace: "ns"}
+	refs.Set(owner1, secretName)
+	refs.Set(owner2, secretName)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "my-secret", Namespace: "ns"}, Data: map[string][]byte{"key": []byte("a")}}
+	other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns"}}
+	requests := refs.ToRequests().Map(handler.MapObject{Meta: secret, Object: secret})
+	assert.ElementsMatch(t, []reconcile.Request{{NamespacedName: owner1}, {NamespacedName: owner2}}, requests)
+	assert.Empty(t, refs.ToRequests().Map(handler.MapObject{Meta: other, Object: other}))
+
+	// only the referenced secrets are watched
+	p := refs.Predicate()
+	assert.True(t, p.Create(event.CreateEvent{Meta: secret, Object: secret}))
+	assert.False(t, p.Create(event.CreateEvent{Meta: other, Object: other}))
+	changed := secret.DeepCopy()
+	changed.Data["key"] = []byte("b")
+	assert.True(t, p.Update(event.UpdateEvent{MetaOld: secret, ObjectOld: secret, MetaNew: changed, ObjectNew: changed}))
+	assert.False(t, p.Update(event.UpdateEvent{MetaOld: secret, ObjectOld: secret, MetaNew: secret, ObjectNew: secret}))
+
+	// the references are replaced
+	refs.Set(owner1)
+	requests = refs.ToRequests().Map(handler.MapObject{Meta: secret, Object: secret})
+	assert.Equal(t, []reconcile.Request{{NamespacedName: owner2}}, requests)
+	refs.Set(owner2, types.NamespacedName{Name: "other", Namespace: "ns"})
+	assert.False(t, refs.IsReferenced(secretName))
+	assert.True(t, p.Create(event.CreateEvent{Meta: other, Object: other}))
+}
@@ -35,7 +35,7 @@ func TestUpdateStatus(t *testing.T) {
 			Namespace:  "rook-ceph",
 			Finalizers: []string{},
 		},
-		Status: &cephv1.Status{
+		Status: &cephv1.CephBlockPoolStatus{
 			Phase: "",
 		},
 	}
@@ -29,8 +29,9 @@ func (o *Operator) startManager(namespaceToWatch string, stopCh <-chan struct{},
 	mgrErrorCh chan error) {
 	// Set up a manager
 	mgrOpts := manager.Options{
-		LeaderElection: false,
-		Namespace:      namespaceToWatch,
+		LeaderElection:     false,
+		Namespace:          namespaceToWatch,
+		MetricsBindAddress: MetricsBindAddress,
 	}
 
 	logger.Info("setting up the controller-runtime manager")
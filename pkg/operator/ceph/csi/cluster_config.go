@@ -33,11 +33,22 @@ import (
 
 var (
 	logger = capnslog.NewPackageLogger("github.com/rook/rook", "ceph-csi")
+
+	// ConfigMutex serializes the updates of the csi config map by the controllers
+	ConfigMutex = &sync.Mutex{}
 )
 
 type csiClusterConfigEntry struct {
 	ClusterID string   `json:"clusterID"`
 	Monitors  []string `json:"monitors"`
+	// Namespace is the namespace of the ceph cluster of the entries that do not have the namespace as cluster ID,
+	// so that their monitors are kept up to date with the cluster
+	Namespace string                `json:"namespace,omitempty"`
+	CephFS    *csiCephFSConfigEntry `json:"cephFS,omitempty"`
+}
+
+type csiCephFSConfigEntry struct {
+	SubvolumeGroup string `json:"subvolumeGroup,omitempty"`
 }
 
 type csiClusterConfig []csiClusterConfigEntry
@@ -106,7 +117,10 @@ func UpdateCsiClusterConfig(
 			centry.Monitors = monEndpoints(mons)
 			found = true
 			cc[i] = centry
-			break
+		} else if centry.Namespace == clusterKey {
+			// the entries of the subvolume groups of the cluster
+			centry.Monitors = monEndpoints(mons)
+			cc[i] = centry
 		}
 	}
 	if !found {
@@ -117,6 +131,53 @@ func UpdateCsiClusterConfig(
 	return formatCsiClusterConfig(cc)
 }
 
+// updateCsiSubVolumeGroupConfig returns a json-formatted string containing the csi cluster config with an entry
+// steering the cephfs volumes of the clusterID into the given subvolume group of the cluster
+func updateCsiSubVolumeGroupConfig(
+	curr, clusterNamespace, clusterID, subvolumeGroup string, mons map[string]*cephconfig.MonInfo) (string, error) {
+
+	cc, err := parseCsiClusterConfig(curr)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse current csi cluster config")
+	}
+
+	centry := csiClusterConfigEntry{
+		ClusterID: clusterID,
+		Monitors:  monEndpoints(mons),
+		Namespace: clusterNamespace,
+		CephFS:    &csiCephFSConfigEntry{SubvolumeGroup: subvolumeGroup},
+	}
+	found := false
+	for i := range cc {
+		if cc[i].ClusterID == clusterID {
+			cc[i] = centry
+			found = true
+			break
+		}
+	}
+	if !found {
+		cc = append(cc, centry)
+	}
+	return formatCsiClusterConfig(cc)
+}
+
+// removeCsiClusterConfigEntry returns a json-formatted string containing the csi cluster config without the
+// entry of the clusterID
+func removeCsiClusterConfigEntry(curr, clusterID string) (string, error) {
+	cc, err := parseCsiClusterConfig(curr)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse current csi cluster config")
+	}
+
+	newCC := csiClusterConfig{}
+	for _, centry := range cc {
+		if centry.ClusterID != clusterID {
+			newCC = append(newCC, centry)
+		}
+	}
+	return formatCsiClusterConfig(newCC)
+}
+
 // CreateCsiConfigMap creates an empty config map that will be later used
 // to provide cluster configuration to ceph-csi. If a config map already
 // exists, it will return it.
@@ -157,6 +218,39 @@ func SaveClusterConfig(
 	if !CSIEnabled() {
 		return nil
 	}
+	return updateCsiConfigMap(clientset, l, func(currData string) (string, error) {
+		return UpdateCsiClusterConfig(currData, clusterNamespace, clusterInfo.Monitors)
+	})
+}
+
+// SaveSubVolumeGroupClusterConfig updates the config map used to provide ceph-csi with the cluster configuration
+// so that the cephfs volumes of the storage classes with the given clusterID are created in the subvolume group
+// of the filesystem. The monitors of the entry are kept up to date with the ones of the cluster.
+func SaveSubVolumeGroupClusterConfig(
+	clientset kubernetes.Interface, clusterNamespace, clusterID, subvolumeGroup string,
+	clusterInfo *cephconfig.ClusterInfo, l sync.Locker) error {
+
+	if !CSIEnabled() {
+		return nil
+	}
+	return updateCsiConfigMap(clientset, l, func(currData string) (string, error) {
+		return updateCsiSubVolumeGroupConfig(currData, clusterNamespace, clusterID, subvolumeGroup, clusterInfo.Monitors)
+	})
+}
+
+// RemoveClusterConfig removes the entry of the clusterID from the config map used to provide ceph-csi with the
+// cluster configuration
+func RemoveClusterConfig(clientset kubernetes.Interface, clusterID string, l sync.Locker) error {
+	if !CSIEnabled() {
+		return nil
+	}
+	return updateCsiConfigMap(clientset, l, func(currData string) (string, error) {
+		return removeCsiClusterConfigEntry(currData, clusterID)
+	})
+}
+
+// updateCsiConfigMap updates the cluster configuration of the csi config map with the given update function
+func updateCsiConfigMap(clientset kubernetes.Interface, l sync.Locker, update func(currData string) (string, error)) error {
 	l.Lock()
 	defer l.Unlock()
 	// csi is deployed into the same namespace as the operator
@@ -173,13 +267,12 @@ func SaveClusterConfig(
 		return errors.Wrap(err, "failed to fetch current csi config map")
 	}
 
-	// update ConfigMap contents for current cluster
+	// update ConfigMap contents
 	currData := configMap.Data[ConfigKey]
 	if currData == "" {
 		currData = "[]"
 	}
-	newData, err := UpdateCsiClusterConfig(
-		currData, clusterNamespace, clusterInfo.Monitors)
+	newData, err := update(currData)
 	if err != nil {
 		return errors.Wrap(err, "failed to update csi config map data")
 	}
@@ -87,3 +87,46 @@ func TestUpdateCsiClusterConfig(t *testing.T) {
 	_, err = UpdateCsiClusterConfig("qqq", "beta", mons2)
 	assert.Error(t, err)
 }
+
+func TestUpdateCsiSubVolumeGroupConfig(t *testing.T) {
+	mons := map[string]*cephconfig.MonInfo{
+		"foo": {Name: "foo", Endpoint: "1.2.3.4:5000"},
+	}
+	s, err := UpdateCsiClusterConfig("[]", "alpha", mons)
+	assert.NoError(t, err)
+
+	// add a subvolume group of the cluster
+	s, err = updateCsiSubVolumeGroupConfig(s, "alpha", "group-a-id", "group-a", mons)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		`[{"clusterID":"alpha","monitors":["1.2.3.4:5000"]},{"clusterID":"group-a-id","monitors":["1.2.3.4:5000"],"namespace":"alpha","cephFS":{"subvolumeGroup":"group-a"}}]`,
+		s)
+
+	// updating the group again does not add another entry
+	s, err = updateCsiSubVolumeGroupConfig(s, "alpha", "group-a-id", "group-a", mons)
+	assert.NoError(t, err)
+	cc, err := parseCsiClusterConfig(s)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(cc))
+
+	// the monitors of the group follow the ones of the cluster
+	mons["bar"] = &cephconfig.MonInfo{Name: "bar", Endpoint: "10.11.12.13:5000"}
+	s, err = UpdateCsiClusterConfig(s, "alpha", mons)
+	assert.NoError(t, err)
+	cc, err = parseCsiClusterConfig(s)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(cc))
+	assert.Equal(t, "group-a-id", cc[1].ClusterID)
+	assert.Equal(t, "group-a", cc[1].CephFS.SubvolumeGroup)
+	assert.Contains(t, cc[1].Monitors, "10.11.12.13:5000")
+	assert.Equal(t, 2, len(cc[1].Monitors))
+
+	// remove the group
+	s, err = removeCsiClusterConfigEntry(s, "group-a-id")
+	assert.NoError(t, err)
+	cc, err = parseCsiClusterConfig(s)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(cc))
+	assert.Equal(t, "alpha", cc[0].ClusterID)
+	assert.Nil(t, cc[0].CephFS)
+}
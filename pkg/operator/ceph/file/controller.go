@@ -79,6 +79,12 @@ type ReconcileCephFilesystem struct {
 // Add creates a new CephFilesystem Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context) error {
+	// Keep the status of the filesystems current between two reconciles
+	refresher := opcontroller.NewStatusRefresher(controllerName, func() { refreshAllStatuses(context, mgr.GetClient()) })
+	if err := mgr.Add(refresher); err != nil {
+		return errors.Wrap(err, "failed to add filesystem status refresher")
+	}
+
 	return add(mgr, newReconciler(mgr, context))
 }
 
@@ -152,7 +158,7 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 
 	// The CR was just created, initializing status fields
 	if cephFilesystem.Status == nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.Created)
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil)
 	}
 
 	// Make sure a CephCluster is present otherwise do nothing
@@ -218,7 +224,7 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 	}
 
 	// validate the filesystem settings
-	if err := validateFilesystem(r.context, cephFilesystem); err != nil {
+	if err := ValidateFilesystem(r.context, cephFilesystem); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "invalid object filesystem %q arguments", cephFilesystem.Name)
 	}
 
@@ -226,12 +232,15 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 	logger.Debug("reconciling ceph filesystem store deployments")
 	reconcileResponse, err = r.reconcileCreateFilesystem(cephFilesystem)
 	if err != nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err)
 		return reconcileResponse, err
 	}
 
 	// Set Ready status, we are done reconciling
-	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, nil)
+
+	// Report the state of the pools as applied by Ceph
+	refreshStatus(r.context, r.client, cephFilesystem)
 
 	// Return and do not requeue
 	logger.Debug("done reconciling")
@@ -260,6 +269,12 @@ func (r *ReconcileCephFilesystem) reconcileCreateFilesystem(cephFilesystem *ceph
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create filesystem %q", cephFilesystem.Name)
 	}
 
+	applied, err := reconcileMirroring(r.context, r.clusterInfo, *cephFilesystem, *ref)
+	updateAppliedMirroring(r.client, types.NamespacedName{Namespace: cephFilesystem.Namespace, Name: cephFilesystem.Name}, applied)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to configure mirroring of filesystem %q", cephFilesystem.Name)
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -278,8 +293,8 @@ func (r *ReconcileCephFilesystem) reconcileDeleteFilesystem(cephFilesystem *ceph
 	return nil
 }
 
-// updateStatus updates an object with a given status
-func updateStatus(client client.Client, name types.NamespacedName, status string) {
+// updateStatus updates an object with a given status and the matching conditions
+func updateStatus(client client.Client, name types.NamespacedName, status string, reconcileErr error) {
 	fs := &cephv1.CephFilesystem{}
 	err := client.Get(context.TODO(), name, fs)
 	if err != nil {
@@ -292,10 +307,11 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 	}
 
 	if fs.Status == nil {
-		fs.Status = &cephv1.Status{}
+		fs.Status = &cephv1.CephFilesystemStatus{}
 	}
 
 	fs.Status.Phase = status
+	opcontroller.SetPhaseConditions(&fs.Status.Conditions, status, reconcileErr)
 	if err := opcontroller.UpdateStatus(client, fs); err != nil {
 		logger.Errorf("failed to set filesystem %q status to %q. %v", fs.Name, status, err)
 		return
@@ -133,7 +133,9 @@ func deleteFilesystem(
 	return nil
 }
 
-func validateFilesystem(context *clusterd.Context, f *cephv1.CephFilesystem) error {
+// ValidateFilesystem validates the filesystem arguments. If the context is nil, the settings requiring the cluster,
+// such as the crush root of the pools, are not validated.
+func ValidateFilesystem(context *clusterd.Context, f *cephv1.CephFilesystem) error {
 	if f.Name == "" {
 		return errors.New("missing name")
 	}
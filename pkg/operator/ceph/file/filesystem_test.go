@@ -45,28 +45,28 @@ func TestValidateSpec(t *testing.T) {
 	fs := &cephv1.CephFilesystem{}
 
 	// missing name
-	assert.NotNil(t, validateFilesystem(context, fs))
+	assert.NotNil(t, ValidateFilesystem(context, fs))
 	fs.Name = "myfs"
 
 	// missing namespace
-	assert.NotNil(t, validateFilesystem(context, fs))
+	assert.NotNil(t, ValidateFilesystem(context, fs))
 	fs.Namespace = "myns"
 
 	// missing data pools
-	assert.NotNil(t, validateFilesystem(context, fs))
+	assert.NotNil(t, ValidateFilesystem(context, fs))
 	p := cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 1, RequireSafeReplicaSize: false}}
 	fs.Spec.DataPools = append(fs.Spec.DataPools, p)
 
 	// missing metadata pool
-	assert.NotNil(t, validateFilesystem(context, fs))
+	assert.NotNil(t, ValidateFilesystem(context, fs))
 	fs.Spec.MetadataPool = p
 
 	// missing mds count
-	assert.NotNil(t, validateFilesystem(context, fs))
+	assert.NotNil(t, ValidateFilesystem(context, fs))
 	fs.Spec.MetadataServer.ActiveCount = 1
 
 	// valid!
-	assert.Nil(t, validateFilesystem(context, fs))
+	assert.Nil(t, ValidateFilesystem(context, fs))
 }
 
 func TestCreateFilesystem(t *testing.T) {
@@ -79,6 +79,9 @@ func TestCreateFilesystem(t *testing.T) {
 
 	executor := &exectest.MockExecutor{
 		MockExecuteCommandWithOutputFile: func(command string, outFileArg string, args ...string) (string, error) {
+			if contains(args, "detail") {
+				return "[]", nil
+			}
 			return "{\"key\":\"mysecurekey\"}", nil
 		},
 		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
@@ -157,6 +160,9 @@ func TestCreateNopoolFilesystem(t *testing.T) {
 
 	executor := &exectest.MockExecutor{
 		MockExecuteCommandWithOutputFile: func(command string, outFileArg string, args ...string) (string, error) {
+			if contains(args, "detail") {
+				return "[]", nil
+			}
 			return "{\"key\":\"mysecurekey\"}", nil
 		},
 		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
@@ -0,0 +1,278 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephconfig "github.com/rook/rook/pkg/daemon/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// bootstrapPeerTokenKey is the key of the bootstrap peer token in the peer Secrets
+	bootstrapPeerTokenKey = "token"
+	// snapshotRootPath is the path of the snapshot schedules and retentions without a path
+	snapshotRootPath = "/"
+)
+
+// bootstrapPeerSecretName returns the name of the Secret with the bootstrap peer token of a filesystem
+func bootstrapPeerSecretName(fsName string) string {
+	return fmt.Sprintf("fs-peer-token-%s", fsName)
+}
+
+// mirroringEnabled returns whether the snapshot mirroring of a filesystem is enabled in its spec
+func mirroringEnabled(fs cephv1.CephFilesystem) bool {
+	return fs.Spec.Mirroring != nil && fs.Spec.Mirroring.Enabled
+}
+
+// reconcileMirroring enables the snapshot mirroring of a filesystem, imports its peers and schedules the snapshots
+// of the mirrored directories. The peers not in the spec are removed, as well as the directories, snapshot schedules
+// and retention applied before and removed from the spec. Mirroring is disabled if it is not in the spec but is still
+// enabled in Ceph. The mirroring settings applied to Ceph are returned, nil if mirroring is disabled.
+func reconcileMirroring(context *clusterd.Context, clusterInfo *cephconfig.ClusterInfo, fs cephv1.CephFilesystem, ownerRef metav1.OwnerReference) (*cephv1.FSMirroringSpec, error) {
+	var applied *cephv1.FSMirroringSpec
+	if fs.Status != nil {
+		applied = fs.Status.AppliedMirroring
+	}
+
+	if !mirroringEnabled(fs) {
+		// The status is refreshed from the spec, only Ceph knows if the mirroring was enabled before
+		details, err := cephclient.GetFilesystem(context, fs.Namespace, fs.Name)
+		if err != nil {
+			return applied, err
+		}
+		if err := removeMirroredDirectories(context, fs, applied, &cephv1.FSMirroringSpec{}); err != nil {
+			return applied, err
+		}
+		if details.MirrorInfo != nil {
+			if err := disableMirroring(context, fs); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+
+	if !clusterInfo.CephVersion.IsAtLeastPacific() {
+		return applied, errors.Errorf("filesystem mirroring requires ceph pacific or newer, current version is %q", clusterInfo.CephVersion.String())
+	}
+
+	for _, module := range []string{cephclient.FilesystemMirroringModuleName, cephclient.SnapshotScheduleModuleName} {
+		if err := cephclient.MgrEnableModule(context, fs.Namespace, module, false); err != nil {
+			return applied, errors.Wrapf(err, "failed to enable mgr module %q", module)
+		}
+	}
+
+	if err := cephclient.EnableFilesystemSnapshotMirror(context, fs.Namespace, fs.Name); err != nil {
+		return applied, err
+	}
+
+	mirroring := fs.Spec.Mirroring
+	if err := reconcilePeers(context, fs); err != nil {
+		return applied, err
+	}
+
+	if err := removeMirroredDirectories(context, fs, applied, mirroring); err != nil {
+		return applied, err
+	}
+
+	for _, path := range mirroring.Directories {
+		if err := cephclient.AddFSMirrorDirectory(context, fs.Namespace, fs.Name, path); err != nil {
+			return applied, err
+		}
+	}
+
+	for _, schedule := range mirroring.SnapshotSchedules {
+		if err := cephclient.AddSnapshotSchedule(context, fs.Namespace, fs.Name, snapshotPath(schedule.Path), schedule.Interval, schedule.StartTime); err != nil {
+			return applied, err
+		}
+	}
+
+	for _, retention := range mirroring.SnapshotRetention {
+		if err := cephclient.AddSnapshotScheduleRetention(context, fs.Namespace, fs.Name, snapshotPath(retention.Path), retention.Duration); err != nil {
+			return applied, err
+		}
+	}
+	applied = &cephv1.FSMirroringSpec{
+		Enabled:           true,
+		Directories:       mirroring.Directories,
+		SnapshotSchedules: mirroring.SnapshotSchedules,
+		SnapshotRetention: mirroring.SnapshotRetention,
+	}
+
+	// Expose the bootstrap peer token of the local site for the remote sites to import it
+	token, err := cephclient.CreateFSMirrorBootstrapPeer(context, fs.Namespace, fs.Name, clusterInfo.FSID)
+	if err != nil {
+		return applied, err
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapPeerSecretName(fs.Name),
+			Namespace: fs.Namespace,
+		},
+		StringData: map[string]string{
+			bootstrapPeerTokenKey: token,
+		},
+		Type: k8sutil.RookType,
+	}
+	k8sutil.SetOwnerRef(&secret.ObjectMeta, &ownerRef)
+	if err := keyring.GetSecretStore(context, fs.Namespace, &ownerRef).CreateSecret(secret); err != nil {
+		return applied, errors.Wrapf(err, "failed to save bootstrap peer token of filesystem %q", fs.Name)
+	}
+
+	return applied, nil
+}
+
+// reconcilePeers imports the peers of the spec and removes the peers listed by Ceph whose site and filesystem are
+// not in the spec
+func reconcilePeers(context *clusterd.Context, fs cephv1.CephFilesystem) error {
+	details, err := cephclient.GetFilesystem(context, fs.Namespace, fs.Name)
+	if err != nil {
+		return err
+	}
+
+	peers := map[string]bool{}
+	removePeers := true
+	if fs.Spec.Mirroring.Peers != nil {
+		for _, secretName := range fs.Spec.Mirroring.Peers.SecretNames {
+			token, err := bootstrapPeerToken(context, fs.Namespace, secretName)
+			if err != nil {
+				return err
+			}
+			if err := cephclient.ImportFSMirrorBootstrapPeer(context, fs.Namespace, fs.Name, token); err != nil {
+				return errors.Wrapf(err, "failed to import peer from secret %q", secretName)
+			}
+			peer, err := cephclient.DecodeFSMirrorBootstrapPeer(token)
+			if err != nil {
+				// Without the site of every peer of the spec, a peer of the spec could be removed
+				logger.Warningf("not removing the peers of filesystem %q missing from the spec, failed to read the peer of secret %q. %v", fs.Name, secretName, err)
+				removePeers = false
+				continue
+			}
+			peers[peer.SiteName+"/"+peer.FilesystemName] = true
+		}
+	}
+
+	if !removePeers || details.MirrorInfo == nil {
+		return nil
+	}
+	for _, peer := range details.MirrorInfo.Peers {
+		if peers[peer.Remote.ClusterName+"/"+peer.Remote.FsName] {
+			continue
+		}
+		if err := cephclient.RemoveFSMirrorPeer(context, fs.Namespace, fs.Name, peer.UUID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeMirroredDirectories removes the mirrored directories, snapshot schedules and retention that were applied and
+// are not in the spec anymore
+func removeMirroredDirectories(context *clusterd.Context, fs cephv1.CephFilesystem, applied, spec *cephv1.FSMirroringSpec) error {
+	if applied == nil {
+		return nil
+	}
+
+	directories := map[string]bool{}
+	for _, path := range spec.Directories {
+		directories[path] = true
+	}
+	for _, path := range applied.Directories {
+		if !directories[path] {
+			if err := cephclient.RemoveFSMirrorDirectory(context, fs.Namespace, fs.Name, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	schedules := map[cephv1.SnapshotScheduleSpec]bool{}
+	for _, schedule := range spec.SnapshotSchedules {
+		schedule.Path = snapshotPath(schedule.Path)
+		schedules[schedule] = true
+	}
+	for _, schedule := range applied.SnapshotSchedules {
+		schedule.Path = snapshotPath(schedule.Path)
+		if !schedules[schedule] {
+			if err := cephclient.RemoveSnapshotSchedule(context, fs.Namespace, fs.Name, schedule.Path, schedule.Interval, schedule.StartTime); err != nil {
+				return err
+			}
+		}
+	}
+
+	retentions := map[cephv1.SnapshotScheduleRetentionSpec]bool{}
+	for _, retention := range spec.SnapshotRetention {
+		retention.Path = snapshotPath(retention.Path)
+		retentions[retention] = true
+	}
+	for _, retention := range applied.SnapshotRetention {
+		retention.Path = snapshotPath(retention.Path)
+		if !retentions[retention] {
+			if err := cephclient.RemoveSnapshotScheduleRetention(context, fs.Namespace, fs.Name, retention.Path, retention.Duration); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// snapshotPath returns the path of a snapshot schedule or retention, the root of the filesystem if empty
+func snapshotPath(path string) string {
+	if path == "" {
+		return snapshotRootPath
+	}
+	return path
+}
+
+// disableMirroring disables the snapshot mirroring of a filesystem and removes its bootstrap peer token
+func disableMirroring(context *clusterd.Context, fs cephv1.CephFilesystem) error {
+	if err := cephclient.DisableFilesystemSnapshotMirror(context, fs.Namespace, fs.Name); err != nil {
+		return err
+	}
+
+	secretName := bootstrapPeerSecretName(fs.Name)
+	err := context.Clientset.CoreV1().Secrets(fs.Namespace).Delete(secretName, &metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete bootstrap peer secret %q", secretName)
+	}
+
+	return nil
+}
+
+// bootstrapPeerToken returns the bootstrap peer token stored in a Secret
+func bootstrapPeerToken(context *clusterd.Context, namespace, secretName string) (string, error) {
+	secret, err := context.Clientset.CoreV1().Secrets(namespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get peer secret %q", secretName)
+	}
+
+	token, ok := secret.Data[bootstrapPeerTokenKey]
+	if !ok || len(token) == 0 {
+		return "", errors.Errorf("peer secret %q has no %q key", secretName, bootstrapPeerTokenKey)
+	}
+
+	return string(token), nil
+}
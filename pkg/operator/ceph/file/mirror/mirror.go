@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror
+
+import (
+	"fmt"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// AppName is the ceph filesystem mirror application name
+	AppName = "rook-ceph-fs-mirror"
+	// daemonID is the ID of the single cephfs-mirror daemon of a cluster
+	daemonID = "a"
+	// minimum amount of memory in MB to run the pod
+	cephFilesystemMirrorPodMinimumMemory uint64 = 512
+)
+
+var updateDeploymentAndWait = mon.UpdateCephDeploymentAndWait
+
+// start runs the cephfs-mirror daemon
+func (r *ReconcileFilesystemMirror) start(fsMirror *cephv1.CephFilesystemMirror) error {
+	// Validate pod's memory if specified
+	err := opcontroller.CheckPodMemory(fsMirror.Spec.Resources, cephFilesystemMirrorPodMinimumMemory)
+	if err != nil {
+		return errors.Wrap(err, "error checking pod memory")
+	}
+
+	// Create the controller owner ref
+	// It will be associated to all resources of the CephFilesystemMirror
+	ref, err := opcontroller.GetControllerObjectOwnerReference(fsMirror, r.scheme)
+	if err != nil || ref == nil {
+		return errors.Wrapf(err, "failed to get controller %q owner reference", fsMirror.Name)
+	}
+
+	resourceName := fmt.Sprintf("%s-%s", AppName, daemonID)
+	daemonConf := &daemonConfig{
+		DaemonID:     daemonID,
+		ResourceName: resourceName,
+		DataPathMap:  config.NewDatalessDaemonDataPathMap(fsMirror.Namespace, r.cephClusterSpec.DataDirHostPath),
+		ownerRef:     *ref,
+		namespace:    fsMirror.Namespace,
+	}
+
+	_, err = r.generateKeyring(daemonConf)
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate keyring for %q", resourceName)
+	}
+
+	// Start the deployment
+	d := r.makeDeployment(daemonConf, fsMirror)
+
+	// Set owner ref to the CephFilesystemMirror object
+	err = controllerutil.SetControllerReference(fsMirror, d, r.scheme)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set owner reference for ceph filesystem mirror deployment %q", d.Name)
+	}
+
+	// Set the deployment hash as an annotation
+	err = patch.DefaultAnnotator.SetLastAppliedAnnotation(d)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set annotation for deployment %q", d.Name)
+	}
+
+	if _, err := r.context.Clientset.AppsV1().Deployments(fsMirror.Namespace).Create(d); err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create %q deployment", resourceName)
+		}
+		logger.Infof("deployment for filesystem mirror %q already exists. updating if needed", resourceName)
+
+		if err := updateDeploymentAndWait(r.context, d, fsMirror.Namespace, config.FilesystemMirrorType, daemonConf.DaemonID, r.cephClusterSpec.SkipUpgradeChecks, false); err != nil {
+			// fail could be an issue updating label selector (immutable), so try del and recreate
+			logger.Debugf("updateDeploymentAndWait failed for filesystem mirror %q. Attempting del-and-recreate. %v", resourceName, err)
+			err = r.context.Clientset.AppsV1().Deployments(fsMirror.Namespace).Delete(resourceName, &metav1.DeleteOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "failed to delete filesystem mirror %q during del-and-recreate update attempt", resourceName)
+			}
+			if _, err := r.context.Clientset.AppsV1().Deployments(fsMirror.Namespace).Create(d); err != nil {
+				return errors.Wrapf(err, "failed to recreate filesystem mirror deployment %q during del-and-recreate update attempt", resourceName)
+			}
+		}
+	}
+
+	logger.Infof("%q deployment started", resourceName)
+	return nil
+}
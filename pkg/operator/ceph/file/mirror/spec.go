@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror
+
+import (
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (r *ReconcileFilesystemMirror) makeDeployment(daemonConfig *daemonConfig, fsMirror *cephv1.CephFilesystemMirror) *apps.Deployment {
+	podSpec := v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   daemonConfig.ResourceName,
+			Labels: controller.PodLabels(AppName, fsMirror.Namespace, config.FilesystemMirrorType, daemonConfig.DaemonID),
+		},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				r.makeChownInitContainer(daemonConfig, fsMirror),
+			},
+			Containers: []v1.Container{
+				r.makeMirroringDaemonContainer(daemonConfig, fsMirror),
+			},
+			RestartPolicy:     v1.RestartPolicyAlways,
+			Volumes:           controller.DaemonVolumes(daemonConfig.DataPathMap, daemonConfig.ResourceName),
+			HostNetwork:       r.cephClusterSpec.Network.IsHost(),
+			PriorityClassName: fsMirror.Spec.PriorityClassName,
+		},
+	}
+	// Replace default unreachable node toleration
+	k8sutil.AddUnreachableNodeToleration(&podSpec.Spec)
+
+	if r.cephClusterSpec.Network.IsHost() {
+		podSpec.Spec.DNSPolicy = v1.DNSClusterFirstWithHostNet
+	} else if r.cephClusterSpec.Network.IsMultus() {
+		k8sutil.ApplyMultus(r.cephClusterSpec.Network.NetworkSpec, &podSpec.ObjectMeta)
+	}
+	fsMirror.Spec.Placement.ApplyToPodSpec(&podSpec.Spec)
+
+	replicas := int32(1)
+	d := &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        daemonConfig.ResourceName,
+			Namespace:   fsMirror.Namespace,
+			Annotations: fsMirror.Spec.Annotations,
+			Labels:      controller.PodLabels(AppName, fsMirror.Namespace, config.FilesystemMirrorType, daemonConfig.DaemonID),
+		},
+		Spec: apps.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: podSpec.Labels,
+			},
+			Template: podSpec,
+			Replicas: &replicas,
+		},
+	}
+	k8sutil.AddRookVersionLabelToDeployment(d)
+	controller.AddCephVersionLabelToDeployment(r.clusterInfo.CephVersion, d)
+
+	return d
+}
+
+func (r *ReconcileFilesystemMirror) makeChownInitContainer(daemonConfig *daemonConfig, fsMirror *cephv1.CephFilesystemMirror) v1.Container {
+	return controller.ChownCephDataDirsInitContainer(
+		*daemonConfig.DataPathMap,
+		r.cephClusterSpec.CephVersion.Image,
+		controller.DaemonVolumeMounts(daemonConfig.DataPathMap, daemonConfig.ResourceName),
+		fsMirror.Spec.Resources,
+		mon.PodSecurityContext(),
+	)
+}
+
+func (r *ReconcileFilesystemMirror) makeMirroringDaemonContainer(daemonConfig *daemonConfig, fsMirror *cephv1.CephFilesystemMirror) v1.Container {
+	container := v1.Container{
+		Name: "cephfs-mirror",
+		Command: []string{
+			"cephfs-mirror",
+		},
+		Args: append(
+			controller.DaemonFlags(r.clusterInfo, daemonConfig.DaemonID),
+			"--foreground",
+			"--name="+fullDaemonName(daemonConfig.DaemonID),
+		),
+		Image:           r.cephClusterSpec.CephVersion.Image,
+		VolumeMounts:    controller.DaemonVolumeMounts(daemonConfig.DataPathMap, daemonConfig.ResourceName),
+		Env:             controller.DaemonEnvVars(r.cephClusterSpec.CephVersion.Image),
+		Resources:       fsMirror.Spec.Resources,
+		SecurityContext: mon.PodSecurityContext(),
+	}
+	return container
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephconfig "github.com/rook/rook/pkg/daemon/ceph/config"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileMirroring(t *testing.T) {
+	namespace := "rook-ceph"
+	commands := []string{}
+	fsGet := `{"mdsmap":{"fs_name":"myfs"}}`
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			i := 0
+			for i < len(args) && !strings.HasPrefix(args[i], "--") {
+				i++
+			}
+			commands = append(commands, strings.Join(args[:i], " "))
+			if strings.Join(args[:2], " ") == "fs get" {
+				return fsGet, nil
+			}
+			if strings.Join(args[:5], " ") == "fs snapshot mirror peer_bootstrap create" {
+				return `{"token":"local-token"}`, nil
+			}
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{Executor: executor, Clientset: test.New(t, 1)}
+	clusterInfo := &cephconfig.ClusterInfo{FSID: "local-fsid", CephVersion: cephver.Pacific}
+	ownerRef := metav1.OwnerReference{Name: "myfs"}
+	fs := cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: namespace},
+		Spec: cephv1.FilesystemSpec{
+			Mirroring: &cephv1.FSMirroringSpec{
+				Enabled:           true,
+				Peers:             &cephv1.MirroringPeerSpec{SecretNames: []string{"peer-a"}},
+				Directories:       []string{"/volumes"},
+				SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "24h"}},
+				SnapshotRetention: []cephv1.SnapshotScheduleRetentionSpec{{Path: "/volumes", Duration: "7d"}},
+			},
+		},
+	}
+
+	// the peer secret does not exist
+	_, err := reconcileMirroring(c, clusterInfo, fs, ownerRef)
+	assert.Error(t, err)
+
+	remoteToken := base64.StdEncoding.EncodeToString([]byte(`{"site_name":"remote-site","filesystem":"myfs"}`))
+	peer := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "peer-a", Namespace: namespace},
+		Data:       map[string][]byte{"token": []byte(remoteToken)},
+	}
+	_, err = c.Clientset.CoreV1().Secrets(namespace).Create(peer)
+	assert.NoError(t, err)
+
+	commands = []string{}
+	applied, err := reconcileMirroring(c, clusterInfo, fs, ownerRef)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"mgr module enable mirroring",
+		"mgr module enable snap_schedule",
+		"fs snapshot mirror enable myfs",
+		"fs get myfs",
+		"fs snapshot mirror peer_bootstrap import myfs " + remoteToken,
+		"fs snapshot mirror add myfs /volumes",
+		"fs snap-schedule add / 24h",
+		"fs snap-schedule retention add /volumes 7d",
+		"fs snapshot mirror peer_bootstrap create myfs client.mirror_remote local-fsid",
+	}, commands)
+	assert.Equal(t, &cephv1.FSMirroringSpec{
+		Enabled:           true,
+		Directories:       []string{"/volumes"},
+		SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "24h"}},
+		SnapshotRetention: []cephv1.SnapshotScheduleRetentionSpec{{Path: "/volumes", Duration: "7d"}},
+	}, applied)
+	secret, err := c.Clientset.CoreV1().Secrets(namespace).Get(bootstrapPeerSecretName("myfs"), metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "local-token", secret.StringData["token"])
+
+	// the peers, directories, schedules and retention removed from the spec are removed from ceph
+	fsGet = `{"mirror_info":{"peers":[
+		{"uuid":"uuid-a","remote":{"client_name":"client.mirror_remote","cluster_name":"remote-site","fs_name":"myfs"}},
+		{"uuid":"uuid-b","remote":{"client_name":"client.mirror_remote","cluster_name":"old-site","fs_name":"myfs"}}]}}`
+	fs.Status = &cephv1.CephFilesystemStatus{AppliedMirroring: applied}
+	fs.Spec.Mirroring = &cephv1.FSMirroringSpec{
+		Enabled:           true,
+		Peers:             &cephv1.MirroringPeerSpec{SecretNames: []string{"peer-a"}},
+		Directories:       []string{"/data"},
+		SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Path: "/", Interval: "24h"}},
+	}
+	commands = []string{}
+	applied, err = reconcileMirroring(c, clusterInfo, fs, ownerRef)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"mgr module enable mirroring",
+		"mgr module enable snap_schedule",
+		"fs snapshot mirror enable myfs",
+		"fs get myfs",
+		"fs snapshot mirror peer_bootstrap import myfs " + remoteToken,
+		"fs snapshot mirror peer_remove myfs uuid-b",
+		"fs snapshot mirror remove myfs /volumes",
+		"fs snap-schedule retention remove /volumes 7d",
+		"fs snapshot mirror add myfs /data",
+		"fs snap-schedule add / 24h",
+		"fs snapshot mirror peer_bootstrap create myfs client.mirror_remote local-fsid",
+	}, commands)
+	assert.Equal(t, []string{"/data"}, applied.Directories)
+
+	// the peers are not removed if the token of a peer cannot be read
+	peer.Data["token"] = []byte("invalid-token")
+	_, err = c.Clientset.CoreV1().Secrets(namespace).Update(peer)
+	assert.NoError(t, err)
+	commands = []string{}
+	_, err = reconcileMirroring(c, clusterInfo, fs, ownerRef)
+	assert.NoError(t, err)
+	assert.NotContains(t, commands, "fs snapshot mirror peer_remove myfs uuid-b")
+
+	// mirroring is not supported before pacific
+	clusterInfo.CephVersion = cephver.Octopus
+	_, err = reconcileMirroring(c, clusterInfo, fs, ownerRef)
+	assert.Error(t, err)
+
+	// mirroring is disabled once removed from the spec if ceph reports it enabled
+	fs.Status = &cephv1.CephFilesystemStatus{AppliedMirroring: applied}
+	fs.Spec.Mirroring = nil
+	commands = []string{}
+	applied, err = reconcileMirroring(c, clusterInfo, fs, ownerRef)
+	assert.NoError(t, err)
+	assert.Nil(t, applied)
+	assert.Equal(t, []string{
+		"fs get myfs",
+		"fs snapshot mirror remove myfs /data",
+		"fs snap-schedule remove / 24h",
+		"fs snapshot mirror disable myfs",
+	}, commands)
+	_, err = c.Clientset.CoreV1().Secrets(namespace).Get(bootstrapPeerSecretName("myfs"), metav1.GetOptions{})
+	assert.Error(t, err)
+
+	// nothing to do once mirroring is disabled in ceph
+	fs.Status = nil
+	fsGet = `{"mdsmap":{"fs_name":"myfs"}}`
+	commands = []string{}
+	_, err = reconcileMirroring(c, clusterInfo, fs, ownerRef)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs get myfs"}, commands)
+}
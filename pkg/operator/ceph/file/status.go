@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/pool"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// poolNames returns the names of all the filesystem pools, the metadata pool first, and the names of the pools
+// that have quotas in their spec
+func poolNames(cephFilesystem *cephv1.CephFilesystem) ([]string, []string) {
+	f := newFS(cephFilesystem.Name, cephFilesystem.Namespace)
+	metadataPoolName := generateMetaDataPoolName(f)
+	names := []string{metadataPoolName}
+	var quotaNames []string
+	if cephFilesystem.Spec.MetadataPool.Quotas.IsEnabled() {
+		quotaNames = append(quotaNames, metadataPoolName)
+	}
+	dataPoolNames := generateDataPoolNames(f, cephFilesystem.Spec)
+	for i, dataPool := range cephFilesystem.Spec.DataPools {
+		names = append(names, dataPoolNames[i])
+		if dataPool.Quotas.IsEnabled() {
+			quotaNames = append(quotaNames, dataPoolNames[i])
+		}
+	}
+	return names, quotaNames
+}
+
+// dataPoolSpecs returns the specs of the data pools of a filesystem indexed by pool name, whose erasure code settings
+// are compared with the profile of the pools
+func dataPoolSpecs(cephFilesystem *cephv1.CephFilesystem) map[string]cephv1.PoolSpec {
+	f := newFS(cephFilesystem.Name, cephFilesystem.Namespace)
+	specs := map[string]cephv1.PoolSpec{}
+	for i, name := range generateDataPoolNames(f, cephFilesystem.Spec) {
+		specs[name] = cephFilesystem.Spec.DataPools[i]
+	}
+	return specs
+}
+
+// refreshStatus updates a filesystem CR with the state of its pools, their usage against their quotas and the
+// bootstrap peer secret of the mirrored filesystems
+func refreshStatus(clusterdContext *clusterd.Context, c client.Client, cephFilesystem *cephv1.CephFilesystem) {
+	name := types.NamespacedName{Name: cephFilesystem.Name, Namespace: cephFilesystem.Namespace}
+	names, quotaNames := poolNames(cephFilesystem)
+	pools, err := pool.GetPoolStatuses(clusterdContext, name.Namespace, names, dataPoolSpecs(cephFilesystem))
+	if err != nil {
+		logger.Warningf("failed to get the state of filesystem %q pools. %v", name, err)
+		return
+	}
+
+	var quotas map[string]cephv1.PoolQuotaStatus
+	if len(quotaNames) > 0 {
+		quotas, err = pool.GetQuotaStatuses(clusterdContext, name.Namespace, quotaNames)
+		if err != nil {
+			logger.Warningf("failed to get quota status of filesystem %q pools. %v", name, err)
+			return
+		}
+	}
+
+	fs := &cephv1.CephFilesystem{}
+	if err := c.Get(context.TODO(), name, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve filesystem %q to refresh its status. %v", name, err)
+		return
+	}
+
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+
+	fs.Status.Pools = pools
+	fs.Status.PoolQuotas = quotas
+	fs.Status.Mirroring = nil
+	if mirroringEnabled(*cephFilesystem) {
+		fs.Status.Mirroring = &cephv1.MirroringInfo{BootstrapPeerSecretName: bootstrapPeerSecretName(name.Name)}
+	}
+	fs.Status.LastChecked = opcontroller.FormatStatusTime(time.Now().UTC())
+	if err := opcontroller.UpdateStatus(c, fs); err != nil {
+		logger.Errorf("failed to refresh status of filesystem %q. %v", name, err)
+		return
+	}
+	logger.Debugf("filesystem %q status refreshed", name)
+}
+
+// updateAppliedMirroring records the mirroring settings applied to Ceph in the status of a filesystem
+func updateAppliedMirroring(c client.Client, name types.NamespacedName, applied *cephv1.FSMirroringSpec) {
+	fs := &cephv1.CephFilesystem{}
+	if err := c.Get(context.TODO(), name, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve filesystem %q to record its applied mirroring. %v", name, err)
+		return
+	}
+
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+
+	fs.Status.AppliedMirroring = applied
+	if err := opcontroller.UpdateStatus(c, fs); err != nil {
+		logger.Errorf("failed to record applied mirroring of filesystem %q. %v", name, err)
+		return
+	}
+	logger.Debugf("filesystem %q applied mirroring recorded", name)
+}
+
+// refreshAllStatuses refreshes the status of all the ready filesystem CRs
+func refreshAllStatuses(clusterdContext *clusterd.Context, c client.Client) {
+	filesystems := &cephv1.CephFilesystemList{}
+	if err := c.List(context.TODO(), filesystems); err != nil {
+		logger.Warningf("failed to list filesystems to refresh their status. %v", err)
+		return
+	}
+
+	for i := range filesystems.Items {
+		fs := &filesystems.Items[i]
+		if !fs.GetDeletionTimestamp().IsZero() || fs.Status == nil || fs.Status.Phase != k8sutil.ReadyStatus {
+			continue
+		}
+		name := types.NamespacedName{Name: fs.Name, Namespace: fs.Namespace}
+		if _, isReadyToReconcile, _, _ := opcontroller.IsReadyToReconcile(c, clusterdContext, name, controllerName); !isReadyToReconcile {
+			continue
+		}
+		refreshStatus(clusterdContext, c, fs)
+	}
+}
@@ -0,0 +1,441 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package subvolumegroup to manage the subvolume groups of a rook filesystem.
+package subvolumegroup
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-fs-subvolumegroup-controller"
+)
+
+// the settings removing the pin of each pinning policy
+var unpinSettings = map[string]string{"export": "-1", "distributed": "0", "random": "0"}
+
+var waitForRequeueIfFilesystemNotReady = reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephFilesystemSubVolumeGroupKind = reflect.TypeOf(cephv1.CephFilesystemSubVolumeGroup{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephFilesystemSubVolumeGroupKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+var _ reconcile.Reconciler = &ReconcileCephFilesystemSubVolumeGroup{}
+
+// ReconcileCephFilesystemSubVolumeGroup reconciles a CephFilesystemSubVolumeGroup object
+type ReconcileCephFilesystemSubVolumeGroup struct {
+	client  client.Client
+	scheme  *runtime.Scheme
+	context *clusterd.Context
+}
+
+// Add creates a new CephFilesystemSubVolumeGroup Controller and adds it to the Manager. The Manager will set fields on
+// the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Reconciler {
+	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
+	mgrScheme := mgr.GetScheme()
+	cephv1.AddToScheme(mgr.GetScheme())
+
+	return &ReconcileCephFilesystemSubVolumeGroup{
+		client:  mgr.GetClient(),
+		scheme:  mgrScheme,
+		context: context,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephFilesystemSubVolumeGroup CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephFilesystemSubVolumeGroup{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephFilesystemSubVolumeGroup object and makes changes based on the
+// state read and what is in the CephFilesystemSubVolumeGroup.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephFilesystemSubVolumeGroup) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephFilesystemSubVolumeGroup) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephFilesystemSubVolumeGroup instance
+	group := &cephv1.CephFilesystemSubVolumeGroup{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, group)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystemSubVolumeGroup resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephFilesystemSubVolumeGroup")
+	}
+
+	// The CR was just created, initializing status fields
+	if group.Status == nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil, nil)
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// We skip the deleteSubVolumeGroup() function since everything is gone already
+		//
+		// Also, only remove the finalizer if the CephCluster is gone
+		// If not, we should wait for it to be ready
+		// This handles the case where the operator is not ready to accept Ceph command but the cluster exists
+		if !group.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.client, group)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, group)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// DELETE: the CR was deleted
+	if !group.GetDeletionTimestamp().IsZero() {
+		logger.Debugf("deleting subvolume group %q", request.NamespacedName)
+		reconcileResponse, err := r.deleteSubVolumeGroup(group)
+		if err != nil {
+			return reconcileResponse, errors.Wrapf(err, "failed to delete subvolume group %q", request.NamespacedName)
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, group)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the subvolume group settings
+	if err := validateSubVolumeGroup(group); err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid subvolume group CR %q spec", request.NamespacedName)
+	}
+
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReconcilingStatus, nil, nil)
+
+	// The filesystem of the group must be ready
+	reconcileResponse, err = r.checkFilesystem(group)
+	if err != nil {
+		logger.Infof("waiting for the filesystem of subvolume group %q. %v", request.NamespacedName, err)
+		return reconcileResponse, nil
+	}
+
+	// CREATE/UPDATE
+	groupStatus, err := r.createSubVolumeGroup(group)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to create subvolume group %q", request.NamespacedName)
+	}
+
+	// Set Ready status, we are done reconciling
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, nil, groupStatus)
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// checkFilesystem checks that the CephFilesystem of the group exists and is ready
+func (r *ReconcileCephFilesystemSubVolumeGroup) checkFilesystem(group *cephv1.CephFilesystemSubVolumeGroup) (reconcile.Result, error) {
+	fs := &cephv1.CephFilesystem{}
+	fsName := types.NamespacedName{Name: group.Spec.FilesystemName, Namespace: group.Namespace}
+	err := r.client.Get(context.TODO(), fsName, fs)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return waitForRequeueIfFilesystemNotReady, errors.Errorf("filesystem %q not found", fsName)
+		}
+		return waitForRequeueIfFilesystemNotReady, errors.Wrapf(err, "failed to get filesystem %q", fsName)
+	}
+	if fs.Status == nil || fs.Status.Phase != k8sutil.ReadyStatus {
+		return waitForRequeueIfFilesystemNotReady, errors.Errorf("filesystem %q is not ready", fsName)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// createSubVolumeGroup creates the group, applies its quota and pinning, and makes it available to the CSI driver.
+// It returns the status of the group.
+func (r *ReconcileCephFilesystemSubVolumeGroup) createSubVolumeGroup(group *cephv1.CephFilesystemSubVolumeGroup) (*cephv1.CephFilesystemSubVolumeGroupStatus, error) {
+	fsName := group.Spec.FilesystemName
+	groupName := subVolumeGroupName(group)
+
+	err := cephclient.CreateSubVolumeGroup(r.context, group.Namespace, fsName, groupName, group.Spec.DataPoolName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only resize the group when a quota is set or must be removed
+	quota := ""
+	if group.Spec.Quota != nil {
+		quota = group.Spec.Quota.String()
+		err = cephclient.ResizeSubVolumeGroup(r.context, group.Namespace, fsName, groupName, strconv.FormatInt(group.Spec.Quota.Value(), 10))
+		if err != nil {
+			return nil, err
+		}
+	} else if group.Status != nil && group.Status.Quota != "" {
+		err = cephclient.ResizeSubVolumeGroup(r.context, group.Namespace, fsName, groupName, cephclient.SubVolumeGroupNoQuota)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Reset the policy applied before when it is removed from the spec or replaced with another policy
+	pin := ""
+	pinType, pinSetting := pinning(group.Spec.Pinning)
+	if group.Status != nil && group.Status.Pinning != "" {
+		appliedType := strings.SplitN(group.Status.Pinning, "=", 2)[0]
+		if unpinSetting, ok := unpinSettings[appliedType]; ok && appliedType != pinType {
+			err = cephclient.PinSubVolumeGroup(r.context, group.Namespace, fsName, groupName, appliedType, unpinSetting)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if pinType != "" {
+		err = cephclient.PinSubVolumeGroup(r.context, group.Namespace, fsName, groupName, pinType, pinSetting)
+		if err != nil {
+			return nil, err
+		}
+		pin = fmt.Sprintf("%s=%s", pinType, pinSetting)
+	}
+
+	path, err := cephclient.GetSubVolumeGroupPath(r.context, group.Namespace, fsName, groupName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Steer the cephfs volumes of the storage classes with the cluster ID of the group into the group
+	clusterInfo, _, _, err := mon.LoadClusterInfo(r.context, group.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to populate cluster info")
+	}
+	clusterID := csiClusterID(group)
+	err = csi.SaveSubVolumeGroupClusterConfig(r.context.Clientset, group.Namespace, clusterID, groupName, clusterInfo, csi.ConfigMutex)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save the csi cluster config of the subvolume group")
+	}
+
+	return &cephv1.CephFilesystemSubVolumeGroupStatus{Path: path, ClusterID: clusterID, Quota: quota, Pinning: pin}, nil
+}
+
+// deleteSubVolumeGroup deletes the group once it does not have any subvolume
+func (r *ReconcileCephFilesystemSubVolumeGroup) deleteSubVolumeGroup(group *cephv1.CephFilesystemSubVolumeGroup) (reconcile.Result, error) {
+	fsName := group.Spec.FilesystemName
+	groupName := subVolumeGroupName(group)
+
+	// The group is gone with the filesystem
+	filesystems, err := cephclient.ListFilesystems(r.context, group.Namespace)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to list filesystems")
+	}
+	fsExists := false
+	for _, fs := range filesystems {
+		if fs.Name == fsName {
+			fsExists = true
+			break
+		}
+	}
+
+	if fsExists {
+		subVolumes, err := cephclient.ListSubVolumes(r.context, group.Namespace, fsName, groupName)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if len(subVolumes) > 0 {
+			return opcontroller.WaitForRequeueIfFinalizerBlocked, errors.Errorf("subvolume group %q still has %d subvolumes, delete them before deleting the group", groupName, len(subVolumes))
+		}
+
+		err = cephclient.DeleteSubVolumeGroup(r.context, group.Namespace, fsName, groupName)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	err = csi.RemoveClusterConfig(r.context.Clientset, csiClusterID(group), csi.ConfigMutex)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to remove the csi cluster config of the subvolume group")
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// validateSubVolumeGroup validates the spec of a subvolume group
+func validateSubVolumeGroup(group *cephv1.CephFilesystemSubVolumeGroup) error {
+	if group.Spec.FilesystemName == "" {
+		return errors.New("missing filesystemName")
+	}
+	if group.Status != nil && group.Status.ClusterID != "" && group.Status.ClusterID != csiClusterID(group) {
+		return errors.New("filesystemName and name cannot be changed")
+	}
+	if group.Spec.Quota != nil && group.Spec.Quota.Sign() < 0 {
+		return errors.Errorf("invalid quota %q", group.Spec.Quota.String())
+	}
+
+	pinning := group.Spec.Pinning
+	pins := 0
+	if pinning.Export != nil {
+		pins++
+		if *pinning.Export < -1 {
+			return errors.Errorf("invalid export pin %d, must be a rank or -1", *pinning.Export)
+		}
+	}
+	if pinning.Distributed != nil {
+		pins++
+		if *pinning.Distributed != 0 && *pinning.Distributed != 1 {
+			return errors.Errorf("invalid distributed pin %d, must be 0 or 1", *pinning.Distributed)
+		}
+	}
+	if pinning.Random != nil {
+		pins++
+		if *pinning.Random < 0 || *pinning.Random > 1 {
+			return errors.Errorf("invalid random pin %v, must be between 0.0 and 1.0", *pinning.Random)
+		}
+	}
+	if pins > 1 {
+		return errors.New("only one of export, distributed and random pinning can be set")
+	}
+
+	return nil
+}
+
+// pinning returns the pin type and setting of the group, an empty type if the group is not pinned
+func pinning(pinning cephv1.SubVolumeGroupPinning) (string, string) {
+	switch {
+	case pinning.Export != nil:
+		return "export", strconv.Itoa(*pinning.Export)
+	case pinning.Distributed != nil:
+		return "distributed", strconv.Itoa(*pinning.Distributed)
+	case pinning.Random != nil:
+		return "random", strconv.FormatFloat(*pinning.Random, 'f', -1, 64)
+	}
+	return "", ""
+}
+
+// subVolumeGroupName returns the name of the group in Ceph
+func subVolumeGroupName(group *cephv1.CephFilesystemSubVolumeGroup) string {
+	if group.Spec.Name != "" {
+		return group.Spec.Name
+	}
+	return group.Name
+}
+
+// csiClusterID returns the cluster ID of the storage classes provisioning CSI volumes in the group. It is a hash
+// since ceph-csi limits the length of the cluster IDs.
+func csiClusterID(group *cephv1.CephFilesystemSubVolumeGroup) string {
+	return k8sutil.Hash(fmt.Sprintf("%s/%s/%s", group.Namespace, group.Spec.FilesystemName, subVolumeGroupName(group)))
+}
+
+// updateStatus updates a subvolume group CR with the given status and the matching conditions. The path,
+// cluster ID and quota of the group are updated when a group status is given.
+func updateStatus(client client.Client, name types.NamespacedName, status string, reconcileErr error, groupStatus *cephv1.CephFilesystemSubVolumeGroupStatus) {
+	group := &cephv1.CephFilesystemSubVolumeGroup{}
+	if err := client.Get(context.TODO(), name, group); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystemSubVolumeGroup resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve subvolume group %q to update status to %q. %v", name, status, err)
+		return
+	}
+
+	if group.Status == nil {
+		group.Status = &cephv1.CephFilesystemSubVolumeGroupStatus{}
+	}
+
+	group.Status.Phase = status
+	opcontroller.SetPhaseConditions(&group.Status.Conditions, status, reconcileErr)
+	if groupStatus != nil {
+		group.Status.Path = groupStatus.Path
+		group.Status.ClusterID = groupStatus.ClusterID
+		group.Status.Quota = groupStatus.Quota
+		group.Status.Pinning = groupStatus.Pinning
+	}
+	if err := opcontroller.UpdateStatus(client, group); err != nil {
+		logger.Warningf("failed to set subvolume group %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("subvolume group %q status updated to %q", name, status)
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subvolumegroup
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestValidateSubVolumeGroup(t *testing.T) {
+	group := &cephv1.CephFilesystemSubVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "group-a", Namespace: "rook-ceph"},
+	}
+
+	// must specify the filesystem
+	assert.Error(t, validateSubVolumeGroup(group))
+	group.Spec.FilesystemName = "myfs"
+	assert.NoError(t, validateSubVolumeGroup(group))
+
+	// only one pinning policy
+	export := 1
+	distributed := 1
+	group.Spec.Pinning = cephv1.SubVolumeGroupPinning{Export: &export, Distributed: &distributed}
+	assert.Error(t, validateSubVolumeGroup(group))
+	group.Spec.Pinning.Distributed = nil
+	assert.NoError(t, validateSubVolumeGroup(group))
+
+	// invalid pin settings
+	distributed = 2
+	group.Spec.Pinning = cephv1.SubVolumeGroupPinning{Distributed: &distributed}
+	assert.Error(t, validateSubVolumeGroup(group))
+	random := 1.5
+	group.Spec.Pinning = cephv1.SubVolumeGroupPinning{Random: &random}
+	assert.Error(t, validateSubVolumeGroup(group))
+	random = 0.01
+	assert.NoError(t, validateSubVolumeGroup(group))
+
+	// the filesystem and the group name cannot change once created
+	group.Status = &cephv1.CephFilesystemSubVolumeGroupStatus{ClusterID: csiClusterID(group)}
+	assert.NoError(t, validateSubVolumeGroup(group))
+	group.Spec.Name = "group-b"
+	assert.Error(t, validateSubVolumeGroup(group))
+}
+
+func TestPinning(t *testing.T) {
+	pinType, _ := pinning(cephv1.SubVolumeGroupPinning{})
+	assert.Equal(t, "", pinType)
+
+	export := -1
+	pinType, pinSetting := pinning(cephv1.SubVolumeGroupPinning{Export: &export})
+	assert.Equal(t, "export", pinType)
+	assert.Equal(t, "-1", pinSetting)
+
+	random := 0.01
+	pinType, pinSetting = pinning(cephv1.SubVolumeGroupPinning{Random: &random})
+	assert.Equal(t, "random", pinType)
+	assert.Equal(t, "0.01", pinSetting)
+}
+
+func TestCephFilesystemSubVolumeGroupController(t *testing.T) {
+	var (
+		name      = "group-a"
+		namespace = "rook-ceph"
+	)
+	quota := resource.MustParse("10Gi")
+	export := 1
+	group := &cephv1.CephFilesystemSubVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: cephv1.CephFilesystemSubVolumeGroupSpec{
+			FilesystemName: "myfs",
+			Quota:          &quota,
+			Pinning:        cephv1.SubVolumeGroupPinning{Export: &export},
+		},
+		Status: &cephv1.CephFilesystemSubVolumeGroupStatus{},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:       k8sutil.ReadyStatus,
+			CephVersion: &cephv1.ClusterVersion{Version: "15.2.4-0"},
+			CephStatus:  &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+	fs := &cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: namespace},
+		Status:     &cephv1.CephFilesystemStatus{Phase: k8sutil.ReadyStatus},
+	}
+
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "status" {
+				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_OK"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
+			}
+			if args[0] == "fs" && args[1] == "subvolumegroup" {
+				commands = append(commands, args[2:])
+				if args[2] == "getpath" {
+					return "/volumes/group-a\n", nil
+				}
+			}
+			return "", nil
+		},
+	}
+	clientset := test.New(t, 3)
+	c := &clusterd.Context{
+		Executor:      executor,
+		RookClientset: rookclient.NewSimpleClientset(),
+		Clientset:     clientset,
+	}
+
+	// Mock clusterInfo
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			"cluster-name": []byte("foo-cluster"),
+			"fsid":         []byte(name),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := c.Clientset.CoreV1().Secrets(namespace).Create(secret)
+	assert.NoError(t, err)
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephFilesystemSubVolumeGroup{}, &cephv1.CephFilesystemSubVolumeGroupList{},
+		&cephv1.CephCluster{}, &cephv1.CephClusterList{}, &cephv1.CephFilesystem{}, &cephv1.CephFilesystemList{})
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+
+	//
+	// The filesystem does not exist yet
+	//
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{group, cephCluster}...)
+	r := &ReconcileCephFilesystemSubVolumeGroup{client: cl, scheme: s, context: c}
+	res, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, 0, len(commands))
+
+	//
+	// SUCCESS! The group is created, resized and pinned
+	//
+	cl = fake.NewFakeClientWithScheme(s, []runtime.Object{group, cephCluster, fs}...)
+	r = &ReconcileCephFilesystemSubVolumeGroup{client: cl, scheme: s, context: c}
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, [][]string{
+		{"create", "myfs", "group-a"},
+		{"resize", "myfs", "group-a", "10737418240"},
+		{"pin", "myfs", "group-a", "export", "1"},
+		{"getpath", "myfs", "group-a"},
+	}, trimFlags(commands))
+
+	err = r.client.Get(context.TODO(), req.NamespacedName, group)
+	assert.NoError(t, err)
+	assert.Equal(t, k8sutil.ReadyStatus, group.Status.Phase)
+	assert.Equal(t, "/volumes/group-a", group.Status.Path)
+	assert.Equal(t, csiClusterID(group), group.Status.ClusterID)
+	assert.Equal(t, "10Gi", group.Status.Quota)
+	assert.Equal(t, "export=1", group.Status.Pinning)
+	ready := opcontroller.FindStatusCondition(group.Status.Conditions, cephv1.ConditionReady)
+	assert.NotNil(t, ready)
+	assert.Equal(t, v1.ConditionTrue, ready.Status)
+
+	//
+	// The pinning policy is replaced, the previous policy is reset
+	//
+	commands = [][]string{}
+	distributed := 1
+	group.Spec.Pinning = cephv1.SubVolumeGroupPinning{Distributed: &distributed}
+	err = r.client.Update(context.TODO(), group)
+	assert.NoError(t, err)
+	_, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"create", "myfs", "group-a"},
+		{"resize", "myfs", "group-a", "10737418240"},
+		{"pin", "myfs", "group-a", "export", "-1"},
+		{"pin", "myfs", "group-a", "distributed", "1"},
+		{"getpath", "myfs", "group-a"},
+	}, trimFlags(commands))
+
+	//
+	// The quota and the pinning are removed from the spec
+	//
+	group = &cephv1.CephFilesystemSubVolumeGroup{}
+	err = r.client.Get(context.TODO(), req.NamespacedName, group)
+	assert.NoError(t, err)
+	assert.Equal(t, "distributed=1", group.Status.Pinning)
+	commands = [][]string{}
+	group.Spec.Quota = nil
+	group.Spec.Pinning = cephv1.SubVolumeGroupPinning{}
+	err = r.client.Update(context.TODO(), group)
+	assert.NoError(t, err)
+	_, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"create", "myfs", "group-a"},
+		{"resize", "myfs", "group-a", "infinite"},
+		{"pin", "myfs", "group-a", "distributed", "0"},
+		{"getpath", "myfs", "group-a"},
+	}, trimFlags(commands))
+
+	group = &cephv1.CephFilesystemSubVolumeGroup{}
+	err = r.client.Get(context.TODO(), req.NamespacedName, group)
+	assert.NoError(t, err)
+	assert.Equal(t, "", group.Status.Pinning)
+	commands = [][]string{}
+	_, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"create", "myfs", "group-a"},
+		{"getpath", "myfs", "group-a"},
+	}, trimFlags(commands))
+}
+
+// trimFlags removes the connection flags appended to the ceph commands
+func trimFlags(commands [][]string) [][]string {
+	trimmed := [][]string{}
+	for _, args := range commands {
+		i := 0
+		for i < len(args) && !strings.HasPrefix(args[i], "--") {
+			i++
+		}
+		trimmed = append(trimmed, args[:i])
+	}
+	return trimmed
+}
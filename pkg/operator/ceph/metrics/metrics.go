@@ -0,0 +1,223 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics exports the prometheus metrics of the Ceph operator. The metrics are registered in the
+// controller-runtime registry and served by the controller-runtime manager, along with the reconcile and
+// workqueue metrics of the controllers.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "rook_ceph"
+
+	healthOK   = "HEALTH_OK"
+	healthWarn = "HEALTH_WARN"
+)
+
+var (
+	// MonFailovers counts the mons failed over by the mon health checker
+	MonFailovers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mon_failovers_total",
+		Help:      "Total number of mons failed over per ceph cluster",
+	}, []string{"namespace"})
+
+	// OSDRemovals counts the OSD deployments removed by the OSD health checker
+	OSDRemovals = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "osd_removals_total",
+		Help:      "Total number of OSD deployments removed after being out and safe to destroy per ceph cluster",
+	}, []string{"namespace"})
+
+	// OSDRestarts counts the OSD pods restarted by the OSD health checker
+	OSDRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "osd_restarts_total",
+		Help:      "Total number of OSD pods stuck terminating on a node not ready that were force deleted per ceph cluster",
+	}, []string{"namespace"})
+
+	// ClusterHealth is the last health of each ceph cluster
+	ClusterHealth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_health_status",
+		Help:      "Last health reported by the ceph cluster: 0 for HEALTH_OK, 1 for HEALTH_WARN and 2 for HEALTH_ERR",
+	}, []string{"namespace"})
+
+	// ClusterHealthTimestamp is the time of the last health check of each ceph cluster
+	ClusterHealthTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_health_last_check_timestamp_seconds",
+		Help:      "Unix time of the last successful health check of the ceph cluster",
+	}, []string{"namespace"})
+
+	// ObjectZoneMaster is whether each object zone is the master zone of its zone group
+	ObjectZoneMaster = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "object_zone_master",
+		Help:      "Whether the object zone is the master zone of its zone group: 1 for the master zone, 0 otherwise",
+	}, []string{"namespace", "zone"})
+
+	// ObjectZoneMetadataSyncLag is the age of the oldest metadata change not synced by each object zone
+	ObjectZoneMetadataSyncLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "object_zone_metadata_sync_lag_seconds",
+		Help:      "Age of the oldest metadata change of the master zone not synced by the object zone",
+	}, []string{"namespace", "zone"})
+
+	// ObjectZoneMetadataSyncBehindShards is the number of metadata shards each object zone is behind
+	ObjectZoneMetadataSyncBehindShards = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "object_zone_metadata_sync_behind_shards",
+		Help:      "Number of metadata log shards with changes not synced by the object zone, -1 if the sync status is unknown",
+	}, []string{"namespace", "zone"})
+
+	// ObjectZoneDataSyncLag is the age of the oldest data change of each source zone not synced by each object zone
+	ObjectZoneDataSyncLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "object_zone_data_sync_lag_seconds",
+		Help:      "Age of the oldest data change of the source zone not synced by the object zone",
+	}, []string{"namespace", "zone", "source_zone"})
+
+	// ObjectZoneDataSyncBehindShards is the number of data shards of each source zone each object zone is behind
+	ObjectZoneDataSyncBehindShards = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "object_zone_data_sync_behind_shards",
+		Help:      "Number of data log shards of the source zone with changes not synced by the object zone, -1 if the sync status is unknown",
+	}, []string{"namespace", "zone", "source_zone"})
+
+	// ObjectZoneBucketsBehind is the number of buckets whose sync is behind among the buckets checked in each object zone
+	ObjectZoneBucketsBehind = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "object_zone_buckets_behind",
+		Help:      "Number of buckets whose sync is behind one of the other zones among a sample of the buckets of the object zone",
+	}, []string{"namespace", "zone"})
+)
+
+// the source zones whose data sync is reported for each object zone, by namespace/zone
+var (
+	objectZoneSources     = map[string][]string{}
+	objectZoneSourcesLock sync.Mutex
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		MonFailovers,
+		OSDRemovals,
+		OSDRestarts,
+		ClusterHealth,
+		ClusterHealthTimestamp,
+		ObjectZoneMaster,
+		ObjectZoneMetadataSyncLag,
+		ObjectZoneMetadataSyncBehindShards,
+		ObjectZoneDataSyncLag,
+		ObjectZoneDataSyncBehindShards,
+		ObjectZoneBucketsBehind,
+	)
+}
+
+// SetClusterHealth records the health reported by the ceph cluster of a namespace
+func SetClusterHealth(clusterNamespace, health string) {
+	ClusterHealth.WithLabelValues(clusterNamespace).Set(healthValue(health))
+	ClusterHealthTimestamp.WithLabelValues(clusterNamespace).Set(float64(time.Now().Unix()))
+}
+
+// DeleteClusterMetrics removes the metrics of the ceph cluster of a namespace
+func DeleteClusterMetrics(clusterNamespace string) {
+	MonFailovers.DeleteLabelValues(clusterNamespace)
+	OSDRemovals.DeleteLabelValues(clusterNamespace)
+	OSDRestarts.DeleteLabelValues(clusterNamespace)
+	ClusterHealth.DeleteLabelValues(clusterNamespace)
+	ClusterHealthTimestamp.DeleteLabelValues(clusterNamespace)
+}
+
+// SetObjectZoneSyncStatus records the master role and the sync status of an object zone
+func SetObjectZoneSyncStatus(zoneNamespace, zone string, master bool, status *cephv1.ZoneSyncStatus) {
+	ObjectZoneMaster.WithLabelValues(zoneNamespace, zone).Set(boolValue(master))
+	ObjectZoneMetadataSyncLag.WithLabelValues(zoneNamespace, zone).Set(float64(status.MetadataSync.LagSeconds))
+	ObjectZoneMetadataSyncBehindShards.WithLabelValues(zoneNamespace, zone).Set(behindShardsValue(status.MetadataSync))
+	ObjectZoneBucketsBehind.WithLabelValues(zoneNamespace, zone).Set(float64(status.BucketsBehind))
+
+	objectZoneSourcesLock.Lock()
+	defer objectZoneSourcesLock.Unlock()
+	key := zoneNamespace + "/" + zone
+	// the source zones removed from the zone group are no longer reported
+	deleteObjectZoneDataSyncMetrics(zoneNamespace, zone, objectZoneSources[key])
+	var sources []string
+	for _, source := range status.DataSync {
+		ObjectZoneDataSyncLag.WithLabelValues(zoneNamespace, zone, source.SourceZone).Set(float64(source.LagSeconds))
+		ObjectZoneDataSyncBehindShards.WithLabelValues(zoneNamespace, zone, source.SourceZone).Set(behindShardsValue(source.SyncProgress))
+		sources = append(sources, source.SourceZone)
+	}
+	objectZoneSources[key] = sources
+}
+
+// DeleteObjectZoneMetrics removes the metrics of an object zone
+func DeleteObjectZoneMetrics(zoneNamespace, zone string) {
+	ObjectZoneMaster.DeleteLabelValues(zoneNamespace, zone)
+	ObjectZoneMetadataSyncLag.DeleteLabelValues(zoneNamespace, zone)
+	ObjectZoneMetadataSyncBehindShards.DeleteLabelValues(zoneNamespace, zone)
+	ObjectZoneBucketsBehind.DeleteLabelValues(zoneNamespace, zone)
+
+	objectZoneSourcesLock.Lock()
+	defer objectZoneSourcesLock.Unlock()
+	key := zoneNamespace + "/" + zone
+	deleteObjectZoneDataSyncMetrics(zoneNamespace, zone, objectZoneSources[key])
+	delete(objectZoneSources, key)
+}
+
+func deleteObjectZoneDataSyncMetrics(zoneNamespace, zone string, sources []string) {
+	for _, source := range sources {
+		ObjectZoneDataSyncLag.DeleteLabelValues(zoneNamespace, zone, source)
+		ObjectZoneDataSyncBehindShards.DeleteLabelValues(zoneNamespace, zone, source)
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// behindShardsValue returns the number of shards behind of a sync, -1 if its progress could not be retrieved
+func behindShardsValue(progress cephv1.SyncProgress) float64 {
+	switch progress.State {
+	case cephv1.SyncStateError, cephv1.SyncStateUnreachable, cephv1.SyncStateUnknown:
+		return -1
+	}
+	return float64(progress.BehindShards)
+}
+
+// healthValue converts a ceph health to the value of the health metric, which follows the
+// ceph_health_status metric exported by the mgr
+func healthValue(health string) float64 {
+	switch health {
+	case healthOK:
+		return 0
+	case healthWarn:
+		return 1
+	default:
+		return 2
+	}
+}
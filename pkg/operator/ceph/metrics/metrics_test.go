@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterHealth(t *testing.T) {
+	SetClusterHealth("ns", "HEALTH_OK")
+	assert.Equal(t, float64(0), testutil.ToFloat64(ClusterHealth.WithLabelValues("ns")))
+	assert.NotZero(t, testutil.ToFloat64(ClusterHealthTimestamp.WithLabelValues("ns")))
+
+	SetClusterHealth("ns", "HEALTH_WARN")
+	assert.Equal(t, float64(1), testutil.ToFloat64(ClusterHealth.WithLabelValues("ns")))
+
+	SetClusterHealth("ns", "HEALTH_ERR")
+	assert.Equal(t, float64(2), testutil.ToFloat64(ClusterHealth.WithLabelValues("ns")))
+
+	// the metrics of a deleted cluster are removed
+	MonFailovers.WithLabelValues("ns").Inc()
+	assert.Equal(t, 1, collectAndCount(MonFailovers))
+	DeleteClusterMetrics("ns")
+	assert.Equal(t, 0, collectAndCount(MonFailovers))
+	assert.Equal(t, 0, collectAndCount(ClusterHealth))
+}
+
+func collectAndCount(c prometheus.Collector) int {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+	count := 0
+	for range ch {
+		count++
+	}
+	return count
+}
+
+func TestObjectZoneSyncStatus(t *testing.T) {
+	status := &cephv1.ZoneSyncStatus{
+		MetadataSync: cephv1.SyncProgress{State: cephv1.SyncStateBehind, BehindShards: 2, LagSeconds: 30},
+		DataSync: []cephv1.DataSyncProgress{
+			{SourceZone: "zone-b", SyncProgress: cephv1.SyncProgress{State: cephv1.SyncStateCaughtUp}},
+			{SourceZone: "zone-c", SyncProgress: cephv1.SyncProgress{State: cephv1.SyncStateUnreachable}},
+		},
+		BucketsBehind: 3,
+	}
+	SetObjectZoneSyncStatus("ns", "zone-a", false, status)
+	assert.Equal(t, float64(0), testutil.ToFloat64(ObjectZoneMaster.WithLabelValues("ns", "zone-a")))
+	assert.Equal(t, float64(30), testutil.ToFloat64(ObjectZoneMetadataSyncLag.WithLabelValues("ns", "zone-a")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(ObjectZoneMetadataSyncBehindShards.WithLabelValues("ns", "zone-a")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(ObjectZoneBucketsBehind.WithLabelValues("ns", "zone-a")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(ObjectZoneDataSyncBehindShards.WithLabelValues("ns", "zone-a", "zone-b")))
+	assert.Equal(t, float64(-1), testutil.ToFloat64(ObjectZoneDataSyncBehindShards.WithLabelValues("ns", "zone-a", "zone-c")))
+
+	// the source zones no longer reported are removed
+	status.DataSync = status.DataSync[:1]
+	SetObjectZoneSyncStatus("ns", "zone-a", true, status)
+	assert.Equal(t, float64(1), testutil.ToFloat64(ObjectZoneMaster.WithLabelValues("ns", "zone-a")))
+	assert.Equal(t, 1, collectAndCount(ObjectZoneDataSyncLag))
+
+	DeleteObjectZoneMetrics("ns", "zone-a")
+	assert.Equal(t, 0, collectAndCount(ObjectZoneMaster))
+	assert.Equal(t, 0, collectAndCount(ObjectZoneDataSyncLag))
+	assert.Equal(t, 0, collectAndCount(ObjectZoneDataSyncBehindShards))
+}
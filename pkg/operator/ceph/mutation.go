@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/admission"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var (
+	// the resources defaulted by the admission controller
+	mutatedResources = []string{"cephclusters", "cephblockpools", "cephfilesystems", "cephobjectstores"}
+)
+
+// MutateCephResource returns the JSON patch setting the defaults applied by the operator in the spec of the ceph
+// resources, and converting the deprecated settings into their replacements. The spec of the resources then shows the
+// settings the operator actually runs.
+func MutateCephResource(request *v1beta1.AdmissionRequest, context *clusterd.Context) ([]byte, error) {
+	if request.Operation != v1beta1.Create && request.Operation != v1beta1.Update {
+		return nil, nil
+	}
+
+	var obj runtime.Object
+	var setDefaults func()
+	switch request.Resource.Resource {
+	case "cephclusters":
+		cluster := &cephv1.CephCluster{}
+		obj, setDefaults = cluster, func() { setClusterSpecDefaults(&cluster.Spec) }
+	case "cephblockpools":
+		p := &cephv1.CephBlockPool{}
+		obj, setDefaults = p, func() { setPoolSpecDefaults(&p.Spec) }
+	case "cephfilesystems":
+		fs := &cephv1.CephFilesystem{}
+		obj, setDefaults = fs, func() {
+			setPoolSpecDefaults(&fs.Spec.MetadataPool)
+			for i := range fs.Spec.DataPools {
+				setPoolSpecDefaults(&fs.Spec.DataPools[i])
+			}
+		}
+	case "cephobjectstores":
+		store := &cephv1.CephObjectStore{}
+		obj, setDefaults = store, func() {
+			setPoolSpecDefaults(&store.Spec.MetadataPool)
+			setPoolSpecDefaults(&store.Spec.DataPool)
+			setGatewaySpecDefaults(&store.Spec.Gateway)
+		}
+	default:
+		return nil, nil
+	}
+
+	if _, _, err := universalDeserializer.Decode(request.Object.Raw, nil, obj); err != nil {
+		return nil, errors.Wrapf(err, "failed to deserialize %s object", request.Resource.Resource)
+	}
+	original := obj.DeepCopyObject()
+	setDefaults()
+
+	patch, err := admission.CreateJSONPatch(request.Object.Raw, original, obj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the patch of %s %q", request.Resource.Resource, request.Name)
+	}
+	return patch, nil
+}
+
+// setClusterSpecDefaults sets the defaults of the cluster settings
+func setClusterSpecDefaults(spec *cephv1.ClusterSpec) {
+	if spec.External.Enable {
+		return
+	}
+	if spec.Mon.Count == 0 {
+		spec.Mon.Count = mon.DefaultMonCount
+	}
+	if spec.Dashboard.Enabled && spec.Dashboard.Port == 0 {
+		spec.Dashboard.Port = mgr.DashboardPort(spec.Dashboard)
+	}
+}
+
+// setPoolSpecDefaults sets the defaults of the pool settings. The empty pools are not changed since they might have been
+// created already, such as by the ceph mgr.
+func setPoolSpecDefaults(spec *cephv1.PoolSpec) {
+	if reflect.DeepEqual(*spec, cephv1.PoolSpec{}) {
+		return
+	}
+	if spec.FailureDomain == "" {
+		spec.FailureDomain = cephv1.DefaultFailureDomain
+	}
+}
+
+// setGatewaySpecDefaults sets the defaults of the rgw settings
+func setGatewaySpecDefaults(spec *cephv1.GatewaySpec) {
+	// the daemonset on all nodes is not supported anymore, the gateway runs the number of instances
+	if spec.AllNodes {
+		logger.Warning("converting 'allNodes' of the object store gateway to 'instances', 'allNodes' is not supported anymore")
+		spec.AllNodes = false
+	}
+	if spec.Instances < 1 {
+		spec.Instances = 1
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"encoding/json"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func mutateCephResource(t *testing.T, resource string, obj, mutated interface{}) {
+	request := newAdmissionRequest(t, resource, v1beta1.Create, obj, nil)
+	patch, err := MutateCephResource(request, nil)
+	assert.NoError(t, err)
+	if patch == nil {
+		assert.NoError(t, json.Unmarshal(request.Object.Raw, mutated))
+		return
+	}
+
+	// apply the patch
+	decoded, err := jsonpatch.DecodePatch(patch)
+	assert.NoError(t, err)
+	b, err := decoded.Apply(request.Object.Raw)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(b, mutated))
+}
+
+func TestMutateCephCluster(t *testing.T) {
+	cluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "rook-ceph"},
+		Spec: cephv1.ClusterSpec{
+			DataDirHostPath: "/var/lib/rook",
+			Dashboard:       cephv1.DashboardSpec{Enabled: true, SSL: true},
+			Storage:         rookv1.StorageScopeSpec{Nodes: []rookv1.Node{{Name: "node1"}}},
+		},
+	}
+	mutated := &cephv1.CephCluster{}
+	mutateCephResource(t, "cephclusters", cluster, mutated)
+	assert.Equal(t, 3, mutated.Spec.Mon.Count)
+	assert.Equal(t, 8443, mutated.Spec.Dashboard.Port)
+	assert.Equal(t, "node1", mutated.Spec.Storage.Nodes[0].Name)
+	assert.Equal(t, "/var/lib/rook", mutated.Spec.DataDirHostPath)
+
+	// the settings are kept
+	cluster = mutated
+	cluster.Spec.Mon.Count = 5
+	cluster.Spec.Dashboard.Port = 9000
+	mutated = &cephv1.CephCluster{}
+	mutateCephResource(t, "cephclusters", cluster, mutated)
+	assert.Equal(t, 5, mutated.Spec.Mon.Count)
+	assert.Equal(t, 9000, mutated.Spec.Dashboard.Port)
+
+	// the external clusters are not changed
+	external := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "rook-ceph"},
+		Spec:       cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}},
+	}
+	patch, err := MutateCephResource(newAdmissionRequest(t, "cephclusters", v1beta1.Create, external, nil), nil)
+	assert.NoError(t, err)
+	assert.Nil(t, patch)
+}
+
+func TestMutateCephPools(t *testing.T) {
+	p := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"},
+		Spec:       cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}},
+	}
+	mutatedPool := &cephv1.CephBlockPool{}
+	mutateCephResource(t, "cephblockpools", p, mutatedPool)
+	assert.Equal(t, "host", mutatedPool.Spec.FailureDomain)
+	assert.Equal(t, uint(3), mutatedPool.Spec.Replicated.Size)
+
+	// the empty pools of an object store are not changed
+	store := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "rook-ceph"},
+		Spec: cephv1.ObjectStoreSpec{
+			DataPool: cephv1.PoolSpec{FailureDomain: "rack", ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}},
+			Gateway:  cephv1.GatewaySpec{Port: 80, AllNodes: true},
+		},
+	}
+	mutatedStore := &cephv1.CephObjectStore{}
+	mutateCephResource(t, "cephobjectstores", store, mutatedStore)
+	assert.Equal(t, cephv1.PoolSpec{}, mutatedStore.Spec.MetadataPool)
+	assert.Equal(t, "rack", mutatedStore.Spec.DataPool.FailureDomain)
+	assert.False(t, mutatedStore.Spec.Gateway.AllNodes)
+	assert.Equal(t, int32(1), mutatedStore.Spec.Gateway.Instances)
+	assert.Equal(t, int32(80), mutatedStore.Spec.Gateway.Port)
+}
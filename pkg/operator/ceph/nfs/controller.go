@@ -217,7 +217,7 @@ func (r *ReconcileCephNFS) reconcile(request reconcile.Request) (reconcile.Resul
 	}
 
 	// validate the store settings
-	if err := validateGanesha(r.context, cephNFS); err != nil {
+	if err := ValidateGanesha(r.context, cephNFS); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "invalid ceph nfs %q arguments", cephNFS.Name)
 	}
 
@@ -246,7 +246,8 @@ func instanceName(n *cephv1.CephNFS, name string) string {
 	return fmt.Sprintf("%s-%s-%s", AppName, n.Name, name)
 }
 
-func validateGanesha(context *clusterd.Context, n *cephv1.CephNFS) error {
+// ValidateGanesha validates the NFS arguments. If the context is nil, the existence of the RADOS pool is not validated.
+func ValidateGanesha(context *clusterd.Context, n *cephv1.CephNFS) error {
 	// core properties
 	if n.Name == "" {
 		return errors.New("missing name")
@@ -268,6 +269,10 @@ func validateGanesha(context *clusterd.Context, n *cephv1.CephNFS) error {
 		return errors.New("at least one active server required")
 	}
 
+	if context == nil {
+		return nil
+	}
+
 	// We cannot run an NFS server if no MDS is running
 	// The existence of the pool provided in n.Spec.RADOS.Pool is necessary otherwise addRADOSConfigFile() will fail
 	_, err := client.GetPoolDetails(context, n.Namespace, n.Spec.RADOS.Pool)
@@ -18,6 +18,7 @@ package object
 
 import (
 	"encoding/json"
+	"strconv"
 	"strings"
 	"time"
 
@@ -232,3 +233,31 @@ func DeleteBucket(c *Context, bucketName string, purge bool) (int, error) {
 
 	return RGWErrorUnknown, errors.Wrap(err, "failed to delete bucket")
 }
+
+// SetQuotaBucket sets and enables the quota of a bucket. A negative value leaves the size or the
+// number of objects unlimited.
+func SetQuotaBucket(c *Context, bucketName string, maxSize, maxObjects int64) (string, int, error) {
+	logger.Infof("setting bucket %q quota to max size %d and max objects %d", bucketName, maxSize, maxObjects)
+	args := []string{"quota", "set", "--quota-scope", "bucket", "--bucket", bucketName,
+		"--max-size", strconv.FormatInt(maxSize, 10), "--max-objects", strconv.FormatInt(maxObjects, 10)}
+	result, err := runAdminCommand(c, args...)
+	if err != nil {
+		return result, RGWErrorUnknown, errors.Wrapf(err, "failed to set quota of bucket %q", bucketName)
+	}
+
+	result, err = runAdminCommand(c, "quota", "enable", "--quota-scope", "bucket", "--bucket", bucketName)
+	if err != nil {
+		return result, RGWErrorUnknown, errors.Wrapf(err, "failed to enable quota of bucket %q", bucketName)
+	}
+	return result, RGWErrorNone, nil
+}
+
+// DisableQuotaBucket disables the quota of a bucket
+func DisableQuotaBucket(c *Context, bucketName string) (string, int, error) {
+	logger.Infof("disabling bucket %q quota", bucketName)
+	result, err := runAdminCommand(c, "quota", "disable", "--quota-scope", "bucket", "--bucket", bucketName)
+	if err != nil {
+		return result, RGWErrorUnknown, errors.Wrapf(err, "failed to disable quota of bucket %q", bucketName)
+	}
+	return result, RGWErrorNone, nil
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	cephObject "github.com/rook/rook/pkg/operator/ceph/object"
+)
+
+// The keys of the OBC additionalConfig configuring the bucket. The same keys in the parameters of the
+// storage class set the defaults of all the buckets of the class.
+const (
+	versioningKey                        = "versioning"
+	lifecyclePrefixKey                   = "lifecyclePrefix"
+	lifecycleExpirationDaysKey           = "lifecycleExpirationDays"
+	lifecycleNoncurrentExpirationDaysKey = "lifecycleNoncurrentExpirationDays"
+	lifecycleTransitionDaysKey           = "lifecycleTransitionDays"
+	lifecycleTransitionStorageClassKey   = "lifecycleTransitionStorageClass"
+	objectLockKey                        = "objectLock"
+	objectLockModeKey                    = "objectLockMode"
+	objectLockRetentionDaysKey           = "objectLockRetentionDays"
+	corsAllowedOriginsKey                = "corsAllowedOrigins"
+	corsAllowedMethodsKey                = "corsAllowedMethods"
+	corsAllowedHeadersKey                = "corsAllowedHeaders"
+	corsMaxAgeSecondsKey                 = "corsMaxAgeSeconds"
+	maxSizeKey                           = "maxSize"
+	maxObjectsKey                        = "maxObjects"
+
+	lifecycleRuleID = "rook-lifecycle"
+)
+
+var (
+	bucketConfigKeys = []string{versioningKey, lifecyclePrefixKey, lifecycleExpirationDaysKey, lifecycleNoncurrentExpirationDaysKey,
+		lifecycleTransitionDaysKey, lifecycleTransitionStorageClassKey, objectLockKey, objectLockModeKey, objectLockRetentionDaysKey,
+		corsAllowedOriginsKey, corsAllowedMethodsKey, corsAllowedHeadersKey, corsMaxAgeSecondsKey, maxSizeKey, maxObjectsKey}
+	defaultCorsAllowedMethods = []string{"GET", "HEAD"}
+)
+
+// bucketConfig is the desired configuration of a bucket. Unset features are left untouched on the bucket.
+type bucketConfig struct {
+	versioning     string
+	lifecycle      []*s3.LifecycleRule
+	objectLock     bool
+	objectLockMode string
+	objectLockDays int64
+	cors           []*s3.CORSRule
+	quota          bool
+	maxSize        int64
+	maxObjects     int64
+}
+
+// bucketConfigParameters merges the bucket settings of the storage class parameters with the
+// additionalConfig of the OBC, the OBC taking precedence.
+func bucketConfigParameters(sc *storagev1.StorageClass, additionalConfig map[string]string) map[string]string {
+	params := map[string]string{}
+	for _, key := range bucketConfigKeys {
+		if sc != nil {
+			if value, ok := sc.Parameters[key]; ok {
+				params[key] = value
+			}
+		}
+		if value, ok := additionalConfig[key]; ok {
+			params[key] = value
+		}
+	}
+	return params
+}
+
+func parseBucketConfig(params map[string]string) (*bucketConfig, error) {
+	config := &bucketConfig{}
+	var err error
+
+	if value := params[versioningKey]; value != "" {
+		switch strings.ToLower(value) {
+		case "enabled", "true":
+			config.versioning = s3.BucketVersioningStatusEnabled
+		case "suspended", "false":
+			config.versioning = s3.BucketVersioningStatusSuspended
+		default:
+			return nil, errors.Errorf("invalid %s %q, must be Enabled or Suspended", versioningKey, value)
+		}
+	}
+
+	if config.lifecycle, err = parseLifecycle(params); err != nil {
+		return nil, err
+	}
+	if err = parseObjectLock(params, config); err != nil {
+		return nil, err
+	}
+	if config.cors, err = parseCors(params); err != nil {
+		return nil, err
+	}
+
+	config.maxSize, config.maxObjects = -1, -1
+	if value := params[maxSizeKey]; value != "" {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil || quantity.Value() < 0 {
+			return nil, errors.Errorf("invalid %s %q, must be a size such as 10Gi", maxSizeKey, value)
+		}
+		config.maxSize = quantity.Value()
+		config.quota = true
+	}
+	if value := params[maxObjectsKey]; value != "" {
+		if config.maxObjects, err = parsePositiveInt(maxObjectsKey, value); err != nil {
+			return nil, err
+		}
+		config.quota = true
+	}
+
+	return config, nil
+}
+
+func parseLifecycle(params map[string]string) ([]*s3.LifecycleRule, error) {
+	var err error
+	rule := &s3.LifecycleRule{
+		ID:     aws.String(lifecycleRuleID),
+		Status: aws.String(s3.ExpirationStatusEnabled),
+		Filter: &s3.LifecycleRuleFilter{Prefix: aws.String(params[lifecyclePrefixKey])},
+	}
+	hasAction := false
+
+	if value := params[lifecycleExpirationDaysKey]; value != "" {
+		rule.Expiration = &s3.LifecycleExpiration{}
+		if rule.Expiration.Days, err = parsePositiveDays(lifecycleExpirationDaysKey, value); err != nil {
+			return nil, err
+		}
+		hasAction = true
+	}
+	if value := params[lifecycleNoncurrentExpirationDaysKey]; value != "" {
+		rule.NoncurrentVersionExpiration = &s3.NoncurrentVersionExpiration{}
+		if rule.NoncurrentVersionExpiration.NoncurrentDays, err = parsePositiveDays(lifecycleNoncurrentExpirationDaysKey, value); err != nil {
+			return nil, err
+		}
+		hasAction = true
+	}
+
+	transitionDays, storageClass := params[lifecycleTransitionDaysKey], params[lifecycleTransitionStorageClassKey]
+	if (transitionDays == "") != (storageClass == "") {
+		return nil, errors.Errorf("%s and %s must be set together", lifecycleTransitionDaysKey, lifecycleTransitionStorageClassKey)
+	}
+	if transitionDays != "" {
+		transition := &s3.Transition{StorageClass: aws.String(storageClass)}
+		if transition.Days, err = parsePositiveDays(lifecycleTransitionDaysKey, transitionDays); err != nil {
+			return nil, err
+		}
+		rule.Transitions = []*s3.Transition{transition}
+		hasAction = true
+	}
+
+	if !hasAction {
+		if params[lifecyclePrefixKey] != "" {
+			return nil, errors.Errorf("%s requires an expiration or a transition", lifecyclePrefixKey)
+		}
+		return nil, nil
+	}
+	return []*s3.LifecycleRule{rule}, nil
+}
+
+func parseObjectLock(params map[string]string, config *bucketConfig) error {
+	if value := params[objectLockKey]; value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.Errorf("invalid %s %q, must be true or false", objectLockKey, value)
+		}
+		config.objectLock = enabled
+	}
+
+	mode, days := strings.ToUpper(params[objectLockModeKey]), params[objectLockRetentionDaysKey]
+	if mode == "" && days == "" {
+		return nil
+	}
+	if !config.objectLock {
+		return errors.Errorf("%s and %s require %s", objectLockModeKey, objectLockRetentionDaysKey, objectLockKey)
+	}
+	if mode != s3.ObjectLockRetentionModeGovernance && mode != s3.ObjectLockRetentionModeCompliance {
+		return errors.Errorf("invalid %s %q, must be GOVERNANCE or COMPLIANCE", objectLockModeKey, params[objectLockModeKey])
+	}
+	if days == "" {
+		return errors.Errorf("%s requires %s", objectLockModeKey, objectLockRetentionDaysKey)
+	}
+	retention, err := parsePositiveDays(objectLockRetentionDaysKey, days)
+	if err != nil {
+		return err
+	}
+	config.objectLockMode = mode
+	config.objectLockDays = *retention
+
+	// objects cannot be locked without versioning
+	if config.versioning == s3.BucketVersioningStatusSuspended {
+		return errors.Errorf("%s requires %s to be Enabled", objectLockKey, versioningKey)
+	}
+	return nil
+}
+
+func parseCors(params map[string]string) ([]*s3.CORSRule, error) {
+	origins := splitList(params[corsAllowedOriginsKey])
+	if len(origins) == 0 {
+		if params[corsAllowedMethodsKey] != "" || params[corsAllowedHeadersKey] != "" || params[corsMaxAgeSecondsKey] != "" {
+			return nil, errors.Errorf("CORS settings require %s", corsAllowedOriginsKey)
+		}
+		return nil, nil
+	}
+
+	rule := &s3.CORSRule{
+		AllowedOrigins: aws.StringSlice(origins),
+		AllowedMethods: aws.StringSlice(defaultCorsAllowedMethods),
+	}
+	if methods := splitList(params[corsAllowedMethodsKey]); len(methods) > 0 {
+		for i, method := range methods {
+			methods[i] = strings.ToUpper(method)
+			switch methods[i] {
+			case "GET", "PUT", "POST", "DELETE", "HEAD":
+			default:
+				return nil, errors.Errorf("invalid CORS method %q in %s", method, corsAllowedMethodsKey)
+			}
+		}
+		rule.AllowedMethods = aws.StringSlice(methods)
+	}
+	if headers := splitList(params[corsAllowedHeadersKey]); len(headers) > 0 {
+		rule.AllowedHeaders = aws.StringSlice(headers)
+	}
+	if value := params[corsMaxAgeSecondsKey]; value != "" {
+		maxAge, err := parsePositiveInt(corsMaxAgeSecondsKey, value)
+		if err != nil {
+			return nil, err
+		}
+		rule.MaxAgeSeconds = &maxAge
+	}
+	return []*s3.CORSRule{rule}, nil
+}
+
+// applyBucketConfig applies the desired configuration to the bucket. The features of the previous
+// configuration that are not desired anymore are removed from the bucket when s3 allows it.
+func (p *Provisioner) applyBucketConfig(s3svc *S3Agent, desired, previous *bucketConfig) error {
+	if previous == nil {
+		previous = &bucketConfig{}
+	}
+
+	if desired.versioning != "" {
+		if err := s3svc.PutBucketVersioning(p.bucketName, desired.versioning); err != nil {
+			return err
+		}
+	} else if previous.versioning != "" {
+		logger.Warningf("versioning of bucket %q cannot be removed, it stays %q", p.bucketName, previous.versioning)
+	}
+
+	if desired.objectLock {
+		if err := s3svc.PutObjectLockConfiguration(p.bucketName, desired.objectLockMode, desired.objectLockDays); err != nil {
+			return errors.Wrapf(err, "object lock must be enabled when bucket %q is created", p.bucketName)
+		}
+	} else if previous.objectLock {
+		logger.Warningf("object lock of bucket %q cannot be disabled", p.bucketName)
+	}
+
+	if len(desired.lifecycle) > 0 {
+		if err := s3svc.PutBucketLifecycle(p.bucketName, desired.lifecycle); err != nil {
+			return err
+		}
+	} else if len(previous.lifecycle) > 0 {
+		if err := s3svc.DeleteBucketLifecycle(p.bucketName); err != nil {
+			return err
+		}
+	}
+
+	if len(desired.cors) > 0 {
+		if err := s3svc.PutBucketCors(p.bucketName, desired.cors); err != nil {
+			return err
+		}
+	} else if len(previous.cors) > 0 {
+		if err := s3svc.DeleteBucketCors(p.bucketName); err != nil {
+			return err
+		}
+	}
+
+	if desired.quota {
+		if _, _, err := cephObject.SetQuotaBucket(p.objectContext, p.bucketName, desired.maxSize, desired.maxObjects); err != nil {
+			return err
+		}
+	} else if previous.quota {
+		if _, _, err := cephObject.DisableQuotaBucket(p.objectContext, p.bucketName); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("applied configuration of bucket %q", p.bucketName)
+	return nil
+}
+
+func parsePositiveDays(key, value string) (*int64, error) {
+	days, err := parsePositiveInt(key, value)
+	if err != nil {
+		return nil, err
+	}
+	if days == 0 {
+		return nil, errors.Errorf("invalid %s %q, must be at least one day", key, value)
+	}
+	return &days, nil
+}
+
+func parsePositiveInt(key, value string) (int64, error) {
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || i < 0 {
+		return 0, errors.Errorf("invalid %s %q, must be a positive integer", key, value)
+	}
+	return i, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
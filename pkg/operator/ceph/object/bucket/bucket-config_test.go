@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephObject "github.com/rook/rook/pkg/operator/ceph/object"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseBucketConfig(t *testing.T) {
+	// nothing configured
+	config, err := parseBucketConfig(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "", config.versioning)
+	assert.Nil(t, config.lifecycle)
+	assert.Nil(t, config.cors)
+	assert.False(t, config.objectLock)
+	assert.False(t, config.quota)
+
+	// everything configured
+	config, err = parseBucketConfig(map[string]string{
+		versioningKey:                        "enabled",
+		lifecyclePrefixKey:                   "logs/",
+		lifecycleExpirationDaysKey:           "30",
+		lifecycleNoncurrentExpirationDaysKey: "7",
+		lifecycleTransitionDaysKey:           "10",
+		lifecycleTransitionStorageClassKey:   "COLD",
+		objectLockKey:                        "true",
+		objectLockModeKey:                    "governance",
+		objectLockRetentionDaysKey:           "5",
+		corsAllowedOriginsKey:                "https://a.example.com, https://b.example.com",
+		corsAllowedMethodsKey:                "get,put",
+		corsMaxAgeSecondsKey:                 "600",
+		maxSizeKey:                           "1Gi",
+		maxObjectsKey:                        "1000",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, s3.BucketVersioningStatusEnabled, config.versioning)
+	assert.Equal(t, 1, len(config.lifecycle))
+	rule := config.lifecycle[0]
+	assert.Equal(t, "logs/", *rule.Filter.Prefix)
+	assert.Equal(t, int64(30), *rule.Expiration.Days)
+	assert.Equal(t, int64(7), *rule.NoncurrentVersionExpiration.NoncurrentDays)
+	assert.Equal(t, int64(10), *rule.Transitions[0].Days)
+	assert.Equal(t, "COLD", *rule.Transitions[0].StorageClass)
+	assert.True(t, config.objectLock)
+	assert.Equal(t, s3.ObjectLockRetentionModeGovernance, config.objectLockMode)
+	assert.Equal(t, int64(5), config.objectLockDays)
+	assert.Equal(t, 1, len(config.cors))
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, stringValues(config.cors[0].AllowedOrigins))
+	assert.Equal(t, []string{"GET", "PUT"}, stringValues(config.cors[0].AllowedMethods))
+	assert.Equal(t, int64(600), *config.cors[0].MaxAgeSeconds)
+	assert.True(t, config.quota)
+	assert.Equal(t, int64(1073741824), config.maxSize)
+	assert.Equal(t, int64(1000), config.maxObjects)
+
+	// a single quota leaves the other unlimited
+	config, err = parseBucketConfig(map[string]string{maxObjectsKey: "10"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), config.maxSize)
+	assert.Equal(t, int64(10), config.maxObjects)
+
+	// the default CORS methods
+	config, err = parseBucketConfig(map[string]string{corsAllowedOriginsKey: "*"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GET", "HEAD"}, stringValues(config.cors[0].AllowedMethods))
+
+	// invalid settings
+	for _, params := range []map[string]string{
+		{versioningKey: "maybe"},
+		{lifecycleExpirationDaysKey: "0"},
+		{lifecycleExpirationDaysKey: "-3"},
+		{lifecycleTransitionDaysKey: "10"},
+		{lifecyclePrefixKey: "logs/"},
+		{objectLockKey: "yes please"},
+		{objectLockModeKey: "GOVERNANCE", objectLockRetentionDaysKey: "1"},
+		{objectLockKey: "true", objectLockModeKey: "FOREVER", objectLockRetentionDaysKey: "1"},
+		{objectLockKey: "true", objectLockModeKey: "COMPLIANCE"},
+		{objectLockKey: "true", versioningKey: "Suspended", objectLockModeKey: "COMPLIANCE", objectLockRetentionDaysKey: "1"},
+		{corsAllowedMethodsKey: "GET"},
+		{corsAllowedOriginsKey: "*", corsAllowedMethodsKey: "PATCH"},
+		{maxSizeKey: "lots"},
+		{maxObjectsKey: "-1"},
+	} {
+		_, err = parseBucketConfig(params)
+		assert.Error(t, err, params)
+	}
+}
+
+func TestBucketConfigParameters(t *testing.T) {
+	sc := &storagev1.StorageClass{
+		Parameters: map[string]string{
+			"objectStoreName": store,
+			versioningKey:     "Enabled",
+			maxObjectsKey:     "100",
+		},
+	}
+	params := bucketConfigParameters(sc, map[string]string{maxObjectsKey: "10", "unrelated": "value"})
+	assert.Equal(t, map[string]string{versioningKey: "Enabled", maxObjectsKey: "10"}, params)
+
+	params = bucketConfigParameters(nil, map[string]string{maxSizeKey: "1G"})
+	assert.Equal(t, map[string]string{maxSizeKey: "1G"}, params)
+}
+
+func TestApplyBucketConfig(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RawQuery)
+	}))
+	defer server.Close()
+	s3svc, err := NewS3Agent("access", "secret", strings.TrimPrefix(server.URL, "http://"))
+	assert.NoError(t, err)
+
+	var adminCommands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			adminCommands = append(adminCommands, strings.Join(args[:3], " "))
+			return "", nil
+		},
+	}
+	p := &Provisioner{bucketName: "my-bucket"}
+	p.objectContext = cephObject.NewContext(&clusterd.Context{Executor: executor}, store, namespace)
+
+	desired, err := parseBucketConfig(map[string]string{
+		versioningKey:              "Enabled",
+		lifecycleExpirationDaysKey: "30",
+		objectLockKey:              "true",
+		corsAllowedOriginsKey:      "*",
+		maxSizeKey:                 "10Gi",
+	})
+	assert.NoError(t, err)
+	err = p.applyBucketConfig(s3svc, desired, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PUT versioning=", "PUT object-lock=", "PUT lifecycle=", "PUT cors="}, requests)
+	assert.Equal(t, []string{"quota set --quota-scope", "quota enable --quota-scope"}, adminCommands)
+
+	// the settings removed from the configuration are removed from the bucket
+	requests, adminCommands = nil, nil
+	err = p.applyBucketConfig(s3svc, &bucketConfig{}, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DELETE lifecycle=", "DELETE cors="}, requests)
+	assert.Equal(t, []string{"quota disable --quota-scope"}, adminCommands)
+}
+
+func TestClaimConfigChanged(t *testing.T) {
+	oldObc := &bktv1alpha1.ObjectBucketClaim{
+		ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1"},
+		Spec: bktv1alpha1.ObjectBucketClaimSpec{
+			ObjectBucketName: "obc-default-my-bucket",
+			AdditionalConfig: map[string]string{maxObjectsKey: "10"},
+		},
+		Status: bktv1alpha1.ObjectBucketClaimStatus{Phase: bktv1alpha1.ObjectBucketClaimStatusPhaseBound},
+	}
+	newObc := oldObc.DeepCopy()
+	newObc.ResourceVersion = "2"
+	assert.False(t, claimConfigChanged(oldObc, newObc))
+
+	newObc.Spec.AdditionalConfig[maxObjectsKey] = "20"
+	assert.True(t, claimConfigChanged(oldObc, newObc))
+
+	// the configuration is applied again on a resync
+	assert.True(t, claimConfigChanged(oldObc, oldObc.DeepCopy()))
+
+	// the claim is not bound yet
+	newObc.Status.Phase = bktv1alpha1.ObjectBucketClaimStatusPhasePending
+	assert.False(t, claimConfigChanged(oldObc, newObc))
+	assert.False(t, claimBound(newObc))
+	assert.True(t, claimBound(oldObc))
+
+	// the claim is being deleted
+	now := metav1.Now()
+	oldObc.DeletionTimestamp = &now
+	assert.False(t, claimBound(oldObc))
+	assert.False(t, claimConfigChanged(oldObc, oldObc.DeepCopy()))
+}
+
+func stringValues(values []*string) []string {
+	result := []string{}
+	for _, v := range values {
+		result = append(result, *v)
+	}
+	return result
+}
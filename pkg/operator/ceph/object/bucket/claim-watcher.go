@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"reflect"
+	"time"
+
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	claimClient "github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/clientset/versioned"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	cephObject "github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+// the configuration of the buckets is applied again periodically in case it changed outside of the OBCs
+const claimResyncPeriod = time.Hour
+
+// obcResource represents the ObjectBucketClaim custom resource of the bucket provisioner library
+var obcResource = k8sutil.CustomResource{
+	Name:    "objectbucketclaim",
+	Plural:  "objectbucketclaims",
+	Group:   bktv1alpha1.SchemeGroupVersion.Group,
+	Version: bktv1alpha1.SchemeGroupVersion.Version,
+	Kind:    reflect.TypeOf(bktv1alpha1.ObjectBucketClaim{}).Name(),
+}
+
+// StartClaimWatch watches the bound OBCs and re-applies the configuration of their bucket when the operator starts,
+// when their additionalConfig changes and every claimResyncPeriod. The bucket provisioner library only handles the
+// creation and deletion of OBCs.
+func (p *Provisioner) StartClaimWatch(stopCh chan struct{}) error {
+	const allNamespaces = ""
+
+	if p.claimClientset == nil {
+		clientset, err := claimClient.NewForConfig(p.context.KubeConfig)
+		if err != nil {
+			return errors.Wrap(err, "failed to create object bucket claim clientset")
+		}
+		p.claimClientset = clientset
+	}
+
+	resourceHandlerFuncs := cache.ResourceEventHandlerFuncs{
+		AddFunc:    p.onClaimAdd,
+		UpdateFunc: p.onClaimUpdate,
+	}
+
+	logger.Infof("start watching object bucket claims for bucket configuration changes")
+	go k8sutil.WatchCRWithResync(obcResource, allNamespaces, resourceHandlerFuncs, p.claimClientset.ObjectbucketV1alpha1().RESTClient(), &bktv1alpha1.ObjectBucketClaim{}, claimResyncPeriod, stopCh)
+	return nil
+}
+
+// onClaimAdd applies the configuration of the OBCs already bound when the watch starts, since their additionalConfig
+// may have changed while the operator was not running
+func (p *Provisioner) onClaimAdd(obj interface{}) {
+	obc, ok := obj.(*bktv1alpha1.ObjectBucketClaim)
+	if !ok || !claimBound(obc) {
+		return
+	}
+
+	if err := p.updateBucketConfig(nil, obc); err != nil {
+		logger.Errorf("failed to apply the bucket configuration of OBC %q in namespace %q. %v", obc.Name, obc.Namespace, err)
+	}
+}
+
+func (p *Provisioner) onClaimUpdate(oldObj, newObj interface{}) {
+	oldObc, ok := oldObj.(*bktv1alpha1.ObjectBucketClaim)
+	if !ok {
+		return
+	}
+	newObc, ok := newObj.(*bktv1alpha1.ObjectBucketClaim)
+	if !ok {
+		return
+	}
+	if !claimConfigChanged(oldObc, newObc) {
+		return
+	}
+
+	if err := p.updateBucketConfig(oldObc, newObc); err != nil {
+		logger.Errorf("failed to update the bucket configuration of OBC %q in namespace %q. %v", newObc.Name, newObc.Namespace, err)
+	}
+}
+
+// claimConfigChanged returns whether the configuration of a bound OBC must be applied again, either because its
+// additionalConfig changed or because the watch resynced the OBC
+func claimConfigChanged(oldObc, newObc *bktv1alpha1.ObjectBucketClaim) bool {
+	if !claimBound(newObc) {
+		return false
+	}
+	if oldObc.ResourceVersion == newObc.ResourceVersion {
+		// a resync
+		return true
+	}
+	return !reflect.DeepEqual(oldObc.Spec.AdditionalConfig, newObc.Spec.AdditionalConfig)
+}
+
+// claimBound returns whether an OBC has a bucket that is not being deleted
+func claimBound(obc *bktv1alpha1.ObjectBucketClaim) bool {
+	return obc.DeletionTimestamp == nil && obc.Status.Phase == bktv1alpha1.ObjectBucketClaimStatusPhaseBound && obc.Spec.ObjectBucketName != ""
+}
+
+// updateBucketConfig applies the configuration of the new OBC to its bucket, removing the settings only
+// present in the old OBC, if any. The receiver is a copy so the shared provisioner is not modified.
+func (p Provisioner) updateBucketConfig(oldObc, newObc *bktv1alpha1.ObjectBucketClaim) error {
+	ob, err := p.claimClientset.ObjectbucketV1alpha1().ObjectBuckets().Get(newObc.Spec.ObjectBucketName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get object bucket %q", newObc.Spec.ObjectBucketName)
+	}
+
+	sc, err := p.getStorageClassWithBackoff(ob.Spec.StorageClassName)
+	if err != nil {
+		return err
+	}
+	if sc.Provisioner != cephObject.GetObjectBucketProvisioner(p.context, p.namespace) {
+		// the bucket is provisioned by another provisioner
+		return nil
+	}
+
+	desired, err := parseBucketConfig(bucketConfigParameters(sc, newObc.Spec.AdditionalConfig))
+	if err != nil {
+		return errors.Wrap(err, "invalid bucket configuration")
+	}
+	var previous *bucketConfig
+	if oldObc != nil {
+		previous, err = parseBucketConfig(bucketConfigParameters(sc, oldObc.Spec.AdditionalConfig))
+		if err != nil {
+			// the previous configuration was never applied
+			previous = nil
+		}
+	}
+
+	logger.Infof("updating configuration of bucket %q for OBC %q", getBucketName(ob), newObc.Name)
+	err = p.initializeDeleteOrRevoke(ob)
+	if err != nil {
+		return err
+	}
+
+	s3svc, err := p.getBucketOwnerS3Agent()
+	if err != nil {
+		return err
+	}
+	return p.applyBucketConfig(s3svc, desired, previous)
+}
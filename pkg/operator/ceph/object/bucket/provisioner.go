@@ -58,6 +58,8 @@ type Provisioner struct {
 	secretName           string
 	secretNamespace      string
 	additionalConfigData map[string]string
+	// the versioning, lifecycle, object lock, CORS and quota settings of the bucket
+	bucketConfig *bucketConfig
 }
 
 var _ apibkt.Provisioner = &Provisioner{}
@@ -89,8 +91,12 @@ func (p Provisioner) Provision(options *apibkt.BucketOptions) (*bktv1alpha1.Obje
 		return nil, err
 	}
 
-	// create the bucket
-	err = s3svc.CreateBucket(p.bucketName)
+	// create the bucket, object lock can only be enabled at creation
+	if p.bucketConfig.objectLock {
+		err = s3svc.CreateBucketWithObjectLock(p.bucketName)
+	} else {
+		err = s3svc.CreateBucket(p.bucketName)
+	}
 	if err != nil {
 		err = errors.Wrapf(err, "error creating bucket %q", p.bucketName)
 		logger.Errorf(err.Error())
@@ -105,6 +111,12 @@ func (p Provisioner) Provision(options *apibkt.BucketOptions) (*bktv1alpha1.Obje
 	}
 	logger.Infof("set user %q bucket max to %d", p.cephUserName, maxBuckets)
 
+	err = p.applyBucketConfig(s3svc, p.bucketConfig, nil)
+	if err != nil {
+		p.deleteOBCResource(p.bucketName)
+		return nil, errors.Wrapf(err, "failed to configure bucket %q", p.bucketName)
+	}
+
 	return p.composeObjectBucket(), nil
 }
 
@@ -137,19 +149,7 @@ func (p Provisioner) Grant(options *apibkt.BucketOptions) (*bktv1alpha1.ObjectBu
 		return nil, err
 	}
 
-	// get the bucket's owner via the bucket metadata
-	stats, _, err := cephObject.GetBucket(p.objectContext, p.bucketName)
-	if err != nil {
-		p.deleteOBCResource("")
-		return nil, errors.Wrapf(err, "could not get bucket stats (bucket: %s)", p.bucketName)
-	}
-	objectUser, _, err := cephObject.GetUser(p.objectContext, stats.Owner)
-	if err != nil {
-		p.deleteOBCResource("")
-		return nil, errors.Wrapf(err, "could not get user (user: %s)", stats.Owner)
-	}
-
-	s3svc, err := NewS3Agent(*objectUser.AccessKey, *objectUser.SecretKey, p.getObjectStoreEndpoint())
+	s3svc, err := p.getBucketOwnerS3Agent()
 	if err != nil {
 		p.deleteOBCResource("")
 		return nil, err
@@ -185,6 +185,13 @@ func (p Provisioner) Grant(options *apibkt.BucketOptions) (*bktv1alpha1.ObjectBu
 		p.deleteOBCResource("")
 		return nil, err
 	}
+
+	err = p.applyBucketConfig(s3svc, p.bucketConfig, nil)
+	if err != nil {
+		p.deleteOBCResource("")
+		return nil, errors.Wrapf(err, "failed to configure bucket %q", p.bucketName)
+	}
+
 	// returned ob with connection info
 	return p.composeObjectBucket(), nil
 }
@@ -324,8 +331,16 @@ func (p *Provisioner) initializeCreateOrGrant(options *apibkt.BucketOptions) err
 	p.setObjectStoreName(sc)
 	p.setObjectStoreNamespace(sc)
 	p.setRegion(sc)
-	p.setAdditionalConfigData(obc.Spec.AdditionalConfig)
+	p.bucketConfig, err = parseBucketConfig(bucketConfigParameters(sc, obc.Spec.AdditionalConfig))
+	if err != nil {
+		return errors.Wrapf(err, "invalid bucket configuration of OBC %q in namespace %q", obc.Name, obc.Namespace)
+	}
 	p.setEndpoint(sc)
+	caBundle, err := p.getObjectStoreCABundle()
+	if err != nil {
+		return err
+	}
+	p.setAdditionalConfigData(obc.Spec.AdditionalConfig, caBundle)
 	err = p.setObjectContext()
 	if err != nil {
 		return err
@@ -366,6 +381,21 @@ func (p *Provisioner) initializeDeleteOrRevoke(ob *bktv1alpha1.ObjectBucket) err
 	return nil
 }
 
+// getBucketOwnerS3Agent returns an S3Agent with the credentials of the bucket's owner, found via
+// the bucket metadata
+func (p *Provisioner) getBucketOwnerS3Agent() (*S3Agent, error) {
+	stats, _, err := cephObject.GetBucket(p.objectContext, p.bucketName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get bucket stats (bucket: %s)", p.bucketName)
+	}
+	objectUser, _, err := cephObject.GetUser(p.objectContext, stats.Owner)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get user (user: %s)", stats.Owner)
+	}
+
+	return NewS3Agent(*objectUser.AccessKey, *objectUser.SecretKey, p.getObjectStoreEndpoint())
+}
+
 // Return the OB struct with minimal fields filled in.
 func (p *Provisioner) composeObjectBucket() *bktv1alpha1.ObjectBucket {
 
@@ -449,11 +479,30 @@ func (p *Provisioner) setBucketName(name string) {
 	p.bucketName = name
 }
 
-func (p *Provisioner) setAdditionalConfigData(additionalConfigData map[string]string) {
-	if len(additionalConfigData) == 0 {
-		additionalConfigData = make(map[string]string)
+// setAdditionalConfigData sets the additional config of the object bucket to the one of the claim, along with the CA
+// bundle of the object store certificate so that the consumers of the bucket can trust it
+func (p *Provisioner) setAdditionalConfigData(additionalConfigData map[string]string, caBundle []byte) {
+	p.additionalConfigData = make(map[string]string, len(additionalConfigData)+1)
+	for key, value := range additionalConfigData {
+		p.additionalConfigData[key] = value
+	}
+	if len(caBundle) > 0 {
+		p.additionalConfigData[caBundleKey] = string(caBundle)
+	}
+}
+
+// getObjectStoreCABundle returns the CA bundle of the certificate of the object store, if any.
+// must be called after setObjectStoreName, setObjectStoreNamespace and setEndpoint
+func (p *Provisioner) getObjectStoreCABundle() ([]byte, error) {
+	// the certificate of an external object store is not managed by rook
+	if p.endpoint != "" {
+		return nil, nil
+	}
+	store, err := getObjectStore(p.context.RookClientset.CephV1(), p.objectStoreNamespace, p.objectStoreName)
+	if err != nil {
+		return nil, err
 	}
-	p.additionalConfigData = additionalConfigData
+	return cephObject.CABundle(p.context, store)
 }
 
 func (p *Provisioner) setEndpoint(sc *storagev1.StorageClass) {
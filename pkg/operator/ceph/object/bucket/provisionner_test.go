@@ -104,3 +104,38 @@ func TestPopulateDomainAndPort(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "rook-ceph-rgw-my-store.rook-ceph", p.storeDomainName)
 }
+
+func TestSetAdditionalConfigData(t *testing.T) {
+	p := NewProvisioner(&clusterd.Context{RookClientset: rookclient.NewSimpleClientset(), Clientset: test.New(t, 1)}, namespace, client.AdminUsername)
+	p.objectStoreName = store
+	p.objectStoreNamespace = namespace
+	cephObjectStore := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: store, Namespace: namespace},
+		Spec: cephv1.ObjectStoreSpec{
+			Gateway: cephv1.GatewaySpec{SSLCertificateRef: "my-cert", SecurePort: int32(443)},
+		},
+	}
+	_, err := p.context.RookClientset.CephV1().CephObjectStores(namespace).Create(cephObjectStore)
+	assert.NoError(t, err)
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cert", Namespace: namespace},
+		Data:       map[string][]byte{"tls.crt": []byte("crt"), "tls.key": []byte("key"), "ca.crt": []byte("ca")},
+	}
+	_, err = p.context.Clientset.CoreV1().Secrets(namespace).Create(secret)
+	assert.NoError(t, err)
+
+	// The CA bundle of the object store is published with the config of the claim
+	caBundle, err := p.getObjectStoreCABundle()
+	assert.NoError(t, err)
+	claimConfig := map[string]string{"maxObjects": "1000"}
+	p.setAdditionalConfigData(claimConfig, caBundle)
+	assert.Equal(t, map[string]string{"maxObjects": "1000", "caBundle": "ca"}, p.additionalConfigData)
+	assert.Equal(t, map[string]string{"maxObjects": "1000"}, claimConfig)
+
+	// The certificate of an external object store is not known
+	p.endpoint = "192.168.0.1:443"
+	caBundle, err = p.getObjectStoreCABundle()
+	assert.NoError(t, err)
+	p.setAdditionalConfigData(nil, caBundle)
+	assert.Equal(t, map[string]string{}, p.additionalConfigData)
+}
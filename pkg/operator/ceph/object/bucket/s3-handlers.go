@@ -17,12 +17,17 @@ limitations under the License.
 package bucket
 
 import (
+	"bytes"
 	"fmt"
+	"net/http"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/private/protocol"
+	"github.com/aws/aws-sdk-go/private/protocol/restxml"
 	"github.com/aws/aws-sdk-go/service/s3"
 	oerrors "github.com/kube-object-storage/lib-bucket-provisioner/pkg/provisioner/api/errors"
 	"github.com/pkg/errors"
@@ -37,9 +42,14 @@ type S3Agent struct {
 func NewS3Agent(accessKey, secretKey, endpoint string) (*S3Agent, error) {
 	const cephRegion = "us-east-1"
 
+	return NewS3AgentForRegion(accessKey, secretKey, endpoint, cephRegion)
+}
+
+// NewS3AgentForRegion creates an S3Agent for the buckets of a region. The endpoint uses http unless its scheme is set.
+func NewS3AgentForRegion(accessKey, secretKey, endpoint, region string) (*S3Agent, error) {
 	sess, err := session.NewSession(
 		aws.NewConfig().
-			WithRegion(cephRegion).
+			WithRegion(region).
 			WithCredentials(credentials.NewStaticCredentials(accessKey, secretKey, "")).
 			WithEndpoint(endpoint).
 			WithS3ForcePathStyle(true).
@@ -56,10 +66,23 @@ func NewS3Agent(accessKey, secretKey, endpoint string) (*S3Agent, error) {
 
 // CreateBucket creates a bucket with the given name
 func (s S3Agent) CreateBucket(name string) error {
-	logger.Infof("creating bucket %q", name)
-	bucketInput := &s3.CreateBucketInput{
+	return s.createBucket(&s3.CreateBucketInput{
 		Bucket: &name,
-	}
+	})
+}
+
+// CreateBucketWithObjectLock creates a bucket with the given name and object lock enabled. Object lock
+// can only be enabled when the bucket is created.
+func (s S3Agent) CreateBucketWithObjectLock(name string) error {
+	return s.createBucket(&s3.CreateBucketInput{
+		Bucket:                     &name,
+		ObjectLockEnabledForBucket: aws.Bool(true),
+	})
+}
+
+func (s S3Agent) createBucket(bucketInput *s3.CreateBucketInput) error {
+	name := *bucketInput.Bucket
+	logger.Infof("creating bucket %q", name)
 	_, err := s.client.CreateBucket(bucketInput)
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok {
@@ -82,6 +105,19 @@ func (s S3Agent) CreateBucket(name string) error {
 	return nil
 }
 
+// PutObject uploads an object to a bucket
+func (s S3Agent) PutObject(bucket, key string, body []byte) error {
+	_, err := s.client.PutObject(&s3.PutObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+		Body:   bytes.NewReader(body),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to upload object %q to bucket %q", key, bucket)
+	}
+	return nil
+}
+
 // PutBucketPolicy applies the policy to the bucket
 func (s S3Agent) PutBucketPolicy(bucket string, policy BucketPolicy) (*s3.PutBucketPolicyOutput, error) {
 
@@ -117,6 +153,153 @@ func (s S3Agent) GetBucketPolicy(bucket string) (*BucketPolicy, error) {
 	return policy, nil
 }
 
+// PutBucketVersioning sets the versioning state of the bucket to "Enabled" or "Suspended"
+func (s S3Agent) PutBucketVersioning(bucket, status string) error {
+	_, err := s.client.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket: &bucket,
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: &status,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set versioning of bucket %q to %q", bucket, status)
+	}
+	return nil
+}
+
+// PutBucketLifecycle replaces the lifecycle rules of the bucket
+func (s S3Agent) PutBucketLifecycle(bucket string, rules []*s3.LifecycleRule) error {
+	_, err := s.client.PutBucketLifecycleConfiguration(&s3.PutBucketLifecycleConfigurationInput{
+		Bucket: &bucket,
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+			Rules: rules,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set lifecycle of bucket %q", bucket)
+	}
+	return nil
+}
+
+// DeleteBucketLifecycle removes the lifecycle rules of the bucket
+func (s S3Agent) DeleteBucketLifecycle(bucket string) error {
+	_, err := s.client.DeleteBucketLifecycle(&s3.DeleteBucketLifecycleInput{
+		Bucket: &bucket,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete lifecycle of bucket %q", bucket)
+	}
+	return nil
+}
+
+// PutBucketCors replaces the CORS rules of the bucket
+func (s S3Agent) PutBucketCors(bucket string, rules []*s3.CORSRule) error {
+	_, err := s.client.PutBucketCors(&s3.PutBucketCorsInput{
+		Bucket: &bucket,
+		CORSConfiguration: &s3.CORSConfiguration{
+			CORSRules: rules,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set CORS of bucket %q", bucket)
+	}
+	return nil
+}
+
+// DeleteBucketCors removes the CORS rules of the bucket
+func (s S3Agent) DeleteBucketCors(bucket string) error {
+	_, err := s.client.DeleteBucketCors(&s3.DeleteBucketCorsInput{
+		Bucket: &bucket,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete CORS of bucket %q", bucket)
+	}
+	return nil
+}
+
+// PutObjectLockConfiguration sets the default retention of the objects of a bucket created with
+// object lock. An empty mode keeps object lock enabled without a default retention.
+func (s S3Agent) PutObjectLockConfiguration(bucket, mode string, days int64) error {
+	config := &s3.ObjectLockConfiguration{
+		ObjectLockEnabled: aws.String(s3.ObjectLockEnabledEnabled),
+	}
+	if mode != "" {
+		config.Rule = &s3.ObjectLockRule{
+			DefaultRetention: &s3.DefaultRetention{
+				Mode: &mode,
+				Days: &days,
+			},
+		}
+	}
+	_, err := s.client.PutObjectLockConfiguration(&s3.PutObjectLockConfigurationInput{
+		Bucket:                  &bucket,
+		ObjectLockConfiguration: config,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set object lock of bucket %q", bucket)
+	}
+	return nil
+}
+
+// PutBucketNotification adds the notification to the bucket, replacing the notification with the same ID and keeping
+// the other notifications of the bucket
+func (s S3Agent) PutBucketNotification(bucket string, notification *s3.TopicConfiguration) error {
+	current, err := s.client.GetBucketNotificationConfiguration(&s3.GetBucketNotificationConfigurationRequest{
+		Bucket: &bucket,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get notifications of bucket %q", bucket)
+	}
+
+	topics := []*s3.TopicConfiguration{notification}
+	for _, topic := range current.TopicConfigurations {
+		if aws.StringValue(topic.Id) != aws.StringValue(notification.Id) {
+			topics = append(topics, topic)
+		}
+	}
+	_, err = s.client.PutBucketNotificationConfiguration(&s3.PutBucketNotificationConfigurationInput{
+		Bucket: &bucket,
+		NotificationConfiguration: &s3.NotificationConfiguration{
+			TopicConfigurations: topics,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set notification %q of bucket %q", aws.StringValue(notification.Id), bucket)
+	}
+	return nil
+}
+
+type deleteBucketNotificationInput struct {
+	_ struct{} `type:"structure"`
+
+	Bucket         *string `location:"uri" locationName:"Bucket" type:"string" required:"true"`
+	NotificationID *string `location:"querystring" locationName:"notification" type:"string"`
+}
+
+type deleteBucketNotificationOutput struct {
+	_ struct{} `type:"structure"`
+}
+
+// DeleteBucketNotification deletes a notification of the bucket. The rgw extension of the s3 API deleting a single
+// notification is used since the s3 API can only replace all the notifications of the bucket.
+func (s S3Agent) DeleteBucketNotification(bucket, id string) error {
+	op := &request.Operation{
+		Name:       "DeleteBucketNotification",
+		HTTPMethod: "DELETE",
+		HTTPPath:   "/{Bucket}",
+	}
+	req := s.client.NewRequest(op, &deleteBucketNotificationInput{Bucket: &bucket, NotificationID: &id}, &deleteBucketNotificationOutput{})
+	req.Handlers.Unmarshal.Swap(restxml.UnmarshalHandler.Name, protocol.UnmarshalDiscardBodyHandler)
+	if err := req.Send(); err != nil {
+		// the notification or the bucket is already gone
+		if aerr, ok := err.(awserr.RequestFailure); ok && aerr.StatusCode() == http.StatusNotFound {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete notification %q of bucket %q", id, bucket)
+	}
+	return nil
+}
+
 // //////////////
 // Policy
 // //////////////
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketNotification(t *testing.T) {
+	var requests []string
+	var putBody string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.Method {
+		case http.MethodGet:
+			_, err := w.Write([]byte(`<NotificationConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <TopicConfiguration><Id>other</Id><Topic>arn:aws:sns:my-store::other-topic</Topic><Event>s3:ObjectCreated:*</Event></TopicConfiguration>
+  <TopicConfiguration><Id>my-notification</Id><Topic>arn:aws:sns:my-store::old-topic</Topic><Event>s3:ObjectCreated:*</Event></TopicConfiguration>
+</NotificationConfiguration>`))
+			assert.NoError(t, err)
+		case http.MethodPut:
+			body, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+			putBody = string(body)
+		default:
+			w.WriteHeader(status)
+		}
+	}))
+	defer server.Close()
+	s3svc, err := NewS3Agent("access", "secret", strings.TrimPrefix(server.URL, "http://"))
+	assert.NoError(t, err)
+
+	// the notification with the same id is replaced, the other notifications are kept
+	err = s3svc.PutBucketNotification("my-bucket", &s3.TopicConfiguration{
+		Id:       aws.String("my-notification"),
+		TopicArn: aws.String("arn:aws:sns:my-store::my-topic"),
+		Events:   aws.StringSlice([]string{"s3:ObjectRemoved:*"}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GET /my-bucket?notification=", "PUT /my-bucket?notification="}, requests)
+	assert.Equal(t, 2, strings.Count(putBody, "<TopicConfiguration>"), putBody)
+	assert.True(t, strings.Contains(putBody, "<Topic>arn:aws:sns:my-store::my-topic</Topic>"), putBody)
+	assert.True(t, strings.Contains(putBody, "<Topic>arn:aws:sns:my-store::other-topic</Topic>"), putBody)
+	assert.False(t, strings.Contains(putBody, "old-topic"), putBody)
+
+	// a single notification is deleted
+	requests = nil
+	err = s3svc.DeleteBucketNotification("my-bucket", "my-notification")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DELETE /my-bucket?notification=my-notification"}, requests)
+
+	// the notification is already gone
+	status = http.StatusNotFound
+	err = s3svc.DeleteBucketNotification("my-bucket", "my-notification")
+	assert.NoError(t, err)
+
+	status = http.StatusForbidden
+	err = s3svc.DeleteBucketNotification("my-bucket", "my-notification")
+	assert.Error(t, err)
+}
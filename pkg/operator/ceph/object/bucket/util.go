@@ -47,6 +47,8 @@ const (
 	objectStoreName      = "objectStoreName"
 	objectStoreNamespace = "objectStoreNamespace"
 	objectStoreEndpoint  = "endpoint"
+	// the key of the additional config of the object buckets with the CA bundle of the object store certificate
+	caBundleKey = "caBundle"
 )
 
 func NewBucketController(cfg *rest.Config, p *Provisioner) (*provisioner.Provisioner, error) {
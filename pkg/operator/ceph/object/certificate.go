@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/admission"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// the annotation of the rgw pods with the hash of their certificate, so that they restart when it changes
+	certificateHashAnnotation = "ceph.rook.io/certificate-hash"
+	tlsCertKeyName            = admission.TLSCertKey
+	tlsKeyKeyName             = admission.TLSKeyKey
+	keyFilename               = "rgw-key.pem"
+	caValidity                = 5 * 365 * 24 * time.Hour
+	certificateValidity       = 365 * 24 * time.Hour
+)
+
+// rgwCertificate is the certificate of the rgw pods
+type rgwCertificate struct {
+	// whether the certificate and its key are in the "tls.crt" and "tls.key" keys of the secret rather than in a
+	// single PEM under the "cert" key
+	tlsSecret bool
+	// the hash of the certificate and its key
+	hash string
+}
+
+// CertificateSecretName returns the name of the secret holding the certificate of the rgw pods of an object store,
+// or an empty string when the object store does not serve TLS
+func CertificateSecretName(store *cephv1.CephObjectStore) string {
+	if store.Spec.Gateway.SSLCertificateRef != "" {
+		return store.Spec.Gateway.SSLCertificateRef
+	}
+	if store.Spec.Gateway.GenerateSSLCertificate {
+		return generatedCertificateSecretName(store.Name)
+	}
+	return ""
+}
+
+func generatedCertificateSecretName(storeName string) string {
+	return fmt.Sprintf("%s-tls", instanceName(storeName))
+}
+
+// CABundle returns the certificate authorities issuing the certificate of the rgw pods of an object store, if any
+func CABundle(context *clusterd.Context, store *cephv1.CephObjectStore) ([]byte, error) {
+	secretName := CertificateSecretName(store)
+	if secretName == "" {
+		return nil, nil
+	}
+	secret, err := context.Clientset.CoreV1().Secrets(store.Namespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get certificate secret %q of object store %q", secretName, store.Name)
+	}
+	return admission.CABundle(secret.Data), nil
+}
+
+// generatedCertificates returns the certificates generated for the rgw pods of an object store
+func generatedCertificates(store *cephv1.CephObjectStore) *admission.GeneratedCertificates {
+	return &admission.GeneratedCertificates{
+		CAName:       fmt.Sprintf("%s-ca", generatedCertificateSecretName(store.Name)),
+		DNSNames:     rgwDNSNames(store),
+		CAValidity:   caValidity,
+		CertValidity: certificateValidity,
+	}
+}
+
+// rgwDNSNames returns the names of the service of the object store
+func rgwDNSNames(store *cephv1.CephObjectStore) []string {
+	name := instanceName(store.Name)
+	return []string{
+		fmt.Sprintf("%s.%s.svc", name, store.Namespace),
+		fmt.Sprintf("%s.%s", name, store.Namespace),
+		name,
+	}
+}
+
+// loadCertificate reads the certificate of the rgw pods from its secret
+func (c *clusterConfig) loadCertificate() (*rgwCertificate, error) {
+	secretName := CertificateSecretName(c.store)
+	if secretName == "" {
+		return nil, nil
+	}
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get certificate secret %q", secretName)
+	}
+
+	keys := []string{certKeyName}
+	tlsSecret := false
+	if _, ok := secret.Data[certKeyName]; !ok {
+		if _, ok := secret.Data[tlsCertKeyName]; !ok {
+			return nil, errors.Errorf("certificate secret %q has neither a %q key nor a %q key", secretName, certKeyName, tlsCertKeyName)
+		}
+		if _, ok := secret.Data[tlsKeyKeyName]; !ok {
+			return nil, errors.Errorf("certificate secret %q has no %q key", secretName, tlsKeyKeyName)
+		}
+		keys = []string{tlsCertKeyName, tlsKeyKeyName}
+		tlsSecret = true
+	}
+
+	return &rgwCertificate{tlsSecret: tlsSecret, hash: hashSecretData(secret, keys)}, nil
+}
+
+// hashSecretData returns a hash of the values of the keys of a secret
+func hashSecretData(secret *v1.Secret, keys []string) string {
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write(secret.Data[key])
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// reconcileGeneratedCertificate generates the certificate of the rgw pods when requested, and renews it and its
+// certificate authority before they expire. It returns the time the certificate must be checked again, or a zero
+// time when the certificate is not generated.
+func (c *clusterConfig) reconcileGeneratedCertificate(now time.Time) (time.Time, error) {
+	if c.store.Spec.Gateway.SSLCertificateRef != "" || !c.store.Spec.Gateway.GenerateSSLCertificate {
+		return time.Time{}, nil
+	}
+
+	secretName := generatedCertificateSecretName(c.store.Name)
+	secrets := c.context.Clientset.CoreV1().Secrets(c.store.Namespace)
+	secret, err := secrets.Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return time.Time{}, errors.Wrapf(err, "failed to get certificate secret %q", secretName)
+		}
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            secretName,
+				Namespace:       c.store.Namespace,
+				Labels:          getLabels(c.store.Name, c.store.Namespace),
+				OwnerReferences: []metav1.OwnerReference{*c.ownerRef},
+			},
+			Type: v1.SecretTypeTLS,
+			Data: map[string][]byte{},
+		}
+		if err := generatedCertificates(c.store).Renew(secret.Data, now); err != nil {
+			return time.Time{}, errors.Wrapf(err, "failed to generate the certificates of secret %q", secretName)
+		}
+		if _, err := secrets.Create(secret); err != nil {
+			return time.Time{}, errors.Wrapf(err, "failed to create certificate secret %q", secretName)
+		}
+		logger.Infof("generated the certificate of object store %q in secret %q", c.store.Name, secretName)
+	} else {
+		renewed, err := generatedCertificates(c.store).RenewIfExpiring(secret.Data, now)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "failed to renew the certificates of secret %q", secretName)
+		}
+		if renewed {
+			if _, err := secrets.Update(secret); err != nil {
+				return time.Time{}, errors.Wrapf(err, "failed to update certificate secret %q", secretName)
+			}
+			logger.Infof("renewed the certificate of object store %q in secret %q", c.store.Name, secretName)
+		}
+	}
+
+	return generatedCertificates(c.store).NextRenewal(secret.Data, now), nil
+}
+
+// certificateRenewalDelay returns how long to wait before renewing the certificate, retrying shortly when the renewal
+// is already due
+func certificateRenewalDelay(renewal, now time.Time) time.Duration {
+	if delay := renewal.Sub(now); delay > time.Minute {
+		return delay
+	}
+	return time.Minute
+}
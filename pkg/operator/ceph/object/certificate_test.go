@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/admission"
+	testop "github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newCertificateConfig(t *testing.T) *clusterConfig {
+	store := simpleStore()
+	store.Spec.Gateway.SecurePort = 443
+	return &clusterConfig{
+		context:  &clusterd.Context{Clientset: testop.New(t, 1)},
+		store:    store,
+		ownerRef: &metav1.OwnerReference{Name: store.Name},
+	}
+}
+
+func TestCertificateSecretName(t *testing.T) {
+	store := simpleStore()
+	assert.Equal(t, "", CertificateSecretName(store))
+
+	store.Spec.Gateway.GenerateSSLCertificate = true
+	assert.Equal(t, "rook-ceph-rgw-default-tls", CertificateSecretName(store))
+
+	// the certificate of the user has precedence
+	store.Spec.Gateway.SSLCertificateRef = "mycert"
+	assert.Equal(t, "mycert", CertificateSecretName(store))
+}
+
+func TestLoadCertificate(t *testing.T) {
+	c := newCertificateConfig(t)
+	secrets := c.context.Clientset.CoreV1().Secrets(c.store.Namespace)
+
+	// no certificate
+	certificate, err := c.loadCertificate()
+	assert.NoError(t, err)
+	assert.Nil(t, certificate)
+
+	// the secret is missing
+	c.store.Spec.Gateway.SSLCertificateRef = "mycert"
+	_, err = c.loadCertificate()
+	assert.Error(t, err)
+
+	// a PEM with the certificate and its key
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mycert", Namespace: c.store.Namespace},
+		Data:       map[string][]byte{certKeyName: []byte("pem")},
+	}
+	_, err = secrets.Create(secret)
+	assert.NoError(t, err)
+	certificate, err = c.loadCertificate()
+	assert.NoError(t, err)
+	assert.False(t, certificate.tlsSecret)
+	pemHash := certificate.hash
+
+	// a kubernetes.io/tls secret requires the key
+	secret.Data = map[string][]byte{tlsCertKeyName: []byte("crt")}
+	_, err = secrets.Update(secret)
+	assert.NoError(t, err)
+	_, err = c.loadCertificate()
+	assert.Error(t, err)
+
+	secret.Data[tlsKeyKeyName] = []byte("key")
+	_, err = secrets.Update(secret)
+	assert.NoError(t, err)
+	certificate, err = c.loadCertificate()
+	assert.NoError(t, err)
+	assert.True(t, certificate.tlsSecret)
+	assert.NotEqual(t, pemHash, certificate.hash)
+}
+
+func TestReconcileGeneratedCertificate(t *testing.T) {
+	c := newCertificateConfig(t)
+	now := time.Now()
+
+	// the certificate is not generated unless requested
+	next, err := c.reconcileGeneratedCertificate(now)
+	assert.NoError(t, err)
+	assert.True(t, next.IsZero())
+
+	// the certificate is generated for the names of the service
+	c.store.Spec.Gateway.GenerateSSLCertificate = true
+	next, err = c.reconcileGeneratedCertificate(now)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, now.Add(certificateValidity-certificateValidity/5), next, time.Minute)
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Get("rook-ceph-rgw-default-tls", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, v1.SecretTypeTLS, secret.Type)
+	assert.Equal(t, c.store.Name, secret.OwnerReferences[0].Name)
+	for _, key := range []string{admission.CACertKey, admission.CAKeyKey, tlsCertKeyName, tlsKeyKeyName} {
+		assert.NotEmpty(t, secret.Data[key])
+	}
+	certificate, err := c.loadCertificate()
+	assert.NoError(t, err)
+	assert.True(t, certificate.tlsSecret)
+
+	// the certificate is kept until it expires soon
+	_, err = c.reconcileGeneratedCertificate(now.Add(time.Hour))
+	assert.NoError(t, err)
+	unchanged, err := c.loadCertificate()
+	assert.NoError(t, err)
+	assert.Equal(t, certificate.hash, unchanged.hash)
+
+	// the certificate is renewed by the same certificate authority
+	_, err = c.reconcileGeneratedCertificate(next.Add(time.Hour))
+	assert.NoError(t, err)
+	renewed, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Get(secret.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret.Data[tlsCertKeyName], renewed.Data[tlsCertKeyName])
+	assert.Equal(t, secret.Data[admission.CACertKey], renewed.Data[admission.CACertKey])
+
+	// the certificate authority is renewed and the previous one remains in the bundle until it expires
+	caExpiration, err := admission.CertificateExpiration(secret.Data[admission.CACertKey])
+	assert.NoError(t, err)
+	_, err = c.reconcileGeneratedCertificate(caExpiration.Add(-time.Hour))
+	assert.NoError(t, err)
+	bundle, err := CABundle(c.context, c.store)
+	assert.NoError(t, err)
+	renewed, err = c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Get(secret.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret.Data[admission.CACertKey], renewed.Data[admission.CACertKey])
+	assert.Equal(t, append(renewed.Data[admission.CACertKey], secret.Data[admission.CACertKey]...), bundle)
+}
+
+func TestCertificateRenewalDelay(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, time.Hour, certificateRenewalDelay(now.Add(time.Hour), now))
+	assert.Equal(t, time.Minute, certificateRenewalDelay(now.Add(-time.Hour), now))
+}
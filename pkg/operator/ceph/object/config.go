@@ -56,7 +56,7 @@ func (c *clusterConfig) portString() string {
 		}
 		portString = fmt.Sprintf("port=%s", strconv.Itoa(int(port)))
 	}
-	if c.store.Spec.Gateway.SecurePort != 0 && c.store.Spec.Gateway.SSLCertificateRef != "" {
+	if c.store.Spec.Gateway.SecurePort != 0 && CertificateSecretName(c.store) != "" {
 		certPath := path.Join(certDir, certFilename)
 		// This is the beast backend
 		// Config is: http://docs.ceph.com/docs/master/radosgw/frontends/#id3
@@ -67,6 +67,10 @@ func (c *clusterConfig) portString() string {
 			portString = fmt.Sprintf("ssl_port=%d ssl_certificate=%s",
 				c.store.Spec.Gateway.SecurePort, certPath)
 		}
+		// The key is in its own file when the secret is a kubernetes.io/tls secret
+		if c.certificate != nil && c.certificate.tlsSecret {
+			portString = fmt.Sprintf("%s ssl_private_key=%s", portString, path.Join(certDir, keyFilename))
+		}
 	}
 	return portString
 }
@@ -75,6 +75,14 @@ func TestPortString(t *testing.T) {
 	result = cfg.portString()
 	assert.Equal(t, "port=80 ssl_port=443 ssl_certificate=/etc/ceph/private/rgw-cert.pem", result)
 
+	// The key of a kubernetes.io/tls secret is in its own file
+	cfg = newConfig()
+	cfg.store.Spec.Gateway.SecurePort = 443
+	cfg.store.Spec.Gateway.GenerateSSLCertificate = true
+	cfg.certificate = &rgwCertificate{tlsSecret: true}
+	result = cfg.portString()
+	assert.Equal(t, "ssl_port=443 ssl_certificate=/etc/ceph/private/rgw-cert.pem ssl_private_key=/etc/ceph/private/rgw-key.pem", result)
+
 	// Secure port requires the cert on beast
 	cfg = newConfig()
 	cfg.store.Spec.Gateway.SecurePort = 443
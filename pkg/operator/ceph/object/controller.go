@@ -82,29 +82,38 @@ type ReconcileCephObjectStore struct {
 	context         *clusterd.Context
 	cephClusterSpec *cephv1.ClusterSpec
 	clusterInfo     *cephconfig.ClusterInfo
+	// the certificate secrets of the object stores
+	secretRefs *opcontroller.SecretReferences
 }
 
 // Add creates a new cephObjectStore Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context) error {
+	// Keep the status of the object stores current between two reconciles
+	refresher := opcontroller.NewStatusRefresher(controllerName, func() { refreshAllStatuses(context, mgr.GetClient()) })
+	if err := mgr.Add(refresher); err != nil {
+		return errors.Wrap(err, "failed to add object store status refresher")
+	}
+
 	return add(mgr, newReconciler(mgr, context))
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Reconciler {
+func newReconciler(mgr manager.Manager, context *clusterd.Context) *ReconcileCephObjectStore {
 	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
 	mgrScheme := mgr.GetScheme()
 	cephv1.AddToScheme(mgr.GetScheme())
 
 	return &ReconcileCephObjectStore{
-		client:    mgr.GetClient(),
-		scheme:    mgrScheme,
-		context:   context,
-		bktclient: bktclient.NewForConfigOrDie(context.KubeConfig),
+		client:     mgr.GetClient(),
+		scheme:     mgrScheme,
+		context:    context,
+		bktclient:  bktclient.NewForConfigOrDie(context.KubeConfig),
+		secretRefs: opcontroller.NewSecretReferences(),
 	}
 }
 
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r *ReconcileCephObjectStore) error {
 	// Create a new controller
 	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -129,6 +138,12 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 	}
 
+	// Watch the certificate secrets to restart the rgw pods when the certificates change
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: r.secretRefs.ToRequests()}, r.secretRefs.Predicate())
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -153,6 +168,7 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			logger.Debug("cephObjectStore resource not found. Ignoring since object must be deleted.")
+			r.secretRefs.Set(request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -161,7 +177,7 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 
 	// The CR was just created, initializing status fields
 	if cephObjectStore.Status == nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.Created)
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil)
 	}
 
 	// Make sure a CephCluster is present otherwise do nothing
@@ -231,16 +247,24 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 		if err != nil {
 			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
 		}
+		r.secretRefs.Set(request.NamespacedName)
 
 		// Return and do not requeue. Successful deletion.
 		return reconcile.Result{}, nil
 	}
 
 	// validate the store settings
-	if err := validateStore(r.context, cephObjectStore); err != nil {
+	if err := ValidateStore(r.context, cephObjectStore); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "invalid object store %q arguments", cephObjectStore.Name)
 	}
 
+	// Restart the rgw pods when their certificate changes
+	if secretName := CertificateSecretName(cephObjectStore); secretName != "" {
+		r.secretRefs.Set(request.NamespacedName, types.NamespacedName{Name: secretName, Namespace: cephObjectStore.Namespace})
+	} else {
+		r.secretRefs.Set(request.NamespacedName)
+	}
+
 	// CREATE/UPDATE
 	logger.Info("reconciling object store deployments")
 	reconcileResponse, err = r.reconcileCreateObjectStore(cephObjectStore, request.NamespacedName)
@@ -249,11 +273,14 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 	}
 
 	// Set Ready status, we are done reconciling
-	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, nil)
 
-	// Return and do not requeue
+	// Report the state of the object store as applied by Ceph
+	refreshStatus(r.context, r.client, cephObjectStore)
+
+	// Return and only requeue to renew the generated certificate
 	logger.Debug("done reconciling")
-	return reconcile.Result{}, nil
+	return reconcileResponse, nil
 }
 
 func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *cephv1.CephObjectStore, name types.NamespacedName) (reconcile.Result, error) {
@@ -320,6 +347,10 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create object store %q", cephObjectStore.Name)
 	}
 
+	// Reconcile again to renew the generated certificate
+	if !cfg.certificateRenewal.IsZero() {
+		return reconcile.Result{RequeueAfter: certificateRenewalDelay(cfg.certificateRenewal, time.Now())}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
@@ -343,48 +374,59 @@ func (r *ReconcileCephObjectStore) reconcileCephZone(store *cephv1.CephObjectSto
 }
 
 func (r *ReconcileCephObjectStore) reconcileMultisiteCRs(cephObjectStore *cephv1.CephObjectStore) (string, string, string, reconcile.Result, error) {
+	realmName, zoneGroupName, zoneName, err := getMultisiteNames(r.context, cephObjectStore)
+	if err != nil {
+		return "", "", "", waitForRequeueIfObjectStoreNotReady, err
+	}
+
+	return realmName, zoneGroupName, zoneName, reconcile.Result{}, nil
+}
+
+// getMultisiteNames returns the names of the realm, zone group and zone of the object store
+func getMultisiteNames(context *clusterd.Context, cephObjectStore *cephv1.CephObjectStore) (string, string, string, error) {
 	if cephObjectStore.Spec.IsMultisite() {
 		zoneName := cephObjectStore.Spec.Zone.Name
-		zone, err := r.context.RookClientset.CephV1().CephObjectZones(cephObjectStore.Namespace).Get(zoneName, metav1.GetOptions{})
+		zone, err := context.RookClientset.CephV1().CephObjectZones(cephObjectStore.Namespace).Get(zoneName, metav1.GetOptions{})
 		if err != nil {
 			if kerrors.IsNotFound(err) {
-				return "", "", "", waitForRequeueIfObjectStoreNotReady, err
+				return "", "", "", err
 			}
-			return "", "", "", waitForRequeueIfObjectStoreNotReady, errors.Wrapf(err, "error getting CephObjectZone %q", cephObjectStore.Spec.Zone.Name)
+			return "", "", "", errors.Wrapf(err, "error getting CephObjectZone %q", cephObjectStore.Spec.Zone.Name)
 		}
-		logger.Infof("CephObjectZone resource %s found", zone.Name)
+		logger.Debugf("CephObjectZone resource %s found", zone.Name)
 
-		zonegroup, err := r.context.RookClientset.CephV1().CephObjectZoneGroups(cephObjectStore.Namespace).Get(zone.Spec.ZoneGroup, metav1.GetOptions{})
+		zonegroup, err := context.RookClientset.CephV1().CephObjectZoneGroups(cephObjectStore.Namespace).Get(zone.Spec.ZoneGroup, metav1.GetOptions{})
 		if err != nil {
 			if kerrors.IsNotFound(err) {
-				return "", "", "", waitForRequeueIfObjectStoreNotReady, err
+				return "", "", "", err
 			}
-			return "", "", "", waitForRequeueIfObjectStoreNotReady, errors.Wrapf(err, "error getting CephObjectZoneGroup %q", zone.Spec.ZoneGroup)
+			return "", "", "", errors.Wrapf(err, "error getting CephObjectZoneGroup %q", zone.Spec.ZoneGroup)
 		}
-		logger.Infof("CephObjectZoneGroup resource %s found", zonegroup.Name)
+		logger.Debugf("CephObjectZoneGroup resource %s found", zonegroup.Name)
 
-		realm, err := r.context.RookClientset.CephV1().CephObjectRealms(cephObjectStore.Namespace).Get(zonegroup.Spec.Realm, metav1.GetOptions{})
+		realm, err := context.RookClientset.CephV1().CephObjectRealms(cephObjectStore.Namespace).Get(zonegroup.Spec.Realm, metav1.GetOptions{})
 		if err != nil {
 			if kerrors.IsNotFound(err) {
-				return "", "", "", waitForRequeueIfObjectStoreNotReady, err
+				return "", "", "", err
 			}
-			return "", "", "", waitForRequeueIfObjectStoreNotReady, errors.Wrapf(err, "error getting CephObjectRealm %q", zonegroup.Spec.Realm)
+			return "", "", "", errors.Wrapf(err, "error getting CephObjectRealm %q", zonegroup.Spec.Realm)
 		}
-		logger.Infof("CephObjectRealm resource %s found", realm.Name)
+		logger.Debugf("CephObjectRealm resource %s found", realm.Name)
 
-		return realm.Name, zonegroup.Name, zone.Name, reconcile.Result{}, nil
+		return realm.Name, zonegroup.Name, zone.Name, nil
 	}
 
-	return cephObjectStore.Name, cephObjectStore.Name, cephObjectStore.Name, reconcile.Result{}, nil
+	return cephObjectStore.Name, cephObjectStore.Name, cephObjectStore.Name, nil
 }
 
 func (r *ReconcileCephObjectStore) setFailedStatus(name types.NamespacedName, errMessage string, err error) (reconcile.Result, error) {
-	updateStatus(r.client, name, k8sutil.ReconcileFailedStatus)
-	return reconcile.Result{}, errors.Wrapf(err, "%s", errMessage)
+	err = errors.Wrapf(err, "%s", errMessage)
+	updateStatus(r.client, name, k8sutil.ReconcileFailedStatus, err)
+	return reconcile.Result{}, err
 }
 
-// updateStatus updates an object with a given status
-func updateStatus(client client.Client, name types.NamespacedName, status string) {
+// updateStatus updates an object with a given status and the matching conditions
+func updateStatus(client client.Client, name types.NamespacedName, status string, reconcileErr error) {
 	objectStore := &cephv1.CephObjectStore{}
 	if err := client.Get(context.TODO(), name, objectStore); err != nil {
 		if kerrors.IsNotFound(err) {
@@ -395,10 +437,11 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 		return
 	}
 	if objectStore.Status == nil {
-		objectStore.Status = &cephv1.Status{}
+		objectStore.Status = &cephv1.ObjectStoreStatus{}
 	}
 
 	objectStore.Status.Phase = status
+	opcontroller.SetPhaseConditions(&objectStore.Status.Conditions, status, reconcileErr)
 	if err := opcontroller.UpdateStatus(client, objectStore); err != nil {
 		logger.Errorf("failed to set object store %q status to %q. %v", name, status, err)
 		return
@@ -27,6 +27,7 @@ import (
 	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
 	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/operator/test"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
@@ -197,7 +198,7 @@ func TestCephObjectStoreController(t *testing.T) {
 	// Create a fake client to mock API calls.
 	cl := fake.NewFakeClientWithScheme(s, object...)
 	// Create a ReconcileCephObjectStore object with the scheme and fake client.
-	r := &ReconcileCephObjectStore{client: cl, scheme: s, context: c}
+	r := &ReconcileCephObjectStore{client: cl, scheme: s, context: c, secretRefs: opcontroller.NewSecretReferences()}
 
 	// Mock request to simulate Reconcile() being called on an event for a
 	// watched resource .
@@ -234,7 +235,7 @@ func TestCephObjectStoreController(t *testing.T) {
 	// Create a fake client to mock API calls.
 	cl = fake.NewFakeClientWithScheme(s, object...)
 	// Create a ReconcileCephObjectStore object with the scheme and fake client.
-	r = &ReconcileCephObjectStore{client: cl, scheme: s, context: c}
+	r = &ReconcileCephObjectStore{client: cl, scheme: s, context: c, secretRefs: opcontroller.NewSecretReferences()}
 	logger.Info("STARTING PHASE 2")
 	res, err = r.Reconcile(req)
 	assert.NoError(t, err)
@@ -305,7 +306,7 @@ func TestCephObjectStoreController(t *testing.T) {
 	c.Executor = executor
 
 	// Create a ReconcileCephObjectStore object with the scheme and fake client.
-	r = &ReconcileCephObjectStore{client: cl, scheme: s, context: c}
+	r = &ReconcileCephObjectStore{client: cl, scheme: s, context: c, secretRefs: opcontroller.NewSecretReferences()}
 
 	logger.Info("STARTING PHASE 3")
 	res, err = r.Reconcile(req)
@@ -0,0 +1,432 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notification to manage the notifications of the buckets of a rook object store.
+package notification
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/coreos/pkg/capnslog"
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/ceph/object/bucket"
+	"github.com/rook/rook/pkg/operator/ceph/object/topic"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-bucket-notification-controller"
+)
+
+var waitForRequeueIfNotificationNotReady = reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephBucketNotificationKind = reflect.TypeOf(cephv1.CephBucketNotification{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephBucketNotificationKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// the events notified when the notification does not list any
+var defaultEvents = []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:*"}
+
+var supportedEvents = []string{
+	"s3:ObjectCreated:*",
+	"s3:ObjectCreated:Put",
+	"s3:ObjectCreated:Post",
+	"s3:ObjectCreated:Copy",
+	"s3:ObjectCreated:CompleteMultipartUpload",
+	"s3:ObjectRemoved:*",
+	"s3:ObjectRemoved:Delete",
+	"s3:ObjectRemoved:DeleteMarkerCreated",
+}
+
+var _ reconcile.Reconciler = &ReconcileBucketNotification{}
+
+// ReconcileBucketNotification reconciles a CephBucketNotification object
+type ReconcileBucketNotification struct {
+	client  client.Client
+	scheme  *runtime.Scheme
+	context *clusterd.Context
+}
+
+// Add creates a new CephBucketNotification Controller and adds it to the Manager. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Reconciler {
+	// Add the cephv1 and the object bucket schemes to the manager scheme so that the controller knows about them
+	mgrScheme := mgr.GetScheme()
+	cephv1.AddToScheme(mgr.GetScheme())
+	bktv1alpha1.AddToScheme(mgr.GetScheme())
+
+	return &ReconcileBucketNotification{
+		client:  mgr.GetClient(),
+		scheme:  mgrScheme,
+		context: context,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephBucketNotification CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephBucketNotification{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephBucketNotification object and makes changes based on the state
+// read and what is in the CephBucketNotification.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileBucketNotification) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileBucketNotification) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephBucketNotification instance
+	notification := &cephv1.CephBucketNotification{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, notification)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketNotification resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephBucketNotification")
+	}
+
+	// The CR was just created, initializing status fields
+	if notification.Status == nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil, nil)
+	}
+
+	// The topic gives the object store of the notification
+	bucketTopic := &cephv1.CephBucketTopic{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: notification.Spec.Topic, Namespace: notification.Namespace}, bucketTopic)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to get the topic of bucket notification %q", request.NamespacedName)
+		}
+		// A topic is not deleted while notifications use it, so the notification was never created
+		if !notification.GetDeletionTimestamp().IsZero() {
+			err = opcontroller.RemoveFinalizer(r.client, notification)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+			return reconcile.Result{}, nil
+		}
+		logger.Infof("waiting for topic %q of bucket notification %q", notification.Spec.Topic, request.NamespacedName)
+		return waitForRequeueIfNotificationNotReady, nil
+	}
+
+	// Make sure a CephCluster is present in the namespace of the object store otherwise do nothing
+	storeName := topic.ObjectStoreName(bucketTopic)
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, storeName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// We skip the deleteNotification() function since everything is gone already
+		if !notification.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.client, notification)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, notification)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// DELETE: the CR was deleted
+	if !notification.GetDeletionTimestamp().IsZero() {
+		logger.Debugf("deleting bucket notification %q", request.NamespacedName)
+		if notification.Status != nil && notification.Status.BucketName != "" {
+			err := r.deleteNotification(notification, storeName, notification.Status.BucketName)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "failed to delete bucket notification %q", request.NamespacedName)
+			}
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, notification)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the notification settings
+	if err := validateNotification(notification); err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid bucket notification CR %q spec", request.NamespacedName)
+	}
+
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReconcilingStatus, nil, nil)
+
+	// The topic must be created and the bucket provisioned
+	if bucketTopic.Status == nil || bucketTopic.Status.ARN == "" {
+		logger.Infof("waiting for topic %q of bucket notification %q to be created", bucketTopic.Name, request.NamespacedName)
+		return waitForRequeueIfNotificationNotReady, nil
+	}
+	bucketName, err := r.getBucketName(notification)
+	if err != nil {
+		logger.Infof("waiting for the bucket of bucket notification %q. %v", request.NamespacedName, err)
+		return waitForRequeueIfNotificationNotReady, nil
+	}
+
+	// The notification moved to another bucket
+	if notification.Status != nil && notification.Status.BucketName != "" && notification.Status.BucketName != bucketName {
+		err := r.deleteNotification(notification, storeName, notification.Status.BucketName)
+		if err != nil {
+			updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err, nil)
+			return reconcile.Result{}, errors.Wrapf(err, "failed to delete bucket notification %q from its previous bucket", request.NamespacedName)
+		}
+	}
+
+	// CREATE/UPDATE
+	err = r.createNotification(notification, storeName, bucketName, bucketTopic.Status.ARN)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to create bucket notification %q", request.NamespacedName)
+	}
+
+	// Set Ready status, we are done reconciling
+	notificationStatus := &cephv1.BucketNotificationStatus{
+		BucketName:         bucketName,
+		TopicARN:           bucketTopic.Status.ARN,
+		ObservedGeneration: notification.Generation,
+		LastSyncTime:       opcontroller.FormatStatusTime(time.Now().UTC()),
+	}
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, nil, notificationStatus)
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// getBucketName returns the name of the bucket of the OBC of the notification once the OBC is bound
+func (r *ReconcileBucketNotification) getBucketName(notification *cephv1.CephBucketNotification) (string, error) {
+	obc := &bktv1alpha1.ObjectBucketClaim{}
+	obcName := types.NamespacedName{Name: notification.Spec.ObjectBucketClaim, Namespace: notification.Namespace}
+	err := r.client.Get(context.TODO(), obcName, obc)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", errors.Errorf("object bucket claim %q not found", obcName)
+		}
+		return "", errors.Wrapf(err, "failed to get object bucket claim %q", obcName)
+	}
+	if obc.Status.Phase != bktv1alpha1.ObjectBucketClaimStatusPhaseBound || obc.Spec.BucketName == "" {
+		return "", errors.Errorf("object bucket claim %q is not bound", obcName)
+	}
+	return obc.Spec.BucketName, nil
+}
+
+// createNotification creates or updates the notification of the bucket
+func (r *ReconcileBucketNotification) createNotification(notification *cephv1.CephBucketNotification, storeName types.NamespacedName, bucketName, topicARN string) error {
+	s3Agent, err := r.newBucketOwnerS3Agent(storeName, bucketName)
+	if err != nil {
+		return err
+	}
+
+	err = s3Agent.PutBucketNotification(bucketName, topicConfiguration(notification, topicARN))
+	if err != nil {
+		return err
+	}
+	logger.Infof("created notification %q of bucket %q", notification.Name, bucketName)
+	return nil
+}
+
+// deleteNotification deletes the notification from the bucket
+func (r *ReconcileBucketNotification) deleteNotification(notification *cephv1.CephBucketNotification, storeName types.NamespacedName, bucketName string) error {
+	s3Agent, err := r.newBucketOwnerS3Agent(storeName, bucketName)
+	if err != nil {
+		if errors.Cause(err) == errBucketNotFound {
+			// The notification is gone with the bucket
+			return nil
+		}
+		return err
+	}
+
+	err = s3Agent.DeleteBucketNotification(bucketName, notification.Name)
+	if err != nil {
+		return err
+	}
+	logger.Infof("deleted notification %q of bucket %q", notification.Name, bucketName)
+	return nil
+}
+
+var errBucketNotFound = errors.New("bucket not found")
+
+// newBucketOwnerS3Agent returns an S3Agent signing the requests with the owner of the bucket. The notifications of a
+// bucket can only be changed by its owner.
+func (r *ReconcileBucketNotification) newBucketOwnerS3Agent(storeName types.NamespacedName, bucketName string) (*bucket.S3Agent, error) {
+	store := &cephv1.CephObjectStore{}
+	err := r.client.Get(context.TODO(), storeName, store)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, errors.Wrapf(errBucketNotFound, "object store %q not found", storeName)
+		}
+		return nil, errors.Wrapf(err, "failed to get object store %q", storeName)
+	}
+
+	objContext := object.NewContext(r.context, store.Name, store.Namespace)
+	stats, errCode, err := object.GetBucket(objContext, bucketName)
+	if err != nil {
+		if errCode == object.RGWErrorNotFound {
+			return nil, errors.Wrapf(errBucketNotFound, "bucket %q", bucketName)
+		}
+		return nil, errors.Wrapf(err, "failed to get bucket %q", bucketName)
+	}
+	owner, _, err := object.GetUser(objContext, stats.Owner)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get owner %q of bucket %q", stats.Owner, bucketName)
+	}
+
+	return bucket.NewS3Agent(*owner.AccessKey, *owner.SecretKey, object.GetEndpoint(store))
+}
+
+// topicConfiguration returns the s3 notification configuration of the notification
+func topicConfiguration(notification *cephv1.CephBucketNotification, topicARN string) *s3.TopicConfiguration {
+	events := notification.Spec.Events
+	if len(events) == 0 {
+		events = defaultEvents
+	}
+
+	config := &s3.TopicConfiguration{
+		Id:       aws.String(notification.Name),
+		TopicArn: aws.String(topicARN),
+		Events:   aws.StringSlice(events),
+	}
+
+	var rules []*s3.FilterRule
+	if notification.Spec.Filter.Prefix != "" {
+		rules = append(rules, &s3.FilterRule{Name: aws.String(s3.FilterRuleNamePrefix), Value: aws.String(notification.Spec.Filter.Prefix)})
+	}
+	if notification.Spec.Filter.Suffix != "" {
+		rules = append(rules, &s3.FilterRule{Name: aws.String(s3.FilterRuleNameSuffix), Value: aws.String(notification.Spec.Filter.Suffix)})
+	}
+	if len(rules) > 0 {
+		config.Filter = &s3.NotificationConfigurationFilter{Key: &s3.KeyFilter{FilterRules: rules}}
+	}
+	return config
+}
+
+// validateNotification validates the spec of a bucket notification
+func validateNotification(notification *cephv1.CephBucketNotification) error {
+	if notification.Spec.Topic == "" {
+		return errors.New("missing topic")
+	}
+	if notification.Spec.ObjectBucketClaim == "" {
+		return errors.New("missing objectBucketClaim")
+	}
+	for _, event := range notification.Spec.Events {
+		supported := false
+		for _, supportedEvent := range supportedEvents {
+			if event == supportedEvent {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return errors.Errorf("invalid event %q, must be one of %s", event, strings.Join(supportedEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// updateStatus updates a bucket notification CR with the given status and the matching conditions. The bucket,
+// the topic and the sync of the notification are updated when a notification status is given.
+func updateStatus(client client.Client, name types.NamespacedName, status string, reconcileErr error, notificationStatus *cephv1.BucketNotificationStatus) {
+	notification := &cephv1.CephBucketNotification{}
+	if err := client.Get(context.TODO(), name, notification); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketNotification resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve bucket notification %q to update status to %q. %v", name, status, err)
+		return
+	}
+
+	if notification.Status == nil {
+		notification.Status = &cephv1.BucketNotificationStatus{}
+	}
+
+	notification.Status.Phase = status
+	opcontroller.SetPhaseConditions(&notification.Status.Conditions, status, reconcileErr)
+	if notificationStatus != nil {
+		notification.Status.BucketName = notificationStatus.BucketName
+		notification.Status.TopicARN = notificationStatus.TopicARN
+		notification.Status.ObservedGeneration = notificationStatus.ObservedGeneration
+		notification.Status.LastSyncTime = notificationStatus.LastSyncTime
+	}
+	if err := opcontroller.UpdateStatus(client, notification); err != nil {
+		logger.Warningf("failed to set bucket notification %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("bucket notification %q status updated to %q", name, status)
+}
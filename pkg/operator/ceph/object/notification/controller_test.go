@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const namespace = "my-namespace"
+
+func newNotification() *cephv1.CephBucketNotification {
+	return &cephv1.CephBucketNotification{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-notification", Namespace: namespace},
+		Spec: cephv1.BucketNotificationSpec{
+			Topic:             "my-topic",
+			ObjectBucketClaim: "my-obc",
+		},
+	}
+}
+
+func TestValidateNotification(t *testing.T) {
+	notification := newNotification()
+	assert.NoError(t, validateNotification(notification))
+
+	notification.Spec.Events = []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:DeleteMarkerCreated"}
+	assert.NoError(t, validateNotification(notification))
+
+	notification.Spec.Events = []string{"s3:ObjectRestore:Post"}
+	assert.Error(t, validateNotification(notification))
+
+	notification = newNotification()
+	notification.Spec.Topic = ""
+	assert.Error(t, validateNotification(notification))
+
+	notification = newNotification()
+	notification.Spec.ObjectBucketClaim = ""
+	assert.Error(t, validateNotification(notification))
+}
+
+func TestTopicConfiguration(t *testing.T) {
+	notification := newNotification()
+	config := topicConfiguration(notification, "arn:aws:sns:my-store::my-topic")
+	assert.Equal(t, "my-notification", aws.StringValue(config.Id))
+	assert.Equal(t, "arn:aws:sns:my-store::my-topic", aws.StringValue(config.TopicArn))
+	assert.Equal(t, defaultEvents, aws.StringValueSlice(config.Events))
+	assert.Nil(t, config.Filter)
+
+	notification.Spec.Events = []string{"s3:ObjectCreated:Put"}
+	notification.Spec.Filter.Prefix = "images/"
+	notification.Spec.Filter.Suffix = ".png"
+	config = topicConfiguration(notification, "arn:aws:sns:my-store::my-topic")
+	assert.Equal(t, []string{"s3:ObjectCreated:Put"}, aws.StringValueSlice(config.Events))
+	assert.Equal(t, []*s3.FilterRule{
+		{Name: aws.String(s3.FilterRuleNamePrefix), Value: aws.String("images/")},
+		{Name: aws.String(s3.FilterRuleNameSuffix), Value: aws.String(".png")},
+	}, config.Filter.Key.FilterRules)
+}
+
+func TestGetBucketName(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NoError(t, bktv1alpha1.AddToScheme(s))
+	obc := &bktv1alpha1.ObjectBucketClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-obc", Namespace: namespace},
+		Spec:       bktv1alpha1.ObjectBucketClaimSpec{GenerateBucketName: "my-bucket"},
+		Status:     bktv1alpha1.ObjectBucketClaimStatus{Phase: bktv1alpha1.ObjectBucketClaimStatusPhasePending},
+	}
+	c := fake.NewFakeClientWithScheme(s, obc)
+	r := &ReconcileBucketNotification{client: c}
+
+	// the claim is not bound yet
+	_, err := r.getBucketName(newNotification())
+	assert.Error(t, err)
+
+	obc.Spec.BucketName = "my-bucket-1234"
+	obc.Status.Phase = bktv1alpha1.ObjectBucketClaimStatusPhaseBound
+	assert.NoError(t, c.Update(context.TODO(), obc))
+	bucketName, err := r.getBucketName(newNotification())
+	assert.NoError(t, err)
+	assert.Equal(t, "my-bucket-1234", bucketName)
+
+	// unknown claim
+	notification := newNotification()
+	notification.Spec.ObjectBucketClaim = "other-obc"
+	_, err = r.getBucketName(notification)
+	assert.Error(t, err)
+}
+
+func TestDeleteNotificationWithoutTopic(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NoError(t, cephv1.AddToScheme(s))
+	notification := newNotification()
+	notification.Finalizers = []string{"cephbucketnotification.ceph.rook.io"}
+	now := metav1.Now()
+	notification.DeletionTimestamp = &now
+	c := fake.NewFakeClientWithScheme(s, notification)
+	r := &ReconcileBucketNotification{client: c, scheme: s, context: &clusterd.Context{}}
+
+	name := types.NamespacedName{Name: notification.Name, Namespace: namespace}
+	res, err := r.Reconcile(reconcile.Request{NamespacedName: name})
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+
+	// the finalizer is removed since the notification was never created without its topic
+	deleted := &cephv1.CephBucketNotification{}
+	err = c.Get(context.TODO(), name, deleted)
+	if err == nil {
+		assert.Empty(t, deleted.Finalizers)
+	} else {
+		assert.True(t, kerrors.IsNotFound(err))
+	}
+}
+
+func TestWaitForTopic(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NoError(t, cephv1.AddToScheme(s))
+	notification := newNotification()
+	c := fake.NewFakeClientWithScheme(s, notification)
+	r := &ReconcileBucketNotification{client: c, scheme: s, context: &clusterd.Context{}}
+
+	name := types.NamespacedName{Name: notification.Name, Namespace: namespace}
+	res, err := r.Reconcile(reconcile.Request{NamespacedName: name})
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+
+	err = c.Get(context.TODO(), name, notification)
+	assert.NoError(t, err)
+	assert.Equal(t, "Created", notification.Status.Phase)
+}
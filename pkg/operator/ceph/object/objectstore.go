@@ -284,9 +284,12 @@ func createPools(context *Context, spec cephv1.ObjectStoreSpec) error {
 	ecProfileName := ""
 	if spec.DataPool.IsErasureCoded() {
 		ecProfileName = client.GetErasureCodeProfileForPool(context.Name)
-		// create a new erasure code profile for the data pool
-		if err := ceph.CreateErasureCodeProfile(context.Context, context.ClusterName, ecProfileName, spec.DataPool); err != nil {
-			return errors.Wrapf(err, "failed to create erasure code profile for object store %s", context.Name)
+		// create a new erasure code profile for the data pool. The profile of an existing pool can't change, the
+		// settings that differ are reported in the status of the object store.
+		if _, err := ceph.GetPoolDetails(context.Context, context.ClusterName, poolName(context.Name, dataPoolName)); err != nil {
+			if err := ceph.CreateErasureCodeProfile(context.Context, context.ClusterName, ecProfileName, spec.DataPool); err != nil {
+				return errors.Wrapf(err, "failed to create erasure code profile for object store %s", context.Name)
+			}
 		}
 	}
 
@@ -299,6 +302,12 @@ func createPools(context *Context, spec cephv1.ObjectStoreSpec) error {
 
 func createSimilarPools(context *Context, pools []string, poolSpec cephv1.PoolSpec, pgCount, ecProfileName string) error {
 	for _, pool := range pools {
+		poolSpec := poolSpec
+		if pool == rootPool {
+			// the root pool is shared by all the object stores, quotas of a single store don't apply to it
+			poolSpec.Quotas = cephv1.QuotaSpec{}
+		}
+
 		// create the pool if it doesn't exist yet
 		name := poolName(context.Name, pool)
 		if poolDetails, err := ceph.GetPoolDetails(context.Context, context.ClusterName, name); err != nil {
@@ -326,6 +335,12 @@ func createSimilarPools(context *Context, pools []string, poolSpec cephv1.PoolSp
 					}
 				}
 			}
+			// the quotas removed from the spec are removed from the pool as well
+			if pool != rootPool {
+				if err := ceph.SetPoolQuota(context.Context, context.ClusterName, name, poolSpec.Quotas); err != nil {
+					return errors.Wrapf(err, "failed to set quotas on pool %q", name)
+				}
+			}
 		}
 		// Set the pg_num_min if not the default so the autoscaler won't immediately increase the pg count
 		if pgCount != ceph.DefaultPGCount {
@@ -20,6 +20,7 @@ package object
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/banzaicloud/k8s-objectmatcher/patch"
 	"github.com/pkg/errors"
@@ -51,6 +52,9 @@ type clusterConfig struct {
 	client            client.Client
 	scheme            *runtime.Scheme
 	Network           cephv1.NetworkSpec
+	certificate       *rgwCertificate
+	// the time the generated certificate must be renewed, if any
+	certificateRenewal time.Time
 }
 
 type rgwConfig struct {
@@ -100,6 +104,16 @@ func (c *clusterConfig) startRGWPods(realmName string, zoneGroupName string, zon
 	}
 	c.ownerRef = ref
 
+	// Generate the certificate if requested, and read it to restart the pods when it changes
+	c.certificateRenewal, err = c.reconcileGeneratedCertificate(time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to generate rgw certificate")
+	}
+	c.certificate, err = c.loadCertificate()
+	if err != nil {
+		return errors.Wrap(err, "failed to load rgw certificate")
+	}
+
 	// start a new deployment and scale up
 	desiredRgwInstances := int(c.store.Spec.Gateway.Instances)
 	for i := 0; i < desiredRgwInstances; i++ {
@@ -315,8 +329,9 @@ func (c *clusterConfig) storeLabelSelector() string {
 	return fmt.Sprintf("rook_object_store=%s", c.store.Name)
 }
 
-// Validate the object store arguments
-func validateStore(context *clusterd.Context, s *cephv1.CephObjectStore) error {
+// ValidateStore validates the object store arguments. If the context is nil, the settings requiring the cluster,
+// such as the crush root of the pools, are not validated.
+func ValidateStore(context *clusterd.Context, s *cephv1.CephObjectStore) error {
 	if s.Name == "" {
 		return errors.New("missing name")
 	}
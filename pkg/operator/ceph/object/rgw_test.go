@@ -61,7 +61,17 @@ func TestStartRGW(t *testing.T) {
 	r := &ReconcileCephObjectStore{client: cl, scheme: s}
 
 	// start a basic cluster
-	c := &clusterConfig{info, context, store, version, &cephv1.ClusterSpec{}, &metav1.OwnerReference{}, data, false, r.client, s, cephv1.NetworkSpec{}}
+	c := &clusterConfig{
+		clusterInfo: info,
+		context:     context,
+		store:       store,
+		rookVersion: version,
+		clusterSpec: &cephv1.ClusterSpec{},
+		ownerRef:    &metav1.OwnerReference{},
+		DataPathMap: data,
+		client:      r.client,
+		scheme:      s,
+	}
 	err := c.startRGWPods(store.Name, store.Name, store.Name)
 	assert.Nil(t, err)
 
@@ -107,7 +117,17 @@ func TestCreateObjectStore(t *testing.T) {
 	object := []runtime.Object{&cephv1.CephObjectStore{}}
 	cl := fake.NewFakeClientWithScheme(s, object...)
 	r := &ReconcileCephObjectStore{client: cl, scheme: s}
-	c := &clusterConfig{info, context, store, "1.2.3.4", &cephv1.ClusterSpec{}, &metav1.OwnerReference{}, data, false, r.client, s, cephv1.NetworkSpec{}}
+	c := &clusterConfig{
+		clusterInfo: info,
+		context:     context,
+		store:       store,
+		rookVersion: "1.2.3.4",
+		clusterSpec: &cephv1.ClusterSpec{},
+		ownerRef:    &metav1.OwnerReference{},
+		DataPathMap: data,
+		client:      r.client,
+		scheme:      s,
+	}
 	err := c.createOrUpdateStore(store.Name, store.Name, store.Name)
 	assert.Nil(t, err)
 }
@@ -127,17 +147,17 @@ func TestGenerateSecretName(t *testing.T) {
 	cl := fake.NewFakeClient([]runtime.Object{}...)
 
 	// start a basic cluster
-	c := &clusterConfig{&config.ClusterInfo{},
-		&clusterd.Context{},
-		&cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "mycluster"}},
-		"v1.1.0",
-		&cephv1.ClusterSpec{},
-		&metav1.OwnerReference{},
-		&cephconfig.DataPathMap{},
-		false,
-		cl,
-		scheme.Scheme,
-		cephv1.NetworkSpec{}}
+	c := &clusterConfig{
+		clusterInfo: &config.ClusterInfo{},
+		context:     &clusterd.Context{},
+		store:       &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "mycluster"}},
+		rookVersion: "v1.1.0",
+		clusterSpec: &cephv1.ClusterSpec{},
+		ownerRef:    &metav1.OwnerReference{},
+		DataPathMap: &cephconfig.DataPathMap{},
+		client:      cl,
+		scheme:      scheme.Scheme,
+	}
 	secret := c.generateSecretName("a")
 	assert.Equal(t, "rook-ceph-rgw-default-a-keyring", secret)
 }
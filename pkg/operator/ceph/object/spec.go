@@ -83,20 +83,28 @@ func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) v1.PodTemplateSpec
 	k8sutil.AddUnreachableNodeToleration(&podSpec)
 
 	// Set the ssl cert if specified
-	if c.store.Spec.Gateway.SSLCertificateRef != "" {
+	if certSecretName := CertificateSecretName(c.store); certSecretName != "" {
 		// Keep the SSL secret as secure as possible in the container. Give only user read perms.
 		// Because the Secret mount is owned by "root" and fsGroup breaks on OCP since we cannot predict it
 		// Also, we don't want to change the SCC for fsGroup to RunAsAny since it has a major broader impact
 		// Let's open the permissions a bit more so that everyone can read the cert.
 		userReadOnly := int32(0444)
+		items := []v1.KeyToPath{
+			{Key: certKeyName, Path: certFilename, Mode: &userReadOnly},
+		}
+		if c.certificate != nil && c.certificate.tlsSecret {
+			items = []v1.KeyToPath{
+				{Key: tlsCertKeyName, Path: certFilename, Mode: &userReadOnly},
+				{Key: tlsKeyKeyName, Path: keyFilename, Mode: &userReadOnly},
+			}
+		}
 		certVol := v1.Volume{
 			Name: certVolumeName,
 			VolumeSource: v1.VolumeSource{
 				Secret: &v1.SecretVolumeSource{
-					SecretName: c.store.Spec.Gateway.SSLCertificateRef,
-					Items: []v1.KeyToPath{
-						{Key: certKeyName, Path: certFilename, Mode: &userReadOnly},
-					}}}}
+					SecretName: certSecretName,
+					Items:      items,
+				}}}
 		podSpec.Volumes = append(podSpec.Volumes, certVol)
 	}
 
@@ -114,6 +122,14 @@ func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) v1.PodTemplateSpec
 	}
 	c.store.Spec.Gateway.Annotations.ApplyToObjectMeta(&podTemplateSpec.ObjectMeta)
 
+	// Restart the pods when the certificate changes
+	if c.certificate != nil {
+		if podTemplateSpec.ObjectMeta.Annotations == nil {
+			podTemplateSpec.ObjectMeta.Annotations = map[string]string{}
+		}
+		podTemplateSpec.ObjectMeta.Annotations[certificateHashAnnotation] = c.certificate.hash
+	}
+
 	if c.clusterSpec.Network.IsHost() {
 		podTemplateSpec.Spec.DNSPolicy = v1.DNSClusterFirstWithHostNet
 	} else if c.clusterSpec.Network.IsMultus() {
@@ -163,7 +179,7 @@ func (c *clusterConfig) makeDaemonContainer(rgwConfig *rgwConfig) v1.Container {
 		SecurityContext: mon.PodSecurityContext(),
 	}
 
-	if c.store.Spec.Gateway.SSLCertificateRef != "" {
+	if CertificateSecretName(c.store) != "" {
 		// Add a volume mount for the ssl certificate
 		mount := v1.VolumeMount{Name: certVolumeName, MountPath: certDir, ReadOnly: true}
 		container.VolumeMounts = append(container.VolumeMounts, mount)
@@ -191,7 +207,7 @@ func (c *clusterConfig) generateLiveProbeScheme() v1.URIScheme {
 
 	// If rgw is configured to use a secured port we need get on https://
 	// Only do this when the Non-SSL port is not used
-	if c.store.Spec.Gateway.Port == 0 && c.store.Spec.Gateway.SecurePort != 0 && c.store.Spec.Gateway.SSLCertificateRef != "" {
+	if c.store.Spec.Gateway.Port == 0 && c.store.Spec.Gateway.SecurePort != 0 && CertificateSecretName(c.store) != "" {
 		uriScheme = v1.URISchemeHTTPS
 	}
 
@@ -205,7 +221,7 @@ func (c *clusterConfig) generateLiveProbePort() intstr.IntOrString {
 
 	// If Host Networking is enabled, the port from the spec must be reflected
 	if c.clusterSpec.Network.IsHost() {
-		if c.store.Spec.Gateway.Port == 0 && c.store.Spec.Gateway.SecurePort != 0 && c.store.Spec.Gateway.SSLCertificateRef != "" {
+		if c.store.Spec.Gateway.Port == 0 && c.store.Spec.Gateway.SecurePort != 0 && CertificateSecretName(c.store) != "" {
 			port = intstr.FromInt(int(c.store.Spec.Gateway.SecurePort))
 		} else {
 			port = intstr.FromInt(int(c.store.Spec.Gateway.Port))
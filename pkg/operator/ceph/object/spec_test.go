@@ -129,6 +129,19 @@ func TestSSLPodSpec(t *testing.T) {
 	assert.True(t, s.Spec.HostNetwork)
 	assert.Equal(t, v1.DNSClusterFirstWithHostNet, s.Spec.DNSPolicy)
 
+	// The certificate and the key of a kubernetes.io/tls secret are mounted, and their hash restarts the pods
+	c.certificate = &rgwCertificate{tlsSecret: true, hash: "myhash"}
+	s = c.makeRGWPodSpec(rgwConfig)
+	assert.Equal(t, "myhash", s.Annotations[certificateHashAnnotation])
+	for _, volume := range s.Spec.Volumes {
+		if volume.Name == certVolumeName {
+			assert.Equal(t, "mycert", volume.Secret.SecretName)
+			assert.Equal(t, tlsCertKeyName, volume.Secret.Items[0].Key)
+			assert.Equal(t, certFilename, volume.Secret.Items[0].Path)
+			assert.Equal(t, tlsKeyKeyName, volume.Secret.Items[1].Key)
+			assert.Equal(t, keyFilename, volume.Secret.Items[1].Path)
+		}
+	}
 }
 
 func TestValidateSpec(t *testing.T) {
@@ -136,31 +149,31 @@ func TestValidateSpec(t *testing.T) {
 
 	// valid store
 	s := simpleStore()
-	err := validateStore(context, s)
+	err := ValidateStore(context, s)
 	assert.Nil(t, err)
 
 	// no name
 	s.Name = ""
-	err = validateStore(context, s)
+	err = ValidateStore(context, s)
 	assert.NotNil(t, err)
 	s.Name = "default"
-	err = validateStore(context, s)
+	err = ValidateStore(context, s)
 	assert.Nil(t, err)
 
 	// no namespace
 	s.Namespace = ""
-	err = validateStore(context, s)
+	err = ValidateStore(context, s)
 	assert.NotNil(t, err)
 	s.Namespace = "mycluster"
-	err = validateStore(context, s)
+	err = ValidateStore(context, s)
 	assert.Nil(t, err)
 
 	// no replication or EC is valid
 	s.Spec.MetadataPool.Replicated.Size = 0
-	err = validateStore(context, s)
+	err = ValidateStore(context, s)
 	assert.Nil(t, err)
 	s.Spec.MetadataPool.Replicated.Size = 1
-	err = validateStore(context, s)
+	err = ValidateStore(context, s)
 	assert.Nil(t, err)
 }
 
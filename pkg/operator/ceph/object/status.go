@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/pool"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// poolNames returns the names of all the pools of the object store and the names of the pools that have quotas
+// in their spec. The root pool shared by all the object stores is not part of them.
+func poolNames(store *cephv1.CephObjectStore) ([]string, []string) {
+	var names, quotaNames []string
+	for _, metadataPool := range metadataPools {
+		name := poolName(store.Name, metadataPool)
+		names = append(names, name)
+		if store.Spec.MetadataPool.Quotas.IsEnabled() {
+			quotaNames = append(quotaNames, name)
+		}
+	}
+	dataName := poolName(store.Name, dataPoolName)
+	names = append(names, dataName)
+	if store.Spec.DataPool.Quotas.IsEnabled() {
+		quotaNames = append(quotaNames, dataName)
+	}
+	return names, quotaNames
+}
+
+// GetEndpoint returns the in-cluster endpoint of the object store service
+func GetEndpoint(store *cephv1.CephObjectStore) string {
+	host := fmt.Sprintf("%s.%s", instanceName(store.Name), store.Namespace)
+	if store.Spec.Gateway.Port == 0 && store.Spec.Gateway.SecurePort != 0 {
+		return fmt.Sprintf("https://%s:%d", host, store.Spec.Gateway.SecurePort)
+	}
+	return fmt.Sprintf("http://%s:%d", host, store.Spec.Gateway.Port)
+}
+
+// GetSecureEndpoint returns the in-cluster TLS endpoint of the object store service, or an empty string when the
+// object store does not serve TLS
+func GetSecureEndpoint(store *cephv1.CephObjectStore) string {
+	if store.Spec.Gateway.SecurePort == 0 || CertificateSecretName(store) == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%s.%s:%d", instanceName(store.Name), store.Namespace, store.Spec.Gateway.SecurePort)
+}
+
+// refreshStatus updates an object store CR with its endpoint, its multisite names, the state of its pools and their
+// usage against their quotas
+func refreshStatus(clusterdContext *clusterd.Context, c client.Client, store *cephv1.CephObjectStore) {
+	name := types.NamespacedName{Name: store.Name, Namespace: store.Namespace}
+	realmName, zoneGroupName, zoneName, err := getMultisiteNames(clusterdContext, store)
+	if err != nil {
+		logger.Warningf("failed to get the multisite names of object store %q. %v", name, err)
+		return
+	}
+
+	names, quotaNames := poolNames(store)
+	// the erasure code settings of the data pool are compared with the profile of the pool
+	specs := map[string]cephv1.PoolSpec{poolName(store.Name, dataPoolName): store.Spec.DataPool}
+	pools, err := pool.GetPoolStatuses(clusterdContext, name.Namespace, names, specs)
+	if err != nil {
+		logger.Warningf("failed to get the state of object store %q pools. %v", name, err)
+		return
+	}
+
+	var quotas map[string]cephv1.PoolQuotaStatus
+	if len(quotaNames) > 0 {
+		quotas, err = pool.GetQuotaStatuses(clusterdContext, name.Namespace, quotaNames)
+		if err != nil {
+			logger.Warningf("failed to get quota status of object store %q pools. %v", name, err)
+			return
+		}
+	}
+
+	objectStore := &cephv1.CephObjectStore{}
+	if err := c.Get(context.TODO(), name, objectStore); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectStore resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve object store %q to refresh its status. %v", name, err)
+		return
+	}
+	if objectStore.Status == nil {
+		objectStore.Status = &cephv1.ObjectStoreStatus{}
+	}
+
+	objectStore.Status.Endpoint = GetEndpoint(objectStore)
+	objectStore.Status.Realm = realmName
+	objectStore.Status.ZoneGroup = zoneGroupName
+	objectStore.Status.Zone = zoneName
+	objectStore.Status.Pools = pools
+	objectStore.Status.PoolQuotas = quotas
+	objectStore.Status.LastChecked = opcontroller.FormatStatusTime(time.Now().UTC())
+	if err := opcontroller.UpdateStatus(c, objectStore); err != nil {
+		logger.Errorf("failed to refresh status of object store %q. %v", name, err)
+		return
+	}
+	logger.Debugf("object store %q status refreshed", name)
+}
+
+// refreshAllStatuses refreshes the status of all the ready object store CRs
+func refreshAllStatuses(clusterdContext *clusterd.Context, c client.Client) {
+	objectStores := &cephv1.CephObjectStoreList{}
+	if err := c.List(context.TODO(), objectStores); err != nil {
+		logger.Warningf("failed to list object stores to refresh their status. %v", err)
+		return
+	}
+
+	for i := range objectStores.Items {
+		store := &objectStores.Items[i]
+		if !store.GetDeletionTimestamp().IsZero() || store.Status == nil || store.Status.Phase != k8sutil.ReadyStatus {
+			continue
+		}
+		name := types.NamespacedName{Name: store.Name, Namespace: store.Namespace}
+		if _, isReadyToReconcile, _, _ := opcontroller.IsReadyToReconcile(c, clusterdContext, name, controllerName); !isReadyToReconcile {
+			continue
+		}
+		refreshStatus(clusterdContext, c, store)
+	}
+}
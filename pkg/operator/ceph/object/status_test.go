@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetEndpoint(t *testing.T) {
+	store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph"}}
+	store.Spec.Gateway.Port = 80
+	assert.Equal(t, "http://rook-ceph-rgw-my-store.rook-ceph:80", GetEndpoint(store))
+
+	store.Spec.Gateway.SecurePort = 443
+	assert.Equal(t, "http://rook-ceph-rgw-my-store.rook-ceph:80", GetEndpoint(store))
+
+	store.Spec.Gateway.Port = 0
+	assert.Equal(t, "https://rook-ceph-rgw-my-store.rook-ceph:443", GetEndpoint(store))
+}
+
+func TestGetSecureEndpoint(t *testing.T) {
+	store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph"}}
+	store.Spec.Gateway.Port = 80
+	assert.Equal(t, "", GetSecureEndpoint(store))
+
+	// rgw only serves the secure port with a certificate
+	store.Spec.Gateway.SecurePort = 443
+	assert.Equal(t, "", GetSecureEndpoint(store))
+
+	store.Spec.Gateway.GenerateSSLCertificate = true
+	assert.Equal(t, "https://rook-ceph-rgw-my-store.rook-ceph:443", GetSecureEndpoint(store))
+}
+
+func TestPoolNames(t *testing.T) {
+	store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph"}}
+	names, quotaNames := poolNames(store)
+	assert.Equal(t, 6, len(names))
+	assert.Equal(t, "my-store.rgw.control", names[0])
+	assert.Equal(t, "my-store.rgw.buckets.data", names[5])
+	assert.Equal(t, 0, len(quotaNames))
+
+	maxObjects := uint64(10)
+	store.Spec.DataPool.Quotas.MaxObjects = &maxObjects
+	_, quotaNames = poolNames(store)
+	assert.Equal(t, []string{"my-store.rgw.buckets.data"}, quotaNames)
+}
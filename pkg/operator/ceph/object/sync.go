@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+const (
+	// the layout of the times in the sync status, which are followed by the microseconds
+	syncTimeLayout = "2006-01-02 15:04:05"
+	// the maximum number of buckets whose sync status is checked at each check of a zone, a new sample of the buckets
+	// is checked each time so that the sync status of a zone with many buckets is retrieved quickly
+	maxBucketSyncChecks = 20
+)
+
+var (
+	behindShardsRegexp = regexp.MustCompile(`is behind on (\d+) shards?`)
+	sourceZoneRegexp   = regexp.MustCompile(`\(([^)]*)\)\s*$`)
+	// the errors of radosgw-admin when the sync info of the source zone cannot be fetched from it, as opposed to the
+	// errors reading the sync status of the local zone
+	unreachableSourceMessages = []string{"failed to fetch master sync status", "failed to fetch source log shards info", "failed to retrieve sync info"}
+)
+
+// MultisiteArgs returns the arguments selecting the realm, zone group and zone of a radosgw-admin command
+func MultisiteArgs(realmName, zoneGroupName, zoneName string) []string {
+	return []string{
+		fmt.Sprintf("--rgw-realm=%s", realmName),
+		fmt.Sprintf("--rgw-zonegroup=%s", zoneGroupName),
+		fmt.Sprintf("--rgw-zone=%s", zoneName),
+	}
+}
+
+// GetZoneSyncStatus returns the progress of the metadata and data sync of a zone with the other zones of its zone
+// group, along with the number of buckets behind among a sample of its buckets
+func GetZoneSyncStatus(c *Context, realmName, zoneGroupName, zoneName string) (*cephv1.ZoneSyncStatus, error) {
+	multisiteArgs := MultisiteArgs(realmName, zoneGroupName, zoneName)
+
+	output, err := RunAdminCommandNoRealm(c, append([]string{"sync", "status"}, multisiteArgs...)...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get sync status of zone %q", zoneName)
+	}
+	status := parseSyncStatus(output, time.Now())
+
+	output, err = RunAdminCommandNoRealm(c, append([]string{"bucket", "list"}, multisiteArgs...)...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list buckets of zone %q", zoneName)
+	}
+	var buckets []string
+	if err := json.Unmarshal([]byte(output), &buckets); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal bucket list")
+	}
+	for _, bucket := range sampleBuckets(buckets, maxBucketSyncChecks) {
+		output, err = RunAdminCommandNoRealm(c, append([]string{"bucket", "sync", "status", "--bucket", bucket}, multisiteArgs...)...)
+		if err != nil {
+			logger.Warningf("failed to get sync status of bucket %q of zone %q. %v", bucket, zoneName, err)
+			continue
+		}
+		status.BucketsChecked++
+		if isBucketSyncBehind(output) {
+			status.BucketsBehind++
+		}
+	}
+
+	return status, nil
+}
+
+// sampleBuckets returns at most max buckets picked at random
+func sampleBuckets(buckets []string, max int) []string {
+	if len(buckets) <= max {
+		return buckets
+	}
+	sample := make([]string, len(buckets))
+	copy(sample, buckets)
+	rand.Shuffle(len(sample), func(i, j int) { sample[i], sample[j] = sample[j], sample[i] })
+	return sample[:max]
+}
+
+// parseSyncStatus parses the output of `radosgw-admin sync status`, e.g.
+//
+//	metadata sync syncing
+//	              full sync: 0/64 shards
+//	              incremental sync: 64/64 shards
+//	              metadata is behind on 1 shards
+//	              behind shards: [19]
+//	              oldest incremental change not applied: 2020-06-02 09:12:32.0.474163s [19]
+//	    data sync source: 3b4e5ba8-4d5e-4c8d-a4d8-7ab09e2b11fa (zone-b)
+//	                      syncing
+//	                      full sync: 0/128 shards
+//	                      incremental sync: 128/128 shards
+//	                      data is caught up with source
+//
+// The state of a sync is Unknown until its progress is recognized, e.g. while the sync is preparing, so that a sync
+// that could not be parsed is never considered caught up or unreachable.
+func parseSyncStatus(output string, now time.Time) *cephv1.ZoneSyncStatus {
+	status := &cephv1.ZoneSyncStatus{MetadataSync: cephv1.SyncProgress{State: cephv1.SyncStateUnknown}}
+	progress := &status.MetadataSync
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "metadata sync"):
+			progress = &status.MetadataSync
+			if strings.Contains(line, "zone is master") {
+				progress.State = cephv1.SyncStateMaster
+				continue
+			}
+
+		case strings.HasPrefix(line, "data sync source:"):
+			sourceZone := ""
+			if match := sourceZoneRegexp.FindStringSubmatch(line); match != nil {
+				sourceZone = match[1]
+			}
+			status.DataSync = append(status.DataSync, cephv1.DataSyncProgress{
+				SourceZone:   sourceZone,
+				SyncProgress: cephv1.SyncProgress{State: cephv1.SyncStateUnknown},
+			})
+			progress = &status.DataSync[len(status.DataSync)-1].SyncProgress
+		}
+
+		switch {
+		case isUnreachableSourceMessage(line):
+			progress.State = cephv1.SyncStateUnreachable
+
+		case strings.Contains(line, "failed") || strings.Contains(line, "ERROR"):
+			progress.State = cephv1.SyncStateError
+
+		case strings.Contains(line, "is caught up with"):
+			progress.State = cephv1.SyncStateCaughtUp
+
+		case behindShardsRegexp.MatchString(line):
+			progress.State = cephv1.SyncStateBehind
+			progress.BehindShards, _ = strconv.Atoi(behindShardsRegexp.FindStringSubmatch(line)[1])
+
+		case strings.HasPrefix(line, "oldest incremental change not applied:"):
+			timestamp := strings.TrimSpace(strings.TrimPrefix(line, "oldest incremental change not applied:"))
+			if len(timestamp) < len(syncTimeLayout) {
+				continue
+			}
+			oldest, err := time.Parse(syncTimeLayout, timestamp[:len(syncTimeLayout)])
+			if err != nil {
+				logger.Debugf("failed to parse oldest change not synced %q. %v", timestamp, err)
+				continue
+			}
+			if lag := now.Sub(oldest); lag > 0 {
+				progress.LagSeconds = int64(lag.Seconds())
+			}
+		}
+	}
+
+	return status
+}
+
+// isUnreachableSourceMessage returns whether a line of the sync status reports that the sync info of the source zone
+// could not be fetched from it
+func isUnreachableSourceMessage(line string) bool {
+	for _, message := range unreachableSourceMessages {
+		if strings.Contains(line, message) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBucketSyncBehind returns whether the output of `radosgw-admin bucket sync status` reports a source zone the
+// bucket is behind
+func isBucketSyncBehind(output string) bool {
+	return behindShardsRegexp.MatchString(output)
+}
+
+// IsZoneCaughtUp returns whether a zone has synced all the changes of the other zones of its zone group
+func IsZoneCaughtUp(status *cephv1.ZoneSyncStatus) bool {
+	if status.MetadataSync.State != cephv1.SyncStateCaughtUp && status.MetadataSync.State != cephv1.SyncStateMaster {
+		return false
+	}
+	for _, source := range status.DataSync {
+		if source.State != cephv1.SyncStateCaughtUp {
+			return false
+		}
+	}
+	return status.BucketsBehind == 0
+}
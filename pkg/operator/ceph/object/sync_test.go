@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const masterSyncStatus = `          realm 5e0ab7b4-2c5c-4a0c-9d50-a2a3e1fe0d1a (realm-a)
+      zonegroup 9a6f1d3e-4a3f-4a33-9a2c-6f0b9d2c6f7e (zonegroup-a)
+           zone 0b0c5e1c-41c2-4c6e-b1e5-5f3c0f0b2a11 (zone-a)
+  metadata sync no sync (zone is master)
+      data sync source: 3b4e5ba8-4d5e-4c8d-a4d8-7ab09e2b11fa (zone-b)
+                        syncing
+                        full sync: 0/128 shards
+                        incremental sync: 128/128 shards
+                        data is caught up with source
+`
+
+const secondarySyncStatus = `          realm 5e0ab7b4-2c5c-4a0c-9d50-a2a3e1fe0d1a (realm-a)
+      zonegroup 9a6f1d3e-4a3f-4a33-9a2c-6f0b9d2c6f7e (zonegroup-a)
+           zone 3b4e5ba8-4d5e-4c8d-a4d8-7ab09e2b11fa (zone-b)
+  metadata sync syncing
+                full sync: 0/64 shards
+                incremental sync: 64/64 shards
+                metadata is behind on 1 shards
+                behind shards: [19]
+                oldest incremental change not applied: 2020-06-02 09:12:32.0.474163s [19]
+      data sync source: 0b0c5e1c-41c2-4c6e-b1e5-5f3c0f0b2a11 (zone-a)
+                        syncing
+                        full sync: 0/128 shards
+                        incremental sync: 128/128 shards
+                        data is behind on 2 shards
+                        behind shards: [41,52]
+                        oldest incremental change not applied: 2020-06-02 09:10:02.0.123456s [41]
+      data sync source: 7c1d9e2a-8f3b-4e6d-9a1c-2b3e4f5a6b7c (zone-c)
+                        failed to retrieve sync info: (5) Input/output error
+`
+
+func TestParseSyncStatus(t *testing.T) {
+	now := time.Date(2020, 6, 2, 9, 13, 2, 0, time.UTC)
+
+	status := parseSyncStatus(masterSyncStatus, now)
+	assert.Equal(t, cephv1.SyncProgress{State: cephv1.SyncStateMaster}, status.MetadataSync)
+	assert.Equal(t, []cephv1.DataSyncProgress{
+		{SourceZone: "zone-b", SyncProgress: cephv1.SyncProgress{State: cephv1.SyncStateCaughtUp}},
+	}, status.DataSync)
+	assert.True(t, IsZoneCaughtUp(status))
+
+	status = parseSyncStatus(secondarySyncStatus, now)
+	assert.Equal(t, cephv1.SyncProgress{State: cephv1.SyncStateBehind, BehindShards: 1, LagSeconds: 30}, status.MetadataSync)
+	assert.Equal(t, []cephv1.DataSyncProgress{
+		{SourceZone: "zone-a", SyncProgress: cephv1.SyncProgress{State: cephv1.SyncStateBehind, BehindShards: 2, LagSeconds: 180}},
+		{SourceZone: "zone-c", SyncProgress: cephv1.SyncProgress{State: cephv1.SyncStateUnreachable}},
+	}, status.DataSync)
+	assert.False(t, IsZoneCaughtUp(status))
+
+	// the master zone cannot be reached
+	status = parseSyncStatus(`  metadata sync syncing
+                failed to fetch master sync status: (5) Input/output error`, now)
+	assert.Equal(t, cephv1.SyncStateUnreachable, status.MetadataSync.State)
+
+	// the local sync status cannot be read
+	status = parseSyncStatus(`  metadata sync syncing
+                failed to read sync status: (2) No such file or directory`, now)
+	assert.Equal(t, cephv1.SyncStateError, status.MetadataSync.State)
+
+	// nothing could be parsed
+	for _, output := range []string{"", "  metadata sync preparing for full sync"} {
+		status = parseSyncStatus(output, now)
+		assert.Equal(t, cephv1.SyncStateUnknown, status.MetadataSync.State)
+		assert.False(t, IsZoneCaughtUp(status))
+	}
+}
+
+func TestGetZoneSyncStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "sync" && args[1] == "status":
+				return masterSyncStatus, nil
+			case args[0] == "bucket" && args[1] == "list":
+				return `["bucket-1","bucket-2"]`, nil
+			case args[0] == "bucket" && args[1] == "sync" && args[4] == "bucket-1":
+				return `    source zone 3b4e5ba8-4d5e-4c8d-a4d8-7ab09e2b11fa (zone-b)
+                bucket is behind on 3 shards
+                behind shards: [0,4,9]`, nil
+			case args[0] == "bucket" && args[1] == "sync":
+				return `    source zone 3b4e5ba8-4d5e-4c8d-a4d8-7ab09e2b11fa (zone-b)
+                bucket is caught up with source`, nil
+			}
+			return "", nil
+		},
+	}
+	c := NewContext(&clusterd.Context{Executor: executor}, "zone-a", "rook-ceph")
+
+	status, err := GetZoneSyncStatus(c, "realm-a", "zonegroup-a", "zone-a")
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.SyncStateMaster, status.MetadataSync.State)
+	assert.Equal(t, 2, status.BucketsChecked)
+	assert.Equal(t, 1, status.BucketsBehind)
+	assert.False(t, IsZoneCaughtUp(status))
+}
+
+func TestSampleBuckets(t *testing.T) {
+	buckets := []string{"a", "b", "c"}
+	assert.Equal(t, buckets, sampleBuckets(buckets, 3))
+
+	sample := sampleBuckets(buckets, 2)
+	assert.Equal(t, 2, len(sample))
+	assert.NotEqual(t, sample[0], sample[1])
+	assert.Subset(t, buckets, sample)
+	// the buckets are not reordered
+	assert.Equal(t, []string{"a", "b", "c"}, buckets)
+}
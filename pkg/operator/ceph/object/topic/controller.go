@@ -0,0 +1,565 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package topic to manage the bucket notification topics of a rook object store.
+package topic
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-bucket-topic-controller"
+	// the region of the signature of the requests, rgw accepts any region
+	cephRegion = "us-east-1"
+
+	credentialsUsernameKey = "username"
+	credentialsPasswordKey = "password"
+)
+
+// WaitForRequeueIfObjectStoreNotReady waits for the object store of a topic or a notification to be ready
+var WaitForRequeueIfObjectStoreNotReady = reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephBucketTopicKind = reflect.TypeOf(cephv1.CephBucketTopic{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephBucketTopicKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+var _ reconcile.Reconciler = &ReconcileBucketTopic{}
+
+// ReconcileBucketTopic reconciles a CephBucketTopic object
+type ReconcileBucketTopic struct {
+	client  client.Client
+	scheme  *runtime.Scheme
+	context *clusterd.Context
+	// the credentials secrets of the topics
+	secretRefs *opcontroller.SecretReferences
+}
+
+// Add creates a new CephBucketTopic Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context) *ReconcileBucketTopic {
+	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
+	mgrScheme := mgr.GetScheme()
+	cephv1.AddToScheme(mgr.GetScheme())
+
+	return &ReconcileBucketTopic{
+		client:     mgr.GetClient(),
+		scheme:     mgrScheme,
+		context:    context,
+		secretRefs: opcontroller.NewSecretReferences(),
+	}
+}
+
+func add(mgr manager.Manager, r *ReconcileBucketTopic) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephBucketTopic CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephBucketTopic{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	// Watch the credentials secrets of the endpoints to update the topics when the credentials change
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: r.secretRefs.ToRequests()}, r.secretRefs.Predicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephBucketTopic object and makes changes based on the state read
+// and what is in the CephBucketTopic.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileBucketTopic) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileBucketTopic) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephBucketTopic instance
+	topic := &cephv1.CephBucketTopic{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, topic)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketTopic resource not found. Ignoring since object must be deleted.")
+			r.secretRefs.Set(request.NamespacedName)
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephBucketTopic")
+	}
+
+	// The CR was just created, initializing status fields
+	if topic.Status == nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil, nil)
+	}
+
+	// Make sure a CephCluster is present in the namespace of the object store otherwise do nothing
+	storeName := ObjectStoreName(topic)
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, storeName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// We skip the deleteTopic() function since everything is gone already
+		if !topic.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.client, topic)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, topic)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// DELETE: the CR was deleted
+	if !topic.GetDeletionTimestamp().IsZero() {
+		logger.Debugf("deleting bucket topic %q", request.NamespacedName)
+		reconcileResponse, err := r.deleteTopic(topic)
+		if err != nil {
+			return reconcileResponse, errors.Wrapf(err, "failed to delete bucket topic %q", request.NamespacedName)
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, topic)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+		r.secretRefs.Set(request.NamespacedName)
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the topic settings
+	if err := validateTopic(topic, nil); err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid bucket topic CR %q spec", request.NamespacedName)
+	}
+
+	// the topic is reconciled again when its credentials secret is created or changes
+	if secretName := topic.Spec.Endpoint.CredentialsSecret; secretName != "" {
+		r.secretRefs.Set(request.NamespacedName, types.NamespacedName{Name: secretName, Namespace: topic.Namespace})
+	} else {
+		r.secretRefs.Set(request.NamespacedName)
+	}
+
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReconcilingStatus, nil, nil)
+
+	// The object store of the topic must be ready
+	store, err := GetReadyObjectStore(r.client, storeName)
+	if err != nil {
+		logger.Infof("waiting for the object store of bucket topic %q. %v", request.NamespacedName, err)
+		return WaitForRequeueIfObjectStoreNotReady, nil
+	}
+	if err := validateTopic(topic, store); err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid bucket topic CR %q spec", request.NamespacedName)
+	}
+
+	// CREATE/UPDATE
+	arn, err := r.createTopic(topic, store)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to create bucket topic %q", request.NamespacedName)
+	}
+
+	// Set Ready status, we are done reconciling
+	topicStatus := &cephv1.BucketTopicStatus{
+		ARN:                arn,
+		ObservedGeneration: topic.Generation,
+		LastSyncTime:       opcontroller.FormatStatusTime(time.Now().UTC()),
+	}
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, nil, topicStatus)
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// createTopic creates or updates the topic in the object store and returns its ARN
+func (r *ReconcileBucketTopic) createTopic(topic *cephv1.CephBucketTopic, store *cephv1.CephObjectStore) (string, error) {
+	pushEndpoint, err := r.pushEndpoint(topic)
+	if err != nil {
+		return "", err
+	}
+
+	snsClient, err := r.newAdminSNSClient(store)
+	if err != nil {
+		return "", err
+	}
+
+	// creating an existing topic updates its attributes
+	out, err := snsClient.CreateTopic(&sns.CreateTopicInput{
+		Name:       aws.String(topic.Name),
+		Attributes: topicAttributes(topic, pushEndpoint),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create topic %q in object store %q", topic.Name, store.Name)
+	}
+
+	arn := aws.StringValue(out.TopicArn)
+	logger.Infof("created bucket topic %q with ARN %q", topic.Name, arn)
+	return arn, nil
+}
+
+// deleteTopic deletes the topic from the object store once no bucket notification uses it
+func (r *ReconcileBucketTopic) deleteTopic(topic *cephv1.CephBucketTopic) (reconcile.Result, error) {
+	notifications := &cephv1.CephBucketNotificationList{}
+	err := r.client.List(context.TODO(), notifications, client.InNamespace(topic.Namespace))
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to list bucket notifications")
+	}
+	for _, notification := range notifications.Items {
+		if notification.Spec.Topic == topic.Name {
+			return opcontroller.WaitForRequeueIfFinalizerBlocked, errors.Errorf("bucket notification %q still uses the topic, delete it before deleting the topic", notification.Name)
+		}
+	}
+
+	// The topic was never created
+	if topic.Status == nil || topic.Status.ARN == "" {
+		return reconcile.Result{}, nil
+	}
+
+	store := &cephv1.CephObjectStore{}
+	err = r.client.Get(context.TODO(), ObjectStoreName(topic), store)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// The topic is gone with the object store
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, errors.Wrap(err, "failed to get the object store of the topic")
+	}
+
+	snsClient, err := r.newAdminSNSClient(store)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	_, err = snsClient.DeleteTopic(&sns.DeleteTopicInput{TopicArn: aws.String(topic.Status.ARN)})
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to delete topic %q", topic.Status.ARN)
+	}
+
+	logger.Infof("deleted bucket topic %q", topic.Status.ARN)
+	return reconcile.Result{}, nil
+}
+
+// newAdminSNSClient returns a client of the SNS compatible API of the object store signing the requests with the
+// admin user of the store. The TLS endpoint of the store is used when it exists since rgw refuses the credentials
+// of a push endpoint sent in clear text.
+func (r *ReconcileBucketTopic) newAdminSNSClient(store *cephv1.CephObjectStore) (*sns.SNS, error) {
+	objContext := object.NewContext(r.context, store.Name, store.Namespace)
+	adminUser, err := object.GetOrCreateAdminUser(objContext)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := object.GetSecureEndpoint(store)
+	if endpoint == "" {
+		return newSNSClient(*adminUser.AccessKey, *adminUser.SecretKey, object.GetEndpoint(store), nil)
+	}
+	caBundle, err := object.CABundle(r.context, store)
+	if err != nil {
+		return nil, err
+	}
+	return newSNSClient(*adminUser.AccessKey, *adminUser.SecretKey, endpoint, caBundle)
+}
+
+// newSNSClient returns a client of an SNS compatible API. The certificate of a TLS endpoint is verified with the
+// given certificate authorities, the ones of the system if empty.
+func newSNSClient(accessKey, secretKey, endpoint string, caBundle []byte) (*sns.SNS, error) {
+	sess, err := session.NewSession(
+		aws.NewConfig().
+			WithRegion(cephRegion).
+			WithCredentials(credentials.NewStaticCredentials(accessKey, secretKey, "")).
+			WithEndpoint(endpoint).
+			WithMaxRetries(3))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create SNS session")
+	}
+	if len(caBundle) == 0 {
+		return sns.New(sess), nil
+	}
+
+	// the client is set after the session is created, which would replace its certificate authorities with the
+	// ones of AWS_CA_BUNDLE
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caBundle) {
+		return nil, errors.New("failed to read the certificate authorities of the object store")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: caPool}
+	return sns.New(sess, aws.NewConfig().WithHTTPClient(&http.Client{Transport: transport})), nil
+}
+
+// pushEndpoint returns the URI of the endpoint of the topic with the credentials of its secret
+func (r *ReconcileBucketTopic) pushEndpoint(topic *cephv1.CephBucketTopic) (string, error) {
+	uri, err := url.Parse(endpointURI(topic.Spec.Endpoint))
+	if err != nil {
+		return "", errors.Wrap(err, "invalid endpoint uri")
+	}
+
+	secretName := topic.Spec.Endpoint.CredentialsSecret
+	if secretName != "" {
+		secret := &corev1.Secret{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: topic.Namespace}, secret)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get endpoint credentials secret %q", secretName)
+		}
+		username := string(secret.Data[credentialsUsernameKey])
+		if username == "" {
+			return "", errors.Errorf("endpoint credentials secret %q has no %q", secretName, credentialsUsernameKey)
+		}
+		uri.User = url.UserPassword(username, string(secret.Data[credentialsPasswordKey]))
+	}
+
+	return uri.String(), nil
+}
+
+// topicAttributes returns the rgw attributes of the topic
+func topicAttributes(topic *cephv1.CephBucketTopic, pushEndpoint string) map[string]*string {
+	attributes := map[string]*string{
+		"push-endpoint": aws.String(pushEndpoint),
+	}
+	if topic.Spec.OpaqueData != "" {
+		attributes["OpaqueData"] = aws.String(topic.Spec.OpaqueData)
+	}
+	if topic.Spec.Persistent {
+		attributes["persistent"] = aws.String("true")
+	}
+
+	endpoint := topic.Spec.Endpoint
+	disableVerifySSL := false
+	switch {
+	case endpoint.HTTP != nil:
+		disableVerifySSL = endpoint.HTTP.DisableVerifySSL
+	case endpoint.AMQP != nil:
+		disableVerifySSL = endpoint.AMQP.DisableVerifySSL
+		attributes["amqp-exchange"] = aws.String(endpoint.AMQP.Exchange)
+		if endpoint.AMQP.AckLevel != "" {
+			attributes["amqp-ack-level"] = aws.String(endpoint.AMQP.AckLevel)
+		}
+	case endpoint.Kafka != nil:
+		disableVerifySSL = endpoint.Kafka.DisableVerifySSL
+		attributes["use-ssl"] = aws.String(strconv.FormatBool(endpoint.Kafka.UseSSL))
+		if endpoint.Kafka.AckLevel != "" {
+			attributes["kafka-ack-level"] = aws.String(endpoint.Kafka.AckLevel)
+		}
+	}
+	if disableVerifySSL {
+		attributes["verify-ssl"] = aws.String("false")
+	}
+	return attributes
+}
+
+// endpointURI returns the URI of the endpoint set in the spec
+func endpointURI(endpoint cephv1.TopicEndpointSpec) string {
+	switch {
+	case endpoint.HTTP != nil:
+		return endpoint.HTTP.URI
+	case endpoint.AMQP != nil:
+		return endpoint.AMQP.URI
+	case endpoint.Kafka != nil:
+		return endpoint.Kafka.URI
+	}
+	return ""
+}
+
+// validateTopic validates the spec of a bucket topic. If the object store is nil, the settings requiring the object
+// store, such as the credentials of the endpoint, are not validated.
+func validateTopic(topic *cephv1.CephBucketTopic, store *cephv1.CephObjectStore) error {
+	if topic.Spec.ObjectStoreName == "" {
+		return errors.New("missing objectStoreName")
+	}
+
+	endpoint := topic.Spec.Endpoint
+	endpoints := 0
+	var schemes, tlsSchemes []string
+	if endpoint.HTTP != nil {
+		endpoints++
+		schemes, tlsSchemes = []string{"http", "https"}, []string{"https"}
+	}
+	if endpoint.AMQP != nil {
+		endpoints++
+		schemes, tlsSchemes = []string{"amqp", "amqps"}, []string{"amqps"}
+		if endpoint.AMQP.Exchange == "" {
+			return errors.New("missing amqp exchange")
+		}
+		if !isOneOf(endpoint.AMQP.AckLevel, "", "none", "broker", "routable") {
+			return errors.Errorf("invalid amqp ackLevel %q, must be none, broker or routable", endpoint.AMQP.AckLevel)
+		}
+	}
+	if endpoint.Kafka != nil {
+		endpoints++
+		schemes, tlsSchemes = []string{"kafka"}, []string{"kafka"}
+		if !isOneOf(endpoint.Kafka.AckLevel, "", "none", "broker") {
+			return errors.Errorf("invalid kafka ackLevel %q, must be none or broker", endpoint.Kafka.AckLevel)
+		}
+		if endpoint.CredentialsSecret != "" && !endpoint.Kafka.UseSSL {
+			return errors.New("kafka credentials require useSSL")
+		}
+	}
+	if endpoints != 1 {
+		return errors.New("exactly one of the http, amqp and kafka endpoints must be set")
+	}
+
+	uri, err := url.Parse(endpointURI(endpoint))
+	if err != nil {
+		return errors.Wrap(err, "invalid endpoint uri")
+	}
+	if !isOneOf(uri.Scheme, schemes...) || uri.Host == "" {
+		return errors.Errorf("invalid endpoint uri %q, must be a %v uri with a host", endpointURI(endpoint), schemes)
+	}
+	if uri.User != nil {
+		return errors.New("the endpoint uri cannot hold credentials, set them in the credentialsSecret")
+	}
+	// rgw only sends the credentials of an endpoint over TLS
+	if endpoint.CredentialsSecret != "" && !isOneOf(uri.Scheme, tlsSchemes...) {
+		return errors.Errorf("the credentials of the endpoint require TLS, the endpoint uri %q must be a %v uri", endpointURI(endpoint), tlsSchemes)
+	}
+	// nor does it accept them in clear text from the operator
+	if endpoint.CredentialsSecret != "" && store != nil && object.GetSecureEndpoint(store) == "" {
+		return errors.Errorf("the credentials of the endpoint can only be sent to object store %q over TLS, set its gateway securePort and certificate", store.Name)
+	}
+
+	return nil
+}
+
+func isOneOf(value string, values ...string) bool {
+	for _, v := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ObjectStoreName returns the name of the CephObjectStore of a topic
+func ObjectStoreName(topic *cephv1.CephBucketTopic) types.NamespacedName {
+	namespace := topic.Spec.ObjectStoreNamespace
+	if namespace == "" {
+		namespace = topic.Namespace
+	}
+	return types.NamespacedName{Name: topic.Spec.ObjectStoreName, Namespace: namespace}
+}
+
+// GetReadyObjectStore returns the CephObjectStore with the given name if it is ready
+func GetReadyObjectStore(c client.Client, name types.NamespacedName) (*cephv1.CephObjectStore, error) {
+	store := &cephv1.CephObjectStore{}
+	err := c.Get(context.TODO(), name, store)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, errors.Errorf("object store %q not found", name)
+		}
+		return nil, errors.Wrapf(err, "failed to get object store %q", name)
+	}
+	if store.Status == nil || store.Status.Phase != k8sutil.ReadyStatus {
+		return nil, errors.Errorf("object store %q is not ready", name)
+	}
+	return store, nil
+}
+
+// updateStatus updates a bucket topic CR with the given status and the matching conditions. The ARN and the sync
+// of the topic are updated when a topic status is given.
+func updateStatus(client client.Client, name types.NamespacedName, status string, reconcileErr error, topicStatus *cephv1.BucketTopicStatus) {
+	topic := &cephv1.CephBucketTopic{}
+	if err := client.Get(context.TODO(), name, topic); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketTopic resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve bucket topic %q to update status to %q. %v", name, status, err)
+		return
+	}
+
+	if topic.Status == nil {
+		topic.Status = &cephv1.BucketTopicStatus{}
+	}
+
+	topic.Status.Phase = status
+	opcontroller.SetPhaseConditions(&topic.Status.Conditions, status, reconcileErr)
+	if topicStatus != nil {
+		topic.Status.ARN = topicStatus.ARN
+		topic.Status.ObservedGeneration = topicStatus.ObservedGeneration
+		topic.Status.LastSyncTime = topicStatus.LastSyncTime
+	}
+	if err := opcontroller.UpdateStatus(client, topic); err != nil {
+		logger.Warningf("failed to set bucket topic %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("bucket topic %q status updated to %q", name, status)
+}
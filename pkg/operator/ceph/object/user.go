@@ -33,6 +33,9 @@ const (
 	RGWErrorBadData
 	RGWErrorParse
 	ErrorCodeFileExists = 17
+
+	// AdminUserID is the user of an object store signing the requests of the operator to the rgw APIs
+	AdminUserID = "rook-ceph-object-admin"
 )
 
 // An ObjectUser defines the details of an object store user.
@@ -42,6 +45,36 @@ type ObjectUser struct {
 	Email       *string `json:"email"`
 	AccessKey   *string `json:"accessKey"`
 	SecretKey   *string `json:"secretKey"`
+	// Keys are all the S3 keys of the user, AccessKey and SecretKey being the first one
+	Keys []ObjectUserKey `json:"keys"`
+	// SwiftKeys are the Swift keys of the subusers of the user
+	SwiftKeys []ObjectUserKey `json:"swiftKeys"`
+	// SubUsers are the subusers of the user
+	SubUsers []ObjectSubUser `json:"subUsers"`
+	// Caps are the capabilities of the user on the admin API
+	Caps []ObjectUserCap `json:"caps"`
+}
+
+// ObjectUserKey is a key of an object store user. The user of a Swift key is the subuser, e.g. "my-user:swift", and
+// it has no access key.
+type ObjectUserKey struct {
+	User      string `json:"user"`
+	AccessKey string `json:"access_key,omitempty"`
+	SecretKey string `json:"secret_key"`
+}
+
+// ObjectSubUser is a subuser of an object store user, e.g. "my-user:swift". The permissions are one of "<none>",
+// "read", "write", "read-write" or "full-control".
+type ObjectSubUser struct {
+	ID          string `json:"id"`
+	Permissions string `json:"permissions"`
+}
+
+// ObjectUserCap is a capability of an object store user on a type of resources of the admin API, e.g. "users". The
+// permission is one of "read", "write" or "*".
+type ObjectUserCap struct {
+	Type       string `json:"type"`
+	Permission string `json:"perm"`
 }
 
 // ListUsers lists the object pool users.
@@ -60,13 +93,13 @@ func ListUsers(c *Context) ([]string, int, error) {
 }
 
 type rgwUserInfo struct {
-	UserID      string `json:"user_id"`
-	DisplayName string `json:"display_name"`
-	Email       string `json:"email"`
-	Keys        []struct {
-		AccessKey string `json:"access_key"`
-		SecretKey string `json:"secret_key"`
-	}
+	UserID      string          `json:"user_id"`
+	DisplayName string          `json:"display_name"`
+	Email       string          `json:"email"`
+	Keys        []ObjectUserKey `json:"keys"`
+	SwiftKeys   []ObjectUserKey `json:"swift_keys"`
+	SubUsers    []ObjectSubUser `json:"subusers"`
+	Caps        []ObjectUserCap `json:"caps"`
 }
 
 func decodeUser(data string) (*ObjectUser, int, error) {
@@ -76,7 +109,15 @@ func decodeUser(data string) (*ObjectUser, int, error) {
 		return nil, RGWErrorParse, errors.Wrap(err, "Failed to unmarshal json")
 	}
 
-	rookUser := ObjectUser{UserID: user.UserID, DisplayName: &user.DisplayName, Email: &user.Email}
+	rookUser := ObjectUser{
+		UserID:      user.UserID,
+		DisplayName: &user.DisplayName,
+		Email:       &user.Email,
+		Keys:        user.Keys,
+		SwiftKeys:   user.SwiftKeys,
+		SubUsers:    user.SubUsers,
+		Caps:        user.Caps,
+	}
 
 	if len(user.Keys) > 0 {
 		rookUser.AccessKey = &user.Keys[0].AccessKey
@@ -140,6 +181,29 @@ func CreateUser(c *Context, user ObjectUser) (*ObjectUser, int, error) {
 	return decodeUser(result)
 }
 
+// GetOrCreateAdminUser returns the admin user of the object store with its keys, creating the user if needed
+func GetOrCreateAdminUser(c *Context) (*ObjectUser, error) {
+	user, errCode, err := GetUser(c, AdminUserID)
+	if err == nil {
+		return user, nil
+	}
+	if errCode != RGWErrorNotFound {
+		return nil, errors.Wrapf(err, "failed to get object store admin user %q", AdminUserID)
+	}
+
+	displayName := "Rook object store admin"
+	user, errCode, err = CreateUser(c, ObjectUser{UserID: AdminUserID, DisplayName: &displayName})
+	if err != nil {
+		if errCode == ErrorCodeFileExists {
+			user, _, err = GetUser(c, AdminUserID)
+			return user, err
+		}
+		return nil, errors.Wrapf(err, "failed to create object store admin user %q", AdminUserID)
+	}
+	logger.Infof("created object store admin user %q", AdminUserID)
+	return user, nil
+}
+
 // UpdateUser updates the user whose ID matches the user.
 func UpdateUser(c *Context, user ObjectUser) (*ObjectUser, int, error) {
 	logger.Infof("Updating user: %s", user.UserID)
@@ -196,7 +260,7 @@ func setUserQuota(c *Context, id string, args []string) (string, int, error) {
 	args = append([]string{"quota", "set", "--uid", id}, args...)
 	result, err := runAdminCommand(c, args...)
 	if err != nil {
-		err = errors.Wrap(err, "failed to set max buckets for user")
+		err = errors.Wrap(err, "failed to set quota for user")
 	}
 	return result, RGWErrorNone, err
 }
@@ -226,3 +290,118 @@ func UnlinkUser(c *Context, id, bucket string) (string, int, error) {
 	}
 	return result, RGWErrorNone, nil
 }
+
+// SetUserMaxBuckets sets the maximum number of buckets a user can own
+func SetUserMaxBuckets(c *Context, id string, max int) error {
+	_, err := runAdminCommand(c, "user", "modify", "--uid", id, "--max-buckets", strconv.Itoa(max))
+	if err != nil {
+		return errors.Wrapf(err, "failed to set max buckets of user %q to %d", id, max)
+	}
+	return nil
+}
+
+// SetUserQuota sets the maximum size in bytes and the maximum number of objects of all the buckets of a user. A
+// negative value leaves the size or the number of objects unlimited, the quota being disabled when both are.
+func SetUserQuota(c *Context, id string, maxSize, maxObjects int64) error {
+	if maxSize < 0 && maxObjects < 0 {
+		_, err := runAdminCommand(c, "quota", "disable", "--quota-scope", "user", "--uid", id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to disable quota of user %q", id)
+		}
+		return nil
+	}
+
+	args := []string{"--quota-scope", "user", "--max-size", strconv.FormatInt(maxSize, 10), "--max-objects", strconv.FormatInt(maxObjects, 10)}
+	if _, _, err := setUserQuota(c, id, args); err != nil {
+		return errors.Wrapf(err, "failed to set quota of user %q", id)
+	}
+	_, err := runAdminCommand(c, "quota", "enable", "--quota-scope", "user", "--uid", id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable quota of user %q", id)
+	}
+	return nil
+}
+
+// AddUserCaps grants capabilities to a user, e.g. "users=read;buckets=*"
+func AddUserCaps(c *Context, id, caps string) error {
+	_, err := runAdminCommand(c, "caps", "add", "--uid", id, "--caps", caps)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add caps %q to user %q", caps, id)
+	}
+	return nil
+}
+
+// RemoveUserCaps revokes capabilities of a user, e.g. "users=read;buckets=*"
+func RemoveUserCaps(c *Context, id, caps string) error {
+	_, err := runAdminCommand(c, "caps", "rm", "--uid", id, "--caps", caps)
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove caps %q from user %q", caps, id)
+	}
+	return nil
+}
+
+// SubUserID returns the ID of a subuser of a user, e.g. "my-user:swift"
+func SubUserID(id, subUser string) string {
+	return id + ":" + subUser
+}
+
+// CreateSubUser creates a Swift subuser of a user with a generated key. The access is one of "read", "write",
+// "readwrite" or "full".
+func CreateSubUser(c *Context, id, subUser, access string) error {
+	args := []string{"subuser", "create", "--uid", id, "--subuser", SubUserID(id, subUser), "--access", access, "--key-type", "swift", "--gen-secret"}
+	_, err := runAdminCommand(c, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create subuser %q of user %q", subUser, id)
+	}
+	return nil
+}
+
+// ModifySubUser changes the access of a subuser of a user
+func ModifySubUser(c *Context, id, subUser, access string) error {
+	_, err := runAdminCommand(c, "subuser", "modify", "--uid", id, "--subuser", SubUserID(id, subUser), "--access", access)
+	if err != nil {
+		return errors.Wrapf(err, "failed to modify subuser %q of user %q", subUser, id)
+	}
+	return nil
+}
+
+// DeleteSubUser deletes a subuser of a user along with its keys
+func DeleteSubUser(c *Context, id, subUser string) error {
+	_, err := runAdminCommand(c, "subuser", "rm", "--uid", id, "--subuser", SubUserID(id, subUser), "--purge-keys")
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete subuser %q of user %q", subUser, id)
+	}
+	return nil
+}
+
+// CreateUserKey generates a new S3 key for a user and returns it. The previous keys of the user remain valid.
+func CreateUserKey(c *Context, user *ObjectUser) (*ObjectUserKey, error) {
+	result, err := runAdminCommand(c, "key", "create", "--uid", user.UserID, "--key-type", "s3", "--gen-access-key", "--gen-secret")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create key for user %q", user.UserID)
+	}
+	updatedUser, _, err := decodeUser(result)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read keys of user %q", user.UserID)
+	}
+
+	previousKeys := map[string]bool{}
+	for _, key := range user.Keys {
+		previousKeys[key.AccessKey] = true
+	}
+	for _, key := range updatedUser.Keys {
+		if !previousKeys[key.AccessKey] {
+			return &key, nil
+		}
+	}
+	return nil, errors.Errorf("failed to find the key created for user %q", user.UserID)
+}
+
+// DeleteUserKey deletes an S3 key of a user
+func DeleteUserKey(c *Context, id, accessKey string) error {
+	_, err := runAdminCommand(c, "key", "rm", "--uid", id, "--key-type", "s3", "--access-key", accessKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete key %q of user %q", accessKey, id)
+	}
+	return nil
+}
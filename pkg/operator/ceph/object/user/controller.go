@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
@@ -70,6 +71,8 @@ type ReconcileObjectStoreUser struct {
 	context         *clusterd.Context
 	objContext      *object.Context
 	userConfig      object.ObjectUser
+	swiftKeys       map[string]string
+	keyStatus       keyStatus
 	cephClusterSpec *cephv1.ClusterSpec
 	clusterInfo     *cephconfig.ClusterInfo
 }
@@ -262,48 +265,70 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 		return reconcileResponse, err
 	}
 
+	// Record the keys in use now that the secret holds the current key
+	err = updateKeyStatus(r.client, request.NamespacedName, r.keyStatus)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		return reconcile.Result{}, err
+	}
+
 	// Set Ready status, we are done reconciling
 	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
-	// Return and do not requeue
+	// Return and only requeue to remove the retired keys
 	logger.Debug("done reconciling")
-	return reconcile.Result{}, nil
+	return requeueForRetiredKeys(r.keyStatus.retiredKeys, time.Now()), nil
 }
 
 func (r *ReconcileObjectStoreUser) reconcileCephUser(cephObjectStoreUser *cephv1.CephObjectStoreUser) (reconcile.Result, error) {
-	err := r.createCephUser(cephObjectStoreUser)
+	user, created, err := r.createCephUser(cephObjectStoreUser)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create object store user %q", cephObjectStoreUser.Name)
 	}
 
+	maxBuckets, err := r.reconcileQuotas(cephObjectStoreUser)
+	if !reflect.DeepEqual(maxBuckets, appliedMaxBuckets(cephObjectStoreUser)) {
+		updateMaxBucketsStatus(r.client, types.NamespacedName{Name: cephObjectStoreUser.Name, Namespace: cephObjectStoreUser.Namespace}, maxBuckets)
+	}
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to set quotas of object store user %q", cephObjectStoreUser.Name)
+	}
+
+	err = r.reconcileCaps(cephObjectStoreUser, user)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to set capabilities of object store user %q", cephObjectStoreUser.Name)
+	}
+
+	r.swiftKeys, err = r.reconcileSubUsers(cephObjectStoreUser, user)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile subusers of object store user %q", cephObjectStoreUser.Name)
+	}
+
+	err = r.reconcileKeys(cephObjectStoreUser, user, created, time.Now())
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile keys of object store user %q", cephObjectStoreUser.Name)
+	}
+
 	return reconcile.Result{}, nil
 }
 
-func (r *ReconcileObjectStoreUser) createCephUser(u *cephv1.CephObjectStoreUser) error {
+// createCephUser creates the user if it does not exist yet and returns it, along with whether it was just created
+func (r *ReconcileObjectStoreUser) createCephUser(u *cephv1.CephObjectStoreUser) (*object.ObjectUser, bool, error) {
 	logger.Infof("creating ceph object user %q in namespace %q", u.Name, u.Namespace)
 	user, rgwerr, err := object.CreateUser(r.objContext, r.userConfig)
 	if err != nil {
 		if rgwerr == object.ErrorCodeFileExists {
 			objectUser, _, err := object.GetUser(r.objContext, r.userConfig.UserID)
 			if err != nil {
-				return errors.Wrapf(err, "failed to get details from ceph object user %q", objectUser.UserID)
+				return nil, false, errors.Wrapf(err, "failed to get details from ceph object user %q", r.userConfig.UserID)
 			}
-
-			// Set access and secret key
-			r.userConfig.AccessKey = objectUser.AccessKey
-			r.userConfig.SecretKey = objectUser.SecretKey
-
-			return nil
+			return objectUser, false, nil
 		}
-		return errors.Wrapf(err, "failed to create ceph object user %q. error code %d", u.Name, rgwerr)
+		return nil, false, errors.Wrapf(err, "failed to create ceph object user %q. error code %d", u.Name, rgwerr)
 	}
 
-	// Set access and secret key
-	r.userConfig.AccessKey = user.AccessKey
-	r.userConfig.SecretKey = user.SecretKey
-
 	logger.Infof("created ceph object user %q", u.Name)
-	return nil
+	return user, true, nil
 }
 
 func (r *ReconcileObjectStoreUser) isObjectStoreInitialized(u *cephv1.CephObjectStoreUser) (*object.Context, error) {
@@ -345,11 +370,13 @@ func (r *ReconcileObjectStoreUser) generateCephUserSecret(u *cephv1.CephObjectSt
 		"AccessKey": *r.userConfig.AccessKey,
 		"SecretKey": *r.userConfig.SecretKey,
 	}
+	for subUser, key := range r.swiftKeys {
+		secrets[swiftKeyPrefix+subUser] = key
+	}
 
-	secretName := fmt.Sprintf("rook-ceph-object-user-%s-%s", u.Spec.Store, u.Name)
 	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      secretName,
+			Name:      userSecretName(u),
 			Namespace: u.Namespace,
 			Labels: map[string]string{
 				"app":               appName,
@@ -365,6 +392,10 @@ func (r *ReconcileObjectStoreUser) generateCephUserSecret(u *cephv1.CephObjectSt
 	return secret
 }
 
+func userSecretName(u *cephv1.CephObjectStoreUser) string {
+	return fmt.Sprintf("rook-ceph-object-user-%s-%s", u.Spec.Store, u.Name)
+}
+
 func (r *ReconcileObjectStoreUser) reconcileCephUserSecret(cephObjectStoreUser *cephv1.CephObjectStoreUser) (reconcile.Result, error) {
 	// Generate Kubernetes Secret
 	secret := r.generateCephUserSecret(cephObjectStoreUser)
@@ -474,13 +505,42 @@ func (r *ReconcileObjectStoreUser) validateUser(u *cephv1.CephObjectStoreUser) e
 			return errors.New("missing store")
 		}
 	}
-	return nil
+	return validateSettings(u)
 }
 
 func labelsForRgw(name string) map[string]string {
 	return map[string]string{"rgw": name, k8sutil.AppAttr: appName}
 }
 
+// appliedMaxBuckets returns the maximum number of buckets applied to the user from the spec
+func appliedMaxBuckets(u *cephv1.CephObjectStoreUser) *int {
+	if u.Status == nil {
+		return nil
+	}
+	return u.Status.MaxBuckets
+}
+
+// updateMaxBucketsStatus records the maximum number of buckets applied to the user from the spec in its status
+func updateMaxBucketsStatus(client client.Client, name types.NamespacedName, maxBuckets *int) {
+	user := &cephv1.CephObjectStoreUser{}
+	if err := client.Get(context.TODO(), name, user); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectStoreUser resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve object store user %q to record its max buckets. %v", name, err)
+		return
+	}
+	if user.Status == nil {
+		user.Status = &cephv1.ObjectStoreUserStatus{}
+	}
+
+	user.Status.MaxBuckets = maxBuckets
+	if err := opcontroller.UpdateStatus(client, user); err != nil {
+		logger.Errorf("failed to record max buckets of object store user %q. %v", name, err)
+	}
+}
+
 // updateStatus updates an object with a given status
 func updateStatus(client client.Client, name types.NamespacedName, status string) {
 	user := &cephv1.CephObjectStoreUser{}
@@ -493,7 +553,7 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 		return
 	}
 	if user.Status == nil {
-		user.Status = &cephv1.Status{}
+		user.Status = &cephv1.ObjectStoreUserStatus{}
 	}
 
 	user.Status.Phase = status
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const defaultKeyRotationGracePeriod = 24 * time.Hour
+
+// keyStatus is the state of the keys of the user recorded in its status
+type keyStatus struct {
+	keyRotation string
+	retiredKeys []cephv1.RetiredObjectUserKey
+}
+
+// reconcileKeys selects the key of the user stored in its secret and rotates it when the value of the rotation
+// annotation changes. The previous key is retired and only removed from the object store once its grace period
+// expires, so that the applications have time to load the new key from the secret.
+// The rotation is recorded in the status before the new key is created. If the reconcile fails after that, the next
+// one adopts the key of the user that is not retired, or creates it, instead of rotating the keys again.
+func (r *ReconcileObjectStoreUser) reconcileKeys(u *cephv1.CephObjectStoreUser, user *object.ObjectUser, created bool, now time.Time) error {
+	status := keyStatus{}
+	if u.Status != nil {
+		status.keyRotation = u.Status.KeyRotation
+	}
+
+	keys := map[string]object.ObjectUserKey{}
+	for _, key := range user.Keys {
+		keys[key.AccessKey] = key
+	}
+
+	// remove the retired keys whose grace period expired
+	retired := map[string]bool{}
+	if u.Status != nil {
+		for _, key := range u.Status.RetiredKeys {
+			if _, ok := keys[key.AccessKey]; !ok {
+				continue
+			}
+			expiration, err := time.Parse(time.RFC3339, key.ExpirationTime)
+			if err == nil && now.Before(expiration) {
+				status.retiredKeys = append(status.retiredKeys, key)
+				retired[key.AccessKey] = true
+				continue
+			}
+			if err := object.DeleteUserKey(r.objContext, u.Name, key.AccessKey); err != nil {
+				return err
+			}
+			delete(keys, key.AccessKey)
+			logger.Infof("removed retired key %q of object store user %q", key.AccessKey, u.Name)
+		}
+	}
+
+	// keep the key of the secret as long as it is valid, otherwise pick the first valid key of the user
+	currentKey, ok := keys[r.secretAccessKey(u)]
+	if !ok || retired[currentKey.AccessKey] {
+		ok = false
+		for _, key := range user.Keys {
+			if _, exists := keys[key.AccessKey]; exists && !retired[key.AccessKey] {
+				currentKey, ok = key, true
+				break
+			}
+		}
+	}
+	// the keys of a new user or of a user without valid keys are already fresh
+	fresh := created || !ok
+	if !ok {
+		newKey, err := object.CreateUserKey(r.objContext, user)
+		if err != nil {
+			return err
+		}
+		currentKey = *newKey
+		logger.Infof("created key %q of object store user %q", currentKey.AccessKey, u.Name)
+	}
+
+	rotation := u.GetAnnotations()[cephv1.ObjectStoreUserRotateKeysAnnotation]
+	if rotation != "" && rotation != status.keyRotation {
+		if !fresh {
+			gracePeriod := defaultKeyRotationGracePeriod
+			if u.Spec.KeyRotationGracePeriod != nil {
+				gracePeriod = u.Spec.KeyRotationGracePeriod.Duration
+			}
+			status.retiredKeys = append(status.retiredKeys, cephv1.RetiredObjectUserKey{
+				AccessKey:      currentKey.AccessKey,
+				ExpirationTime: opcontroller.FormatStatusTime(now.Add(gracePeriod)),
+			})
+			status.keyRotation = rotation
+			if err := updateKeyStatus(r.client, types.NamespacedName{Name: u.Name, Namespace: u.Namespace}, status); err != nil {
+				return err
+			}
+
+			newKey, err := object.CreateUserKey(r.objContext, user)
+			if err != nil {
+				return err
+			}
+			logger.Infof("rotated key %q of object store user %q to key %q, the previous key remains valid for %s", currentKey.AccessKey, u.Name, newKey.AccessKey, gracePeriod.String())
+			currentKey = *newKey
+		}
+		status.keyRotation = rotation
+	}
+
+	r.userConfig.AccessKey = &currentKey.AccessKey
+	r.userConfig.SecretKey = &currentKey.SecretKey
+	r.keyStatus = status
+	return nil
+}
+
+// secretAccessKey returns the access key stored in the secret of the user, if any
+func (r *ReconcileObjectStoreUser) secretAccessKey(u *cephv1.CephObjectStoreUser) string {
+	secret := &v1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: userSecretName(u), Namespace: u.Namespace}, secret)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			logger.Warningf("failed to get secret of object store user %q. %v", u.Name, err)
+		}
+		return ""
+	}
+	return string(secret.Data["AccessKey"])
+}
+
+// requeueForRetiredKeys requeues the reconcile when the first retired key expires
+func requeueForRetiredKeys(retiredKeys []cephv1.RetiredObjectUserKey, now time.Time) reconcile.Result {
+	var next time.Duration
+	for _, key := range retiredKeys {
+		expiration, err := time.Parse(time.RFC3339, key.ExpirationTime)
+		if err != nil {
+			continue
+		}
+		wait := expiration.Sub(now)
+		if wait < time.Second {
+			wait = time.Second
+		}
+		if next == 0 || wait < next {
+			next = wait
+		}
+	}
+	if next == 0 {
+		return reconcile.Result{}
+	}
+	return reconcile.Result{RequeueAfter: next}
+}
+
+// updateKeyStatus records the key rotation and the retired keys of the user in its status
+func updateKeyStatus(client client.Client, name types.NamespacedName, status keyStatus) error {
+	user := &cephv1.CephObjectStoreUser{}
+	if err := client.Get(context.TODO(), name, user); err != nil {
+		return errors.Wrapf(err, "failed to retrieve object store user %q to update its keys status", name)
+	}
+	if user.Status == nil {
+		user.Status = &cephv1.ObjectStoreUserStatus{}
+	}
+	user.Status.KeyRotation = status.keyRotation
+	user.Status.RetiredKeys = status.retiredKeys
+	if err := opcontroller.UpdateStatus(client, user); err != nil {
+		return errors.Wrapf(err, "failed to update keys status of object store user %q", name)
+	}
+	return nil
+}
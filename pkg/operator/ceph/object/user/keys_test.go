@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const rotatedKeysJSON = `{
+	"user_id": "my-user",
+	"keys": [
+		{"user": "my-user", "access_key": "AK1", "secret_key": "SK1"},
+		{"user": "my-user", "access_key": "AK2", "secret_key": "SK2"}
+	]
+}`
+
+// newKeysReconcile returns a reconciler of the user whose secret holds the given access key, recording the
+// radosgw-admin commands it runs
+func newKeysReconcile(u *cephv1.CephObjectStoreUser, secretAccessKey string, commands *[]string) *ReconcileObjectStoreUser {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			*commands = append(*commands, strings.Join(args[:2], " "))
+			if args[0] == "key" && args[1] == "create" {
+				return rotatedKeysJSON, nil
+			}
+			return "", nil
+		},
+	}
+	s := runtime.NewScheme()
+	_ = cephv1.AddToScheme(s)
+	_ = v1.AddToScheme(s)
+	objects := []runtime.Object{u.DeepCopy()}
+	if secretAccessKey != "" {
+		objects = append(objects, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: userSecretName(u), Namespace: u.Namespace},
+			Data:       map[string][]byte{"AccessKey": []byte(secretAccessKey)},
+		})
+	}
+	c := &clusterd.Context{Executor: executor}
+	return &ReconcileObjectStoreUser{
+		client:     fake.NewFakeClientWithScheme(s, objects...),
+		context:    c,
+		objContext: object.NewContext(c, u.Spec.Store, u.Namespace),
+	}
+}
+
+func TestReconcileKeys(t *testing.T) {
+	now := time.Date(2020, 6, 2, 9, 0, 0, 0, time.UTC)
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: store},
+	}
+	user := &object.ObjectUser{UserID: name, Keys: []object.ObjectUserKey{{User: name, AccessKey: "AK1", SecretKey: "SK1"}}}
+	var commands []string
+
+	// the key of the user is stored in the secret
+	r := newKeysReconcile(u, "", &commands)
+	assert.NoError(t, r.reconcileKeys(u, user, false, now))
+	assert.Equal(t, "AK1", *r.userConfig.AccessKey)
+	assert.Equal(t, "SK1", *r.userConfig.SecretKey)
+	assert.Empty(t, commands)
+
+	// the keys of a new user are not rotated
+	u.Annotations = map[string]string{cephv1.ObjectStoreUserRotateKeysAnnotation: "1"}
+	r = newKeysReconcile(u, "", &commands)
+	assert.NoError(t, r.reconcileKeys(u, user, true, now))
+	assert.Equal(t, "AK1", *r.userConfig.AccessKey)
+	assert.Equal(t, keyStatus{keyRotation: "1"}, r.keyStatus)
+	assert.Empty(t, commands)
+
+	// the rotation creates a new key and retires the key of the secret
+	u.Spec.KeyRotationGracePeriod = &metav1.Duration{Duration: time.Hour}
+	r = newKeysReconcile(u, "AK1", &commands)
+	assert.NoError(t, r.reconcileKeys(u, user, false, now))
+	assert.Equal(t, "AK2", *r.userConfig.AccessKey)
+	assert.Equal(t, "SK2", *r.userConfig.SecretKey)
+	retiredKeys := []cephv1.RetiredObjectUserKey{{AccessKey: "AK1", ExpirationTime: opcontroller.FormatStatusTime(now.Add(time.Hour))}}
+	assert.Equal(t, keyStatus{keyRotation: "1", retiredKeys: retiredKeys}, r.keyStatus)
+	assert.Equal(t, []string{"key create"}, commands)
+	assert.Equal(t, time.Hour, requeueForRetiredKeys(r.keyStatus.retiredKeys, now).RequeueAfter)
+
+	// the rotation is recorded before the key is created, the key created by a failed reconcile is adopted
+	recorded := &cephv1.CephObjectStoreUser{}
+	assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, recorded))
+	assert.Equal(t, "1", recorded.Status.KeyRotation)
+	assert.Equal(t, retiredKeys, recorded.Status.RetiredKeys)
+	commands = nil
+	rotatedUser := &object.ObjectUser{UserID: name, Keys: []object.ObjectUserKey{
+		{User: name, AccessKey: "AK1", SecretKey: "SK1"},
+		{User: name, AccessKey: "AK2", SecretKey: "SK2"},
+	}}
+	r = newKeysReconcile(recorded, "AK1", &commands)
+	assert.NoError(t, r.reconcileKeys(recorded, rotatedUser, false, now))
+	assert.Equal(t, "AK2", *r.userConfig.AccessKey)
+	assert.Equal(t, retiredKeys, r.keyStatus.retiredKeys)
+	assert.Empty(t, commands)
+
+	// the retired key remains valid during its grace period
+	commands = nil
+	u.Status = &cephv1.ObjectStoreUserStatus{KeyRotation: "1", RetiredKeys: retiredKeys}
+	user.Keys = append(user.Keys, object.ObjectUserKey{User: name, AccessKey: "AK2", SecretKey: "SK2"})
+	r = newKeysReconcile(u, "AK2", &commands)
+	assert.NoError(t, r.reconcileKeys(u, user, false, now.Add(time.Minute)))
+	assert.Equal(t, "AK2", *r.userConfig.AccessKey)
+	assert.Equal(t, retiredKeys, r.keyStatus.retiredKeys)
+	assert.Empty(t, commands)
+
+	// the retired key is removed once its grace period expired
+	r = newKeysReconcile(u, "AK2", &commands)
+	assert.NoError(t, r.reconcileKeys(u, user, false, now.Add(2*time.Hour)))
+	assert.Equal(t, "AK2", *r.userConfig.AccessKey)
+	assert.Equal(t, keyStatus{keyRotation: "1"}, r.keyStatus)
+	assert.Equal(t, []string{"key rm"}, commands)
+	assert.False(t, requeueForRetiredKeys(r.keyStatus.retiredKeys, now).Requeue)
+}
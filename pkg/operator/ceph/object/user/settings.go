@@ -0,0 +1,235 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+)
+
+const (
+	defaultSubUserAccess = "full"
+	// the default maximum number of buckets of a user in rgw
+	defaultMaxBuckets = 1000
+	// the prefix of the keys of the secret holding the Swift keys of the subusers
+	swiftKeyPrefix = "SwiftKey-"
+)
+
+var (
+	subUserNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+	// the permissions of the subusers reported by radosgw-admin for each access
+	subUserPermissions = map[string]string{
+		"read":      "read",
+		"write":     "write",
+		"readwrite": "read-write",
+		"full":      "full-control",
+	}
+)
+
+// capabilities returns the capabilities of the spec by type of resources, with the permissions as reported by
+// radosgw-admin
+func capabilities(spec *cephv1.ObjectUserCapSpec) (map[string]string, error) {
+	caps := map[string]string{"users": "", "buckets": "", "usage": "", "metadata": ""}
+	if spec == nil {
+		return caps, nil
+	}
+
+	for capType, value := range map[string]string{"users": spec.Users, "buckets": spec.Buckets, "usage": spec.Usage, "metadata": spec.Metadata} {
+		switch strings.Join(strings.Fields(value), "") {
+		case "":
+		case "read":
+			caps[capType] = "read"
+		case "write":
+			caps[capType] = "write"
+		case "*", "read,write", "write,read":
+			caps[capType] = "*"
+		default:
+			return nil, errors.Errorf("invalid %q capability %q, must be one of \"read\", \"write\" or \"read, write\"", capType, value)
+		}
+	}
+	return caps, nil
+}
+
+// validateSettings validates the quotas, the capabilities and the subusers of the user
+func validateSettings(u *cephv1.CephObjectStoreUser) error {
+	if quotas := u.Spec.Quotas; quotas != nil {
+		if quotas.MaxBuckets != nil && *quotas.MaxBuckets < 0 {
+			return errors.Errorf("invalid max buckets %d, must not be negative", *quotas.MaxBuckets)
+		}
+		if quotas.MaxSize != nil && quotas.MaxSize.Sign() < 0 {
+			return errors.Errorf("invalid max size %q, must not be negative", quotas.MaxSize.String())
+		}
+		if quotas.MaxObjects != nil && *quotas.MaxObjects < 0 {
+			return errors.Errorf("invalid max objects %d, must not be negative", *quotas.MaxObjects)
+		}
+	}
+
+	if _, err := capabilities(u.Spec.Capabilities); err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for _, subUser := range u.Spec.SubUsers {
+		if !subUserNameRegexp.MatchString(subUser.Name) {
+			return errors.Errorf("invalid subuser name %q, must only contain alphanumeric characters, '.', '_' and '-'", subUser.Name)
+		}
+		if names[subUser.Name] {
+			return errors.Errorf("subuser %q is defined more than once", subUser.Name)
+		}
+		names[subUser.Name] = true
+		if _, ok := subUserPermissions[subUserAccess(subUser)]; subUser.Access != "" && !ok {
+			return errors.Errorf("invalid access %q of subuser %q, must be one of \"read\", \"write\", \"readwrite\" or \"full\"", subUser.Access, subUser.Name)
+		}
+	}
+
+	if period := u.Spec.KeyRotationGracePeriod; period != nil && period.Duration < 0 {
+		return errors.Errorf("invalid key rotation grace period %q, must not be negative", period.Duration.String())
+	}
+	return nil
+}
+
+func subUserAccess(subUser cephv1.ObjectUserSubUserSpec) string {
+	if subUser.Access == "" {
+		return defaultSubUserAccess
+	}
+	return subUser.Access
+}
+
+// reconcileQuotas sets the quotas of the user and returns the maximum number of buckets applied from the spec. The
+// maximum number of buckets is only reset to the default of rgw when the value applied before is removed from the
+// spec, otherwise the value set outside of the spec is kept. The other quotas that are not set are unlimited, so
+// removing a quota from the spec removes it from the user.
+func (r *ReconcileObjectStoreUser) reconcileQuotas(u *cephv1.CephObjectStoreUser) (*int, error) {
+	var maxBuckets *int
+	maxSize, maxObjects := int64(-1), int64(-1)
+	if quotas := u.Spec.Quotas; quotas != nil {
+		maxBuckets = quotas.MaxBuckets
+		if quotas.MaxSize != nil {
+			maxSize = quotas.MaxSize.Value()
+		}
+		if quotas.MaxObjects != nil {
+			maxObjects = *quotas.MaxObjects
+		}
+	}
+
+	if maxBuckets != nil {
+		if err := object.SetUserMaxBuckets(r.objContext, u.Name, *maxBuckets); err != nil {
+			return appliedMaxBuckets(u), err
+		}
+	} else if appliedMaxBuckets(u) != nil {
+		if err := object.SetUserMaxBuckets(r.objContext, u.Name, defaultMaxBuckets); err != nil {
+			return appliedMaxBuckets(u), err
+		}
+	}
+
+	return maxBuckets, object.SetUserQuota(r.objContext, u.Name, maxSize, maxObjects)
+}
+
+// reconcileCaps grants the capabilities of the spec to the user and revokes the others. Only the capabilities on the
+// users, the buckets, the usage and the metadata are managed.
+func (r *ReconcileObjectStoreUser) reconcileCaps(u *cephv1.CephObjectStoreUser, user *object.ObjectUser) error {
+	expected, err := capabilities(u.Spec.Capabilities)
+	if err != nil {
+		return err
+	}
+	current := map[string]string{}
+	for _, c := range user.Caps {
+		current[c.Type] = c.Permission
+	}
+
+	for capType, permission := range expected {
+		if current[capType] == permission {
+			continue
+		}
+		if current[capType] != "" {
+			if err := object.RemoveUserCaps(r.objContext, u.Name, fmt.Sprintf("%s=%s", capType, current[capType])); err != nil {
+				return err
+			}
+		}
+		if permission != "" {
+			if err := object.AddUserCaps(r.objContext, u.Name, fmt.Sprintf("%s=%s", capType, permission)); err != nil {
+				return err
+			}
+		}
+		logger.Infof("set %q capability of object store user %q to %q", capType, u.Name, permission)
+	}
+	return nil
+}
+
+// reconcileSubUsers creates, updates and deletes the subusers of the user to match the spec. It returns the Swift
+// keys of the subusers by subuser name.
+func (r *ReconcileObjectStoreUser) reconcileSubUsers(u *cephv1.CephObjectStoreUser, user *object.ObjectUser) (map[string]string, error) {
+	current := map[string]string{}
+	for _, subUser := range user.SubUsers {
+		current[subUser.ID] = subUser.Permissions
+	}
+
+	changed := false
+	expected := map[string]bool{}
+	for _, subUser := range u.Spec.SubUsers {
+		id := object.SubUserID(u.Name, subUser.Name)
+		expected[id] = true
+		access := subUserAccess(subUser)
+		permissions, exists := current[id]
+		if !exists {
+			if err := object.CreateSubUser(r.objContext, u.Name, subUser.Name, access); err != nil {
+				return nil, err
+			}
+			logger.Infof("created subuser %q of object store user %q", subUser.Name, u.Name)
+			changed = true
+		} else if permissions != subUserPermissions[access] {
+			if err := object.ModifySubUser(r.objContext, u.Name, subUser.Name, access); err != nil {
+				return nil, err
+			}
+			logger.Infof("set access of subuser %q of object store user %q to %q", subUser.Name, u.Name, access)
+		}
+	}
+	for id := range current {
+		if expected[id] {
+			continue
+		}
+		subUser := strings.TrimPrefix(id, u.Name+":")
+		if err := object.DeleteSubUser(r.objContext, u.Name, subUser); err != nil {
+			return nil, err
+		}
+		logger.Infof("deleted subuser %q of object store user %q", subUser, u.Name)
+	}
+
+	// the keys of the new subusers are generated by the object store
+	if changed {
+		var err error
+		user, _, err = object.GetUser(r.objContext, u.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the keys of the subusers of object store user %q", u.Name)
+		}
+	}
+
+	swiftKeys := map[string]string{}
+	for _, key := range user.SwiftKeys {
+		subUser := strings.TrimPrefix(key.User, u.Name+":")
+		if expected[key.User] {
+			swiftKeys[subUser] = key.SecretKey
+		}
+	}
+	return swiftKeys, nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const subUsersJSON = `{
+	"user_id": "my-user",
+	"subusers": [{"id": "my-user:swift", "permissions": "read"}],
+	"swift_keys": [{"user": "my-user:swift", "secret_key": "swift-secret"}]
+}`
+
+func newSettingsReconcile(commands *[]string) *ReconcileObjectStoreUser {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			// skip the options of the realm and the cluster
+			cmd := []string{}
+			for _, arg := range args {
+				if strings.HasPrefix(arg, "--rgw-realm") {
+					break
+				}
+				cmd = append(cmd, arg)
+			}
+			*commands = append(*commands, strings.Join(cmd, " "))
+			if args[0] == "user" && args[1] == "info" {
+				return subUsersJSON, nil
+			}
+			return "", nil
+		},
+	}
+	return &ReconcileObjectStoreUser{objContext: object.NewContext(&clusterd.Context{Executor: executor}, store, namespace)}
+}
+
+func TestValidateSettings(t *testing.T) {
+	u := &cephv1.CephObjectStoreUser{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	assert.NoError(t, validateSettings(u))
+
+	maxBuckets, maxSize := 10, resource.MustParse("10Gi")
+	u.Spec.Quotas = &cephv1.ObjectUserQuotaSpec{MaxBuckets: &maxBuckets, MaxSize: &maxSize}
+	u.Spec.Capabilities = &cephv1.ObjectUserCapSpec{Users: "read", Buckets: "read, write"}
+	u.Spec.SubUsers = []cephv1.ObjectUserSubUserSpec{{Name: "swift"}, {Name: "reader", Access: "read"}}
+	assert.NoError(t, validateSettings(u))
+
+	maxBuckets = -1
+	assert.Error(t, validateSettings(u))
+	maxBuckets = 10
+
+	u.Spec.Capabilities.Usage = "all"
+	assert.Error(t, validateSettings(u))
+	u.Spec.Capabilities.Usage = ""
+
+	u.Spec.SubUsers[1].Access = "admin"
+	assert.Error(t, validateSettings(u))
+	u.Spec.SubUsers[1] = cephv1.ObjectUserSubUserSpec{Name: "swift"}
+	assert.Error(t, validateSettings(u))
+	u.Spec.SubUsers[1] = cephv1.ObjectUserSubUserSpec{Name: "my:user"}
+	assert.Error(t, validateSettings(u))
+}
+
+func TestReconcileQuotas(t *testing.T) {
+	var commands []string
+	r := newSettingsReconcile(&commands)
+	u := &cephv1.CephObjectStoreUser{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+
+	// the max buckets set outside of the spec are kept
+	applied, err := r.reconcileQuotas(u)
+	assert.NoError(t, err)
+	assert.Nil(t, applied)
+	assert.Equal(t, []string{
+		"quota disable --quota-scope user --uid my-user",
+	}, commands)
+
+	commands = nil
+	maxBuckets, maxSize := 10, resource.MustParse("1Ki")
+	u.Spec.Quotas = &cephv1.ObjectUserQuotaSpec{MaxBuckets: &maxBuckets, MaxSize: &maxSize}
+	applied, err = r.reconcileQuotas(u)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, *applied)
+	assert.Equal(t, []string{
+		"user modify --uid my-user --max-buckets 10",
+		"quota set --uid my-user --quota-scope user --max-size 1024 --max-objects -1",
+		"quota enable --quota-scope user --uid my-user",
+	}, commands)
+
+	// the max buckets applied from the spec are reset once removed from the spec
+	commands = nil
+	u.Status = &cephv1.ObjectStoreUserStatus{MaxBuckets: applied}
+	u.Spec.Quotas = nil
+	applied, err = r.reconcileQuotas(u)
+	assert.NoError(t, err)
+	assert.Nil(t, applied)
+	assert.Equal(t, []string{
+		"user modify --uid my-user --max-buckets 1000",
+		"quota disable --quota-scope user --uid my-user",
+	}, commands)
+}
+
+func TestReconcileCaps(t *testing.T) {
+	var commands []string
+	r := newSettingsReconcile(&commands)
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: cephv1.ObjectStoreUserSpec{
+			Capabilities: &cephv1.ObjectUserCapSpec{Users: "read", Buckets: "read, write"},
+		},
+	}
+	user := &object.ObjectUser{UserID: name, Caps: []object.ObjectUserCap{
+		{Type: "buckets", Permission: "*"},
+		{Type: "usage", Permission: "read"},
+		// the other capabilities are not managed
+		{Type: "zone", Permission: "read"},
+	}}
+
+	assert.NoError(t, r.reconcileCaps(u, user))
+	assert.ElementsMatch(t, []string{
+		"caps add --uid my-user --caps users=read",
+		"caps rm --uid my-user --caps usage=read",
+	}, commands)
+}
+
+func TestReconcileSubUsers(t *testing.T) {
+	var commands []string
+	r := newSettingsReconcile(&commands)
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: cephv1.ObjectStoreUserSpec{
+			SubUsers: []cephv1.ObjectUserSubUserSpec{{Name: "swift", Access: "read"}},
+		},
+	}
+
+	// the subuser is created and its key read
+	swiftKeys, err := r.reconcileSubUsers(u, &object.ObjectUser{UserID: name})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"swift": "swift-secret"}, swiftKeys)
+	assert.Equal(t, []string{
+		"subuser create --uid my-user --subuser my-user:swift --access read --key-type swift --gen-secret",
+		"user info --uid my-user",
+	}, commands)
+
+	// the access of the subuser is updated and the other subusers deleted
+	commands = nil
+	u.Spec.SubUsers[0].Access = ""
+	user := &object.ObjectUser{
+		UserID:    name,
+		SubUsers:  []object.ObjectSubUser{{ID: "my-user:swift", Permissions: "read"}, {ID: "my-user:old", Permissions: "full-control"}},
+		SwiftKeys: []object.ObjectUserKey{{User: "my-user:swift", SecretKey: "swift-secret"}, {User: "my-user:old", SecretKey: "old-secret"}},
+	}
+	swiftKeys, err = r.reconcileSubUsers(u, user)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"swift": "swift-secret"}, swiftKeys)
+	assert.Equal(t, []string{
+		"subuser modify --uid my-user --subuser my-user:swift --access full",
+		"subuser rm --uid my-user --subuser my-user:old --purge-keys",
+	}, commands)
+}
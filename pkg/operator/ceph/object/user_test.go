@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"testing"
+
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const userInfoJSON = `{
+	"user_id": "my-user",
+	"display_name": "my user",
+	"email": "",
+	"subusers": [{"id": "my-user:swift", "permissions": "full-control"}],
+	"keys": [
+		{"user": "my-user", "access_key": "AK1", "secret_key": "SK1"},
+		{"user": "my-user", "access_key": "AK2", "secret_key": "SK2"}
+	],
+	"swift_keys": [{"user": "my-user:swift", "secret_key": "swift-secret"}],
+	"caps": [{"type": "users", "perm": "read"}]
+}`
+
+func TestDecodeUser(t *testing.T) {
+	user, errCode, err := decodeUser(userInfoJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, RGWErrorNone, errCode)
+	assert.Equal(t, "my-user", user.UserID)
+	assert.Equal(t, "AK1", *user.AccessKey)
+	assert.Equal(t, "SK1", *user.SecretKey)
+	assert.Equal(t, 2, len(user.Keys))
+	assert.Equal(t, []ObjectUserKey{{User: "my-user:swift", SecretKey: "swift-secret"}}, user.SwiftKeys)
+	assert.Equal(t, []ObjectSubUser{{ID: "my-user:swift", Permissions: "full-control"}}, user.SubUsers)
+	assert.Equal(t, []ObjectUserCap{{Type: "users", Permission: "read"}}, user.Caps)
+
+	_, errCode, err = decodeUser("not json")
+	assert.Error(t, err)
+	assert.Equal(t, RGWErrorParse, errCode)
+}
+
+func TestCreateUserKey(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "key" && args[1] == "create" {
+				return userInfoJSON, nil
+			}
+			return "", nil
+		},
+	}
+	c := NewContext(&clusterd.Context{Executor: executor}, "my-store", "rook-ceph")
+
+	user := &ObjectUser{UserID: "my-user", Keys: []ObjectUserKey{{User: "my-user", AccessKey: "AK1", SecretKey: "SK1"}}}
+	key, err := CreateUserKey(c, user)
+	assert.NoError(t, err)
+	assert.Equal(t, &ObjectUserKey{User: "my-user", AccessKey: "AK2", SecretKey: "SK2"}, key)
+
+	// no new key in the output
+	user.Keys = append(user.Keys, *key)
+	_, err = CreateUserKey(c, user)
+	assert.Error(t, err)
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
+	opmetrics "github.com/rook/rook/pkg/operator/ceph/metrics"
 	"github.com/rook/rook/pkg/operator/ceph/object"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util/exec"
@@ -71,6 +72,12 @@ type ReconcileObjectZone struct {
 // Add creates a new CephObjectZone Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context) error {
+	// Keep the sync status of the zones current between two reconciles
+	refresher := opcontroller.NewStatusRefresher(controllerName, func() { refreshAllStatuses(context, mgr.GetClient()) })
+	if err := mgr.Add(refresher); err != nil {
+		return errors.Wrap(err, "failed to add object zone status refresher")
+	}
+
 	return add(mgr, newReconciler(mgr, context))
 }
 
@@ -151,6 +158,7 @@ func (r *ReconcileObjectZone) reconcile(request reconcile.Request) (reconcile.Re
 	// DELETE: the CR was deleted
 	if !cephObjectZone.GetDeletionTimestamp().IsZero() {
 		logger.Debugf("deleting zone CR %q", cephObjectZone.Name)
+		opmetrics.DeleteObjectZoneMetrics(cephObjectZone.Namespace, cephObjectZone.Name)
 
 		// Return and do not requeue. Successful deletion.
 		return reconcile.Result{}, nil
@@ -184,8 +192,19 @@ func (r *ReconcileObjectZone) reconcile(request reconcile.Request) (reconcile.Re
 		return r.setFailedStatus(request.NamespacedName, "failed to create ceph zone", err)
 	}
 
+	// Promote the zone to master if requested
+	reconcileResponse, err = r.reconcileMaster(cephObjectZone, realmName)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		return reconcileResponse, errors.Wrapf(err, "failed to reconcile master zone of zone group %q", cephObjectZone.Spec.ZoneGroup)
+	}
+	if reconcileResponse.Requeue {
+		return reconcileResponse, nil
+	}
+
 	// Set Ready status, we are done reconciling
 	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
+	refreshSyncStatus(r.context, r.client, cephObjectZone, realmName)
 
 	// Return and do not requeue
 	logger.Debug("zone done reconciling")
@@ -288,7 +307,7 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 		return
 	}
 	if objectZone.Status == nil {
-		objectZone.Status = &cephv1.Status{}
+		objectZone.Status = &cephv1.ObjectZoneStatus{}
 	}
 
 	objectZone.Status.Phase = status
@@ -0,0 +1,264 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	opmetrics "github.com/rook/rook/pkg/operator/ceph/metrics"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// the keys of the system user of a realm in its keys secret
+	realmAccessKeyName = "access-key"
+	realmSecretKeyName = "secret-key"
+)
+
+var waitForRequeueIfZoneSyncBehind = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+
+// getMasterZone returns the name of the master zone of the zone group of a zone
+func getMasterZone(objContext *object.Context, realmName, zoneGroupName string) (string, error) {
+	output, err := object.RunAdminCommandNoRealm(objContext, "zonegroup", "get", "--rgw-realm="+realmName, "--rgw-zonegroup="+zoneGroupName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get zone group %q", zoneGroupName)
+	}
+	masterZone, err := decodeMasterZoneName(output)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse `radosgw-admin zonegroup get` output")
+	}
+	return masterZone, nil
+}
+
+// reconcileMaster promotes the zone to the master zone of its zone group when requested in its spec. The realm and its
+// period are pulled first so that a zone returning after an outage of its site knows the master zone promoted in the
+// meantime. The current master zone is then checked with the sync status of the zone: when the sync status of the
+// master zone cannot be fetched from it, e.g. during an outage of its site, the zone is promoted right away. Otherwise
+// the zone is only promoted once it has caught up with the sync so that no change is lost, a sync in error or whose
+// state is unknown is waited for. A zone is promoted once per generation of its spec, so that
+// two zones set as master don't take the role from each other.
+// The zones are never demoted on their own since a zone group always needs a master zone.
+func (r *ReconcileObjectZone) reconcileMaster(zone *cephv1.CephObjectZone, realmName string) (reconcile.Result, error) {
+	objContext := object.NewContext(r.context, zone.Name, zone.Namespace)
+	if err := r.pullRealm(objContext, zone.Namespace, realmName); err != nil {
+		return reconcile.Result{}, err
+	}
+	masterZone, err := getMasterZone(objContext, realmName, zone.Spec.ZoneGroup)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if zone.Spec.Master == nil {
+		return reconcile.Result{}, nil
+	}
+	if !*zone.Spec.Master {
+		if masterZone == zone.Name {
+			logger.Infof("zone %q remains the master zone of zone group %q until another zone is promoted", zone.Name, zone.Spec.ZoneGroup)
+		}
+		return reconcile.Result{}, nil
+	}
+	if masterZone == zone.Name {
+		r.setPromotedGeneration(zone)
+		return reconcile.Result{}, nil
+	}
+	if zone.Status != nil && zone.Status.PromotedGeneration == zone.Generation {
+		logger.Warningf("zone %q is set as master but zone %q took over the master role of zone group %q, update the spec of zone %q to promote it again", zone.Name, masterZone, zone.Spec.ZoneGroup, zone.Name)
+		return reconcile.Result{}, nil
+	}
+
+	if masterZone != "" {
+		syncStatus, err := object.GetZoneSyncStatus(objContext, realmName, zone.Spec.ZoneGroup, zone.Name)
+		if err != nil {
+			return waitForRequeueIfZoneSyncBehind, err
+		}
+		switch {
+		case syncStatus.MetadataSync.State == cephv1.SyncStateUnreachable:
+			logger.Warningf("promoting zone %q to master of zone group %q in place of unreachable zone %q, changes of zone %q not synced yet are lost", zone.Name, zone.Spec.ZoneGroup, masterZone, masterZone)
+		case !object.IsZoneCaughtUp(syncStatus):
+			logger.Infof("waiting for zone %q to catch up with the sync before taking over the master role of zone %q, metadata sync is %q", zone.Name, masterZone, syncStatus.MetadataSync.State)
+			return waitForRequeueIfZoneSyncBehind, nil
+		}
+	}
+
+	err = promoteZone(objContext, realmName, zone.Spec.ZoneGroup, zone.Name)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	r.setPromotedGeneration(zone)
+	logger.Infof("zone %q is the master zone of zone group %q", zone.Name, zone.Spec.ZoneGroup)
+	return reconcile.Result{}, nil
+}
+
+// pullRealm pulls the realm and its current period from the pull endpoint of the realm, if any. The zones of a realm
+// without pull endpoint or CephObjectRealm share the period of the local cluster.
+func (r *ReconcileObjectZone) pullRealm(objContext *object.Context, namespace, realmName string) error {
+	realm := &cephv1.CephObjectRealm{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: realmName, Namespace: namespace}, realm)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get CephObjectRealm %q", realmName)
+	}
+	endpoint := realm.Spec.Pull.Endpoint
+	if endpoint == "" {
+		return nil
+	}
+
+	secretName := realmKeysSecretName(realmName)
+	secret := &corev1.Secret{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: namespace}, secret)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the keys secret %q of realm %q", secretName, realmName)
+	}
+	accessKey, secretKey := string(secret.Data[realmAccessKeyName]), string(secret.Data[realmSecretKeyName])
+	if accessKey == "" || secretKey == "" {
+		return errors.Errorf("the keys secret %q of realm %q must have %q and %q", secretName, realmName, realmAccessKeyName, realmSecretKeyName)
+	}
+
+	pullArgs := []string{"--url=" + endpoint, "--access-key=" + accessKey, "--secret=" + secretKey, "--rgw-realm=" + realmName}
+	if _, err := object.RunAdminCommandNoRealm(objContext, append([]string{"realm", "pull"}, pullArgs...)...); err != nil {
+		return errors.Wrapf(err, "failed to pull realm %q from %q", realmName, endpoint)
+	}
+	if _, err := object.RunAdminCommandNoRealm(objContext, append([]string{"period", "pull"}, pullArgs...)...); err != nil {
+		return errors.Wrapf(err, "failed to pull the period of realm %q from %q", realmName, endpoint)
+	}
+	logger.Debugf("pulled realm %q from %q", realmName, endpoint)
+	return nil
+}
+
+// realmKeysSecretName returns the name of the secret with the keys of the system user of a realm
+func realmKeysSecretName(realmName string) string {
+	return realmName + "-keys"
+}
+
+// setPromotedGeneration records in the status of the zone that it was promoted to master for the current generation
+// of its spec
+func (r *ReconcileObjectZone) setPromotedGeneration(zone *cephv1.CephObjectZone) {
+	if zone.Status != nil && zone.Status.PromotedGeneration == zone.Generation {
+		return
+	}
+	name := types.NamespacedName{Name: zone.Name, Namespace: zone.Namespace}
+	objectZone := &cephv1.CephObjectZone{}
+	if err := r.client.Get(context.TODO(), name, objectZone); err != nil {
+		logger.Warningf("failed to retrieve object zone %q to record its promotion. %v", name, err)
+		return
+	}
+	if objectZone.Status == nil {
+		objectZone.Status = &cephv1.ObjectZoneStatus{}
+	}
+	objectZone.Status.PromotedGeneration = zone.Generation
+	if err := opcontroller.UpdateStatus(r.client, objectZone); err != nil {
+		logger.Warningf("failed to record the promotion of object zone %q. %v", name, err)
+	}
+}
+
+// promoteZone makes the zone the master and default zone of its zone group and commits the new period so that the
+// other zones follow the new master
+func promoteZone(objContext *object.Context, realmName, zoneGroupName, zoneName string) error {
+	multisiteArgs := object.MultisiteArgs(realmName, zoneGroupName, zoneName)
+
+	args := append([]string{"zone", "modify", "--master", "--default", "--read-only=false"}, multisiteArgs...)
+	_, err := object.RunAdminCommandNoRealm(objContext, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to promote zone %q to master", zoneName)
+	}
+
+	args = append([]string{"period", "update", "--commit"}, multisiteArgs...)
+	_, err = object.RunAdminCommandNoRealm(objContext, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to commit the period promoting zone %q to master", zoneName)
+	}
+	return nil
+}
+
+// refreshSyncStatus updates the master role and the sync status of the zone in its status and its metrics
+func refreshSyncStatus(clusterdContext *clusterd.Context, c client.Client, zone *cephv1.CephObjectZone, realmName string) {
+	name := types.NamespacedName{Name: zone.Name, Namespace: zone.Namespace}
+	objContext := object.NewContext(clusterdContext, zone.Name, zone.Namespace)
+	masterZone, err := getMasterZone(objContext, realmName, zone.Spec.ZoneGroup)
+	if err != nil {
+		logger.Warningf("failed to get the master zone of zone %q. %v", name, err)
+		return
+	}
+	master := masterZone == zone.Name
+
+	syncStatus, err := object.GetZoneSyncStatus(objContext, realmName, zone.Spec.ZoneGroup, zone.Name)
+	if err != nil {
+		logger.Warningf("failed to get sync status of zone %q. %v", name, err)
+		syncStatus = &cephv1.ZoneSyncStatus{MetadataSync: cephv1.SyncProgress{State: cephv1.SyncStateError}}
+	}
+	opmetrics.SetObjectZoneSyncStatus(zone.Namespace, zone.Name, master, syncStatus)
+
+	objectZone := &cephv1.CephObjectZone{}
+	if err := c.Get(context.TODO(), name, objectZone); err != nil {
+		if !kerrors.IsNotFound(err) {
+			logger.Warningf("failed to retrieve object zone %q to update its sync status. %v", name, err)
+		}
+		return
+	}
+	if objectZone.Status == nil {
+		objectZone.Status = &cephv1.ObjectZoneStatus{}
+	}
+	objectZone.Status.Master = master
+	objectZone.Status.SyncStatus = syncStatus
+	objectZone.Status.LastChecked = opcontroller.FormatStatusTime(time.Now().UTC())
+	if err := opcontroller.UpdateStatus(c, objectZone); err != nil {
+		logger.Warningf("failed to update sync status of object zone %q. %v", name, err)
+		return
+	}
+	logger.Debugf("object zone %q sync status updated", name)
+}
+
+// refreshAllStatuses refreshes the sync status of all the ready zones
+func refreshAllStatuses(clusterdContext *clusterd.Context, c client.Client) {
+	zones := &cephv1.CephObjectZoneList{}
+	if err := c.List(context.TODO(), zones); err != nil {
+		logger.Warningf("failed to list object zones to refresh their status. %v", err)
+		return
+	}
+
+	for i := range zones.Items {
+		zone := &zones.Items[i]
+		if !zone.GetDeletionTimestamp().IsZero() || zone.Status == nil || zone.Status.Phase != k8sutil.ReadyStatus {
+			continue
+		}
+		name := types.NamespacedName{Name: zone.Name, Namespace: zone.Namespace}
+		if _, isReadyToReconcile, _, _ := opcontroller.IsReadyToReconcile(c, clusterdContext, name, controllerName); !isReadyToReconcile {
+			continue
+		}
+
+		zoneGroup := &cephv1.CephObjectZoneGroup{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: zone.Spec.ZoneGroup, Namespace: zone.Namespace}, zoneGroup)
+		if err != nil {
+			logger.Warningf("failed to get zone group %q of zone %q to refresh its status. %v", zone.Spec.ZoneGroup, name, err)
+			continue
+		}
+		refreshSyncStatus(clusterdContext, c, zone, zoneGroup.Spec.Realm)
+	}
+}
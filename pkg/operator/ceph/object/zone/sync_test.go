@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const zoneGroupGet = `{
+    "id": "9a6f1d3e-4a3f-4a33-9a2c-6f0b9d2c6f7e",
+    "name": "zonegroup-a",
+    "master_zone": "0b0c5e1c-41c2-4c6e-b1e5-5f3c0f0b2a11",
+    "zones": [
+        {"id": "0b0c5e1c-41c2-4c6e-b1e5-5f3c0f0b2a11", "name": "zone-a"},
+        {"id": "3b4e5ba8-4d5e-4c8d-a4d8-7ab09e2b11fa", "name": "zone-b"}
+    ]
+}`
+
+func newZone(name string, master *bool) *cephv1.CephObjectZone {
+	return &cephv1.CephObjectZone{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+		Spec:       cephv1.ObjectZoneSpec{ZoneGroup: "zonegroup-a", Master: master},
+	}
+}
+
+func TestDecodeMasterZoneName(t *testing.T) {
+	name, err := decodeMasterZoneName(zoneGroupGet)
+	assert.NoError(t, err)
+	assert.Equal(t, "zone-a", name)
+
+	name, err = decodeMasterZoneName(`{"master_zone": "", "zones": []}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "", name)
+
+	_, err = decodeMasterZoneName("not json")
+	assert.Error(t, err)
+}
+
+func TestReconcileMaster(t *testing.T) {
+	yes, no := true, false
+	var commands []string
+	syncStatus := ""
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			commands = append(commands, args[0]+" "+args[1])
+			switch {
+			case args[0] == "zonegroup" && args[1] == "get":
+				return zoneGroupGet, nil
+			case args[0] == "sync" && args[1] == "status":
+				return syncStatus, nil
+			case args[0] == "bucket" && args[1] == "list":
+				return "[]", nil
+			}
+			return "", nil
+		},
+	}
+	s := runtime.NewScheme()
+	assert.NoError(t, cephv1.AddToScheme(s))
+	assert.NoError(t, corev1.AddToScheme(s))
+	realm := &cephv1.CephObjectRealm{ObjectMeta: metav1.ObjectMeta{Name: "realm-a", Namespace: "rook-ceph"}}
+	newReconcile := func(objects ...runtime.Object) *ReconcileObjectZone {
+		commands = nil
+		objects = append(objects, realm.DeepCopy())
+		return &ReconcileObjectZone{client: fake.NewFakeClientWithScheme(s, objects...), scheme: s, context: &clusterd.Context{Executor: executor}}
+	}
+	promotedGeneration := func(r *ReconcileObjectZone, name string) int64 {
+		zone := &cephv1.CephObjectZone{}
+		assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "rook-ceph"}, zone))
+		if zone.Status == nil {
+			return 0
+		}
+		return zone.Status.PromotedGeneration
+	}
+
+	// the master zone is left as is when not set
+	zoneB := newZone("zone-b", nil)
+	r := newReconcile(zoneB)
+	res, err := r.reconcileMaster(zoneB, "realm-a")
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, []string{"zonegroup get"}, commands)
+
+	// the zone is already the master
+	zoneA := newZone("zone-a", &yes)
+	zoneA.Generation = 3
+	r = newReconcile(zoneA)
+	_, err = r.reconcileMaster(zoneA, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"zonegroup get"}, commands)
+	assert.Equal(t, int64(3), promotedGeneration(r, "zone-a"))
+
+	// failover: the master zone cannot be reached, the zone is promoted right away
+	zoneB = newZone("zone-b", &yes)
+	zoneB.Generation = 2
+	syncStatus = `  metadata sync syncing
+                failed to fetch master sync status: (5) Input/output error`
+	r = newReconcile(zoneB)
+	_, err = r.reconcileMaster(zoneB, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"zonegroup get", "sync status", "bucket list", "zone modify", "period update"}, commands)
+	assert.Equal(t, int64(2), promotedGeneration(r, "zone-b"))
+
+	// the zone is not promoted again for the same spec once another zone took over the master role
+	zoneB.Status = &cephv1.ObjectZoneStatus{PromotedGeneration: 2}
+	r = newReconcile(zoneB)
+	_, err = r.reconcileMaster(zoneB, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"zonegroup get"}, commands)
+
+	// handback: the zone waits to catch up with the sync before being promoted
+	zoneB = newZone("zone-b", &yes)
+	syncStatus = `  metadata sync syncing
+                metadata is behind on 1 shards
+      data sync source: 0b0c5e1c-41c2-4c6e-b1e5-5f3c0f0b2a11 (zone-a)
+                        data is caught up with source`
+	r = newReconcile(zoneB)
+	res, err = r.reconcileMaster(zoneB, "realm-a")
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, []string{"zonegroup get", "sync status", "bucket list"}, commands)
+
+	// a reachable master zone whose sync is in error or not recognized is waited for
+	for _, status := range []string{
+		`  metadata sync syncing
+                failed to read sync status: (2) No such file or directory`,
+		`  metadata sync preparing for full sync
+                full sync: 64/64 shards`,
+	} {
+		syncStatus = status
+		r = newReconcile(zoneB)
+		res, err = r.reconcileMaster(zoneB, "realm-a")
+		assert.NoError(t, err)
+		assert.True(t, res.Requeue)
+		assert.Equal(t, []string{"zonegroup get", "sync status", "bucket list"}, commands)
+	}
+
+	syncStatus = `  metadata sync syncing
+                metadata is caught up with master
+      data sync source: 0b0c5e1c-41c2-4c6e-b1e5-5f3c0f0b2a11 (zone-a)
+                        data is caught up with source`
+	r = newReconcile(zoneB)
+	res, err = r.reconcileMaster(zoneB, "realm-a")
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, []string{"zonegroup get", "sync status", "bucket list", "zone modify", "period update"}, commands)
+
+	// the master zone set to false is not demoted on its own
+	zoneA = newZone("zone-a", &no)
+	r = newReconcile(zoneA)
+	_, err = r.reconcileMaster(zoneA, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"zonegroup get"}, commands)
+
+	// the realm and its period are pulled before the master zone is checked
+	realm.Spec.Pull.Endpoint = "http://rgw.site-b:80"
+	r = newReconcile(zoneA)
+	_, err = r.reconcileMaster(zoneA, "realm-a")
+	assert.Error(t, err)
+	assert.Empty(t, commands)
+
+	keys := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "realm-a-keys", Namespace: "rook-ceph"},
+		Data:       map[string][]byte{"access-key": []byte("access"), "secret-key": []byte("secret")},
+	}
+	r = newReconcile(zoneA, keys)
+	_, err = r.reconcileMaster(zoneA, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"realm pull", "period pull", "zonegroup get"}, commands)
+}
@@ -24,6 +24,10 @@ import (
 
 type masterZoneType struct {
 	MasterZone string `json:"master_zone"`
+	Zones      []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"zones"`
 }
 
 func decodeMasterZone(data string) (string, error) {
@@ -36,6 +40,23 @@ func decodeMasterZone(data string) (string, error) {
 	return zoneGroupGet.MasterZone, err
 }
 
+// decodeMasterZoneName returns the name of the master zone of the zone group, empty if the zone group has no
+// master zone yet
+func decodeMasterZoneName(data string) (string, error) {
+	var zoneGroupGet masterZoneType
+	err := json.Unmarshal([]byte(data), &zoneGroupGet)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to unmarshal json")
+	}
+
+	for _, zone := range zoneGroupGet.Zones {
+		if zone.ID == zoneGroupGet.MasterZone {
+			return zone.Name, nil
+		}
+	}
+	return "", nil
+}
+
 // validateZoneCR validates the zone arguments
 func validateZoneCR(u *cephv1.CephObjectZone) error {
 	if u.Name == "" {
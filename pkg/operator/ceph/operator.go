@@ -36,6 +36,7 @@ import (
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/sig-storage-lib-external-provisioner/controller"
@@ -62,6 +63,9 @@ var (
 	// EnableDiscoveryDaemon Whether to enable the daemon for device discovery. If true, the rook-ceph-discover daemonset will be started.
 	EnableDiscoveryDaemon = true
 
+	// MetricsBindAddress The address the operator serves its prometheus metrics on. Set to "0" to disable the metrics endpoint.
+	MetricsBindAddress = ":8080"
+
 	// ImmediateRetryResult Return this for a immediate retry of the reconciliation loop with the same request object.
 	ImmediateRetryResult = reconcile.Result{Requeue: true}
 )
@@ -120,6 +124,12 @@ func (o *Operator) Run() error {
 		}
 	}
 
+	if EnableAdmissionController {
+		if err := reconcileWebhookCertificates(o.context); err != nil {
+			return errors.Wrap(err, "failed to configure the certificates of the admission controller")
+		}
+	}
+
 	logger.Debug("checking for admission controller secrets")
 	err := StartControllerIfSecretPresent(o.context, o.rookImage)
 	if err != nil {
@@ -163,6 +173,15 @@ func (o *Operator) Run() error {
 	mgrErrorChan := make(chan error)
 	go o.startManager(namespaceToWatch, stopChan, mgrErrorChan)
 
+	// Renew the certificates of the admission controller before they expire
+	if EnableAdmissionController {
+		go wait.Until(func() {
+			if err := reconcileWebhookCertificates(o.context); err != nil {
+				logger.Errorf("failed to renew the certificates of the admission controller. %v", err)
+			}
+		}, webhookCertificatesCheckInterval, stopChan)
+	}
+
 	// Start the operator setting watcher
 	go o.clusterController.StartOperatorSettingsWatch(namespaceToWatch, stopChan)
 
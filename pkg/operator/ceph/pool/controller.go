@@ -72,6 +72,12 @@ type ReconcileCephBlockPool struct {
 // Add creates a new CephBlockPool Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context) error {
+	// Keep the status of the pools current between two reconciles
+	refresher := opcontroller.NewStatusRefresher(controllerName, func() { refreshAllStatuses(context, mgr.GetClient()) })
+	if err := mgr.Add(refresher); err != nil {
+		return errors.Wrap(err, "failed to add pool status refresher")
+	}
+
 	return add(mgr, newReconciler(mgr, context))
 }
 
@@ -134,7 +140,7 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 
 	// The CR was just created, initializing status fields
 	if cephBlockPool.Status == nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.Created)
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil)
 	}
 
 	// Make sure a CephCluster is present otherwise do nothing
@@ -188,7 +194,7 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		return reconcile.Result{}, errors.Wrapf(err, "invalid pool CR %q spec", cephBlockPool.Name)
 	}
 
-	updateStatus(r.client, request.NamespacedName, k8sutil.ReconcilingStatus)
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReconcilingStatus, nil)
 
 	// Get CephCluster version
 	cephVersion, err := opcontroller.GetImageVersion(cephCluster)
@@ -211,26 +217,56 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 	// CREATE/UPDATE
 	reconcileResponse, err = r.reconcileCreatePool(cephBlockPool)
 	if err != nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err)
 		return reconcileResponse, errors.Wrapf(err, "failed to create pool %q.", cephBlockPool.GetName())
 	}
 
+	// Move the pool to a new crush rule if its failure domain, device class or crush root changed
+	err = reconcileCrushRule(r.context, r.client, cephBlockPool)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to update the crush rule of pool %q.", cephBlockPool.GetName())
+	}
+
+	// Configure the mirroring of the pool
+	err = r.reconcileMirroring(cephBlockPool, cephVersion)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to configure mirroring of pool %q.", cephBlockPool.GetName())
+	}
+
 	// Set Ready status, we are done reconciling
-	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, nil)
 
-	// Return and do not requeue
+	// Report the state of the pool as applied by Ceph
+	refreshStatus(r.context, r.client, request.NamespacedName, cephBlockPool.Spec)
+
+	// Return and do not requeue, unless the data of the pool is being migrated
 	logger.Debug("done reconciling")
-	return reconcile.Result{}, nil
+	return reconcileResponse, nil
 }
 
 func (r *ReconcileCephBlockPool) reconcileCreatePool(cephBlockPool *cephv1.CephBlockPool) (reconcile.Result, error) {
-	err := createPool(r.context, cephBlockPool)
+	// The profile of an existing erasure coded pool can't change, only its properties are updated
+	exists, reconcileResponse, err := reconcileErasureCodeProfile(r.context, r.client, cephBlockPool)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile the erasure code profile of pool %q.", cephBlockPool.GetName())
+	}
+	if exists {
+		err = cephclient.SetCommonPoolProperties(r.context, cephBlockPool.Spec, cephBlockPool.Namespace, cephBlockPool.Name, poolApplicationNameRBD)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to update pool %q.", cephBlockPool.GetName())
+		}
+		return reconcileResponse, nil
+	}
+
+	err = createPool(r.context, cephBlockPool)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create pool %q.", cephBlockPool.GetName())
 	}
 
 	// Let's return here so that on the initial creation we don't check for update right away
-	return reconcile.Result{}, nil
+	return reconcileResponse, nil
 }
 
 // Create the pool
@@ -258,14 +294,28 @@ func deletePool(context *clusterd.Context, p *cephv1.CephBlockPool) error {
 			if err != nil {
 				return errors.Wrapf(err, "failed to delete pool %q", p.Name)
 			}
+
+			// the rule named after the pool is removed with the pool, but not the rule of the last crush rule change
+			if p.Status != nil && p.Status.CrushRuleChange != nil && p.Status.CrushRuleChange.Rule != p.Name {
+				if err := cephclient.DeleteCrushRule(context, p.Namespace, p.Status.CrushRuleChange.Rule); err != nil {
+					logger.Warningf("failed to delete the crush rule of pool %q. %v", p.Name, err)
+				}
+			}
+
+			// the rule of an erasure coded pool is named after the target pool of its last migration
+			if p.Status != nil && p.Status.ErasureCodeMigration != nil && p.Status.ErasureCodeMigration.Phase == ecMigrationPhaseCompleted {
+				if err := cephclient.DeleteCrushRule(context, p.Namespace, p.Status.ErasureCodeMigration.TargetPool); err != nil {
+					logger.Warningf("failed to delete the crush rule of pool %q. %v", p.Name, err)
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
-// updateStatus updates a pool CR with the given status
-func updateStatus(client client.Client, poolName types.NamespacedName, status string) {
+// updateStatus updates a pool CR with the given status and the matching conditions
+func updateStatus(client client.Client, poolName types.NamespacedName, status string, reconcileErr error) {
 	pool := &cephv1.CephBlockPool{}
 	err := client.Get(context.TODO(), poolName, pool)
 	if err != nil {
@@ -278,10 +328,11 @@ func updateStatus(client client.Client, poolName types.NamespacedName, status st
 	}
 
 	if pool.Status == nil {
-		pool.Status = &cephv1.Status{}
+		pool.Status = &cephv1.CephBlockPoolStatus{}
 	}
 
 	pool.Status.Phase = status
+	opcontroller.SetPhaseConditions(&pool.Status.Conditions, status, reconcileErr)
 	if err := opcontroller.UpdateStatus(client, pool); err != nil {
 		logger.Warningf("failed to set pool %q status to %q. %v", pool.Name, status, err)
 		return
@@ -23,11 +23,13 @@ import (
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 
 	"github.com/rook/rook/pkg/clusterd"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -164,6 +166,9 @@ func TestCreatePool(t *testing.T) {
 			if command == "ceph" && args[1] == "erasure-code-profile" {
 				return `{"k":"2","m":"1","plugin":"jerasure","technique":"reed_sol_van"}`, nil
 			}
+			if command == "ceph" && args[1] == "pool" && args[2] == "ls" {
+				return "[]", nil
+			}
 			return "", nil
 		},
 	}
@@ -260,7 +265,7 @@ func TestCephBlockPoolController(t *testing.T) {
 				Size: replicas,
 			},
 		},
-		Status: &cephv1.Status{
+		Status: &cephv1.CephBlockPoolStatus{
 			Phase: "",
 		},
 	}
@@ -363,6 +368,19 @@ func TestCephBlockPoolController(t *testing.T) {
 			if args[0] == "status" {
 				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_OK"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
 			}
+			if args[0] == "osd" && args[1] == "pool" && args[2] == "ls" {
+				return `[{"pool":1,"pool_name":"my-pool","size":3,"crush_rule":1}]`, nil
+			}
+			if args[0] == "osd" && args[1] == "crush" && args[2] == "dump" {
+				return `{"rules":[{"rule_id":1,"rule_name":"my-pool","steps":[{"op":"take","item_name":"default"},{"op":"chooseleaf_firstn","type":"host"},{"op":"emit"}]}]}`, nil
+			}
+
+			return "", nil
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+				return `{"mode":"disabled"}`, nil
+			}
 
 			return "", nil
 		},
@@ -379,4 +397,7 @@ func TestCephBlockPoolController(t *testing.T) {
 	err = r.client.Get(context.TODO(), req.NamespacedName, pool)
 	assert.NoError(t, err)
 	assert.Equal(t, "Ready", pool.Status.Phase)
+	ready := opcontroller.FindStatusCondition(pool.Status.Conditions, cephv1.ConditionReady)
+	assert.NotNil(t, ready)
+	assert.Equal(t, v1.ConditionTrue, ready.Status)
 }
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileCrushRule switches an existing replicated pool to a new CRUSH rule when the failure domain, the device
+// class or the crush root of its spec changed. The change is refused if the new placement has fewer failure domains
+// than the replicas of the pool, or if the data movement of the previous change is not complete yet. The crush rule
+// of a pool in stretch mode is left to Ceph.
+func reconcileCrushRule(clusterdContext *clusterd.Context, c client.Client, p *cephv1.CephBlockPool) error {
+	if !p.Spec.IsReplicated() {
+		return nil
+	}
+
+	details, err := cephclient.ListPoolDetails(clusterdContext, p.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to list pool details")
+	}
+	ruleID := -1
+	for _, detail := range details {
+		if detail.Name == p.Name {
+			// the stretch mode moved the pool to the stretch rule, whose placement is not the one of the spec
+			if detail.IsStretchPool() {
+				logger.Debugf("pool %q is in stretch mode, not changing its crush rule", p.Name)
+				return nil
+			}
+			ruleID = detail.CrushRule
+			break
+		}
+	}
+	if ruleID < 0 {
+		return errors.Errorf("pool %q not found", p.Name)
+	}
+
+	crushMap, err := cephclient.GetCrushMap(clusterdContext, p.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get crush map")
+	}
+	currentRule, current, err := cephclient.GetCrushRulePlacement(crushMap, ruleID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the crush rule of pool %q", p.Name)
+	}
+
+	desired := cephclient.DesiredCrushRulePlacement(p.Spec)
+	if current == desired {
+		return nil
+	}
+
+	if p.Status != nil && p.Status.CrushRuleChange != nil && !p.Status.CrushRuleChange.Completed {
+		return errors.Errorf("refusing to move pool %q to %s until the data movement to crush rule %q is complete",
+			p.Name, desired.String(), p.Status.CrushRuleChange.Rule)
+	}
+	failureDomains := cephclient.CountFailureDomains(crushMap, desired)
+	if failureDomains < int(p.Spec.Replicated.Size) {
+		return errors.Errorf("refusing to move pool %q to %s: %d %q buckets hold OSDs, fewer than the %d replicas of the pool",
+			p.Name, desired.String(), failureDomains, desired.FailureDomain, p.Spec.Replicated.Size)
+	}
+
+	newRule := cephclient.CrushRuleName(p.Name, desired)
+	logger.Infof("moving pool %q from crush rule %q (%s) to crush rule %q (%s)", p.Name, currentRule, current.String(), newRule, desired.String())
+	if err := cephclient.CreateReplicationCrushRule(clusterdContext, p.Namespace, newRule, p.Spec); err != nil {
+		return err
+	}
+	if err := cephclient.SetPoolCrushRule(clusterdContext, p.Namespace, p.Name, newRule); err != nil {
+		return errors.Wrapf(err, "failed to switch pool %q to crush rule %q", p.Name, newRule)
+	}
+
+	// the rules created by a previous change are not used anymore. the rule named after the pool is kept since it is
+	// created again with each reconcile, and removed with the pool.
+	if strings.HasPrefix(currentRule, p.Name+"_") {
+		if err := cephclient.DeleteCrushRule(clusterdContext, p.Namespace, currentRule); err != nil {
+			logger.Warningf("failed to delete the previous crush rule of pool %q. %v", p.Name, err)
+		}
+	}
+
+	change := &cephv1.PoolCrushRuleChangeStatus{
+		PreviousRule: currentRule,
+		Rule:         newRule,
+		StartTime:    opcontroller.FormatStatusTime(time.Now().UTC()),
+	}
+	updateCrushRuleChangeStatus(c, types.NamespacedName{Name: p.Name, Namespace: p.Namespace}, change)
+	return nil
+}
+
+// refreshCrushRuleChange updates the progress of the data movement of a pool after its crush rule changed
+func refreshCrushRuleChange(clusterdContext *clusterd.Context, poolName types.NamespacedName, change *cephv1.PoolCrushRuleChangeStatus) error {
+	stats, err := cephclient.GetPoolRecoveryStats(clusterdContext, poolName.Namespace, poolName.Name)
+	if err != nil {
+		return err
+	}
+
+	change.MisplacedObjects = stats.MisplacedObjects
+	change.TotalObjects = stats.MisplacedTotal
+	if stats.MisplacedObjects == 0 {
+		logger.Infof("data movement of pool %q to crush rule %q is complete", poolName, change.Rule)
+		change.Completed = true
+		change.CompletionTime = opcontroller.FormatStatusTime(time.Now().UTC())
+	}
+	return nil
+}
+
+// updateCrushRuleChangeStatus reports a change of the crush rule of a pool in its status
+func updateCrushRuleChangeStatus(c client.Client, poolName types.NamespacedName, change *cephv1.PoolCrushRuleChangeStatus) {
+	pool := &cephv1.CephBlockPool{}
+	if err := c.Get(context.TODO(), poolName, pool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve pool %q to report its crush rule change. %v", poolName, err)
+		return
+	}
+
+	if pool.Status == nil {
+		pool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+	pool.Status.CrushRuleChange = change
+	if err := opcontroller.UpdateStatus(c, pool); err != nil {
+		logger.Warningf("failed to report the crush rule change of pool %q. %v", pool.Name, err)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileCrushRule(t *testing.T) {
+	executed := []string{}
+	misplaced := "10"
+	stretchBuckets := "0"
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			executed = append(executed, strings.Split(strings.Join(args, " "), " --")[0])
+			if args[0] == "osd" && args[1] == "pool" && args[2] == "ls" {
+				return `[{"pool":1,"pool_name":"mypool","size":2,"crush_rule":1,"peering_crush_bucket_count":` + stretchBuckets + `}]`, nil
+			}
+			if args[0] == "osd" && args[1] == "pool" && args[2] == "stats" {
+				return `[{"pool_name":"mypool","recovery":{"misplaced_objects":` + misplaced + `,"misplaced_total":20}}]`, nil
+			}
+			if args[0] == "df" {
+				return `{"pools":[]}`, nil
+			}
+			if args[0] == "osd" && args[1] == "crush" && args[2] == "dump" {
+				return `{"buckets":[
+					{"id":-1,"name":"default","type_name":"root","items":[{"id":-2},{"id":-3}]},
+					{"id":-2,"name":"host1","type_name":"host","items":[{"id":0}]},
+					{"id":-3,"name":"host2","type_name":"host","items":[{"id":1}]}],
+				"rules":[{"rule_id":1,"rule_name":"mypool","steps":[{"op":"take","item_name":"default"},{"op":"chooseleaf_firstn","type":"osd"},{"op":"emit"}]}]}`, nil
+			}
+			return "", nil
+		},
+	}
+	clusterdContext := &clusterd.Context{Executor: executor}
+
+	p := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: "myns"},
+		Spec: cephv1.PoolSpec{
+			FailureDomain: "osd",
+			Replicated:    cephv1.ReplicatedSpec{Size: 2},
+		},
+		Status: &cephv1.CephBlockPoolStatus{},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, p)
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{p.DeepCopy()}...)
+	name := types.NamespacedName{Name: p.Name, Namespace: p.Namespace}
+
+	// the placement did not change
+	assert.NoError(t, reconcileCrushRule(clusterdContext, cl, p))
+	assert.NotContains(t, executed, "osd pool set mypool crush_rule mypool_default_host")
+
+	// there are fewer hosts than replicas
+	p.Spec.FailureDomain = "host"
+	p.Spec.Replicated.Size = 3
+	assert.Error(t, reconcileCrushRule(clusterdContext, cl, p))
+	assert.NotContains(t, executed, "osd pool set mypool crush_rule mypool_default_host")
+
+	// the pool is moved to a new rule
+	p.Spec.Replicated.Size = 2
+	assert.NoError(t, reconcileCrushRule(clusterdContext, cl, p))
+	assert.Contains(t, executed, "osd crush rule create-replicated mypool_default_host default host")
+	assert.Contains(t, executed, "osd pool set mypool crush_rule mypool_default_host")
+	// the rule named after the pool is kept
+	assert.NotContains(t, executed, "osd crush rule rm mypool")
+	assert.NoError(t, cl.Get(context.TODO(), name, p))
+	assert.Equal(t, "mypool", p.Status.CrushRuleChange.PreviousRule)
+	assert.Equal(t, "mypool_default_host", p.Status.CrushRuleChange.Rule)
+	assert.False(t, p.Status.CrushRuleChange.Completed)
+
+	// another change is refused until the data movement is complete
+	p.Spec.DeviceClass = "ssd"
+	assert.Error(t, reconcileCrushRule(clusterdContext, cl, p))
+
+	// the progress of the data movement is reported
+	refreshStatus(clusterdContext, cl, name, p.Spec)
+	assert.NoError(t, cl.Get(context.TODO(), name, p))
+	assert.Equal(t, uint64(10), p.Status.CrushRuleChange.MisplacedObjects)
+	assert.Equal(t, uint64(20), p.Status.CrushRuleChange.TotalObjects)
+	assert.False(t, p.Status.CrushRuleChange.Completed)
+
+	misplaced = "0"
+	refreshStatus(clusterdContext, cl, name, p.Spec)
+	assert.NoError(t, cl.Get(context.TODO(), name, p))
+	assert.True(t, p.Status.CrushRuleChange.Completed)
+	assert.NotEmpty(t, p.Status.CrushRuleChange.CompletionTime)
+
+	// the crush rule of a pool in stretch mode is left to ceph
+	stretchBuckets = "2"
+	executed = []string{}
+	p.Spec.DeviceClass = "hdd"
+	assert.NoError(t, reconcileCrushRule(clusterdContext, cl, p))
+	assert.Equal(t, []string{"osd pool ls detail"}, executed)
+}
@@ -0,0 +1,425 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	ecMigrationPhaseMigratingImages = "MigratingImages"
+	ecMigrationPhaseSwappingPools   = "SwappingPools"
+	ecMigrationPhaseCompleted       = "Completed"
+	// the type of the replicated pools in "ceph osd pool ls detail"
+	replicatedPoolType = 1
+)
+
+var (
+	// the progress of the migration of a pool is checked again until it completes
+	ecMigrationRequeue = reconcile.Result{Requeue: true, RequeueAfter: time.Minute}
+	// the data of the images is copied to the target pool in the background
+	imageMigrations = newImageMigrationExecutor()
+)
+
+// imageMigrationExecutor runs "rbd migration execute" in the background since copying the data of an image may take
+// hours. The executions are tracked per image until the next reconcile checks their result.
+type imageMigrationExecutor struct {
+	mutex   sync.Mutex
+	running map[string]bool
+	failed  map[string]error
+	// wg tracks the executions in the background
+	wg sync.WaitGroup
+}
+
+func newImageMigrationExecutor() *imageMigrationExecutor {
+	return &imageMigrationExecutor{running: map[string]bool{}, failed: map[string]error{}}
+}
+
+// start executes the migration of an image in the background unless it is already running
+func (e *imageMigrationExecutor) start(clusterdContext *clusterd.Context, namespace, imageName, poolName string) {
+	key := imageMigrationKey(namespace, imageName, poolName)
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.running[key] {
+		return
+	}
+	e.running[key] = true
+	delete(e.failed, key)
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		err := cephclient.ExecuteImageMigration(clusterdContext, namespace, imageName, poolName)
+		e.mutex.Lock()
+		defer e.mutex.Unlock()
+		delete(e.running, key)
+		if err != nil {
+			e.failed[key] = err
+		}
+	}()
+}
+
+// result returns whether the migration of an image is running and the error of its last execution, which is only
+// reported once
+func (e *imageMigrationExecutor) result(namespace, imageName, poolName string) (bool, error) {
+	key := imageMigrationKey(namespace, imageName, poolName)
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	err := e.failed[key]
+	delete(e.failed, key)
+	return e.running[key], err
+}
+
+func imageMigrationKey(namespace, imageName, poolName string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, poolName, imageName)
+}
+
+// ecMigrationTargetPool returns the name of the pool receiving the data of a pool migrated to the erasure code settings
+// of its spec. The name only depends on these settings so that an interrupted migration resumes with the same pool.
+func ecMigrationTargetPool(p *cephv1.CephBlockPool) string {
+	settings := fmt.Sprintf("k=%d m=%d %s", p.Spec.ErasureCoded.DataChunks, p.Spec.ErasureCoded.CodingChunks, cephclient.DesiredCrushRulePlacement(p.Spec).String())
+	return fmt.Sprintf("%s-migration-%s", p.Name, k8sutil.Hash(settings)[:8])
+}
+
+// reconcileErasureCodeProfile compares the erasure code settings of the spec of an existing erasure coded pool with
+// the profile of the pool. Since the profile of a pool can't change, the settings that differ are reported in the
+// status of the pool and, if the migration is allowed, the data of the pool is migrated to a new pool created with the
+// settings of the spec. It returns whether the erasure coded pool exists, in which case only its properties are updated.
+func reconcileErasureCodeProfile(clusterdContext *clusterd.Context, c client.Client, p *cephv1.CephBlockPool) (bool, reconcile.Result, error) {
+	var previousDrift []string
+	var previousMigration, migration *cephv1.PoolErasureCodeMigrationStatus
+	if p.Status != nil {
+		previousDrift = p.Status.ErasureCodeDrift
+		previousMigration = p.Status.ErasureCodeMigration
+		if previousMigration != nil {
+			migration = previousMigration.DeepCopy()
+		}
+	}
+	migrating := migration != nil && migration.Phase != ecMigrationPhaseCompleted
+	if !p.Spec.IsErasureCoded() && !migrating {
+		return false, reconcile.Result{}, nil
+	}
+	poolName := types.NamespacedName{Name: p.Name, Namespace: p.Namespace}
+
+	// a migration in progress completes with the settings it started with
+	if migrating {
+		err := continueErasureCodeMigration(clusterdContext, p, migration)
+		if !reflect.DeepEqual(previousMigration, migration) {
+			updateErasureCodeStatus(c, poolName, previousDrift, migration)
+		}
+		if err != nil {
+			return true, reconcile.Result{}, errors.Wrapf(err, "failed to migrate pool %q to pool %q", p.Name, migration.TargetPool)
+		}
+		if migration.Phase != ecMigrationPhaseCompleted {
+			return true, ecMigrationRequeue, nil
+		}
+		previousMigration = migration
+	}
+
+	details, err := cephclient.ListPoolDetails(clusterdContext, p.Namespace)
+	if err != nil {
+		return false, reconcile.Result{}, errors.Wrap(err, "failed to list pool details")
+	}
+	detail := findPoolDetail(details, p.Name)
+	if detail == nil || detail.ErasureCodeProfile == "" {
+		// the pool is created with the spec
+		return false, reconcile.Result{}, nil
+	}
+
+	profile, err := cephclient.GetErasureCodeProfileDetails(clusterdContext, p.Namespace, detail.ErasureCodeProfile)
+	if err != nil {
+		return true, reconcile.Result{}, errors.Wrapf(err, "failed to get erasure code profile %q of pool %q", detail.ErasureCodeProfile, p.Name)
+	}
+	drift := cephclient.ErasureCodeProfileDrift(p.Spec, profile)
+
+	result := reconcile.Result{}
+	if len(drift) > 0 {
+		if p.Spec.ErasureCoded.AllowMigration {
+			migration = &cephv1.PoolErasureCodeMigrationStatus{
+				Phase:           ecMigrationPhaseMigratingImages,
+				TargetPool:      ecMigrationTargetPool(p),
+				PreviousProfile: detail.ErasureCodeProfile,
+				StartTime:       opcontroller.FormatStatusTime(time.Now().UTC()),
+				TargetSpec:      p.Spec.DeepCopy(),
+			}
+			migration.TargetProfile = cephclient.GetErasureCodeProfileForPool(migration.TargetPool)
+			logger.Infof("migrating the data of pool %q to pool %q to apply the erasure code settings %v", p.Name, migration.TargetPool, drift)
+			err = continueErasureCodeMigration(clusterdContext, p, migration)
+			result = ecMigrationRequeue
+		} else {
+			logger.Warningf("the erasure code settings %v of pool %q are not applied since the profile %q of the pool can't change. "+
+				"set allowMigration to migrate the data of the pool to a new pool with these settings", drift, p.Name, detail.ErasureCodeProfile)
+		}
+	}
+
+	if !reflect.DeepEqual(previousDrift, drift) || !reflect.DeepEqual(previousMigration, migration) {
+		updateErasureCodeStatus(c, poolName, drift, migration)
+	}
+	if err != nil {
+		return true, reconcile.Result{}, errors.Wrapf(err, "failed to migrate pool %q to pool %q", p.Name, migration.TargetPool)
+	}
+	return true, result, nil
+}
+
+// continueErasureCodeMigration runs the next steps of the migration of a pool: the target pool is created, the data of
+// the rbd images using the pool as data pool is migrated to the target pool, and once the pool is empty the target
+// pool takes the name of the pool.
+func continueErasureCodeMigration(clusterdContext *clusterd.Context, p *cephv1.CephBlockPool, migration *cephv1.PoolErasureCodeMigrationStatus) error {
+	if migration.Phase == ecMigrationPhaseMigratingImages {
+		details, err := cephclient.ListPoolDetails(clusterdContext, p.Namespace)
+		if err != nil {
+			return errors.Wrap(err, "failed to list pool details")
+		}
+		if findPoolDetail(details, migration.TargetPool) == nil {
+			// the spec may have changed since the migration started
+			targetSpec := p.Spec
+			if migration.TargetSpec != nil {
+				targetSpec = *migration.TargetSpec
+			}
+			logger.Infof("creating pool %q to migrate the data of pool %q", migration.TargetPool, p.Name)
+			if err := cephclient.CreatePoolWithProfile(clusterdContext, p.Namespace, migration.TargetPool, targetSpec, poolApplicationNameRBD); err != nil {
+				return errors.Wrapf(err, "failed to create pool %q", migration.TargetPool)
+			}
+		}
+
+		migrated, err := migrateImages(clusterdContext, p, migration, details)
+		if err != nil || !migrated {
+			return err
+		}
+		logger.Infof("the data of pool %q is migrated, swapping pool %q with pool %q", p.Name, p.Name, migration.TargetPool)
+		migration.Phase = ecMigrationPhaseSwappingPools
+		migration.Message = ""
+	}
+
+	if migration.Phase == ecMigrationPhaseSwappingPools {
+		if err := swapPools(clusterdContext, p, migration); err != nil {
+			migration.Message = fmt.Sprintf("failed to swap pool %q with pool %q", p.Name, migration.TargetPool)
+			return err
+		}
+		logger.Infof("completed the migration of pool %q to erasure code profile %q", p.Name, migration.TargetProfile)
+		migration.Phase = ecMigrationPhaseCompleted
+		migration.Message = ""
+		migration.CompletionTime = opcontroller.FormatStatusTime(time.Now().UTC())
+	}
+
+	return nil
+}
+
+// migrateImages migrates the data of the rbd images of the replicated rbd pools that use a pool as data pool to the
+// target pool of its migration. Ceph refuses to prepare the migration of an image in use, such images are reported as
+// pending until they are not used anymore. The data of the prepared images is copied in the background and their
+// migration is committed by a later reconcile. It returns whether the pool is empty.
+func migrateImages(clusterdContext *clusterd.Context, p *cephv1.CephBlockPool, migration *cephv1.PoolErasureCodeMigrationStatus, details []cephclient.CephStoragePoolLsDetail) (bool, error) {
+	pending := []string{}
+	executing := []string{}
+	for _, pool := range details {
+		if _, ok := pool.ApplicationMetadata[poolApplicationNameRBD]; !ok || pool.Type != replicatedPoolType {
+			continue
+		}
+		images, err := cephclient.ListImages(clusterdContext, p.Namespace, pool.Name)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to list the images of pool %q", pool.Name)
+		}
+
+		for _, image := range images {
+			imageSpec := fmt.Sprintf("%s/%s", pool.Name, image.Name)
+			info, err := cephclient.GetImageInfo(clusterdContext, p.Namespace, image.Name, pool.Name)
+			if err != nil {
+				return false, err
+			}
+			if info.DataPool != p.Name && info.DataPool != migration.TargetPool {
+				continue
+			}
+			status, err := cephclient.GetImageStatus(clusterdContext, p.Namespace, image.Name, pool.Name)
+			if err != nil {
+				return false, err
+			}
+
+			executed := false
+			if info.DataPool == p.Name {
+				if len(status.Watchers) > 0 {
+					pending = append(pending, fmt.Sprintf("%s: in use", imageSpec))
+					continue
+				}
+				logger.Infof("migrating the data of image %q from pool %q to pool %q", imageSpec, p.Name, migration.TargetPool)
+				if err := cephclient.PrepareImageMigration(clusterdContext, p.Namespace, image.Name, pool.Name, migration.TargetPool); err != nil {
+					logger.Warningf("failed to prepare the migration of image %q. %v", imageSpec, err)
+					pending = append(pending, fmt.Sprintf("%s: failed to prepare the migration", imageSpec))
+					continue
+				}
+			} else if status.Migration == nil {
+				// the image was migrated already
+				continue
+			} else {
+				executed = status.Migration.State == "executed"
+			}
+
+			if !executed {
+				// the execution is started again after a failure or a restart of the operator
+				running, err := imageMigrations.result(p.Namespace, image.Name, pool.Name)
+				if err != nil {
+					logger.Warningf("failed to execute the migration of image %q, retrying. %v", imageSpec, err)
+				}
+				if !running {
+					imageMigrations.start(clusterdContext, p.Namespace, image.Name, pool.Name)
+				}
+				executing = append(executing, imageSpec)
+				continue
+			}
+			if err := cephclient.CommitImageMigration(clusterdContext, p.Namespace, image.Name, pool.Name); err != nil {
+				logger.Warningf("failed to commit the migration of image %q. %v", imageSpec, err)
+				pending = append(pending, fmt.Sprintf("%s: failed to commit the migration", imageSpec))
+				continue
+			}
+			migration.MigratedImages++
+		}
+	}
+
+	migration.PendingImages = nil
+	migration.ExecutingImages = nil
+	if len(pending) > 0 || len(executing) > 0 {
+		if len(pending) > 0 {
+			migration.PendingImages = pending
+		}
+		if len(executing) > 0 {
+			migration.ExecutingImages = executing
+		}
+		migration.Message = fmt.Sprintf("waiting for %d images to be migrated", len(pending)+len(executing))
+		return false, nil
+	}
+
+	// the images in the trash or not managed by rbd still hold data in the pool
+	stats, err := cephclient.GetPoolStats(clusterdContext, p.Namespace)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get pool stats")
+	}
+	for _, pool := range stats.Pools {
+		if pool.Name == p.Name && pool.Stats.Objects > 0 {
+			migration.Message = fmt.Sprintf("waiting for the %d remaining objects of pool %q to be removed", uint64(pool.Stats.Objects), p.Name)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// swapPools renames the migrated pool and gives its name to the target pool of the migration, then deletes the
+// migrated pool with its profile and its crush rule. Each step is skipped if it was done by a previous reconcile.
+func swapPools(clusterdContext *clusterd.Context, p *cephv1.CephBlockPool, migration *cephv1.PoolErasureCodeMigrationStatus) error {
+	previousPool := fmt.Sprintf("%s-previous", migration.TargetPool)
+	details, err := cephclient.ListPoolDetails(clusterdContext, p.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to list pool details")
+	}
+
+	poolExists := findPoolDetail(details, p.Name) != nil
+	targetExists := findPoolDetail(details, migration.TargetPool) != nil
+	if poolExists && targetExists {
+		if err := cephclient.RenamePool(clusterdContext, p.Namespace, p.Name, previousPool); err != nil {
+			return err
+		}
+		poolExists = false
+	}
+	if targetExists {
+		if err := cephclient.RenamePool(clusterdContext, p.Namespace, migration.TargetPool, p.Name); err != nil {
+			return err
+		}
+		poolExists = true
+	}
+	if !poolExists {
+		return errors.Errorf("neither pool %q nor pool %q exist", p.Name, migration.TargetPool)
+	}
+
+	previous := findPoolDetail(details, previousPool)
+	if previous == nil {
+		previous = findPoolDetail(details, p.Name)
+		if previous != nil && previous.ErasureCodeProfile != migration.PreviousProfile {
+			// the previous pool was deleted already
+			previous = nil
+		}
+	}
+	if previous != nil {
+		// the erasure coded pools are created with a crush rule named after the pool at the time
+		crushMap, err := cephclient.GetCrushMap(clusterdContext, p.Namespace)
+		if err != nil {
+			return errors.Wrap(err, "failed to get crush map")
+		}
+		if err := cephclient.DeletePool(clusterdContext, p.Namespace, previousPool); err != nil {
+			return errors.Wrapf(err, "failed to delete the migrated pool %q", previousPool)
+		}
+		for _, rule := range crushMap.Rules {
+			if rule.ID == previous.CrushRule && rule.Name != previousPool {
+				if err := cephclient.DeleteCrushRule(clusterdContext, p.Namespace, rule.Name); err != nil {
+					logger.Warningf("failed to delete the crush rule of the migrated pool %q. %v", previousPool, err)
+				}
+			}
+		}
+	}
+	if migration.PreviousProfile != "" {
+		if err := cephclient.DeleteErasureCodeProfile(clusterdContext, p.Namespace, migration.PreviousProfile); err != nil {
+			logger.Warningf("failed to delete the erasure code profile of the migrated pool %q. %v", previousPool, err)
+		}
+	}
+
+	return nil
+}
+
+func findPoolDetail(details []cephclient.CephStoragePoolLsDetail, poolName string) *cephclient.CephStoragePoolLsDetail {
+	for i := range details {
+		if details[i].Name == poolName {
+			return &details[i]
+		}
+	}
+	return nil
+}
+
+// updateErasureCodeStatus reports the erasure code settings of a pool that differ from its profile and the progress of
+// the migration of its data in its status
+func updateErasureCodeStatus(c client.Client, poolName types.NamespacedName, drift []string, migration *cephv1.PoolErasureCodeMigrationStatus) {
+	pool := &cephv1.CephBlockPool{}
+	if err := c.Get(context.TODO(), poolName, pool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve pool %q to report its erasure code settings. %v", poolName, err)
+		return
+	}
+
+	if pool.Status == nil {
+		pool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+	pool.Status.ErasureCodeDrift = drift
+	pool.Status.ErasureCodeMigration = migration
+	if err := opcontroller.UpdateStatus(c, pool); err != nil {
+		logger.Warningf("failed to report the erasure code settings of pool %q. %v", pool.Name, err)
+	}
+}
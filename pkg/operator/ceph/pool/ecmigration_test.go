@@ -0,0 +1,336 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type testImage struct {
+	dataPool  string
+	inUse     bool
+	migration string
+}
+
+func TestReconcileErasureCodeProfile(t *testing.T) {
+	pools := []cephclient.CephStoragePoolLsDetail{
+		{Number: 1, Name: "replicapool", Type: 1, CrushRule: 0, ApplicationMetadata: map[string]map[string]string{"rbd": {}}},
+		{Number: 2, Name: "ecpool", Type: 3, CrushRule: 1, ErasureCodeProfile: "ecpool_ecprofile"},
+	}
+	rules := `[{"rule_id":0,"rule_name":"replicapool"},{"rule_id":1,"rule_name":"ecpool"}]`
+	images := map[string]*testImage{
+		"img1": {dataPool: "ecpool"},
+		"img2": {dataPool: "ecpool", inUse: true},
+		"img3": {},
+	}
+	executed := []string{}
+	// the migrations of the images are executed in the background
+	var mutex sync.Mutex
+
+	findPool := func(name string) int {
+		for i := range pools {
+			if pools[i].Name == name {
+				return i
+			}
+		}
+		return -1
+	}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			cmd := strings.Split(strings.Join(args, " "), " --")[0]
+			executed = append(executed, cmd)
+			switch {
+			case cmd == "osd pool ls detail":
+				output, _ := json.Marshal(pools)
+				return string(output), nil
+			case cmd == "osd erasure-code-profile get default":
+				return `{"k":"2","m":"1","plugin":"jerasure","technique":"reed_sol_van"}`, nil
+			case cmd == "osd erasure-code-profile get ecpool_ecprofile":
+				return `{"k":"2","m":"1","plugin":"jerasure","technique":"reed_sol_van","crush-failure-domain":"host"}`, nil
+			case strings.HasPrefix(cmd, "osd erasure-code-profile get "):
+				return `{"k":"4","m":"2","plugin":"jerasure","technique":"reed_sol_van","crush-failure-domain":"host"}`, nil
+			case strings.HasPrefix(cmd, "osd pool create "):
+				pools = append(pools, cephclient.CephStoragePoolLsDetail{Number: 3, Name: args[3], Type: 3, CrushRule: 2, ErasureCodeProfile: args[6]})
+				rules = fmt.Sprintf(`[{"rule_id":0,"rule_name":"replicapool"},{"rule_id":1,"rule_name":"ecpool"},{"rule_id":2,"rule_name":%q}]`, args[3])
+			case strings.HasPrefix(cmd, "osd pool rename "):
+				pools[findPool(args[3])].Name = args[4]
+			case strings.HasPrefix(cmd, "osd pool get "):
+				return fmt.Sprintf(`{"pool":%q,"pool_id":2}`, args[3]), nil
+			case strings.HasPrefix(cmd, "osd pool delete "):
+				i := findPool(args[3])
+				pools = append(pools[:i], pools[i+1:]...)
+			case cmd == "osd crush dump":
+				return `{"rules":` + rules + `}`, nil
+			case cmd == "df detail":
+				objects := 0
+				for _, image := range images {
+					if image.dataPool == "ecpool" {
+						objects += 10
+					}
+				}
+				return fmt.Sprintf(`{"pools":[{"name":"ecpool","stats":{"objects":%d}}]}`, objects), nil
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			executed = append(executed, strings.Join(args, " "))
+			switch args[0] {
+			case "ls":
+				if args[2] != "replicapool" {
+					return "", fmt.Errorf("unexpected pool %q", args[2])
+				}
+				return `[{"image":"img1"},{"image":"img2"},{"image":"img3"}]`, nil
+			case "pool":
+				return `{"images":{"count":0}}`, nil
+			}
+			var image *testImage
+			for _, arg := range args {
+				if strings.HasPrefix(arg, "replicapool/") {
+					image = images[strings.TrimPrefix(arg, "replicapool/")]
+				}
+			}
+			switch args[0] {
+			case "info":
+				return fmt.Sprintf(`{"name":"img","data_pool":%q}`, image.dataPool), nil
+			case "status":
+				status := `{"watchers":[]}`
+				if image.inUse {
+					status = `{"watchers":[{"address":"10.0.0.1:0/1"}]}`
+				} else if image.migration != "" {
+					status = fmt.Sprintf(`{"watchers":[],"migration":{"state":%q}}`, image.migration)
+				}
+				return status, nil
+			case "migration":
+				if args[1] == "prepare" {
+					image.dataPool = args[3]
+					image.migration = "prepared"
+				} else if args[1] == "execute" {
+					image.migration = "executed"
+				} else {
+					image.migration = ""
+				}
+			}
+			return "", nil
+		},
+	}
+	clusterdContext := &clusterd.Context{Executor: executor}
+
+	p := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "ecpool", Namespace: "myns"},
+		Spec: cephv1.PoolSpec{
+			ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1},
+		},
+		Status: &cephv1.CephBlockPoolStatus{},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, p)
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{p.DeepCopy()}...)
+	name := types.NamespacedName{Name: p.Name, Namespace: p.Namespace}
+
+	// the profile matches the spec
+	exists, result, err := reconcileErasureCodeProfile(clusterdContext, cl, p)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.False(t, result.Requeue)
+	assert.NoError(t, cl.Get(context.TODO(), name, p))
+	assert.Nil(t, p.Status.ErasureCodeDrift)
+
+	// the drift is reported
+	p.Spec.ErasureCoded = cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2}
+	exists, result, err = reconcileErasureCodeProfile(clusterdContext, cl, p)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.False(t, result.Requeue)
+	assert.NoError(t, cl.Get(context.TODO(), name, p))
+	assert.Equal(t, []string{"dataChunks: 2 -> 4", "codingChunks: 1 -> 2"}, p.Status.ErasureCodeDrift)
+	assert.Nil(t, p.Status.ErasureCodeMigration)
+	assert.Equal(t, 2, len(pools))
+
+	// the migration starts, the data of the image is copied in the background and the image in use is pending
+	p.Spec.ErasureCoded = cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2, AllowMigration: true}
+	targetPool := ecMigrationTargetPool(p)
+	_, result, err = reconcileErasureCodeProfile(clusterdContext, cl, p)
+	assert.NoError(t, err)
+	assert.True(t, result.Requeue)
+	imageMigrations.wg.Wait()
+	assert.NoError(t, cl.Get(context.TODO(), name, p))
+	migration := p.Status.ErasureCodeMigration
+	assert.Equal(t, ecMigrationPhaseMigratingImages, migration.Phase)
+	assert.Equal(t, targetPool, migration.TargetPool)
+	assert.True(t, strings.HasPrefix(migration.TargetPool, "ecpool-migration-"))
+	assert.Equal(t, migration.TargetPool+"_ecprofile", migration.TargetProfile)
+	assert.Equal(t, "ecpool_ecprofile", migration.PreviousProfile)
+	assert.Equal(t, cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2, AllowMigration: true}, migration.TargetSpec.ErasureCoded)
+	assert.Equal(t, 0, migration.MigratedImages)
+	assert.Equal(t, []string{"replicapool/img2: in use"}, migration.PendingImages)
+	assert.Equal(t, []string{"replicapool/img1"}, migration.ExecutingImages)
+	assert.Equal(t, migration.TargetPool, images["img1"].dataPool)
+	assert.Equal(t, "executed", images["img1"].migration)
+	assert.Equal(t, "", images["img3"].dataPool)
+	assert.Contains(t, executed, fmt.Sprintf("osd erasure-code-profile set %s k=4 m=2 plugin=jerasure technique=reed_sol_van", migration.TargetProfile))
+
+	// the executed migration is committed, and the migration of the image prepared before an interruption is executed
+	images["img2"].inUse = false
+	images["img2"].dataPool = migration.TargetPool
+	images["img2"].migration = "prepared"
+	p.Spec.ErasureCoded = cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2, AllowMigration: true}
+	_, result, err = reconcileErasureCodeProfile(clusterdContext, cl, p)
+	assert.NoError(t, err)
+	assert.True(t, result.Requeue)
+	imageMigrations.wg.Wait()
+	p = &cephv1.CephBlockPool{}
+	assert.NoError(t, cl.Get(context.TODO(), name, p))
+	migration = p.Status.ErasureCodeMigration
+	assert.Equal(t, 1, migration.MigratedImages)
+	assert.Empty(t, migration.PendingImages)
+	assert.Equal(t, []string{"replicapool/img2"}, migration.ExecutingImages)
+	assert.Equal(t, "", images["img1"].migration)
+
+	// once all the images are migrated the pools are swapped
+	p.Spec.ErasureCoded = cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2, AllowMigration: true}
+	_, result, err = reconcileErasureCodeProfile(clusterdContext, cl, p)
+	assert.NoError(t, err)
+	assert.False(t, result.Requeue)
+	p = &cephv1.CephBlockPool{}
+	assert.NoError(t, cl.Get(context.TODO(), name, p))
+	migration = p.Status.ErasureCodeMigration
+	assert.Equal(t, ecMigrationPhaseCompleted, migration.Phase)
+	assert.Equal(t, 2, migration.MigratedImages)
+	assert.Empty(t, migration.PendingImages)
+	assert.Empty(t, migration.ExecutingImages)
+	assert.NotEmpty(t, migration.CompletionTime)
+	assert.Nil(t, p.Status.ErasureCodeDrift)
+	assert.Contains(t, executed, fmt.Sprintf("osd pool rename ecpool %s-previous", migration.TargetPool))
+	assert.Contains(t, executed, fmt.Sprintf("osd pool rename %s ecpool", migration.TargetPool))
+	assert.Contains(t, executed, fmt.Sprintf("osd pool delete %s-previous %s-previous", migration.TargetPool, migration.TargetPool))
+	assert.Contains(t, executed, "osd crush rule rm ecpool")
+	assert.Contains(t, executed, "osd erasure-code-profile rm ecpool_ecprofile")
+	assert.Equal(t, 2, len(pools))
+	assert.Equal(t, "ecpool", pools[1].Name)
+	assert.Equal(t, migration.TargetProfile, pools[1].ErasureCodeProfile)
+}
+
+func TestContinueErasureCodeMigrationTargetSpec(t *testing.T) {
+	executed := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			cmd := strings.Split(strings.Join(args, " "), " --")[0]
+			executed = append(executed, cmd)
+			switch {
+			case cmd == "osd pool ls detail":
+				return `[{"pool_name":"ecpool","pool":2,"type":3,"erasure_code_profile":"ecpool_ecprofile"}]`, nil
+			case cmd == "osd erasure-code-profile get default":
+				return `{"k":"2","m":"1","plugin":"jerasure","technique":"reed_sol_van"}`, nil
+			case strings.HasPrefix(cmd, "osd pool get "):
+				return `{}`, errors.New("not found")
+			case cmd == "df detail":
+				return `{"pools":[{"name":"ecpool","stats":{"objects":10}}]}`, nil
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return "[]", nil
+		},
+	}
+	clusterdContext := &clusterd.Context{Executor: executor}
+
+	// the spec changed to a replicated pool during the migration
+	p := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "ecpool", Namespace: "myns"},
+		Spec:       cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}},
+	}
+	migration := &cephv1.PoolErasureCodeMigrationStatus{
+		Phase:           ecMigrationPhaseMigratingImages,
+		TargetPool:      "ecpool-migration-1234abcd",
+		TargetProfile:   "ecpool-migration-1234abcd_ecprofile",
+		PreviousProfile: "ecpool_ecprofile",
+		TargetSpec:      &cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2, AllowMigration: true}},
+	}
+
+	// the target pool is created with the settings the migration started with
+	err := continueErasureCodeMigration(clusterdContext, p, migration)
+	assert.NoError(t, err)
+	assert.Equal(t, ecMigrationPhaseMigratingImages, migration.Phase)
+	assert.Contains(t, executed, "osd erasure-code-profile set ecpool-migration-1234abcd_ecprofile k=4 m=2 plugin=jerasure technique=reed_sol_van")
+	assert.Contains(t, executed, "osd pool create ecpool-migration-1234abcd 0 erasure ecpool-migration-1234abcd_ecprofile")
+}
+
+func TestECMigrationTargetPool(t *testing.T) {
+	p := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "ecpool", Namespace: "myns"},
+		Spec:       cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2}},
+	}
+
+	// the name only depends on the erasure code settings
+	name := ecMigrationTargetPool(p)
+	assert.True(t, strings.HasPrefix(name, "ecpool-migration-"))
+	p.Spec.ErasureCoded.AllowMigration = true
+	assert.Equal(t, name, ecMigrationTargetPool(p))
+	p.Spec.FailureDomain = "osd"
+	assert.NotEqual(t, name, ecMigrationTargetPool(p))
+}
+
+func TestImageMigrationExecutor(t *testing.T) {
+	failed := true
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if failed {
+				return "", fmt.Errorf("failed to execute %q", args)
+			}
+			return "", nil
+		},
+	}
+	clusterdContext := &clusterd.Context{Executor: executor}
+	e := newImageMigrationExecutor()
+
+	// the error of a failed execution is reported once
+	e.start(clusterdContext, "myns", "img", "replicapool")
+	e.wg.Wait()
+	running, err := e.result("myns", "img", "replicapool")
+	assert.False(t, running)
+	assert.Error(t, err)
+	_, err = e.result("myns", "img", "replicapool")
+	assert.NoError(t, err)
+
+	failed = false
+	e.start(clusterdContext, "myns", "img", "replicapool")
+	e.wg.Wait()
+	running, err = e.result("myns", "img", "replicapool")
+	assert.False(t, running)
+	assert.NoError(t, err)
+}
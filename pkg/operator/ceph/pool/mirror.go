@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	mirroringModeImage    = "image"
+	mirroringModePool     = "pool"
+	mirroringModeDisabled = "disabled"
+	// BootstrapPeerTokenKey is the key of the bootstrap peer token in the peer Secrets
+	BootstrapPeerTokenKey = "token"
+	// BootstrapPeerPoolKey is the key of the name of the mirrored pool in the peer Secrets
+	BootstrapPeerPoolKey = "pool"
+)
+
+// bootstrapPeerSecretName returns the name of the Secret with the bootstrap peer token of a pool
+func bootstrapPeerSecretName(poolName string) string {
+	return fmt.Sprintf("pool-peer-token-%s", poolName)
+}
+
+// reconcileMirroring enables the mirroring of a pool, schedules the mirror snapshots of its images and exposes
+// its bootstrap peer token. Mirroring is disabled if it is not in the spec but is still enabled in Ceph.
+func (r *ReconcileCephBlockPool) reconcileMirroring(cephBlockPool *cephv1.CephBlockPool, cephVersion *cephver.CephVersion) error {
+	mirroring := cephBlockPool.Spec.Mirroring
+	if !mirroring.Enabled {
+		// RBD cannot store its images in an erasure coded pool, so it could never be mirrored
+		if cephBlockPool.Spec.IsErasureCoded() {
+			return nil
+		}
+		// The status is refreshed from the spec, only Ceph knows if the mirroring was enabled before. A pool that was
+		// never used by RBD may fail to report its mirroring, which must not fail the reconcile of the pool.
+		info, err := cephclient.GetPoolMirroringInfo(r.context, cephBlockPool.Namespace, cephBlockPool.Name)
+		if err != nil {
+			logger.Warningf("failed to check if mirroring is enabled on pool %q, not disabling it. %v", cephBlockPool.Name, err)
+			return nil
+		}
+		if info.Mode != "" && info.Mode != mirroringModeDisabled {
+			if cephVersion.IsAtLeastOctopus() {
+				if err := reconcileSnapshotSchedules(r.context, cephBlockPool, nil); err != nil {
+					return err
+				}
+			}
+			return disableMirroring(r.context, cephBlockPool)
+		}
+		return nil
+	}
+
+	// Snapshot-based mirroring was introduced in Octopus
+	if mirroring.SnapshotSchedulesEnabled() && !cephVersion.IsAtLeastOctopus() {
+		return errors.Errorf("mirroring snapshot schedules require ceph octopus or newer, current version is %q", cephVersion.String())
+	}
+
+	if err := cephclient.EnablePoolMirroring(r.context, cephBlockPool.Namespace, cephBlockPool.Name, mirroring.Mode); err != nil {
+		return err
+	}
+
+	if cephVersion.IsAtLeastOctopus() {
+		if err := reconcileSnapshotSchedules(r.context, cephBlockPool, mirroring.SnapshotSchedules); err != nil {
+			return err
+		}
+	}
+
+	// Expose the bootstrap peer token of the local site for the remote sites to import it
+	clusterInfo, _, _, err := mon.LoadClusterInfo(r.context, cephBlockPool.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to populate cluster info")
+	}
+	token, err := cephclient.CreateRBDMirrorBootstrapPeer(r.context, cephBlockPool.Namespace, cephBlockPool.Name, clusterInfo.FSID)
+	if err != nil {
+		return err
+	}
+	ref, err := opcontroller.GetControllerObjectOwnerReference(cephBlockPool, r.scheme)
+	if err != nil || ref == nil {
+		return errors.Wrapf(err, "failed to get controller %q owner reference", cephBlockPool.Name)
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapPeerSecretName(cephBlockPool.Name),
+			Namespace: cephBlockPool.Namespace,
+		},
+		StringData: map[string]string{
+			BootstrapPeerTokenKey: token,
+			BootstrapPeerPoolKey:  cephBlockPool.Name,
+		},
+		Type: k8sutil.RookType,
+	}
+	k8sutil.SetOwnerRef(&secret.ObjectMeta, ref)
+	if err := keyring.GetSecretStore(r.context, cephBlockPool.Namespace, ref).CreateSecret(secret); err != nil {
+		return errors.Wrapf(err, "failed to save bootstrap peer token of pool %q", cephBlockPool.Name)
+	}
+
+	return nil
+}
+
+// reconcileSnapshotSchedules adds the mirror snapshot schedules of a pool and removes the schedules listed by Ceph
+// whose interval is not in the spec
+func reconcileSnapshotSchedules(context *clusterd.Context, cephBlockPool *cephv1.CephBlockPool, schedules []cephv1.SnapshotScheduleSpec) error {
+	intervals := map[string]bool{}
+	for _, schedule := range schedules {
+		if err := cephclient.AddRBDMirrorSnapshotSchedule(context, cephBlockPool.Namespace, cephBlockPool.Name, schedule.Interval, schedule.StartTime); err != nil {
+			return err
+		}
+		intervals[normalizeScheduleInterval(schedule.Interval)] = true
+	}
+
+	current, err := cephclient.ListRBDMirrorSnapshotSchedules(context, cephBlockPool.Namespace, cephBlockPool.Name)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range current {
+		if intervals[normalizeScheduleInterval(schedule.Interval)] {
+			continue
+		}
+		if err := cephclient.RemoveRBDMirrorSnapshotSchedule(context, cephBlockPool.Namespace, cephBlockPool.Name, schedule.Interval, schedule.StartTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeScheduleInterval returns an interval the way Ceph reports it, in the largest unit of minutes, hours or
+// days that divides it, e.g. "24h" is reported as "1d"
+func normalizeScheduleInterval(interval string) string {
+	if interval == "" {
+		return interval
+	}
+	minutes := map[byte]int{'m': 1, 'h': 60, 'd': 60 * 24}
+	unit, ok := minutes[interval[len(interval)-1]]
+	if !ok {
+		return interval
+	}
+	value, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil {
+		return interval
+	}
+
+	total := value * unit
+	switch {
+	case total%(60*24) == 0:
+		return fmt.Sprintf("%dd", total/(60*24))
+	case total%60 == 0:
+		return fmt.Sprintf("%dh", total/60)
+	default:
+		return fmt.Sprintf("%dm", total)
+	}
+}
+
+// disableMirroring disables the mirroring of a pool and removes its bootstrap peer token
+func disableMirroring(context *clusterd.Context, cephBlockPool *cephv1.CephBlockPool) error {
+	if err := cephclient.DisablePoolMirroring(context, cephBlockPool.Namespace, cephBlockPool.Name); err != nil {
+		return err
+	}
+
+	secretName := bootstrapPeerSecretName(cephBlockPool.Name)
+	err := context.Clientset.CoreV1().Secrets(cephBlockPool.Namespace).Delete(secretName, &metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete bootstrap peer secret %q", secretName)
+	}
+
+	return nil
+}
+
+// getMirroringStatus returns the mirroring configuration and health of a mirrored pool
+func getMirroringStatus(context *clusterd.Context, namespace, poolName string) (*cephv1.MirroringInfo, *cephv1.MirroringStatusSummary, error) {
+	info, err := cephclient.GetPoolMirroringInfo(context, namespace, poolName)
+	if err != nil {
+		return nil, nil, err
+	}
+	status, err := cephclient.GetPoolMirroringStatus(context, namespace, poolName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mirroringInfo := &cephv1.MirroringInfo{
+		BootstrapPeerSecretName: bootstrapPeerSecretName(poolName),
+		Mode:                    info.Mode,
+		SiteName:                info.SiteName,
+	}
+	for _, peer := range info.Peers {
+		mirroringInfo.Peers = append(mirroringInfo.Peers, cephv1.MirroringPeerInfo{
+			UUID:       peer.UUID,
+			Direction:  peer.Direction,
+			SiteName:   peer.SiteName,
+			ClientName: peer.ClientName,
+		})
+	}
+
+	mirroringStatus := &cephv1.MirroringStatusSummary{
+		Health:       status.Summary.Health,
+		DaemonHealth: status.Summary.DaemonHealth,
+		ImageHealth:  status.Summary.ImageHealth,
+		States:       status.Summary.States,
+	}
+
+	return mirroringInfo, mirroringStatus, nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestValidateMirroringSpec(t *testing.T) {
+	// mirroring is disabled
+	m := cephv1.MirroringSpec{}
+	assert.NoError(t, validateMirroringSpec(&m))
+
+	// the mode is required
+	m.Enabled = true
+	assert.Error(t, validateMirroringSpec(&m))
+	m.Mode = "image"
+	assert.NoError(t, validateMirroringSpec(&m))
+	m.Mode = "images"
+	assert.Error(t, validateMirroringSpec(&m))
+
+	// the schedules need an interval
+	m.Mode = "pool"
+	m.SnapshotSchedules = []cephv1.SnapshotScheduleSpec{{StartTime: "14:00:00-05:00"}}
+	assert.Error(t, validateMirroringSpec(&m))
+	m.SnapshotSchedules[0].Interval = "24h"
+	assert.NoError(t, validateMirroringSpec(&m))
+}
+
+func TestReconcileMirroring(t *testing.T) {
+	namespace := "rook-ceph"
+	commands := []string{}
+	cephMirroringMode := "disabled"
+	cephSchedules := `[{"interval":"1d","start_time":null},{"interval":"12h","start_time":"14:00:00"}]`
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			i := 0
+			for i < len(args) && !strings.HasPrefix(args[i], "--cluster") {
+				i++
+			}
+			commands = append(commands, strings.Join(args[:i], " "))
+			if strings.Join(args[:5], " ") == "mirror pool peer bootstrap create" {
+				return "local-token\n", nil
+			}
+			if strings.Join(args[:3], " ") == "mirror pool info" {
+				if cephMirroringMode == "" {
+					return "", errors.New("rbd: failed to open pool")
+				}
+				return fmt.Sprintf(`{"mode":%q,"site_name":"local-fsid","peers":[]}`, cephMirroringMode), nil
+			}
+			if strings.Join(args[:4], " ") == "mirror snapshot schedule ls" {
+				return cephSchedules, nil
+			}
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{Executor: executor, Clientset: test.New(t, 1)}
+	monSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			"cluster-name": []byte("foo-cluster"),
+			"fsid":         []byte("local-fsid"),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := c.Clientset.CoreV1().Secrets(namespace).Create(monSecret)
+	assert.NoError(t, err)
+
+	p := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: namespace},
+		Spec: cephv1.PoolSpec{
+			Mirroring: cephv1.MirroringSpec{
+				Enabled:           true,
+				Mode:              "image",
+				SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "24h"}},
+			},
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, p)
+	r := &ReconcileCephBlockPool{scheme: s, context: c}
+
+	// snapshot schedules are not supported before octopus
+	err = r.reconcileMirroring(p, &cephver.Nautilus)
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(commands))
+
+	err = r.reconcileMirroring(p, &cephver.Octopus)
+	assert.NoError(t, err)
+	// the schedules that are not in the spec are removed
+	assert.Equal(t, []string{
+		"mirror pool enable mypool image",
+		"mirror snapshot schedule add --pool mypool 24h",
+		"mirror snapshot schedule ls --pool mypool",
+		"mirror snapshot schedule remove --pool mypool 12h 14:00:00",
+		"mirror pool peer bootstrap create mypool --site-name local-fsid",
+	}, commands)
+	secret, err := c.Clientset.CoreV1().Secrets(namespace).Get(bootstrapPeerSecretName("mypool"), metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "local-token", secret.StringData["token"])
+	assert.Equal(t, "mypool", secret.StringData["pool"])
+
+	// mirroring is only disabled if ceph reports it as enabled, whatever the status
+	commands = []string{}
+	p.Spec.Mirroring = cephv1.MirroringSpec{}
+	err = r.reconcileMirroring(p, &cephver.Octopus)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mirror pool info mypool"}, commands)
+
+	// mirroring is disabled once removed from the spec
+	commands = []string{}
+	cephMirroringMode = "image"
+	err = r.reconcileMirroring(p, &cephver.Octopus)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"mirror pool info mypool",
+		"mirror snapshot schedule ls --pool mypool",
+		"mirror snapshot schedule remove --pool mypool 1d",
+		"mirror snapshot schedule remove --pool mypool 12h 14:00:00",
+		"mirror pool disable mypool",
+	}, commands)
+	_, err = c.Clientset.CoreV1().Secrets(namespace).Get(bootstrapPeerSecretName("mypool"), metav1.GetOptions{})
+	assert.Error(t, err)
+
+	// failing to read the mirroring of a pool without mirroring does not fail the reconcile
+	commands = []string{}
+	cephMirroringMode = ""
+	err = r.reconcileMirroring(p, &cephver.Octopus)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mirror pool info mypool"}, commands)
+
+	// the mirroring of erasure coded pools is not checked
+	commands = []string{}
+	p.Spec.ErasureCoded = cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}
+	err = r.reconcileMirroring(p, &cephver.Octopus)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(commands))
+}
+
+func TestNormalizeScheduleInterval(t *testing.T) {
+	assert.Equal(t, "1d", normalizeScheduleInterval("24h"))
+	assert.Equal(t, "1d", normalizeScheduleInterval("1440m"))
+	assert.Equal(t, "90m", normalizeScheduleInterval("90m"))
+	assert.Equal(t, "2h", normalizeScheduleInterval("120m"))
+	assert.Equal(t, "1w", normalizeScheduleInterval("1w"))
+	assert.Equal(t, "", normalizeScheduleInterval(""))
+}
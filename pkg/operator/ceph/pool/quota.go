@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+// GetQuotaStatuses returns the usage of the given pools against their quotas, indexed by pool name.
+// Pools that do not exist (yet) are not part of the result.
+func GetQuotaStatuses(context *clusterd.Context, namespace string, poolNames []string) (map[string]cephv1.PoolQuotaStatus, error) {
+	stats, err := cephclient.GetPoolStats(context, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pool stats")
+	}
+
+	statuses := map[string]cephv1.PoolQuotaStatus{}
+	for _, poolName := range poolNames {
+		for _, pool := range stats.Pools {
+			if pool.Name != poolName {
+				continue
+			}
+			statuses[poolName] = cephv1.PoolQuotaStatus{
+				MaxBytes:    uint64(pool.Stats.QuotaBytes),
+				MaxObjects:  uint64(pool.Stats.QuotaObjects),
+				UsedBytes:   uint64(pool.Stats.Stored),
+				UsedObjects: uint64(pool.Stats.Objects),
+			}
+			break
+		}
+	}
+
+	return statuses, nil
+}
+
+// getBlockPoolQuotaStatus returns the usage of a block pool against its quotas, including the size provisioned
+// by the rbd images of the pool
+func getBlockPoolQuotaStatus(context *clusterd.Context, namespace, poolName string) (*cephv1.PoolQuotaStatus, error) {
+	statuses, err := GetQuotaStatuses(context, namespace, []string{poolName})
+	if err != nil {
+		return nil, err
+	}
+	status, ok := statuses[poolName]
+	if !ok {
+		return nil, errors.Errorf("pool %q not found in the pool stats", poolName)
+	}
+
+	rbdStats, err := cephclient.GetPoolStatistics(context, poolName, namespace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get rbd statistics of pool %q", poolName)
+	}
+	status.ProvisionedBytes = uint64(rbdStats.Images.ProvisionedBytes)
+
+	return &status, nil
+}
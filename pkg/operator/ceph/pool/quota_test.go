@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const dfDetailOutput = `{"pools":[
+{"name":"replicapool","id":1,"stats":{"stored":4096,"bytes_used":12288,"objects":3,"quota_objects":0,"quota_bytes":1073741824}},
+{"name":"myfs-data0","id":2,"stats":{"stored":0,"bytes_used":512,"objects":10,"quota_objects":100,"quota_bytes":0}}]}`
+
+func TestGetQuotaStatuses(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outputFile string, args ...string) (string, error) {
+			if args[0] == "df" && args[1] == "detail" {
+				return dfDetailOutput, nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "pool" && args[1] == "stats" && args[2] == "replicapool" {
+				return `{"images":{"count":2,"provisioned_bytes":2147483648,"snap_count":0},"trash":{"count":0,"provisioned_bytes":0,"snap_count":0}}`, nil
+			}
+			return "", errors.Errorf("unexpected rbd command %q", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	statuses, err := GetQuotaStatuses(context, "ns", []string{"replicapool", "myfs-data0", "missing"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(statuses))
+	assert.Equal(t, cephv1.PoolQuotaStatus{MaxBytes: 1073741824, UsedBytes: 4096, UsedObjects: 3}, statuses["replicapool"])
+	// the raw usage of "bytes_used" is not reported as stored data
+	assert.Equal(t, cephv1.PoolQuotaStatus{MaxObjects: 100, UsedObjects: 10}, statuses["myfs-data0"])
+
+	status, err := getBlockPoolQuotaStatus(context, "ns", "replicapool")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2147483648), status.ProvisionedBytes)
+	assert.Equal(t, uint64(1073741824), status.MaxBytes)
+
+	_, err = getBlockPoolQuotaStatus(context, "ns", "missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetPoolStatuses returns the state of the given pools as applied by Ceph, in the same order.
+// Pools that do not exist (yet) are not part of the result. The erasure code settings of the given specs, indexed by
+// pool name, that differ from the profile of their pool are reported as its erasure code drift.
+func GetPoolStatuses(context *clusterd.Context, namespace string, poolNames []string, specs map[string]cephv1.PoolSpec) ([]cephv1.PoolStatus, error) {
+	details, err := cephclient.ListPoolDetails(context, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pool details")
+	}
+	stats, err := cephclient.GetPoolStats(context, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pool stats")
+	}
+
+	ecProfiles := map[string]cephclient.CephErasureCodeProfile{}
+	statuses := []cephv1.PoolStatus{}
+	for _, poolName := range poolNames {
+		for _, detail := range details {
+			if detail.Name != poolName {
+				continue
+			}
+			status := cephv1.PoolStatus{
+				Name:               detail.Name,
+				ID:                 detail.Number,
+				Replicas:           detail.Size,
+				MinReplicas:        detail.MinSize,
+				ErasureCodeProfile: detail.ErasureCodeProfile,
+				PGs:                detail.PgNum,
+			}
+
+			if detail.ErasureCodeProfile != "" {
+				profile, ok := ecProfiles[detail.ErasureCodeProfile]
+				if !ok {
+					profile, err = cephclient.GetErasureCodeProfileDetails(context, namespace, detail.ErasureCodeProfile)
+					if err != nil {
+						return nil, errors.Wrapf(err, "failed to get erasure code profile %q of pool %q", detail.ErasureCodeProfile, poolName)
+					}
+					ecProfiles[detail.ErasureCodeProfile] = profile
+				}
+				status.DataChunks = profile.DataChunkCount
+				status.CodingChunks = profile.CodingChunkCount
+				if spec, ok := specs[poolName]; ok && spec.IsErasureCoded() {
+					status.ErasureCodeDrift = cephclient.ErasureCodeProfileDrift(spec, profile)
+				}
+			}
+
+			for _, pool := range stats.Pools {
+				if pool.Name == poolName {
+					// "bytes_used" includes the replicas, only the data stored is reported
+					status.UsedBytes = uint64(pool.Stats.Stored)
+					status.AvailableBytes = uint64(pool.Stats.MaxAvail)
+					break
+				}
+			}
+
+			statuses = append(statuses, status)
+			break
+		}
+	}
+
+	return statuses, nil
+}
+
+// refreshStatus updates a pool CR with the state of the pool, its usage against its quotas, its mirroring health and
+// the data movement of the last change of its crush rule
+func refreshStatus(clusterdContext *clusterd.Context, c client.Client, poolName types.NamespacedName, spec cephv1.PoolSpec) {
+	// the drift of a block pool is reported with the progress of its migration
+	statuses, err := GetPoolStatuses(clusterdContext, poolName.Namespace, []string{poolName.Name}, nil)
+	if err != nil {
+		logger.Warningf("failed to get the state of pool %q. %v", poolName, err)
+		return
+	}
+	var poolStatus *cephv1.PoolStatus
+	if len(statuses) > 0 {
+		poolStatus = &statuses[0]
+	}
+
+	var quota *cephv1.PoolQuotaStatus
+	if spec.Quotas.IsEnabled() {
+		quota, err = getBlockPoolQuotaStatus(clusterdContext, poolName.Namespace, poolName.Name)
+		if err != nil {
+			logger.Warningf("failed to get quota status of pool %q. %v", poolName, err)
+			return
+		}
+	}
+
+	var mirroringInfo *cephv1.MirroringInfo
+	var mirroringStatus *cephv1.MirroringStatusSummary
+	if spec.Mirroring.Enabled {
+		mirroringInfo, mirroringStatus, err = getMirroringStatus(clusterdContext, poolName.Namespace, poolName.Name)
+		if err != nil {
+			logger.Warningf("failed to get mirroring status of pool %q. %v", poolName, err)
+			return
+		}
+	}
+
+	pool := &cephv1.CephBlockPool{}
+	if err := c.Get(context.TODO(), poolName, pool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve pool %q to refresh its status. %v", poolName, err)
+		return
+	}
+
+	if pool.Status == nil {
+		pool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+
+	if pool.Status.CrushRuleChange != nil && !pool.Status.CrushRuleChange.Completed {
+		if err := refreshCrushRuleChange(clusterdContext, poolName, pool.Status.CrushRuleChange); err != nil {
+			logger.Warningf("failed to refresh the data movement of pool %q. %v", poolName, err)
+		}
+	}
+
+	pool.Status.Pool = poolStatus
+	pool.Status.Quota = quota
+	pool.Status.MirroringInfo = mirroringInfo
+	pool.Status.MirroringStatus = mirroringStatus
+	pool.Status.LastChecked = opcontroller.FormatStatusTime(time.Now().UTC())
+	if err := opcontroller.UpdateStatus(c, pool); err != nil {
+		logger.Warningf("failed to refresh status of pool %q. %v", pool.Name, err)
+		return
+	}
+	logger.Debugf("pool %q status refreshed", poolName)
+}
+
+// refreshAllStatuses refreshes the status of all the ready pool CRs
+func refreshAllStatuses(clusterdContext *clusterd.Context, c client.Client) {
+	pools := &cephv1.CephBlockPoolList{}
+	if err := c.List(context.TODO(), pools); err != nil {
+		logger.Warningf("failed to list pools to refresh their status. %v", err)
+		return
+	}
+
+	for _, pool := range pools.Items {
+		if !pool.GetDeletionTimestamp().IsZero() || pool.Status == nil || pool.Status.Phase != k8sutil.ReadyStatus {
+			continue
+		}
+		name := types.NamespacedName{Name: pool.Name, Namespace: pool.Namespace}
+		if _, isReadyToReconcile, _, _ := opcontroller.IsReadyToReconcile(c, clusterdContext, name, controllerName); !isReadyToReconcile {
+			continue
+		}
+		refreshStatus(clusterdContext, c, name, pool.Spec)
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const poolLsDetailOutput = `[
+{"pool":1,"pool_name":"replicapool","type":1,"size":3,"min_size":2,"crush_rule":1,"pg_num":32,"erasure_code_profile":""},
+{"pool":2,"pool_name":"ecpool","type":3,"size":3,"min_size":2,"crush_rule":2,"pg_num":8,"erasure_code_profile":"ecpool_ecprofile"}]`
+
+const poolDfOutput = `{"pools":[
+{"name":"replicapool","id":1,"stats":{"stored":4096,"bytes_used":12288,"objects":3,"max_avail":1000000}},
+{"name":"ecpool","id":2,"stats":{"stored":100,"bytes_used":150,"objects":1,"max_avail":2000000}}]}`
+
+func TestGetPoolStatuses(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outputFile string, args ...string) (string, error) {
+			if args[0] == "osd" && args[1] == "pool" && args[2] == "ls" && args[3] == "detail" {
+				return poolLsDetailOutput, nil
+			}
+			if args[0] == "df" && args[1] == "detail" {
+				return poolDfOutput, nil
+			}
+			if args[0] == "osd" && args[1] == "erasure-code-profile" && args[2] == "get" {
+				assert.Equal(t, "ecpool_ecprofile", args[3])
+				return `{"k":"2","m":"1","plugin":"jerasure","technique":"reed_sol_van"}`, nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	statuses, err := GetPoolStatuses(context, "ns", []string{"ecpool", "missing", "replicapool"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []cephv1.PoolStatus{
+		{Name: "ecpool", ID: 2, Replicas: 3, MinReplicas: 2, ErasureCodeProfile: "ecpool_ecprofile", DataChunks: 2, CodingChunks: 1, PGs: 8, UsedBytes: 100, AvailableBytes: 2000000},
+		{Name: "replicapool", ID: 1, Replicas: 3, MinReplicas: 2, PGs: 32, UsedBytes: 4096, AvailableBytes: 1000000},
+	}, statuses)
+
+	// the erasure code settings of the spec that differ from the profile are reported
+	specs := map[string]cephv1.PoolSpec{
+		"ecpool":      {ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 1}},
+		"replicapool": {Replicated: cephv1.ReplicatedSpec{Size: 3}},
+	}
+	statuses, err = GetPoolStatuses(context, "ns", []string{"ecpool", "replicapool"}, specs)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dataChunks: 2 -> 4"}, statuses[0].ErasureCodeDrift)
+	assert.Nil(t, statuses[1].ErasureCodeDrift)
+}
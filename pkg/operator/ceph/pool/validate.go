@@ -25,7 +25,7 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 )
 
-// ValidatePool Validate the pool arguments
+// ValidatePool Validate the pool arguments. If the context is nil, the settings requiring the cluster are not validated.
 func ValidatePool(context *clusterd.Context, p *cephv1.CephBlockPool) error {
 	if p.Name == "" {
 		return errors.New("missing name")
@@ -36,15 +36,60 @@ func ValidatePool(context *clusterd.Context, p *cephv1.CephBlockPool) error {
 	if err := ValidatePoolSpec(context, p.Namespace, &p.Spec); err != nil {
 		return err
 	}
+	if err := validateMirroringSpec(&p.Spec.Mirroring); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateMirroringSpec validates the mirroring settings of a block pool
+func validateMirroringSpec(m *cephv1.MirroringSpec) error {
+	if !m.Enabled {
+		return nil
+	}
+
+	switch m.Mode {
+	case mirroringModeImage, mirroringModePool:
+		break
+	default:
+		return errors.Errorf("unrecognized mirroring mode %q. only 'image' and 'pool' are supported", m.Mode)
+	}
+
+	for _, schedule := range m.SnapshotSchedules {
+		if schedule.Interval == "" {
+			return errors.New("mirroring snapshot schedules must have an interval")
+		}
+	}
+
 	return nil
 }
 
-// ValidatePoolSpec validates the Ceph block pool spec CR
+// ValidatePoolSpec validates the Ceph block pool spec CR. If the context is nil, the failure domain and the crush root
+// are not validated against the crush map.
 func ValidatePoolSpec(context *clusterd.Context, namespace string, p *cephv1.PoolSpec) error {
 	if p.IsReplicated() && p.IsErasureCoded() {
 		return errors.New("both replication and erasure code settings cannot be specified")
 	}
 
+	// validate pool replica size
+	if p.Replicated.Size == 1 && p.Replicated.RequireSafeReplicaSize {
+		return errors.Errorf("error pool size is %d and requireSafeReplicaSize is %t, must be false", p.Replicated.Size, p.Replicated.RequireSafeReplicaSize)
+	}
+
+	// validate pool compression mode if specified
+	if p.CompressionMode != "" {
+		switch p.CompressionMode {
+		case "none", "passive", "aggressive", "force":
+			break
+		default:
+			return errors.Errorf("unrecognized compression mode %q", p.CompressionMode)
+		}
+	}
+
+	if context == nil {
+		return nil
+	}
+
 	var crush cephclient.CrushMap
 	var err error
 	if p.FailureDomain != "" || p.CrushRoot != "" {
@@ -82,20 +127,5 @@ func ValidatePoolSpec(context *clusterd.Context, namespace string, p *cephv1.Poo
 		}
 	}
 
-	// validate pool replica size
-	if p.Replicated.Size == 1 && p.Replicated.RequireSafeReplicaSize {
-		return errors.Errorf("error pool size is %d and requireSafeReplicaSize is %t, must be false", p.Replicated.Size, p.Replicated.RequireSafeReplicaSize)
-	}
-
-	// validate pool compression mode if specified
-	if p.CompressionMode != "" {
-		switch p.CompressionMode {
-		case "none", "passive", "aggressive", "force":
-			break
-		default:
-			return errors.Errorf("unrecognized compression mode %q", p.CompressionMode)
-		}
-	}
-
 	return nil
 }
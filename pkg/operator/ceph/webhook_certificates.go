@@ -0,0 +1,237 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/admission"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	webhookConfigName = "rook-ceph-webhook"
+	// the annotation of the secret of the admission controller when its certificates are generated by the operator
+	generatedCertificatesAnnotation = "ceph.rook.io/generated-certificates"
+	caValidity                      = 5 * 365 * 24 * time.Hour
+	servingCertValidity             = 365 * 24 * time.Hour
+)
+
+var (
+	// EnableAdmissionController Whether the operator generates and rotates the certificates of the admission controller
+	// and registers its validating and mutating webhooks
+	EnableAdmissionController = false
+	// the interval to check if the certificates of the admission controller must be renewed
+	webhookCertificatesCheckInterval = 12 * time.Hour
+	// the resources validated by the admission controller
+	validatedResources = []string{"cephclusters", "cephblockpools", "cephfilesystems", "cephobjectstores", "cephnfses", "cephclients"}
+)
+
+// reconcileWebhookCertificates generates the certificates of the admission controller, renews them before they
+// expire, and registers the validating and mutating webhooks with the certificate authority. The certificates of a
+// secret created by the admin are left untouched.
+func reconcileWebhookCertificates(context *clusterd.Context) error {
+	secret, err := context.Clientset.CoreV1().Secrets(namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get secret %q", appName)
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        appName,
+				Namespace:   namespace,
+				Annotations: map[string]string{generatedCertificatesAnnotation: "true"},
+			},
+			Data: map[string][]byte{},
+		}
+		if err := webhookCertificates().Renew(secret.Data, time.Now()); err != nil {
+			return errors.Wrap(err, "failed to generate the certificates of the admission controller")
+		}
+		logger.Infof("generated the certificates of the admission controller in secret %q", appName)
+		if _, err := context.Clientset.CoreV1().Secrets(namespace).Create(secret); err != nil {
+			return errors.Wrapf(err, "failed to create secret %q", appName)
+		}
+	} else if secret.Annotations[generatedCertificatesAnnotation] != "true" {
+		logger.Infof("the certificates of the admission controller in secret %q are not generated by the operator, they are not renewed", appName)
+		return nil
+	} else {
+		renewed, err := webhookCertificates().RenewIfExpiring(secret.Data, time.Now())
+		if err != nil {
+			return errors.Wrap(err, "failed to renew the certificates of the admission controller")
+		}
+		if renewed {
+			logger.Infof("renewed the certificates of the admission controller in secret %q", appName)
+			if _, err := context.Clientset.CoreV1().Secrets(namespace).Update(secret); err != nil {
+				return errors.Wrapf(err, "failed to update secret %q", appName)
+			}
+		}
+	}
+
+	if err := createOrUpdateWebhookConfig(context, admission.CABundle(secret.Data)); err != nil {
+		return err
+	}
+	return createOrUpdateMutatingWebhookConfig(context, admission.CABundle(secret.Data))
+}
+
+// webhookCertificates returns the certificates of the admission controller for the names of its service
+func webhookCertificates() *admission.GeneratedCertificates {
+	return &admission.GeneratedCertificates{
+		CAName:       fmt.Sprintf("%s-ca", appName),
+		DNSNames:     webhookDNSNames(),
+		CAValidity:   caValidity,
+		CertValidity: servingCertValidity,
+	}
+}
+
+// webhookDNSNames returns the names of the service of the admission controller
+func webhookDNSNames() []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", appName, namespace),
+		fmt.Sprintf("%s.%s", appName, namespace),
+		appName,
+	}
+}
+
+// createOrUpdateWebhookConfig registers the admission controller to validate the ceph resources
+func createOrUpdateWebhookConfig(context *clusterd.Context, caBundle []byte) error {
+	path := "/validate"
+	failurePolicy := admissionv1beta1.Ignore
+	sideEffects := admissionv1beta1.SideEffectClassNone
+	timeout := int32(5)
+	webhookConfig := &admissionv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: webhookConfigName,
+		},
+		Webhooks: []admissionv1beta1.ValidatingWebhook{
+			{
+				Name: fmt.Sprintf("%s.%s.svc", appName, namespace),
+				Rules: []admissionv1beta1.RuleWithOperations{
+					{
+						Operations: []admissionv1beta1.OperationType{admissionv1beta1.Create, admissionv1beta1.Update},
+						Rule: admissionv1beta1.Rule{
+							APIGroups:   []string{cephv1.CustomResourceGroup},
+							APIVersions: []string{cephv1.Version},
+							Resources:   validatedResources,
+						},
+					},
+				},
+				ClientConfig: admissionv1beta1.WebhookClientConfig{
+					Service: &admissionv1beta1.ServiceReference{
+						Name:      appName,
+						Namespace: namespace,
+						Path:      &path,
+					},
+					CABundle: caBundle,
+				},
+				// the requests are admitted while the admission controller is not running
+				FailurePolicy:           &failurePolicy,
+				SideEffects:             &sideEffects,
+				TimeoutSeconds:          &timeout,
+				AdmissionReviewVersions: []string{"v1beta1"},
+			},
+		},
+	}
+
+	client := context.Clientset.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations()
+	existing, err := client.Get(webhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get validating webhook %q", webhookConfigName)
+		}
+		if _, err := client.Create(webhookConfig); err != nil {
+			return errors.Wrapf(err, "failed to create validating webhook %q", webhookConfigName)
+		}
+		logger.Infof("created validating webhook %q", webhookConfigName)
+		return nil
+	}
+
+	webhookConfig.ResourceVersion = existing.ResourceVersion
+	if _, err := client.Update(webhookConfig); err != nil {
+		return errors.Wrapf(err, "failed to update validating webhook %q", webhookConfigName)
+	}
+	return nil
+}
+
+// createOrUpdateMutatingWebhookConfig registers the admission controller to set the defaults of the ceph resources
+func createOrUpdateMutatingWebhookConfig(context *clusterd.Context, caBundle []byte) error {
+	path := "/mutate"
+	failurePolicy := admissionv1beta1.Ignore
+	sideEffects := admissionv1beta1.SideEffectClassNone
+	// the defaults are set again if another webhook changes the resources
+	reinvocationPolicy := admissionv1beta1.IfNeededReinvocationPolicy
+	timeout := int32(5)
+	webhookConfig := &admissionv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: webhookConfigName,
+		},
+		Webhooks: []admissionv1beta1.MutatingWebhook{
+			{
+				Name: fmt.Sprintf("%s.%s.svc", appName, namespace),
+				Rules: []admissionv1beta1.RuleWithOperations{
+					{
+						Operations: []admissionv1beta1.OperationType{admissionv1beta1.Create, admissionv1beta1.Update},
+						Rule: admissionv1beta1.Rule{
+							APIGroups:   []string{cephv1.CustomResourceGroup},
+							APIVersions: []string{cephv1.Version},
+							Resources:   mutatedResources,
+						},
+					},
+				},
+				ClientConfig: admissionv1beta1.WebhookClientConfig{
+					Service: &admissionv1beta1.ServiceReference{
+						Name:      appName,
+						Namespace: namespace,
+						Path:      &path,
+					},
+					CABundle: caBundle,
+				},
+				// the requests are admitted without the defaults while the admission controller is not running
+				FailurePolicy:           &failurePolicy,
+				SideEffects:             &sideEffects,
+				ReinvocationPolicy:      &reinvocationPolicy,
+				TimeoutSeconds:          &timeout,
+				AdmissionReviewVersions: []string{"v1beta1"},
+			},
+		},
+	}
+
+	client := context.Clientset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations()
+	existing, err := client.Get(webhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get mutating webhook %q", webhookConfigName)
+		}
+		if _, err := client.Create(webhookConfig); err != nil {
+			return errors.Wrapf(err, "failed to create mutating webhook %q", webhookConfigName)
+		}
+		logger.Infof("created mutating webhook %q", webhookConfigName)
+		return nil
+	}
+
+	webhookConfig.ResourceVersion = existing.ResourceVersion
+	if _, err := client.Update(webhookConfig); err != nil {
+		return errors.Wrapf(err, "failed to update mutating webhook %q", webhookConfigName)
+	}
+	return nil
+}
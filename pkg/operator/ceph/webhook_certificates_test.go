@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/admission"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconcileWebhookCertificates(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	context := &clusterd.Context{Clientset: clientset}
+
+	// the certificates are generated and the webhook is registered
+	assert.NoError(t, reconcileWebhookCertificates(context))
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(appName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "true", secret.Annotations[generatedCertificatesAnnotation])
+	for _, key := range []string{admission.CACertKey, admission.CAKeyKey, admission.TLSCertKey, admission.TLSKeyKey} {
+		assert.NotEmpty(t, secret.Data[key], key)
+	}
+	webhook, err := clientset.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Get(webhookConfigName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(webhook.Webhooks))
+	assert.Equal(t, secret.Data[admission.CACertKey], webhook.Webhooks[0].ClientConfig.CABundle)
+	assert.Equal(t, validatedResources, webhook.Webhooks[0].Rules[0].Resources)
+	mutatingWebhook, err := clientset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(webhookConfigName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, secret.Data[admission.CACertKey], mutatingWebhook.Webhooks[0].ClientConfig.CABundle)
+	assert.Equal(t, mutatedResources, mutatingWebhook.Webhooks[0].Rules[0].Resources)
+
+	// nothing to renew
+	renewed, err := webhookCertificates().RenewIfExpiring(secret.Data, time.Now())
+	assert.NoError(t, err)
+	assert.False(t, renewed)
+
+	// the serving certificate is renewed before it expires
+	caCert := secret.Data[admission.CACertKey]
+	tlsCert := secret.Data[admission.TLSCertKey]
+	renewed, err = webhookCertificates().RenewIfExpiring(secret.Data, time.Now().Add(servingCertValidity-24*time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, renewed)
+	assert.Equal(t, caCert, secret.Data[admission.CACertKey])
+	assert.NotEqual(t, tlsCert, secret.Data[admission.TLSCertKey])
+
+	// the certificate authority is renewed before it expires, the previous one is still trusted
+	renewed, err = webhookCertificates().RenewIfExpiring(secret.Data, time.Now().Add(caValidity-24*time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, renewed)
+	assert.NotEqual(t, caCert, secret.Data[admission.CACertKey])
+	assert.Equal(t, caCert, secret.Data[admission.PreviousCACertKey])
+	assert.Equal(t, append(append([]byte{}, secret.Data[admission.CACertKey]...), caCert...), admission.CABundle(secret.Data))
+	expiration, err := admission.CertificateExpiration(secret.Data[admission.TLSCertKey])
+	assert.NoError(t, err)
+	assert.True(t, expiration.After(time.Now().Add(servingCertValidity-time.Hour)))
+
+	// the certificates provided by the admin are not changed
+	clientset = fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: appName, Namespace: namespace},
+		Data:       map[string][]byte{admission.TLSCertKey: []byte("cert"), admission.TLSKeyKey: []byte("key")},
+	})
+	assert.NoError(t, reconcileWebhookCertificates(&clusterd.Context{Clientset: clientset}))
+	secret, err = clientset.CoreV1().Secrets(namespace).Get(appName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("cert"), secret.Data[admission.TLSCertKey])
+	_, err = clientset.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Get(webhookConfigName, metav1.GetOptions{})
+	assert.Error(t, err)
+}
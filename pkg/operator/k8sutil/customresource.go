@@ -18,6 +18,8 @@ limitations under the License.
 package k8sutil
 
 import (
+	"time"
+
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
@@ -49,6 +51,12 @@ type CustomResource struct {
 // When the watch has detected a create, update, or delete event, it will handled by the functions in the resourceEventHandlers. After the callback returns, the watch loop will continue for the next event.
 // If the callback returns an error, the error will be logged.
 func WatchCR(resource CustomResource, namespace string, handlers cache.ResourceEventHandlerFuncs, client rest.Interface, objType runtime.Object, done <-chan struct{}) error {
+	return WatchCRWithResync(resource, namespace, handlers, client, objType, 0, done)
+}
+
+// WatchCRWithResync begins watching the custom resource like WatchCR. Every resyncPeriod, all the resources in the cache
+// retrigger an update event with the same old and new object. A resyncPeriod of 0 disables the resync.
+func WatchCRWithResync(resource CustomResource, namespace string, handlers cache.ResourceEventHandlerFuncs, client rest.Interface, objType runtime.Object, resyncPeriod time.Duration, done <-chan struct{}) error {
 	source := cache.NewListWatchFromClient(
 		client,
 		resource.Plural,
@@ -63,7 +71,7 @@ func WatchCR(resource CustomResource, namespace string, handlers cache.ResourceE
 		// resyncPeriod
 		// Every resyncPeriod, all resources in the cache will retrigger events.
 		// Set to 0 to disable the resync.
-		0,
+		resyncPeriod,
 
 		// Your custom resource event handlers.
 		handlers)
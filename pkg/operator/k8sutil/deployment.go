@@ -96,46 +96,126 @@ func UpdateDeploymentAndWait(context *clusterd.Context, modifiedDeployment *apps
 		}
 
 		// wait for the deployment to be restarted
-		sleepTime := 2
-		attempts := 30
-		if currentDeployment.Spec.ProgressDeadlineSeconds != nil {
-			// make the attempts double the progress deadline since the pod is both stopping and starting
-			attempts = 2 * (int(*currentDeployment.Spec.ProgressDeadlineSeconds) / sleepTime)
+		d, err := waitForDeploymentUpdate(context, currentDeployment, namespace)
+		if err != nil {
+			return nil, err
 		}
-		for i := 0; i < attempts; i++ {
-			// check for the status of the deployment
-			d, err := context.Clientset.AppsV1().Deployments(namespace).Get(modifiedDeployment.Name, metav1.GetOptions{})
-			if err != nil {
-				return nil, fmt.Errorf("failed to get deployment %q. %v", modifiedDeployment.Name, err)
-			}
-			if d.Status.ObservedGeneration != currentDeployment.Status.ObservedGeneration && d.Status.UpdatedReplicas > 0 && d.Status.ReadyReplicas > 0 {
-				logger.Infof("finished waiting for updated deployment %q", d.Name)
 
-				// Now we check if we can go to the next daemon
-				err = verifyCallback("continue")
-				if err != nil {
-					return nil, fmt.Errorf("failed to check if deployment %q can continue: %v", modifiedDeployment.Name, err)
-				}
+		// Now we check if we can go to the next daemon
+		err = verifyCallback("continue")
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if deployment %q can continue: %v", modifiedDeployment.Name, err)
+		}
 
-				return d, nil
-			}
+		return d, nil
+	}
 
-			// If ProgressDeadlineExceeded is reached let's fail earlier
-			// This can happen if one of the deployment cannot be scheduled on a node and stays in "pending" state
-			for _, condition := range d.Status.Conditions {
-				if condition.Type == v1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
-					return nil, fmt.Errorf("gave up waiting for deployment %q to update because %q", modifiedDeployment.Name, condition.Reason)
-				}
-			}
+	logger.Infof("deployment %q did not change, nothing to update", currentDeployment.Name)
+	return nil, nil
+}
+
+// DeploymentNeedsUpdate returns whether a deployment differs from the newly generated one
+func DeploymentNeedsUpdate(context *clusterd.Context, modifiedDeployment *apps.Deployment, namespace string) (bool, error) {
+	currentDeployment, err := context.Clientset.AppsV1().Deployments(namespace).Get(modifiedDeployment.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get deployment %s. %+v", modifiedDeployment.Name, err)
+	}
+
+	patchResult, err := patch.DefaultPatchMaker.Calculate(currentDeployment, modifiedDeployment)
+	if err != nil {
+		return false, fmt.Errorf("failed to calculate diff between current deployment %q and newly generated one. %v", currentDeployment.Name, err)
+	}
+	return !patchResult.IsEmpty(), nil
+}
+
+// UpdateDeploymentsAndWait updates several deployments together and waits until they are all running to return.
+// Like UpdateDeploymentAndWait, the callback verifies that the deployments can be stopped before they are all updated,
+// and that we can continue the update procedure once they are all running again.
+func UpdateDeploymentsAndWait(context *clusterd.Context, modifiedDeployments []*apps.Deployment, namespace string, verifyCallback func(action string) error) error {
+	if len(modifiedDeployments) == 0 {
+		return nil
+	}
+
+	names := []string{}
+	for _, d := range modifiedDeployments {
+		names = append(names, d.Name)
+	}
+	logger.Infof("updating deployments %v after verifying it is safe to stop them", names)
 
-			logger.Debugf("deployment %q status=%+v", d.Name, d.Status)
-			time.Sleep(time.Duration(sleepTime) * time.Second)
+	// Let's verify the deployments can be stopped together
+	// retry for 5 times, every minute
+	err := util.Retry(5, 60*time.Second, func() error {
+		return verifyCallback("stop")
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check if deployments %v can be updated. %v", names, err)
+	}
+
+	currentDeployments := []*apps.Deployment{}
+	for _, modifiedDeployment := range modifiedDeployments {
+		currentDeployment, err := context.Clientset.AppsV1().Deployments(namespace).Get(modifiedDeployment.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get deployment %s. %+v", modifiedDeployment.Name, err)
 		}
-		return nil, fmt.Errorf("gave up waiting for deployment %q to update", modifiedDeployment.Name)
+
+		// Set hash annotation to the newly generated deployment
+		if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(modifiedDeployment); err != nil {
+			return fmt.Errorf("failed to set hash annotation on deployment %q. %v", modifiedDeployment.Name, err)
+		}
+
+		if _, err := context.Clientset.AppsV1().Deployments(namespace).Update(modifiedDeployment); err != nil {
+			return fmt.Errorf("failed to update deployment %q. %v", modifiedDeployment.Name, err)
+		}
+		currentDeployments = append(currentDeployments, currentDeployment)
 	}
 
-	logger.Infof("deployment %q did not change, nothing to update", currentDeployment.Name)
-	return nil, nil
+	// the deployments restart in parallel, so the wait for the first one covers most of the wait for the others
+	for _, currentDeployment := range currentDeployments {
+		if _, err := waitForDeploymentUpdate(context, currentDeployment, namespace); err != nil {
+			return err
+		}
+	}
+
+	// Now we check if we can go to the next deployments
+	err = verifyCallback("continue")
+	if err != nil {
+		return fmt.Errorf("failed to check if deployments %v can continue: %v", names, err)
+	}
+
+	return nil
+}
+
+// waitForDeploymentUpdate waits for the pod of an updated deployment to be running, and returns the updated deployment
+func waitForDeploymentUpdate(context *clusterd.Context, currentDeployment *apps.Deployment, namespace string) (*apps.Deployment, error) {
+	sleepTime := 2
+	attempts := 30
+	if currentDeployment.Spec.ProgressDeadlineSeconds != nil {
+		// make the attempts double the progress deadline since the pod is both stopping and starting
+		attempts = 2 * (int(*currentDeployment.Spec.ProgressDeadlineSeconds) / sleepTime)
+	}
+	for i := 0; i < attempts; i++ {
+		// check for the status of the deployment
+		d, err := context.Clientset.AppsV1().Deployments(namespace).Get(currentDeployment.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment %q. %v", currentDeployment.Name, err)
+		}
+		if d.Status.ObservedGeneration != currentDeployment.Status.ObservedGeneration && d.Status.UpdatedReplicas > 0 && d.Status.ReadyReplicas > 0 {
+			logger.Infof("finished waiting for updated deployment %q", d.Name)
+			return d, nil
+		}
+
+		// If ProgressDeadlineExceeded is reached let's fail earlier
+		// This can happen if one of the deployment cannot be scheduled on a node and stays in "pending" state
+		for _, condition := range d.Status.Conditions {
+			if condition.Type == v1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+				return nil, fmt.Errorf("gave up waiting for deployment %q to update because %q", currentDeployment.Name, condition.Reason)
+			}
+		}
+
+		logger.Debugf("deployment %q status=%+v", d.Name, d.Status)
+		time.Sleep(time.Duration(sleepTime) * time.Second)
+	}
+	return nil, fmt.Errorf("gave up waiting for deployment %q to update", currentDeployment.Name)
 }
 
 // GetDeployments returns a list of deployment names labels matching a given selector
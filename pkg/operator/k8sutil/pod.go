@@ -334,19 +334,19 @@ func SetNodeAntiAffinityForPod(pod *v1.PodSpec, p rookv1.Placement, requiredDuri
 	}
 }
 
-func ForceDeletePodIfStuck(context *clusterd.Context, pod v1.Pod) error {
+func ForceDeletePodIfStuck(context *clusterd.Context, pod v1.Pod) (bool, error) {
 	logger.Debugf("checking if pod %q is stuck and should be force deleted", pod.Name)
 	if pod.DeletionTimestamp.IsZero() {
 		logger.Debugf("skipping pod %q restart since the pod is not deleted", pod.Name)
-		return nil
+		return false, nil
 	}
 	node, err := context.Clientset.CoreV1().Nodes().Get(pod.Spec.NodeName, metav1.GetOptions{})
 	if err != nil {
-		return errors.Wrap(err, "node status is not available")
+		return false, errors.Wrap(err, "node status is not available")
 	}
 	if NodeIsReady(*node) {
 		logger.Debugf("skipping restart of pod %q since the node status is ready", pod.Name)
-		return nil
+		return false, nil
 	}
 
 	logger.Infof("force deleting pod %q that appears to be stuck terminating", pod.Name)
@@ -354,8 +354,8 @@ func ForceDeletePodIfStuck(context *clusterd.Context, pod v1.Pod) error {
 	deleteOpts := &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod}
 	if err := context.Clientset.CoreV1().Pods(pod.Namespace).Delete(pod.Name, deleteOpts); err != nil {
 		logger.Warningf("pod %q deletion failed. %v", pod.Name, err)
-		return nil
+		return false, nil
 	}
 	logger.Infof("pod %q deletion succeeded", pod.Name)
-	return nil
+	return true, nil
 }
@@ -34,6 +34,9 @@ import (
 
 func getMonitoringClient() (*monitoringclient.Clientset, error) {
 	cfg, err := clientcmd.BuildConfigFromFlags("", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build monitoring client config. %v", err)
+	}
 	client, err := monitoringclient.NewForConfig(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get monitoring client. %v", err)
@@ -126,3 +129,17 @@ func CreateOrUpdatePrometheusRule(prometheusRule *monitoringv1.PrometheusRule) (
 	}
 	return promRule, nil
 }
+
+// DeleteServiceMonitor deletes a serviceMonitor object. Deleting a missing serviceMonitor is a no-op.
+func DeleteServiceMonitor(namespace, name string) error {
+	logger.Debugf("deleting servicemonitor %s", name)
+	client, err := getMonitoringClient()
+	if err != nil {
+		return fmt.Errorf("failed to get monitoring client. %v", err)
+	}
+	err = client.MonitoringV1().ServiceMonitors(namespace).Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete servicemonitor. %v", err)
+	}
+	return nil
+}